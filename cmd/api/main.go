@@ -1,19 +1,31 @@
 package main
 
 import (
-	"database/sql"
+	"context"
 	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
 	"github.com/sasalatart/batcoms/config"
-	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/db/backend"
+	"github.com/sasalatart/batcoms/db/seeder"
 	"github.com/sasalatart/batcoms/http"
+	"github.com/sasalatart/batcoms/http/middleware"
+	"github.com/sasalatart/batcoms/pkg/io/json"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/ratelimit"
+	"github.com/sasalatart/batcoms/pkg/tracing"
 	"github.com/spf13/viper"
-	"gorm.io/gorm"
 )
 
 var testModeFlag = flag.Bool("test", false, "Whether the API should run in test mode or not")
+var dataFlag = flag.String("data", "", "A scraped data file to serve from memory instead of from a database")
 
 func init() {
 	config.Setup()
@@ -21,23 +33,121 @@ func init() {
 }
 
 func main() {
-	var db *gorm.DB
-	var sqlDB *sql.DB
-	var port int
+	if err := run(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run serves the API until it is told to shut down, or until it can no longer listen for
+// connections, in which case the error is returned once everything has been closed
+func run() error {
+	port := viper.GetInt("PORT")
 	if *testModeFlag {
-		db, sqlDB = postgresql.Connect(postgresql.DefaultTestConfig())
 		port = viper.GetInt("PORT_TEST")
-	} else {
-		db, sqlDB = postgresql.Connect(nil)
-		port = viper.GetInt("PORT")
 	}
-	defer sqlDB.Close()
-
-	server := http.Setup(
-		postgresql.NewFactionsRepository(db),
-		postgresql.NewCommandersRepository(db),
-		postgresql.NewBattlesRepository(db),
-		false,
+	loggerService := logger.New(log.Writer(), os.Stderr)
+	shutdownTracing, err := tracing.Setup(
+		viper.GetString("TRACING_EXPORTER"),
+		viper.GetString("TRACING_OTLP_ENDPOINT"),
+		viper.GetString("TRACING_SERVICE_NAME"),
 	)
-	log.Fatal(server.Listen(fmt.Sprintf(":%d", port)))
+	if err != nil {
+		log.Fatalf("Error setting up tracing: %s\n", err)
+	}
+	b := connect(loggerService)
+	defer b.Close()
+
+	// Integration tests make requests from a single IP faster than anonymous clients may
+	var rateLimit func(*fiber.Ctx) error
+	if !*testModeFlag {
+		rateLimit = middleware.WithRateLimit(b.APIKeys, rateLimits(), viper.GetDuration("API_KEYS_CACHE_TTL"))
+	}
+	server := http.Setup(b.Factions, b.Commanders, b.Battles, b.Translations, http.Options{
+		FlagsDir:    viper.GetString("FLAGS_DIR"),
+		RateLimit:   rateLimit,
+		ProxyHeader: viper.GetString("PROXY_HEADER"),
+		Logger:      loggerService,
+		Checks:      []middleware.Check{{Name: "database", Run: b.Ping}},
+	})
+	listening := make(chan error, 1)
+	go func() {
+		listening <- server.Listen(fmt.Sprintf(":%d", port))
+	}()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	timeout := viper.GetDuration("SHUTDOWN_TIMEOUT")
+	var listenErr error
+	select {
+	case sig := <-signals:
+		loggerService.With(logger.KV("signal", sig.String())).Info("Shutting down, draining in-flight requests")
+		if err := shutdown(server, timeout); err != nil {
+			loggerService.Error(err)
+		}
+	case err := <-listening:
+		listenErr = errors.Wrap(err, "Listening for connections")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := shutdownTracing(ctx); err != nil {
+		loggerService.Error(err)
+	}
+	return listenErr
+}
+
+// rateLimits returns the configured limits of the requests made by clients without an API key, and
+// of all of the requests made from each IP
+func rateLimits() middleware.RateLimits {
+	return middleware.RateLimits{
+		Anonymous: ratelimit.Limit{
+			PerMinute: viper.GetInt("RATE_LIMIT_ANONYMOUS_PER_MINUTE"),
+			Burst:     viper.GetInt("RATE_LIMIT_ANONYMOUS_BURST"),
+		},
+		PerIP: ratelimit.Limit{
+			PerMinute: viper.GetInt("RATE_LIMIT_IP_PER_MINUTE"),
+			Burst:     viper.GetInt("RATE_LIMIT_IP_BURST"),
+		},
+	}
+}
+
+// shutdown stops accepting connections and waits for in-flight requests to be handled, for up to the
+// given timeout
+func shutdown(server *fiber.App, timeout time.Duration) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- server.Shutdown()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("Requests still in flight after %s", timeout)
+	}
+}
+
+// connect connects to the configured database, unless a data file was given, in which case it is
+// loaded into memory together with the reviewed merges and faction relationships
+func connect(loggerService *logger.Logger) backend.Backend {
+	if *dataFlag == "" {
+		b, err := backend.ConnectWithRetry(*testModeFlag, loggerService)
+		if err != nil {
+			log.Fatalf("Error connecting to the database: %s\n", err)
+		}
+		return b
+	}
+
+	importedData := new(seeder.ImportedData)
+	if err := json.Import(*dataFlag, importedData); err != nil {
+		log.Fatalf("Error importing data: %s\n", err)
+	}
+	merges, err := seeder.ImportMerges(viper.GetString("ACTOR_MERGES"))
+	if err != nil {
+		log.Fatalf("Error importing actor merges: %s\n", err)
+	}
+	seeder.Merge(importedData, merges, loggerService)
+	mappings, err := seeder.ImportRelationships(viper.GetString("FACTION_RELATIONSHIPS"))
+	if err != nil {
+		log.Fatalf("Error importing faction relationships: %s\n", err)
+	}
+	return backend.Memory(importedData, mappings, loggerService)
 }
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sasalatart/batcoms/config"
+	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	uuid "github.com/satori/go.uuid"
+)
+
+const usage = `Usage:
+  apikeys issue -name=<name> [-perMinute=600] [-burst=100]
+  apikeys list
+  apikeys revoke -id=<id>`
+
+func init() {
+	config.Setup()
+}
+
+// main issues, lists and revokes the keys through which clients of the API get higher rate limits
+// than anonymous ones. Keys are stored hashed, so these are only shown once, when issued
+func main() {
+	if len(os.Args) < 2 {
+		log.Fatalln(usage)
+	}
+
+	db, sqlDB, err := postgresql.Open(nil)
+	if err != nil {
+		log.Fatalf("Error connecting to the database: %s\n", err)
+	}
+	defer sqlDB.Close()
+	if err := postgresql.MigrateAPIKeys(db); err != nil {
+		log.Fatalf("Error creating the API keys table: %s\n", err)
+	}
+	repo := postgresql.NewAPIKeysRepository(db)
+
+	switch command, args := os.Args[1], os.Args[2:]; command {
+	case "issue":
+		issue(repo, args)
+	case "list":
+		list(repo)
+	case "revoke":
+		revoke(repo, args)
+	default:
+		log.Fatalf("Unknown command %q\n%s\n", command, usage)
+	}
+}
+
+func issue(repo apikeys.Repository, args []string) {
+	flags := flag.NewFlagSet("issue", flag.ExitOnError)
+	name := flags.String("name", "", "Who the key is issued to")
+	perMinute := flags.Int("perMinute", 600, "How many requests per minute the key may make")
+	burst := flags.Int("burst", 100, "How many requests the key may make at once")
+	flags.Parse(args)
+
+	key, err := apikeys.Generate()
+	if err != nil {
+		log.Fatalf("Error issuing API key: %s\n", err)
+	}
+	id, err := repo.CreateOne(apikeys.CreationInput{
+		Name:              *name,
+		Hash:              apikeys.Hash(key),
+		RequestsPerMinute: *perMinute,
+		Burst:             *burst,
+	})
+	if err != nil {
+		log.Fatalf("Error issuing API key: %s\n", err)
+	}
+	fmt.Printf("Issued API key %s to %s. It will not be shown again:\n%s\n", id, *name, key)
+}
+
+func list(repo apikeys.Repository) {
+	keys, err := repo.FindMany(context.Background())
+	if err != nil {
+		log.Fatalf("Error listing API keys: %s\n", err)
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tPER MINUTE\tBURST\tCREATED\tREVOKED")
+	for _, k := range keys {
+		revoked := "-"
+		if k.Revoked() {
+			revoked = k.RevokedAt.Format(time.RFC3339)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\t%s\n", k.ID, k.Name, k.RequestsPerMinute, k.Burst, k.CreatedAt.Format(time.RFC3339), revoked)
+	}
+	w.Flush()
+}
+
+func revoke(repo apikeys.Repository, args []string) {
+	flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+	idFlag := flags.String("id", "", "The ID of the key to revoke")
+	flags.Parse(args)
+
+	id, err := uuid.FromString(*idFlag)
+	if err != nil {
+		log.Fatalf("Invalid ID %q: %s\n", *idFlag, err)
+	}
+	if err := repo.Revoke(id); err != nil {
+		log.Fatalf("Error revoking API key %s: %s\n", id, err)
+	}
+	fmt.Printf("Revoked API key %s\n", id)
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/sasalatart/batcoms/db/seeder"
+	jsonio "github.com/sasalatart/batcoms/pkg/io/json"
+)
+
+var formatFlag = flag.String("format", "text", "The format of the report, text or json")
+
+func init() {
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %s [-format=text|json] old.json new.json\n", os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+}
+
+// main compares two files of scraped data, reporting the battles, factions and commanders that were
+// added or removed, and the dates, results, sides, strength and casualties that changed for the
+// battles that are in both of them
+func main() {
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if *formatFlag != "text" && *formatFlag != "json" {
+		log.Fatalf("Unknown format %q, must be text or json\n", *formatFlag)
+	}
+	previous := importData(flag.Arg(0))
+	current := importData(flag.Arg(1))
+	changes := seeder.Compare(previous, current)
+
+	w := bufio.NewWriter(os.Stdout)
+	if *formatFlag == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(changes); err != nil {
+			log.Fatalf("Error encoding changes: %s\n", err)
+		}
+	} else {
+		writeText(w, flag.Arg(0), flag.Arg(1), previous, current, changes)
+	}
+	if err := w.Flush(); err != nil {
+		log.Fatalf("Error writing changes: %s\n", err)
+	}
+}
+
+func importData(fileName string) *seeder.ImportedData {
+	data := new(seeder.ImportedData)
+	if err := jsonio.Import(fileName, data); err != nil {
+		log.Fatalf("Error importing data: %s\n", err)
+	}
+	if err := data.CheckSchemaVersion(); err != nil {
+		log.Fatalf("Error importing %s: %s\n", fileName, err)
+	}
+	return data
+}
+
+// writeText writes the changes in a human-readable report, in which added entities are preceded by
+// "+", removed ones by "-" and changed battles by "~", followed by their changed fields
+func writeText(w io.Writer, previousName, currentName string, previous, current *seeder.ImportedData, changes seeder.Changes) {
+	describe := func(name string, data *seeder.ImportedData) string {
+		return fmt.Sprintf(
+			"%s (%d battles, %d factions, %d commanders)",
+			name, len(data.WikiBattlesByID), len(data.WikiFactionsByID), len(data.WikiCommandersByID),
+		)
+	}
+	fmt.Fprintf(w, "Comparing %s with %s\n", describe(previousName, previous), describe(currentName, current))
+	if changes.Empty() {
+		fmt.Fprintln(w, "\nNo changes")
+		return
+	}
+
+	writeEntries := func(title, mark string, entries []seeder.Entry) {
+		if len(entries) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n%s (%d):\n", title, len(entries))
+		for _, e := range entries {
+			fmt.Fprintf(w, "  %s %d %s <%s>\n", mark, e.WikiID, e.Name, e.URL)
+		}
+	}
+	writeEntries("Battles added", "+", changes.Battles.Added)
+	writeEntries("Battles removed", "-", changes.Battles.Removed)
+	writeEntries("Factions added", "+", changes.Factions.Added)
+	writeEntries("Factions removed", "-", changes.Factions.Removed)
+	writeEntries("Commanders added", "+", changes.Commanders.Added)
+	writeEntries("Commanders removed", "-", changes.Commanders.Removed)
+
+	if len(changes.Battles.Changed) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\nBattles changed (%d):\n", len(changes.Battles.Changed))
+	for _, bc := range changes.Battles.Changed {
+		fmt.Fprintf(w, "  ~ %d %s <%s>\n", bc.WikiID, bc.Name, bc.URL)
+		for _, f := range bc.Fields {
+			fmt.Fprintf(w, "      %s: %q -> %q\n", f.Field, f.Previous, f.Current)
+		}
+	}
+}
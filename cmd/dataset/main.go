@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/sasalatart/batcoms/config"
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/spf13/viper"
+)
+
+var dataFlag = flag.String("data", "", "The file with the scraped data to release, instead of SCRAPER_DATA")
+var versionFlag = flag.String("version", "", "The version of the release, instead of the date in which the data was scraped")
+var releasesFlag = flag.String("releases", "", "The directory in which releases are stored, instead of DATASET_RELEASES")
+
+func init() {
+	config.Setup()
+	flag.Parse()
+}
+
+// main packages the scraped data into a new release, stored in a directory named after its version
+// together with a manifest (with its schema version, counts and checksums) and a changelog against
+// the previous release
+func main() {
+	loggerService := logger.New(log.Writer(), os.Stderr).Named("dataset")
+	dataFileName := *dataFlag
+	if dataFileName == "" {
+		dataFileName = viper.GetString("SCRAPER_DATA")
+	}
+	releasesDir := *releasesFlag
+	if releasesDir == "" {
+		releasesDir = viper.GetString("DATASET_RELEASES")
+	}
+
+	m, err := seeder.Release(dataFileName, releasesDir, *versionFlag)
+	if err != nil {
+		log.Fatalf("Error releasing data: %s\n", err)
+	}
+	loggerService.With(
+		logger.KV("version", m.Version),
+		logger.KV("previous", m.Previous),
+		logger.KV("battles", m.Counts.Battles),
+		logger.KV("factions", m.Counts.Factions),
+		logger.KV("commanders", m.Counts.Commanders),
+		logger.KV("checksum", m.Checksums[seeder.DataFileName]),
+	).Info("Released data")
+}
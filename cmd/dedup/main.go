@@ -0,0 +1,102 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sasalatart/batcoms/config"
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/io/json"
+	"github.com/spf13/viper"
+)
+
+func init() {
+	config.Setup()
+}
+
+// main finds likely duplicates among the scraped factions and commanders, and asks for each group
+// of them which one is canonical. Approved merges are appended to the merges file, which is read
+// by the seeder
+func main() {
+	importedData := new(seeder.ImportedData)
+	if err := json.Import(viper.GetString("SCRAPER_DATA"), importedData); err != nil {
+		log.Fatalf("Error importing data: %s\n", err)
+	}
+
+	mergesFileName := viper.GetString("ACTOR_MERGES")
+	merges, err := seeder.ImportMerges(mergesFileName)
+	if err != nil {
+		log.Fatalf("Error importing merges: %s\n", err)
+	}
+	reviewed := make(map[wikiactors.Kind]map[int]bool)
+	for _, m := range merges {
+		if reviewed[m.Kind] == nil {
+			reviewed[m.Kind] = make(map[int]bool)
+		}
+		reviewed[m.Kind][m.Canonical] = true
+		for _, id := range m.Duplicates {
+			reviewed[m.Kind][id] = true
+		}
+	}
+
+	actorsByID := make(map[wikiactors.Kind]map[int]wikiactors.Actor)
+	var actors []wikiactors.Actor
+	for _, byID := range []map[string]wikiactors.Actor{importedData.WikiFactionsByID, importedData.WikiCommandersByID} {
+		for _, a := range byID {
+			if actorsByID[a.Kind] == nil {
+				actorsByID[a.Kind] = make(map[int]wikiactors.Actor)
+			}
+			actorsByID[a.Kind][a.ID] = a
+			actors = append(actors, a)
+		}
+	}
+
+	reader := bufio.NewReader(os.Stdin)
+	for _, d := range wikiactors.FindDuplicates(actors) {
+		if allReviewed(d, reviewed) {
+			continue
+		}
+		fmt.Printf("\nPossible duplicates (%s):\n", strings.Join(d.Reasons, ", "))
+		for i, id := range d.IDs {
+			a := actorsByID[d.Kind][id]
+			fmt.Printf("  [%d] %s (%d) %s\n", i+1, a.Name, a.ID, a.URL)
+		}
+		fmt.Printf("Number of the canonical one, \"s\" to skip or \"q\" to quit: ")
+
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+		if err != nil || answer == "q" {
+			break
+		}
+		choice, err := strconv.Atoi(answer)
+		if err != nil || choice < 1 || choice > len(d.IDs) {
+			continue
+		}
+		m := wikiactors.Merge{Kind: d.Kind, Canonical: d.IDs[choice-1]}
+		for _, id := range d.IDs {
+			if id != m.Canonical {
+				m.Duplicates = append(m.Duplicates, id)
+			}
+		}
+		merges = append(merges, m)
+	}
+
+	if err := json.Export(mergesFileName, merges); err != nil {
+		log.Fatalf("Error exporting merges: %s\n", err)
+	}
+	fmt.Printf("\nMerges saved to %s\n", mergesFileName)
+}
+
+func allReviewed(d wikiactors.Duplicates, reviewed map[wikiactors.Kind]map[int]bool) bool {
+	for _, id := range d.IDs {
+		if !reviewed[d.Kind][id] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"log"
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/config"
+	"github.com/sasalatart/batcoms/db/backend"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/pkg/linkeddata"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/spf13/viper"
+)
+
+var formatFlag = flag.String("format", linkeddata.FormatTurtle, "The RDF format of the dump, turtle or ntriples")
+var outFlag = flag.String("out", "", "The file in which to store the dump, instead of writing it to stdout")
+var baseURLFlag = flag.String("baseURL", "", "The base URL of the IRIs of entities, instead of LINKED_DATA_BASE_URL")
+
+func init() {
+	config.Setup()
+	flag.Parse()
+}
+
+// main dumps all of the factions, commanders and battles in the database as RDF, described with
+// schema.org and the custom vocabulary of pkg/linkeddata, and linked to their Wikipedia articles and
+// Wikidata items with owl:sameAs
+func main() {
+	loggerService := logger.New(log.Writer(), os.Stderr)
+	b, err := backend.ConnectWithRetry(false, loggerService)
+	if err != nil {
+		log.Fatalf("Error connecting to the database: %s\n", err)
+	}
+	defer b.Close()
+
+	var out io.Writer = os.Stdout
+	if *outFlag != "" {
+		file, err := os.Create(*outFlag)
+		if err != nil {
+			log.Fatalf("Error creating %s: %s\n", *outFlag, err)
+		}
+		defer file.Close()
+		out = file
+	}
+	w, err := linkeddata.NewWriter(out, *formatFlag)
+	if err != nil {
+		log.Fatalf("Error exporting: %s\n", err)
+	}
+	baseURL := *baseURLFlag
+	if baseURL == "" {
+		baseURL = viper.GetString("LINKED_DATA_BASE_URL")
+	}
+
+	counts, err := export(b, linkeddata.NewMapper(baseURL), w)
+	if err != nil {
+		log.Fatalf("Error exporting: %s\n", err)
+	}
+	loggerService.With(
+		logger.KV("factions", counts[0]),
+		logger.KV("commanders", counts[1]),
+		logger.KV("battles", counts[2]),
+	).Info("Exported dataset")
+}
+
+// export writes all of the factions, commanders and battles of the given backend, returning how
+// many of each were written
+func export(b backend.Backend, m *linkeddata.Mapper, w *linkeddata.Writer) ([3]int, error) {
+	var counts [3]int
+	err := b.Factions.Stream(context.Background(), factions.FindManyQuery{}, func(f factions.Faction) error {
+		counts[0]++
+		w.Write(m.Faction(f))
+		return nil
+	})
+	if err != nil {
+		return counts, errors.Wrap(err, "Exporting factions")
+	}
+	err = b.Commanders.Stream(context.Background(), commanders.FindManyQuery{}, func(c commanders.Commander) error {
+		counts[1]++
+		w.Write(m.Commander(c))
+		return nil
+	})
+	if err != nil {
+		return counts, errors.Wrap(err, "Exporting commanders")
+	}
+	err = b.Battles.Stream(context.Background(), battles.FindManyQuery{}, func(battle battles.Battle) error {
+		counts[2]++
+		w.Write(m.Battle(battle))
+		return nil
+	})
+	if err != nil {
+		return counts, errors.Wrap(err, "Exporting battles")
+	}
+	return counts, errors.Wrap(w.Flush(), "Writing the dump")
+}
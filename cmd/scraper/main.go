@@ -1,42 +1,75 @@
 package main
 
 import (
-	"fmt"
-	"io/ioutil"
+	"flag"
 	"log"
 	"os"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sasalatart/batcoms/config"
 	"github.com/sasalatart/batcoms/domain/wikibattles"
 	"github.com/sasalatart/batcoms/pkg/io/json"
 	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/metrics"
 	"github.com/sasalatart/batcoms/pkg/scraper/battles"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
 	"github.com/sasalatart/batcoms/pkg/scraper/list"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
 	"github.com/spf13/viper"
 )
 
+var langFlag = flag.String("lang", "en", "The language edition of Wikipedia to scrape (en, es, fr or de)")
+var localizeFlag = flag.String("localize", "", "Comma-separated languages in which to also fetch the names and summaries of battles")
+var wikidataFlag = flag.Bool("wikidata", false, "Whether to also fetch the Wikidata facts of battles, factions and commanders")
+
 func init() {
 	config.Setup()
 }
 
 func main() {
-	loggerService := logger.New(ioutil.Discard, os.Stderr)
-	scraperService := battles.NewScraper(loggerService)
+	flag.Parse()
+
+	edition, err := editions.ByLanguage(*langFlag)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	var localizeTo []string
+	if *localizeFlag != "" {
+		localizeTo = strings.Split(*localizeFlag, ",")
+	}
+
+	loggerService := logger.New(log.Writer(), os.Stderr).Named("scraper")
+	scraperService := battles.NewScraperFor(edition, loggerService, localizeTo...)
 
-	var failedCount int
+	var failedCount int64
+	start := time.Now()
 	semaphore := make(chan bool, 10)
-	list := list.Scrape(loggerService)
-	for i, battle := range list {
+	list := list.ScrapeEdition(edition, loggerService)
+	for _, battle := range list {
 		semaphore <- true
-		fmt.Printf("\r%d/%d (failed: %d)", i, len(list), failedCount)
-		go func(i int, b wikibattles.BattleItem) {
+		go func(b wikibattles.BattleItem) {
 			if _, err := scraperService.ScrapeOne(b.URL); err != nil {
-				failedCount++
-				loggerService.Error(errors.Wrapf(err, "Error scraping %s", b.URL))
+				atomic.AddInt64(&failedCount, 1)
+				loggerService.With(logger.KV("url", b.URL)).Error(errors.Wrap(err, "Error scraping battle"))
 			}
 			<-semaphore
-		}(i, battle)
+		}(battle)
+	}
+	for i := 0; i < cap(semaphore); i++ {
+		semaphore <- true
+	}
+	loggerService.With(
+		logger.KV("total", len(list)),
+		logger.KV("failed", atomic.LoadInt64(&failedCount)),
+		logger.KV("duration_ms", time.Since(start).Milliseconds()),
+	).Info("Finished scraping battles")
+
+	if *wikidataFlag {
+		loggerService.Info("Fetching Wikidata facts")
+		scraperService.Enrich(wikidata.NewClient(wikidata.DefaultURL))
 	}
 
 	data := scraperService.Data()
@@ -44,4 +77,7 @@ func main() {
 	if err := json.Export(fileName, data); err != nil {
 		log.Fatalln(err)
 	}
+	if err := metrics.Export("scraper", viper.GetString("METRICS_TEXTFILE_DIR"), viper.GetString("METRICS_PUSHGATEWAY")); err != nil {
+		loggerService.Error(errors.Wrap(err, "Exporting metrics"))
+	}
 }
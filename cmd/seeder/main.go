@@ -2,7 +2,6 @@ package main
 
 import (
 	"flag"
-	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
@@ -11,14 +10,18 @@ import (
 
 	"github.com/pkg/errors"
 	"github.com/sasalatart/batcoms/config"
-	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/db/backend"
 	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/pkg/flags"
 	"github.com/sasalatart/batcoms/pkg/io/json"
 	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/metrics"
 	"github.com/spf13/viper"
 )
 
 var dataURL = flag.String("dataURL", "", "The URL from which the seed data file can be downloaded")
+var manifestURL = flag.String("manifestURL", "", "The URL from which the manifest of the release of the seed data file can be downloaded, to verify its checksum")
+var downloadFlagsFlag = flag.Bool("downloadFlags", false, "Whether to also store the images of the flags of factions locally")
 
 func init() {
 	config.Setup()
@@ -26,52 +29,113 @@ func init() {
 }
 
 func main() {
-	db, sqlDB := postgresql.Connect(nil)
-	defer sqlDB.Close()
-
 	loggerService := logger.New(log.Writer(), os.Stderr)
+	err := run(loggerService)
+	if err := metrics.Export("seeder", viper.GetString("METRICS_TEXTFILE_DIR"), viper.GetString("METRICS_PUSHGATEWAY")); err != nil {
+		loggerService.Error(errors.Wrap(err, "Exporting metrics"))
+	}
+	if err != nil {
+		log.Fatalf("%s\n", err)
+	}
+}
 
-	dataFileName := fileNameFor(*dataURL, viper.GetString("SCRAPER_DATA"), loggerService)
+// run seeds the database, returning the first error found instead of exiting, so that main still
+// exports the metrics collected until then
+func run(loggerService *logger.Logger) error {
+	b, err := backend.ConnectWithRetry(false, loggerService)
+	if err != nil {
+		return errors.Wrap(err, "Error connecting to the database")
+	}
+	defer b.Close()
+
+	dataFileName, err := fileNameFor(*dataURL, viper.GetString("SCRAPER_DATA"), loggerService)
+	if err != nil {
+		return err
+	}
 	if *dataURL != "" {
 		defer os.Remove(dataFileName)
 	}
 
+	if *manifestURL != "" {
+		if err := verify(dataFileName, *manifestURL, loggerService); err != nil {
+			return err
+		}
+	}
+
+	if err := seeder.CheckSchemaVersionOf(dataFileName); err != nil {
+		return errors.Wrap(err, "Error importing data")
+	}
 	importedData := new(seeder.ImportedData)
 	if err := json.Import(dataFileName, importedData); err != nil {
-		log.Fatalf("Error importing data: %s\n", err)
+		return errors.Wrap(err, "Error importing data")
+	}
+
+	merges, err := seeder.ImportMerges(viper.GetString("ACTOR_MERGES"))
+	if err != nil {
+		return errors.Wrap(err, "Error importing actor merges")
 	}
+	seeder.Merge(importedData, merges, loggerService)
 
-	postgresql.Reset(db)
-	seeder.Seed(
-		importedData,
-		postgresql.NewFactionsRepository(db),
-		postgresql.NewCommandersRepository(db),
-		postgresql.NewBattlesRepository(db),
-		loggerService,
-	)
+	if *downloadFlagsFlag {
+		seeder.DownloadFlags(importedData, flags.NewDownloader(flags.DefaultURL, viper.GetString("FLAGS_DIR")), loggerService)
+	}
+
+	b.Reset()
+	seeder.Seed(importedData, b.Factions, b.Commanders, b.Battles, b.Translations, loggerService)
+
+	relationshipsFileName := viper.GetString("FACTION_RELATIONSHIPS")
+	mappings, err := seeder.ImportRelationships(relationshipsFileName)
+	if err != nil {
+		return errors.Wrap(err, "Error importing faction relationships")
+	}
+	if len(mappings) == 0 {
+		loggerService.With(logger.KV("file", relationshipsFileName)).Warn("No faction relationships found, skipping")
+		return nil
+	}
+	seeder.Relate(mappings, b.Factions, loggerService)
+	return nil
 }
 
-func fileNameFor(url, defaultName string, loggerService logger.Interface) string {
-	handleError := func(err error, from string) {
-		if err != nil {
-			log.Fatalf("No data may be read from %s: %s", from, err)
+func fileNameFor(url, defaultName string, loggerService *logger.Logger) (string, error) {
+	if url == "" {
+		loggerService.With(logger.KV("file", defaultName)).Info("No URL supplied, falling back to the default file")
+		if _, err := os.Stat(defaultName); err != nil {
+			return "", errors.Wrapf(err, "No data may be read from %s", defaultName)
 		}
+		return defaultName, nil
 	}
 
-	if url == "" {
-		loggerService.Info(fmt.Sprintf("No URL supplied, falling back to %s...\n", defaultName))
-		_, err := os.Stat(defaultName)
-		handleError(err, defaultName)
-		return defaultName
+	tmpFile, err := download(url, "data file", loggerService)
+	if err != nil {
+		return "", errors.Wrapf(err, "No data may be read from %s", url)
+	}
+	return tmpFile.Name(), nil
+}
+
+// verify checks that the seed data file is the one of the release described by the manifest at the
+// given URL, returning an error otherwise
+func verify(dataFileName, url string, loggerService *logger.Logger) error {
+	manifestFile, err := download(url, "manifest", loggerService)
+	if err != nil {
+		return errors.Wrapf(err, "No manifest may be read from %s", url)
 	}
+	defer os.Remove(manifestFile.Name())
 
-	tmpFile, err := download(url, loggerService)
-	handleError(err, url)
-	return tmpFile.Name()
+	m, err := seeder.ImportManifest(manifestFile.Name())
+	if err != nil {
+		return errors.Wrap(err, "Error importing manifest")
+	}
+	if err := m.Verify(dataFileName); err != nil {
+		return errors.Wrap(err, "Error verifying data")
+	}
+	loggerService.With(logger.KV("version", m.Version)).Info("Verified data file against its manifest")
+	return nil
 }
 
-func download(url string, logger logger.Interface) (*os.File, error) {
-	logger.Info(fmt.Sprintf("Downloading from %s...\n", url))
+// download stores the file at the given URL, which is described as what in logs, into a temporary
+// file
+func download(url, what string, loggerService *logger.Logger) (*os.File, error) {
+	loggerService.With(logger.KV("url", url)).Info("Downloading " + what)
 	resp, err := http.Get(url)
 	if err != nil {
 		return nil, errors.Wrap(err, "Downloading file")
@@ -2,6 +2,7 @@ package config
 
 import (
 	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/pkg/logger"
 	"github.com/spf13/viper"
 )
 
@@ -19,6 +20,35 @@ func Setup() {
 	mustBindEnv("POSTGRES_HOST")
 	mustBindEnv("POSTGRES_PORT")
 	mustBindEnv("POSTGRES_PASS")
+	mustBindEnv("DB_BACKEND")
+	mustBindEnv("SQLITE_PATH")
+	mustBindEnv("SQLITE_PATH_TEST")
+	mustBindEnv("DATES_JDN")
+	mustBindEnv("LOG_LEVEL")
+	mustBindEnv("LOG_FORMAT")
+	mustBindEnv("METRICS_TEXTFILE_DIR")
+	mustBindEnv("METRICS_PUSHGATEWAY")
+	mustBindEnv("DB_CONNECT_ATTEMPTS")
+	mustBindEnv("DB_CONNECT_BACKOFF")
+	mustBindEnv("DB_MAX_OPEN_CONNS")
+	mustBindEnv("DB_MAX_IDLE_CONNS")
+	mustBindEnv("DB_CONN_MAX_LIFETIME")
+	mustBindEnv("SHUTDOWN_TIMEOUT")
+	mustBindEnv("TRACING_EXPORTER")
+	mustBindEnv("TRACING_OTLP_ENDPOINT")
+	mustBindEnv("TRACING_SERVICE_NAME")
+	mustBindEnv("RATE_LIMIT_ANONYMOUS_PER_MINUTE")
+	mustBindEnv("RATE_LIMIT_ANONYMOUS_BURST")
+	mustBindEnv("RATE_LIMIT_IP_PER_MINUTE")
+	mustBindEnv("RATE_LIMIT_IP_BURST")
+	mustBindEnv("PROXY_HEADER")
+	mustBindEnv("API_KEYS_CACHE_TTL")
+	mustBindEnv("LINKED_DATA_BASE_URL")
+	mustBindEnv("DATASET_RELEASES")
+
+	if err := logger.Configure(viper.GetString("LOG_LEVEL"), viper.GetString("LOG_FORMAT")); err != nil {
+		panic(errors.Wrap(err, "Configuring logger"))
+	}
 }
 
 func mustBindEnv(key string) {
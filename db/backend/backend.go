@@ -0,0 +1,154 @@
+package backend
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sasalatart/batcoms/db/memory"
+	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/db/sqlite"
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/spf13/viper"
+)
+
+// Backend groups the repositories of one of the supported databases, together with the means to
+// reset its tables, to check that it is still reachable and to close its connection. API keys are
+// only stored in PostgreSQL, so APIKeys is nil for any other backend
+type Backend struct {
+	Factions     factions.Repository
+	Commanders   commanders.Repository
+	Battles      battles.Repository
+	Translations translations.Repository
+	APIKeys      apikeys.Repository
+	Reset        func()
+	Ping         func(ctx context.Context) error
+	Close        func() error
+}
+
+// Connect connects to the database chosen through the DB_BACKEND config, which may be "postgresql"
+// (the default) or "sqlite". When test is true, the test database of that backend is used instead.
+// It panics if the database can not be reached
+func Connect(test bool) Backend {
+	b, err := Open(test)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// ConnectWithRetry is like Connect, but makes up to DB_CONNECT_ATTEMPTS attempts to connect before
+// giving up, so that processes may start before their database does. It waits DB_CONNECT_BACKOFF
+// after the first failed attempt, and twice as long after each one of the following ones. Failed
+// attempts are logged through l
+func ConnectWithRetry(test bool, l logger.Interface) (Backend, error) {
+	log := logger.From(l).Named("backend")
+	attempts := viper.GetInt("DB_CONNECT_ATTEMPTS")
+	backoff := viper.GetDuration("DB_CONNECT_BACKOFF")
+	for attempt := 1; ; attempt++ {
+		b, err := Open(test)
+		if err == nil || attempt >= attempts {
+			return b, err
+		}
+		log.With(
+			logger.KV("attempt", attempt),
+			logger.KV("attempts", attempts),
+			logger.KV("backoff_ms", backoff.Milliseconds()),
+		).Error(err)
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// Open is like Connect, but returns an error instead of panicking. The pool of connections is set
+// up with the DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS and DB_CONN_MAX_LIFETIME configs, each of which is
+// left as the default of database/sql when not positive
+func Open(test bool) (Backend, error) {
+	switch name := viper.GetString("DB_BACKEND"); name {
+	case "", "postgresql":
+		var c *postgresql.ConnectionConfig
+		if test {
+			c = postgresql.DefaultTestConfig()
+		}
+		db, sqlDB, err := postgresql.Open(c)
+		if err != nil {
+			return Backend{}, err
+		}
+		configurePool(sqlDB)
+		return Backend{
+			Factions:     postgresql.NewFactionsRepository(db),
+			Commanders:   postgresql.NewCommandersRepository(db),
+			Battles:      postgresql.NewBattlesRepository(db, numScale()),
+			Translations: postgresql.NewTranslationsRepository(db),
+			APIKeys:      postgresql.NewAPIKeysRepository(db),
+			Reset:        func() { postgresql.Reset(db) },
+			Ping:         sqlDB.PingContext,
+			Close:        sqlDB.Close,
+		}, nil
+	case "sqlite":
+		var path string
+		if test {
+			path = sqlite.DefaultTestPath()
+		}
+		db, sqlDB, err := sqlite.Open(path)
+		if err != nil {
+			return Backend{}, err
+		}
+		configurePool(sqlDB)
+		return Backend{
+			Factions:     sqlite.NewFactionsRepository(db),
+			Commanders:   sqlite.NewCommandersRepository(db),
+			Battles:      sqlite.NewBattlesRepository(db, numScale()),
+			Translations: sqlite.NewTranslationsRepository(db),
+			Reset:        func() { sqlite.Reset(db) },
+			Ping:         sqlDB.PingContext,
+			Close:        sqlDB.Close,
+		}, nil
+	default:
+		return Backend{}, fmt.Errorf("Unknown DB_BACKEND %q, must be postgresql or sqlite", name)
+	}
+}
+
+// numScale decides from the DATES_JDN config how the dates of battles are converted into numbers,
+// which must be done in the same way by every process that stores or compares them
+func numScale() dates.NumScale {
+	return dates.ScaleFor(viper.GetBool("DATES_JDN"))
+}
+
+func configurePool(sqlDB *sql.DB) {
+	if n := viper.GetInt("DB_MAX_OPEN_CONNS"); n > 0 {
+		sqlDB.SetMaxOpenConns(n)
+	}
+	if n := viper.GetInt("DB_MAX_IDLE_CONNS"); n > 0 {
+		sqlDB.SetMaxIdleConns(n)
+	}
+	if d := viper.GetDuration("DB_CONN_MAX_LIFETIME"); d > 0 {
+		sqlDB.SetConnMaxLifetime(d)
+	}
+}
+
+// Memory keeps the given data in memory, seeding and relating it in the same way as databases are,
+// so that it may be served without any external dependencies. Resetting it empties the store
+func Memory(data *seeder.ImportedData, mappings []factions.RelationshipMapping, logger logger.Interface) Backend {
+	s := memory.NewStore()
+	b := Backend{
+		Factions:     memory.NewFactionsRepo(s),
+		Commanders:   memory.NewCommandersRepo(s),
+		Battles:      memory.NewBattlesRepo(s, numScale()),
+		Translations: memory.NewTranslationsRepo(s),
+		Reset:        s.Reset,
+		Ping:         func(context.Context) error { return nil },
+		Close:        func() error { return nil },
+	}
+	seeder.Seed(data, b.Factions, b.Commanders, b.Battles, b.Translations, logger)
+	seeder.Relate(mappings, b.Factions, logger)
+	return b
+}
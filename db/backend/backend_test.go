@@ -0,0 +1,36 @@
+package backend_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sasalatart/batcoms/db/backend"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectWithRetry(t *testing.T) {
+	for key, value := range map[string]interface{}{
+		"DB_BACKEND":          "unknown",
+		"DB_CONNECT_ATTEMPTS": 3,
+		"DB_CONNECT_BACKOFF":  time.Millisecond,
+	} {
+		previous := viper.Get(key)
+		viper.Set(key, value)
+		defer viper.Set(key, previous)
+	}
+
+	errWriter := new(bytes.Buffer)
+	l := logger.NewEncoded(new(bytes.Buffer), errWriter, logger.ConsoleEncoder{}, logger.InfoLevel)
+	_, err := backend.ConnectWithRetry(false, l)
+	assert.EqualError(t, err, `Unknown DB_BACKEND "unknown", must be postgresql or sqlite`)
+
+	lines := strings.Split(strings.TrimSpace(errWriter.String()), "\n")
+	if assert.Len(t, lines, 2, "Should log every failed attempt but the last one") {
+		assert.Contains(t, lines[0], "attempt=1 attempts=3 backoff_ms=1")
+		assert.Contains(t, lines[1], "attempt=2 attempts=3 backoff_ms=2")
+	}
+}
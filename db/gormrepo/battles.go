@@ -0,0 +1,422 @@
+package gormrepo
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/domain/statistics"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// BattlesRepository is the repository that abstracts access to the underlying database operations
+// used to query and mutate data relating to battles. This implementation relies on GORM and also
+// executes validations before interacting with the database
+type BattlesRepository struct {
+	db        *gorm.DB
+	dialect   Dialect
+	validator *validator.Validate
+	scale     dates.NumScale
+}
+
+// NewBattlesRepository returns a pointer to a ready-to-use gormrepo.BattlesRepository, which queries
+// a database of the given dialect, and stores and compares the dates of battles as numbers in the
+// given scale
+func NewBattlesRepository(db *gorm.DB, dialect Dialect, scale dates.NumScale) *BattlesRepository {
+	return &BattlesRepository{db, dialect, validator.New(), scale}
+}
+
+// FindOne finds the first battle in the database that matches the query, together with the related
+// factions and commanders that the query does not omit
+func (r *BattlesRepository) FindOne(ctx context.Context, query battles.FindOneQuery) (battles.Battle, error) {
+	b := new(schema.Battle)
+	where := schema.Battle{URL: query.URL, Name: query.Name}
+	where.ID = query.ID
+	db := preloadRelations(r.db.WithContext(ctx), query.Omit)
+	if err := db.Where(where).First(b).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return battles.Battle{}, domain.ErrNotFound
+	} else if err != nil {
+		return battles.Battle{}, errors.Wrap(err, "Finding a battle")
+	}
+	return deserializeBattle(b)
+}
+
+// Any tells whether at least one battle is stored in the database, without counting nor loading any
+// of them
+func (r *BattlesRepository) Any(ctx context.Context) (bool, error) {
+	var exists bool
+	if err := r.db.WithContext(ctx).Raw("SELECT EXISTS (SELECT 1 FROM battles)").Row().Scan(&exists); err != nil {
+		return false, errors.Wrap(err, "Checking whether any battle exists")
+	}
+	return exists, nil
+}
+
+// FindMany does a paginated search of all battles matching the given query
+func (r *BattlesRepository) FindMany(ctx context.Context, query battles.FindManyQuery, page int) ([]battles.Battle, int, error) {
+	var records int64
+	result := &[]schema.Battle{}
+
+	db, ok, err := r.filter(ctx, query)
+	if err != nil {
+		return []battles.Battle{}, 0, err
+	} else if !ok {
+		return []battles.Battle{}, 1, nil
+	}
+	db = preloadRelations(db, query.Omit)
+
+	if err := db.Count(&records).Error; err != nil {
+		return []battles.Battle{}, 0, err
+	}
+	pages := int((records / perPage) + 1)
+
+	if err := paginate(db.Order("start_date_num ASC"), page, perPage).Find(result).Error; err != nil {
+		return []battles.Battle{}, pages, err
+	}
+
+	battles, err := deserializeBattles(result)
+	return battles, pages, err
+}
+
+// Stream goes through all of the battles matching the given query with a database cursor, loading
+// them together with their relations a chunk at a time
+func (r *BattlesRepository) Stream(ctx context.Context, query battles.FindManyQuery, each func(battles.Battle) error) error {
+	db, ok, err := r.filter(ctx, query)
+	if err != nil || !ok {
+		return err
+	}
+	rows, err := db.Select("battles.id").Order("start_date_num ASC").Rows()
+	if err != nil {
+		return errors.Wrap(err, "Streaming battles")
+	}
+	defer rows.Close()
+
+	var ids []uuid.UUID
+	flush := func() error {
+		chunk := []schema.Battle{}
+		if err := preloadRelations(r.db.WithContext(ctx), query.Omit).Where("id IN ?", ids).Find(&chunk).Error; err != nil {
+			return errors.Wrap(err, "Loading a chunk of streamed battles")
+		}
+		byID := make(map[uuid.UUID]*schema.Battle, len(chunk))
+		for i := range chunk {
+			byID[chunk[i].ID] = &chunk[i]
+		}
+		for _, id := range ids {
+			b, err := deserializeBattle(byID[id])
+			if err != nil {
+				return err
+			}
+			if err := each(b); err != nil {
+				return err
+			}
+		}
+		ids = ids[:0]
+		return nil
+	}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return errors.Wrap(err, "Scanning a streamed battle")
+		}
+		ids = append(ids, id)
+		if len(ids) == streamChunkSize {
+			if err := flush(); err != nil {
+				return err
+			}
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "Streaming battles")
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+	return flush()
+}
+
+// filter returns a query for the battles matching the given one, or false when none of them may
+// match it
+func (r *BattlesRepository) filter(ctx context.Context, query battles.FindManyQuery) (*gorm.DB, bool, error) {
+	var db = r.db.WithContext(ctx).Model(&schema.Battle{})
+	if query.FactionID != uuid.Nil && query.IncludeRelated {
+		db = db.Where("battles.id IN (SELECT battle_id FROM battle_factions WHERE faction_id IN ("+relatedFactionsSQL(r.dialect)+"))", query.FactionID)
+	} else if query.FactionID != uuid.Nil {
+		db = db.Joins("JOIN battle_factions bf ON bf.battle_id = battles.id").
+			Where("bf.faction_id = ?", query.FactionID)
+	}
+	if query.CommanderID != uuid.Nil {
+		db = db.Joins("JOIN battle_commanders bc ON bc.battle_id = battles.id").
+			Where("bc.commander_id = ?", query.CommanderID)
+	}
+	if !query.FromDate.IsZero() {
+		db = db.Where("start_date_num >= ?", r.scale.Num(query.FromDate))
+	}
+	if !query.ToDate.IsZero() {
+		db = db.Where("end_date_num <= ?", r.scale.Num(query.ToDate))
+	}
+	db = r.dialect.Match(db, "battles", "name", query.Name)
+	db = r.dialect.Match(db, "battles", "summary", query.Summary)
+	db = r.dialect.Match(db, "battles", "place", query.Place)
+	db = r.dialect.Match(db, "battles", "result", query.Result)
+	if query.ConcurrentWith != uuid.Nil {
+		ref := new(schema.Battle)
+		if err := r.db.WithContext(ctx).Where("id = ?", query.ConcurrentWith).Take(ref).Error; err != nil {
+			return nil, false, errors.Wrap(err, "Finding the battle to compare dates with")
+		}
+		db = db.Where("battles.id <> ?", ref.ID).
+			Where("start_date_num <= ? AND end_date_num >= ?", ref.EndDateNum, ref.StartDateNum)
+		if query.WithinKm > 0 {
+			if ref.LatitudeNum == nil || ref.LongitudeNum == nil {
+				return nil, false, nil
+			}
+			db = r.dialect.WithinKm(db, *ref.LatitudeNum, *ref.LongitudeNum, query.WithinKm)
+		}
+	}
+	if query.RelatedTo != uuid.Nil {
+		db = db.Where("battles.id <> ?", query.RelatedTo).Where(relatedBattlesSQL, query.RelatedTo, query.RelatedTo, query.RelatedTo)
+	}
+	return db, true, nil
+}
+
+// CreateOne creates a battle in the database, together with entries in the corresponding tables
+// that let us relate the battle with other factions and commanders. The operation returns the ID of
+// the new battle
+func (r *BattlesRepository) CreateOne(data battles.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating battle creation input")
+	}
+	b, err := serializeBattle(r.scale, battles.Battle{
+		WikiID:             data.WikiID,
+		URL:                data.URL,
+		Name:               data.Name,
+		PartOf:             data.PartOf,
+		Summary:            data.Summary,
+		StartDate:          data.StartDate,
+		EndDate:            data.EndDate,
+		Location:           data.Location,
+		Result:             data.Result,
+		TerritorialChanges: data.TerritorialChanges,
+		Strength:           data.Strength,
+		Casualties:         data.Casualties,
+		Wikidata:           data.Wikidata,
+	})
+	if err != nil {
+		return uuid.Nil, errors.Wrap(err, "Serializing battles.CreationInput")
+	}
+	addBattleFactions := func(fIDs []uuid.UUID, side schema.SideKind) {
+		for _, fID := range fIDs {
+			b.BattleFactions = append(b.BattleFactions, schema.BattleFaction{
+				FactionID: fID,
+				Side:      side,
+				Flag:      data.FlagsByFaction[fID],
+			})
+		}
+	}
+	addBattleFactions(data.FactionsBySide.A, schema.SideA)
+	addBattleFactions(data.FactionsBySide.B, schema.SideB)
+	addBattleCommanders := func(cIDs []uuid.UUID, side schema.SideKind) {
+		for _, cID := range cIDs {
+			b.BattleCommanders = append(b.BattleCommanders, schema.BattleCommander{CommanderID: cID, Side: side})
+		}
+	}
+	addBattleCommanders(data.CommandersBySide.A, schema.SideA)
+	addBattleCommanders(data.CommandersBySide.B, schema.SideB)
+	for fID, cIDS := range data.CommandersByFaction {
+		for _, cID := range cIDS {
+			b.BattleCommanderFactions = append(b.BattleCommanderFactions, schema.BattleCommanderFaction{
+				FactionID:   fID,
+				CommanderID: cID,
+				Confidence:  string(data.CommanderConfidences[cID]),
+			})
+		}
+	}
+	if err := r.db.Create(b).Error; err != nil {
+		return uuid.Nil, errors.Wrap(err, "Creating the battle")
+	}
+	return b.ID, nil
+}
+
+// relatedBattlesSQL matches battles that share at least one faction or commander with the battle
+// supplied as argument (three times), or that were part of the same conflict as it
+const relatedBattlesSQL = `(
+	battles.id IN (
+		SELECT rbf.battle_id FROM battle_factions rbf
+		WHERE rbf.faction_id IN (SELECT faction_id FROM battle_factions WHERE battle_id = ?)
+	) OR battles.id IN (
+		SELECT rbc.battle_id FROM battle_commanders rbc
+		WHERE rbc.commander_id IN (SELECT commander_id FROM battle_commanders WHERE battle_id = ?)
+	) OR (
+		battles.part_of <> '' AND battles.part_of = (SELECT part_of FROM battles WHERE id = ?)
+	)
+)`
+
+func serializeBattle(scale dates.NumScale, b battles.Battle) (*schema.Battle, error) {
+	strength, err := json.Marshal(b.Strength)
+	if err != nil {
+		return nil, errors.Wrap(err, "Stringifying strength")
+	}
+	casualties, err := json.Marshal(b.Casualties)
+	if err != nil {
+		return nil, errors.Wrap(err, "Stringifying casualties")
+	}
+	startDateDetails, err := json.Marshal(b.StartDate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Stringifying startDate")
+	}
+	endDateDetails, err := json.Marshal(b.EndDate)
+	if err != nil {
+		return nil, errors.Wrap(err, "Stringifying endDate")
+	}
+	res := &schema.Battle{
+		WikiID:             b.WikiID,
+		URL:                b.URL,
+		Name:               b.Name,
+		PartOf:             b.PartOf,
+		Summary:            b.Summary,
+		StartDate:          b.StartDate.String(),
+		StartDateNum:       scale.Num(b.StartDate),
+		StartDateDetails:   datatypes.JSON(startDateDetails),
+		EndDate:            b.EndDate.String(),
+		EndDateNum:         scale.Num(b.EndDate),
+		EndDateDetails:     datatypes.JSON(endDateDetails),
+		Place:              b.Location.Place,
+		Latitude:           b.Location.Latitude,
+		Longitude:          b.Location.Longitude,
+		Result:             b.Result,
+		TerritorialChanges: b.TerritorialChanges,
+		Strength:           datatypes.JSON(strength),
+		Casualties:         datatypes.JSON(casualties),
+		Wikidata:           wikidataToJSON(b.Wikidata),
+	}
+	if lat, lon, ok := b.Location.Coordinates(); ok {
+		res.LatitudeNum = &lat
+		res.LongitudeNum = &lon
+	}
+	return res, nil
+}
+
+// preloadRelations preloads the factions and commanders of battles, except for those omitted. How
+// commanders were assigned to factions is only preloaded together with both
+func preloadRelations(db *gorm.DB, omit battles.Relations) *gorm.DB {
+	if !omit.Factions {
+		db = db.Preload("BattleFactions.Faction")
+	}
+	if !omit.Commanders {
+		db = db.Preload("BattleCommanders.Commander")
+	}
+	if !omit.Factions && !omit.Commanders {
+		db = db.Preload("BattleCommanderFactions")
+	}
+	return db
+}
+
+func deserializeBattle(b *schema.Battle) (battles.Battle, error) {
+	if b == nil {
+		return battles.Battle{}, errors.New("Empty battle to deserialize")
+	}
+	strength := statistics.SideNumbers{}
+	if err := fromJSON(b.Strength, &strength); err != nil {
+		return battles.Battle{}, errors.Wrapf(err, "Deserializing strength")
+	}
+	casualties := statistics.SideNumbers{}
+	if err := fromJSON(b.Casualties, &casualties); err != nil {
+		return battles.Battle{}, errors.Wrapf(err, "Deserializing casualties")
+	}
+	startDate, err := deserializeDate(b.StartDate, b.StartDateDetails)
+	if err != nil {
+		return battles.Battle{}, errors.Wrapf(err, "Deserializing startDate")
+	}
+	endDate, err := deserializeDate(b.EndDate, b.EndDateDetails)
+	if err != nil {
+		return battles.Battle{}, errors.Wrapf(err, "Deserializing endDate")
+	}
+	commanders := battles.CommandersBySide{}
+	for _, bc := range b.BattleCommanders {
+		commander := deserializeCommander(&bc.Commander)
+		if bc.Side == schema.SideA {
+			commanders.A = append(commanders.A, commander)
+		} else {
+			commanders.B = append(commanders.B, commander)
+		}
+	}
+	factions := battles.FactionsBySide{}
+	for _, bf := range b.BattleFactions {
+		faction := deserializeFaction(&bf.Faction)
+		if bf.Flag != "" {
+			faction.Flag = bf.Flag
+		}
+		if bf.Side == schema.SideA {
+			factions.A = append(factions.A, faction)
+		} else {
+			factions.B = append(factions.B, faction)
+		}
+	}
+	commandersByFaction := make(battles.CommandersByFaction)
+	var commanderConfidences map[uuid.UUID]battles.Confidence
+	for _, bcf := range b.BattleCommanderFactions {
+		commandersByFaction[bcf.FactionID] = append(commandersByFaction[bcf.FactionID], bcf.CommanderID)
+		if bcf.Confidence != "" {
+			if commanderConfidences == nil {
+				commanderConfidences = make(map[uuid.UUID]battles.Confidence)
+			}
+			commanderConfidences[bcf.CommanderID] = battles.Confidence(bcf.Confidence)
+		}
+	}
+	res := battles.Battle{
+		ID:        b.ID,
+		WikiID:    b.WikiID,
+		URL:       b.URL,
+		Name:      b.Name,
+		PartOf:    b.PartOf,
+		Summary:   b.Summary,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Location: locations.Location{
+			Place:     b.Place,
+			Latitude:  b.Latitude,
+			Longitude: b.Longitude,
+		},
+		Result:               b.Result,
+		TerritorialChanges:   b.TerritorialChanges,
+		Strength:             strength,
+		Casualties:           casualties,
+		Factions:             factions,
+		Commanders:           commanders,
+		CommandersByFaction:  commandersByFaction,
+		CommanderConfidences: commanderConfidences,
+		Wikidata:             wikidataFromJSON(b.Wikidata),
+	}
+	return res, nil
+}
+
+// deserializeDate prefers the details of a date, which keep its precision, approximation, calendar
+// and uncertainty. Rows stored before those details existed only have the plain date
+func deserializeDate(date string, details datatypes.JSON) (dates.Historic, error) {
+	if len(details) == 0 {
+		return dates.New(date)
+	}
+	h := dates.Historic{}
+	if err := fromJSON(details, &h); err != nil {
+		return dates.Historic{}, err
+	}
+	return h, nil
+}
+
+func deserializeBattles(bb *[]schema.Battle) ([]battles.Battle, error) {
+	results := []battles.Battle{}
+	for _, b := range *bb {
+		battle, err := deserializeBattle(&b)
+		if err != nil {
+			return results, err
+		}
+		results = append(results, battle)
+	}
+	return results, nil
+}
@@ -0,0 +1,228 @@
+package gormrepo
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// CommandersRepository is the repository that abstracts access to the underlying database operations
+// used to query and mutate data relating to commanders. This implementation relies on GORM and also
+// executes validations before interacting with the database
+type CommandersRepository struct {
+	db        *gorm.DB
+	dialect   Dialect
+	validator *validator.Validate
+}
+
+// NewCommandersRepository returns a pointer to a ready-to-use gormrepo.CommandersRepository, which
+// queries a database of the given dialect
+func NewCommandersRepository(db *gorm.DB, dialect Dialect) *CommandersRepository {
+	return &CommandersRepository{db, dialect, validator.New()}
+}
+
+// FindOne finds the first commander in the database that matches the query. Commanders searched
+// only by WikiID or URL are also looked up among the aliases of those that were merged
+func (r *CommandersRepository) FindOne(ctx context.Context, query commanders.FindOneQuery) (commanders.Commander, error) {
+	db := r.db.WithContext(ctx)
+	c := &schema.Commander{}
+	err := db.Where(query).First(c).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && query.ID == uuid.Nil && query.Name == "" {
+		aliases := db.
+			Model(&schema.CommanderAlias{}).
+			Select("commander_id").
+			Where(schema.CommanderAlias{WikiID: query.WikiID, URL: query.URL})
+		err = db.Where("id IN (?)", aliases).First(c).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return commanders.Commander{}, domain.ErrNotFound
+	} else if err != nil {
+		return commanders.Commander{}, errors.Wrap(err, "Executing CommandersRepository.FindOne")
+	}
+	return deserializeCommander(c), nil
+}
+
+// FindMany does a paginated search of all commanders matching the given query
+func (r *CommandersRepository) FindMany(ctx context.Context, query commanders.FindManyQuery, page int) ([]commanders.Commander, int, error) {
+	var records int64
+	result := &[]schema.Commander{}
+
+	db, err := r.filter(ctx, query)
+	if err != nil {
+		return []commanders.Commander{}, 0, err
+	}
+
+	if err := db.Count(&records).Error; err != nil {
+		return []commanders.Commander{}, 0, err
+	}
+	pages := int((records / perPage) + 1)
+
+	if err := paginate(db.Order("name DESC"), page, perPage).Find(result).Error; err != nil {
+		return []commanders.Commander{}, pages, err
+	}
+
+	return deserializeCommanders(result), pages, nil
+}
+
+// Stream goes through all of the commanders matching the given query with a database cursor
+func (r *CommandersRepository) Stream(ctx context.Context, query commanders.FindManyQuery, each func(commanders.Commander) error) error {
+	db, err := r.filter(ctx, query)
+	if err != nil {
+		return err
+	}
+	rows, err := db.Order("name DESC").Rows()
+	if err != nil {
+		return errors.Wrap(err, "Streaming commanders")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &schema.Commander{}
+		if err := r.db.ScanRows(rows, record); err != nil {
+			return errors.Wrap(err, "Scanning a streamed commander")
+		}
+		if err := each(deserializeCommander(record)); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "Streaming commanders")
+	}
+	return nil
+}
+
+// filter returns a query for the commanders matching the given one
+func (r *CommandersRepository) filter(ctx context.Context, query commanders.FindManyQuery) (*gorm.DB, error) {
+	var db = r.db.WithContext(ctx).Model(&schema.Commander{})
+	if query.FactionID != uuid.Nil {
+		var cIDs []uuid.UUID
+		bcf := r.db.WithContext(ctx).Model(&schema.BattleCommanderFaction{})
+		if query.IncludeRelated {
+			bcf = bcf.Where("faction_id IN ("+relatedFactionsSQL(r.dialect)+")", query.FactionID)
+		} else {
+			bcf = bcf.Where(schema.BattleCommanderFaction{FactionID: query.FactionID})
+		}
+		err := bcf.Pluck("commander_id", &cIDs).Error
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("id IN ?", cIDs)
+	}
+	db = r.dialect.Match(db, "commanders", "name", query.Name)
+	db = r.dialect.Match(db, "commanders", "summary", query.Summary)
+	return db, nil
+}
+
+// CreateOne creates a commander in the database. The operation returns the ID of the new commander
+func (r *CommandersRepository) CreateOne(data commanders.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating commander creation input")
+	}
+	c := serializeCommander(commanders.Commander{
+		WikiID:      data.WikiID,
+		URL:         data.URL,
+		Name:        data.Name,
+		Summary:     data.Summary,
+		BirthDate:   data.BirthDate,
+		DeathDate:   data.DeathDate,
+		Allegiances: data.Allegiances,
+		Wikidata:    data.Wikidata,
+	})
+	if err := r.db.Create(c).Error; err != nil {
+		return uuid.Nil, errors.Wrap(err, "Creating a commander")
+	}
+	return c.ID, nil
+}
+
+// CreateAlias keeps the WikiID and URL of a duplicate commander as aliases of the one it was merged
+// into
+func (r *CommandersRepository) CreateAlias(data commanders.AliasCreationInput) error {
+	if err := r.validator.Struct(data); err != nil {
+		return errors.Wrap(err, "Validating commander alias creation input")
+	}
+	ca := &schema.CommanderAlias{
+		WikiID:      data.WikiID,
+		URL:         data.URL,
+		CommanderID: data.CommanderID,
+	}
+	if err := r.db.Create(ca).Error; err != nil {
+		return errors.Wrap(err, "Creating a commander alias")
+	}
+	return nil
+}
+
+func serializeCommander(c commanders.Commander) *schema.Commander {
+	return &schema.Commander{
+		WikiID:    c.WikiID,
+		URL:       c.URL,
+		Name:      c.Name,
+		Summary:   c.Summary,
+		Biography: biographyToJSON(c),
+		Wikidata:  wikidataToJSON(c.Wikidata),
+	}
+}
+
+func deserializeCommander(c *schema.Commander) commanders.Commander {
+	b := biographyFromJSON(c.Biography)
+	return commanders.Commander{
+		ID:          c.ID,
+		WikiID:      c.WikiID,
+		URL:         c.URL,
+		Name:        c.Name,
+		Summary:     c.Summary,
+		BirthDate:   b.BirthDate,
+		DeathDate:   b.DeathDate,
+		Allegiances: b.Allegiances,
+		Wikidata:    wikidataFromJSON(c.Wikidata),
+	}
+}
+
+// biography is how the details of the life of a commander are stored
+type biography struct {
+	BirthDate   *dates.Historic         `json:"birthDate,omitempty"`
+	DeathDate   *dates.Historic         `json:"deathDate,omitempty"`
+	Allegiances []commanders.Allegiance `json:"allegiances,omitempty"`
+}
+
+// biographyToJSON stores the details of the life of a commander as JSON, or as NULL if none of
+// them are known
+func biographyToJSON(c commanders.Commander) datatypes.JSON {
+	if c.BirthDate == nil && c.DeathDate == nil && len(c.Allegiances) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(biography{c.BirthDate, c.DeathDate, c.Allegiances})
+	if err != nil {
+		return nil
+	}
+	return datatypes.JSON(data)
+}
+
+// biographyFromJSON reads the details of the life of a commander, leaving them empty if none are
+// known
+func biographyFromJSON(data datatypes.JSON) biography {
+	var res biography
+	if len(data) == 0 {
+		return res
+	}
+	if err := fromJSON(data, &res); err != nil {
+		return biography{}
+	}
+	return res
+}
+
+func deserializeCommanders(cc *[]schema.Commander) []commanders.Commander {
+	results := []commanders.Commander{}
+	for _, c := range *cc {
+		results = append(results, deserializeCommander(&c))
+	}
+	return results
+}
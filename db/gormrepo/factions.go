@@ -0,0 +1,219 @@
+package gormrepo
+
+import (
+	"context"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/factions"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// FactionsRepository is the repository that abstracts access to the underlying database operations
+// used to query and mutate data relating to factions. This implementation relies on GORM and also
+// executes validations before interacting with the database
+type FactionsRepository struct {
+	db        *gorm.DB
+	dialect   Dialect
+	validator *validator.Validate
+}
+
+// NewFactionsRepository returns a pointer to a ready-to-use gormrepo.FactionsRepository, which
+// queries a database of the given dialect
+func NewFactionsRepository(db *gorm.DB, dialect Dialect) *FactionsRepository {
+	return &FactionsRepository{db, dialect, validator.New()}
+}
+
+// FindOne finds the first faction in the database that matches the query. Factions searched only by
+// WikiID or URL are also looked up among the aliases of those that were merged
+func (r *FactionsRepository) FindOne(ctx context.Context, query factions.FindOneQuery) (factions.Faction, error) {
+	db := r.db.WithContext(ctx)
+	f := &schema.Faction{}
+	err := db.Where(query).First(f).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) && query.ID == uuid.Nil && query.Name == "" {
+		aliases := db.
+			Model(&schema.FactionAlias{}).
+			Select("faction_id").
+			Where(schema.FactionAlias{WikiID: query.WikiID, URL: query.URL})
+		err = db.Where("id IN (?)", aliases).First(f).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return factions.Faction{}, domain.ErrNotFound
+	} else if err != nil {
+		return factions.Faction{}, errors.Wrap(err, "Executing FactionsRepository.FindOne")
+	}
+	return deserializeFaction(f), nil
+}
+
+// FindMany does a paginated search of all factions matching the given query
+func (r *FactionsRepository) FindMany(ctx context.Context, query factions.FindManyQuery, page int) ([]factions.Faction, int, error) {
+	var records int64
+	result := &[]schema.Faction{}
+
+	db, err := r.filter(ctx, query)
+	if err != nil {
+		return []factions.Faction{}, 0, err
+	}
+
+	if err := db.Count(&records).Error; err != nil {
+		return []factions.Faction{}, 0, err
+	}
+	pages := int((records / perPage) + 1)
+
+	if err := paginate(db.Order("name DESC"), page, perPage).Find(result).Error; err != nil {
+		return []factions.Faction{}, pages, err
+	}
+
+	return deserializeFactions(result), pages, nil
+}
+
+// Stream goes through all of the factions matching the given query with a database cursor
+func (r *FactionsRepository) Stream(ctx context.Context, query factions.FindManyQuery, each func(factions.Faction) error) error {
+	db, err := r.filter(ctx, query)
+	if err != nil {
+		return err
+	}
+	rows, err := db.Order("name DESC").Rows()
+	if err != nil {
+		return errors.Wrap(err, "Streaming factions")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		record := &schema.Faction{}
+		if err := r.db.ScanRows(rows, record); err != nil {
+			return errors.Wrap(err, "Scanning a streamed faction")
+		}
+		if err := each(deserializeFaction(record)); err != nil {
+			return err
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return errors.Wrap(err, "Streaming factions")
+	}
+	return nil
+}
+
+// filter returns a query for the factions matching the given one
+func (r *FactionsRepository) filter(ctx context.Context, query factions.FindManyQuery) (*gorm.DB, error) {
+	var db = r.db.WithContext(ctx).Model(&schema.Faction{})
+	if query.CommanderID != uuid.Nil {
+		var fIDs []uuid.UUID
+		err := r.db.WithContext(ctx).
+			Model(&schema.BattleCommanderFaction{}).
+			Where(schema.BattleCommanderFaction{CommanderID: query.CommanderID}).
+			Pluck("faction_id", &fIDs).
+			Error
+		if err != nil {
+			return nil, err
+		}
+		db = db.Where("id IN ?", fIDs)
+	}
+	db = r.dialect.Match(db, "factions", "name", query.Name)
+	db = r.dialect.Match(db, "factions", "summary", query.Summary)
+	return db, nil
+}
+
+// CreateOne creates a faction in the database. The operation returns the ID of the new faction
+func (r *FactionsRepository) CreateOne(data factions.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating faction creation input")
+	}
+	f := serializeFaction(factions.Faction{
+		WikiID:   data.WikiID,
+		URL:      data.URL,
+		Name:     data.Name,
+		Summary:  data.Summary,
+		Flag:     data.Flag,
+		Wikidata: data.Wikidata,
+	})
+	if err := r.db.Create(f).Error; err != nil {
+		return uuid.Nil, errors.Wrap(err, "Creating a faction")
+	}
+	return f.ID, nil
+}
+
+// CreateRelationship stores how a faction is related to another one
+func (r *FactionsRepository) CreateRelationship(data factions.RelationshipCreationInput) error {
+	if err := r.validator.Struct(data); err != nil {
+		return errors.Wrap(err, "Validating faction relationship creation input")
+	}
+	fr := &schema.FactionRelationship{
+		FactionID: data.FactionID,
+		RelatedID: data.RelatedID,
+		Kind:      string(data.Kind),
+	}
+	if err := r.db.Create(fr).Error; err != nil {
+		return errors.Wrap(err, "Creating a faction relationship")
+	}
+	return nil
+}
+
+// CreateAlias keeps the WikiID and URL of a duplicate faction as aliases of the one it was merged
+// into
+func (r *FactionsRepository) CreateAlias(data factions.AliasCreationInput) error {
+	if err := r.validator.Struct(data); err != nil {
+		return errors.Wrap(err, "Validating faction alias creation input")
+	}
+	fa := &schema.FactionAlias{
+		WikiID:    data.WikiID,
+		URL:       data.URL,
+		FactionID: data.FactionID,
+	}
+	if err := r.db.Create(fa).Error; err != nil {
+		return errors.Wrap(err, "Creating a faction alias")
+	}
+	return nil
+}
+
+// relatedFactionsSQL returns the SQL that selects the ID of a faction together with those of all the
+// factions related to it in a database of the given dialect, following relationships recursively:
+// its children, its members (when it is a coalition), and both its predecessors and successors.
+// Parents and coalitions are not followed upwards, as they would include factions that are not part
+// of the given one
+func relatedFactionsSQL(d Dialect) string {
+	return `
+	WITH RECURSIVE related(id) AS (
+		SELECT ` + d.IDParam + `
+		UNION
+		SELECT CASE WHEN fr.related_id = r.id THEN fr.faction_id ELSE fr.related_id END
+		FROM faction_relationships fr
+		JOIN related r ON fr.related_id = r.id OR (fr.kind = 'predecessor' AND fr.faction_id = r.id)
+	)
+	SELECT id FROM related
+`
+}
+
+func serializeFaction(f factions.Faction) *schema.Faction {
+	return &schema.Faction{
+		WikiID:   f.WikiID,
+		URL:      f.URL,
+		Name:     f.Name,
+		Summary:  f.Summary,
+		Flag:     f.Flag,
+		Wikidata: wikidataToJSON(f.Wikidata),
+	}
+}
+
+func deserializeFaction(f *schema.Faction) factions.Faction {
+	return factions.Faction{
+		ID:       f.ID,
+		WikiID:   f.WikiID,
+		URL:      f.URL,
+		Name:     f.Name,
+		Summary:  f.Summary,
+		Flag:     f.Flag,
+		Wikidata: wikidataFromJSON(f.Wikidata),
+	}
+}
+
+func deserializeFactions(ff *[]schema.Faction) []factions.Faction {
+	results := []factions.Faction{}
+	for _, f := range *ff {
+		results = append(results, deserializeFaction(&f))
+	}
+	return results
+}
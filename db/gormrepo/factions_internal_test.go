@@ -0,0 +1,84 @@
+package gormrepo
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+func TestRelatedFactionsSQL(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "batcoms_test.db")), &gorm.Config{})
+	require.NoError(t, err)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	defer sqlDB.Close()
+	require.NoError(t, db.AutoMigrate(&schema.Faction{}, &schema.FactionRelationship{}))
+	dialect := Dialect{IDParam: "?"}
+	fs := NewFactionsRepository(db, dialect)
+
+	names := []string{"Kingdom", "Republic", "Empire", "Confederation", "Britain", "Coalition", "Unrelated"}
+	ids := make(map[string]uuid.UUID)
+	for i, name := range names {
+		input := mocks.FactionCreationInput()
+		input.WikiID = i + 1
+		input.URL = "https://en.wikipedia.org/wiki/" + name
+		input.Name = name
+		id, err := fs.CreateOne(input)
+		require.NoError(t, err, "Creating %s", name)
+		ids[name] = id
+	}
+	relationships := []struct {
+		faction string
+		kind    factions.RelationshipKind
+		related string
+	}{
+		{"Republic", factions.PredecessorKind, "Kingdom"},
+		{"Empire", factions.PredecessorKind, "Republic"},
+		{"Confederation", factions.ParentKind, "Empire"},
+		{"Britain", factions.CoalitionKind, "Coalition"},
+	}
+	for _, r := range relationships {
+		err := fs.CreateRelationship(factions.RelationshipCreationInput{
+			FactionID: ids[r.faction],
+			RelatedID: ids[r.related],
+			Kind:      r.kind,
+		})
+		require.NoError(t, err, "Relating %s to %s", r.faction, r.related)
+	}
+
+	cases := []struct {
+		faction  string
+		expected []string
+	}{
+		{"Kingdom", []string{"Kingdom", "Republic", "Empire", "Confederation"}},
+		{"Empire", []string{"Kingdom", "Republic", "Empire", "Confederation"}},
+		{"Confederation", []string{"Confederation"}},
+		{"Coalition", []string{"Britain", "Coalition"}},
+		{"Britain", []string{"Britain"}},
+		{"Unrelated", []string{"Unrelated"}},
+	}
+	for _, c := range cases {
+		rows, err := db.Raw(relatedFactionsSQL(dialect), ids[c.faction]).Rows()
+		require.NoError(t, err, "Relating %s", c.faction)
+		var found []uuid.UUID
+		for rows.Next() {
+			var id uuid.UUID
+			require.NoError(t, rows.Scan(&id), "Scanning the factions related to %s", c.faction)
+			found = append(found, id)
+		}
+		require.NoError(t, rows.Close())
+		var expected []uuid.UUID
+		for _, name := range c.expected {
+			expected = append(expected, ids[name])
+		}
+		assert.ElementsMatch(t, expected, found, "Factions related to %s", c.faction)
+	}
+}
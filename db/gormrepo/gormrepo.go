@@ -0,0 +1,68 @@
+package gormrepo
+
+import (
+	"encoding/json"
+
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Dialect contains what the repositories need to know about the SQL dialect of a database, beyond
+// what GORM already abstracts
+type Dialect struct {
+	// Match restricts the query to the rows of the table whose column contains the given value as a
+	// phrase, by using the full-text search of the database. Empty values do not restrict it
+	Match func(db *gorm.DB, table, column, value string) *gorm.DB
+	// WithinKm restricts the query to the battles that took place at most km kilometres away from the
+	// given coordinates. Battles without coordinates are never within any distance
+	WithinKm func(db *gorm.DB, latitude, longitude, km float64) *gorm.DB
+	// IDParam is how a UUID supplied as argument is written in raw SQL, such as "CAST(? AS uuid)" for
+	// databases that would otherwise not know its type
+	IDParam string
+}
+
+func fromJSON(data datatypes.JSON, storeTo interface{}) error {
+	parsed, err := data.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(parsed, storeTo); err != nil {
+		return err
+	}
+	return nil
+}
+
+// wikidataToJSON stores the Wikidata facts of an entity as JSON, or as NULL if it has none
+func wikidataToJSON(facts *wikidata.Facts) datatypes.JSON {
+	if facts == nil {
+		return nil
+	}
+	data, err := json.Marshal(facts)
+	if err != nil {
+		return nil
+	}
+	return datatypes.JSON(data)
+}
+
+// wikidataFromJSON reads the Wikidata facts of an entity, returning nil if it has none
+func wikidataFromJSON(data datatypes.JSON) *wikidata.Facts {
+	if len(data) == 0 {
+		return nil
+	}
+	facts := new(wikidata.Facts)
+	if err := fromJSON(data, facts); err != nil {
+		return nil
+	}
+	return facts
+}
+
+func paginate(db *gorm.DB, page, perPage int) *gorm.DB {
+	return db.Offset((page - 1) * perPage).Limit(perPage)
+}
+
+const perPage = 50
+
+// streamChunkSize is how many battles are loaded together with their relations at a time while
+// streaming them, which is as many as in a page
+const streamChunkSize = perPage
@@ -0,0 +1,21 @@
+package schema
+
+import uuid "github.com/satori/go.uuid"
+
+// FactionAlias is used to store the WikiID and URL of a duplicate faction that has been merged into
+// another one, so that they still resolve to it. This struct defines the SQL schema
+type FactionAlias struct {
+	WikiID    int       `gorm:"primaryKey;autoIncrement:false"`
+	URL       string    `gorm:"not null;uniqueIndex"`
+	FactionID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Faction   Faction
+}
+
+// CommanderAlias is used to store the WikiID and URL of a duplicate commander that has been merged
+// into another one, so that they still resolve to it. This struct defines the SQL schema
+type CommanderAlias struct {
+	WikiID      int       `gorm:"primaryKey;autoIncrement:false"`
+	URL         string    `gorm:"not null;uniqueIndex"`
+	CommanderID uuid.UUID `gorm:"type:uuid;not null;index"`
+	Commander   Commander
+}
@@ -0,0 +1,15 @@
+package schema
+
+import "time"
+
+// APIKey is used to store the hash of a key issued to a client of the API, together with its rate
+// limits. This struct defines the SQL schema
+type APIKey struct {
+	Base
+	Name              string `gorm:"not null"`
+	Hash              string `gorm:"not null;uniqueIndex"`
+	RequestsPerMinute int    `gorm:"not null"`
+	Burst             int    `gorm:"not null"`
+	CreatedAt         time.Time
+	RevokedAt         *time.Time
+}
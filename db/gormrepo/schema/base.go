@@ -0,0 +1,20 @@
+package schema
+
+import (
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// Base contains common columns for all tables.
+type Base struct {
+	ID uuid.UUID `gorm:"type:uuid;default:(uuid_generate_v4())"`
+}
+
+// BeforeCreate generates the ID of new rows stored in SQLite, which has no built-in function to
+// generate UUIDs, and from which GORM can not read back the IDs generated by the database either
+func (b *Base) BeforeCreate(tx *gorm.DB) error {
+	if b.ID == uuid.Nil && tx.Dialector.Name() == "sqlite" {
+		b.ID = uuid.NewV4()
+	}
+	return nil
+}
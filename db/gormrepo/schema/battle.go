@@ -12,15 +12,20 @@ type Battle struct {
 	Summary                 string  `gorm:"not null"`
 	StartDate               string  `gorm:"not null;index"`
 	StartDateNum            float64 `gorm:"not null;index"`
+	StartDateDetails        datatypes.JSON
 	EndDate                 string  `gorm:"not null;index"`
 	EndDateNum              float64 `gorm:"not null;index"`
-	Place                   string  `gorm:"not null"`
+	EndDateDetails          datatypes.JSON
+	Place                   string `gorm:"not null"`
 	Latitude                string
 	Longitude               string
+	LatitudeNum             *float64
+	LongitudeNum            *float64
 	Result                  string `gorm:"not null"`
 	TerritorialChanges      string
 	Strength                datatypes.JSON
 	Casualties              datatypes.JSON
+	Wikidata                datatypes.JSON
 	BattleCommanders        []BattleCommander
 	BattleFactions          []BattleFaction
 	BattleCommanderFactions []BattleCommanderFaction
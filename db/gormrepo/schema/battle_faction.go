@@ -2,12 +2,14 @@ package schema
 
 import uuid "github.com/satori/go.uuid"
 
-// BattleFaction is used to store which factions were part of a battle, and in which side they
-// fought. This struct defines the SQL schema
+// BattleFaction is used to store which factions were part of a battle, in which side they fought,
+// and the flag under which they did so, as it may differ from their current one. This struct
+// defines the SQL schema
 type BattleFaction struct {
 	BattleID  uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
 	FactionID uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
 	Side      SideKind  `gorm:"type:integer;primaryKey;not null"`
+	Flag      string
 	Battle    Battle
 	Faction   Faction
 }
@@ -0,0 +1,14 @@
+package schema
+
+import uuid "github.com/satori/go.uuid"
+
+// FactionRelationship is used to store how factions are related to each other, such as a faction
+// being the predecessor of another one. These relationships are maintained by hand, and are not
+// part of the scraped data. This struct defines the SQL schema
+type FactionRelationship struct {
+	FactionID uuid.UUID `gorm:"type:uuid;primaryKey;not null"`
+	RelatedID uuid.UUID `gorm:"type:uuid;primaryKey;not null;index"`
+	Kind      string    `gorm:"primaryKey;not null"`
+	Faction   Faction
+	Related   Faction `gorm:"foreignKey:RelatedID"`
+}
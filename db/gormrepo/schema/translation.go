@@ -0,0 +1,13 @@
+package schema
+
+import uuid "github.com/satori/go.uuid"
+
+// Translation is used to store the name and summary of a faction, commander or battle in a specific
+// language. This struct defines the SQL schema
+type Translation struct {
+	Base
+	EntityID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_translations_entity_language"`
+	Language string    `gorm:"not null;uniqueIndex:idx_translations_entity_language"`
+	Name     string    `gorm:"not null"`
+	Summary  string    `gorm:"not null"`
+}
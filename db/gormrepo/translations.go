@@ -0,0 +1,73 @@
+package gormrepo
+
+import (
+	"context"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/domain/translations"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// TranslationsRepository is the repository that abstracts access to the underlying database
+// operations used to query and mutate the translations of factions, commanders and battles. This
+// implementation relies on GORM and also executes validations before interacting with the database
+type TranslationsRepository struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+// NewTranslationsRepository returns a pointer to a ready-to-use gormrepo.TranslationsRepository
+func NewTranslationsRepository(db *gorm.DB) *TranslationsRepository {
+	return &TranslationsRepository{db, validator.New()}
+}
+
+// FindMany finds the translations into the language of the query of all the entities with the given
+// IDs. Entities without such a translation are simply not included in the results
+func (r *TranslationsRepository) FindMany(ctx context.Context, query translations.FindManyQuery) ([]translations.Translation, error) {
+	if len(query.EntityIDs) == 0 {
+		return []translations.Translation{}, nil
+	}
+	result := &[]schema.Translation{}
+	err := r.db.WithContext(ctx).
+		Where("language = ? AND entity_id IN ?", query.Language, query.EntityIDs).
+		Find(result).
+		Error
+	if err != nil {
+		return []translations.Translation{}, errors.Wrap(err, "Executing TranslationsRepository.FindMany")
+	}
+	return deserializeTranslations(result), nil
+}
+
+// CreateOne creates a translation in the database. The operation returns the ID of the new
+// translation
+func (r *TranslationsRepository) CreateOne(data translations.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating translation creation input")
+	}
+	t := &schema.Translation{
+		EntityID: data.EntityID,
+		Language: data.Language,
+		Name:     data.Name,
+		Summary:  data.Summary,
+	}
+	if err := r.db.Create(t).Error; err != nil {
+		return uuid.Nil, errors.Wrap(err, "Creating a translation")
+	}
+	return t.ID, nil
+}
+
+func deserializeTranslations(tt *[]schema.Translation) []translations.Translation {
+	results := []translations.Translation{}
+	for _, t := range *tt {
+		results = append(results, translations.Translation{
+			EntityID: t.EntityID,
+			Language: t.Language,
+			Name:     t.Name,
+			Summary:  t.Summary,
+		})
+	}
+	return results
+}
@@ -0,0 +1,286 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+)
+
+// BattlesRepo is an in-memory implementation of battles.Repository
+type BattlesRepo struct {
+	store     *Store
+	validator *validator.Validate
+	scale     dates.NumScale
+}
+
+// NewBattlesRepo returns a pointer to a ready-to-use memory.BattlesRepo that keeps its battles in
+// the given store, where their factions and commanders must also be kept. Their dates are compared
+// as numbers in the given scale
+func NewBattlesRepo(s *Store, scale dates.NumScale) *BattlesRepo {
+	return &BattlesRepo{store: s, validator: validator.New(), scale: scale}
+}
+
+// FindOne finds the first battle that matches the query, together with its related factions and
+// commanders
+func (r *BattlesRepo) FindOne(_ context.Context, query battles.FindOneQuery) (battles.Battle, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	for _, b := range r.store.battles {
+		if (query.ID == uuid.Nil || b.ID == query.ID) &&
+			(query.Name == "" || b.Name == query.Name) &&
+			(query.URL == "" || b.URL == query.URL) {
+			return r.store.battle(b).Without(query.Omit), nil
+		}
+	}
+	return battles.Battle{}, domain.ErrNotFound
+}
+
+// FindMany does a paginated search of all battles matching the given query
+func (r *BattlesRepo) FindMany(_ context.Context, query battles.FindManyQuery, page int) ([]battles.Battle, int, error) {
+	found, err := r.find(query)
+	if err != nil {
+		return []battles.Battle{}, 0, err
+	}
+	from, to, pages := paginate(len(found), page)
+	return found[from:to], pages, nil
+}
+
+// Any tells whether at least one battle is kept in the store
+func (r *BattlesRepo) Any(ctx context.Context) (bool, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+	return len(r.store.battles) > 0, ctx.Err()
+}
+
+// Stream goes through all of the battles matching the given query
+func (r *BattlesRepo) Stream(_ context.Context, query battles.FindManyQuery, each func(battles.Battle) error) error {
+	found, err := r.find(query)
+	if err != nil {
+		return err
+	}
+	for _, b := range found {
+		if err := each(b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns all of the battles matching the given query, sorted as FindMany does
+func (r *BattlesRepo) find(query battles.FindManyQuery) ([]battles.Battle, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	filters := []func(b storedBattle) bool{
+		func(b storedBattle) bool {
+			return matches(b.Name, query.Name) &&
+				matches(b.Summary, query.Summary) &&
+				matches(b.Location.Place, query.Place) &&
+				matches(b.Result, query.Result)
+		},
+	}
+	if query.FactionID != uuid.Nil {
+		fIDs := map[uuid.UUID]bool{query.FactionID: true}
+		if query.IncludeRelated {
+			fIDs = r.store.relatedFactionIDs(query.FactionID)
+		}
+		filters = append(filters, func(b storedBattle) bool {
+			return containsAny(b.FactionsBySide, fIDs)
+		})
+	}
+	if query.CommanderID != uuid.Nil {
+		filters = append(filters, func(b storedBattle) bool {
+			return containsAny(b.CommandersBySide, map[uuid.UUID]bool{query.CommanderID: true})
+		})
+	}
+	if !query.FromDate.IsZero() {
+		fromDateNum := r.scale.Num(query.FromDate)
+		filters = append(filters, func(b storedBattle) bool {
+			return r.scale.Num(b.StartDate) >= fromDateNum
+		})
+	}
+	if !query.ToDate.IsZero() {
+		toDateNum := r.scale.Num(query.ToDate)
+		filters = append(filters, func(b storedBattle) bool {
+			return r.scale.Num(b.EndDate) <= toDateNum
+		})
+	}
+	if query.ConcurrentWith != uuid.Nil {
+		ref, ok := r.store.storedBattle(query.ConcurrentWith)
+		if !ok {
+			return nil, errors.Wrap(domain.ErrNotFound, "Finding the battle to compare dates with")
+		}
+		filters = append(filters, func(b storedBattle) bool {
+			return b.ID != ref.ID &&
+				r.scale.Num(b.StartDate) <= r.scale.Num(ref.EndDate) &&
+				r.scale.Num(b.EndDate) >= r.scale.Num(ref.StartDate)
+		})
+		if query.WithinKm > 0 {
+			refLat, refLon, ok := ref.Location.Coordinates()
+			if !ok {
+				return []battles.Battle{}, nil
+			}
+			filters = append(filters, func(b storedBattle) bool {
+				lat, lon, ok := b.Location.Coordinates()
+				return ok && locations.DistanceKm(refLat, refLon, lat, lon) <= query.WithinKm
+			})
+		}
+	}
+	if query.RelatedTo != uuid.Nil {
+		ref, _ := r.store.storedBattle(query.RelatedTo)
+		refFactions := ids(ref.FactionsBySide)
+		refCommanders := ids(ref.CommandersBySide)
+		filters = append(filters, func(b storedBattle) bool {
+			return b.ID != query.RelatedTo && (containsAny(b.FactionsBySide, refFactions) ||
+				containsAny(b.CommandersBySide, refCommanders) ||
+				(b.PartOf != "" && b.PartOf == ref.PartOf))
+		})
+	}
+
+	var found []storedBattle
+	for _, b := range r.store.battles {
+		included := true
+		for _, filter := range filters {
+			if !filter(b) {
+				included = false
+				break
+			}
+		}
+		if included {
+			found = append(found, b)
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool {
+		return r.scale.Num(found[i].StartDate) < r.scale.Num(found[j].StartDate)
+	})
+
+	result := []battles.Battle{}
+	for _, b := range found {
+		result = append(result, r.store.battle(b).Without(query.Omit))
+	}
+	return result, nil
+}
+
+// CreateOne stores a battle, which may only refer to factions and commanders that have already
+// been stored. The operation returns the ID of the new battle
+func (r *BattlesRepo) CreateOne(data battles.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating battle creation input")
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, b := range r.store.battles {
+		if b.WikiID == data.WikiID || b.URL == data.URL || b.Name == data.Name {
+			return uuid.Nil, errors.Errorf("Creating the battle: battle with WikiID %d, URL %s or name %s already exists", data.WikiID, data.URL, data.Name)
+		}
+	}
+	for fID := range ids(data.FactionsBySide) {
+		if _, err := r.store.faction(fID); err != nil {
+			return uuid.Nil, errors.Wrapf(err, "Creating the battle: faction %s", fID)
+		}
+	}
+	for cID := range ids(data.CommandersBySide) {
+		if _, err := r.store.commander(cID); err != nil {
+			return uuid.Nil, errors.Wrapf(err, "Creating the battle: commander %s", cID)
+		}
+	}
+	b := storedBattle{ID: uuid.NewV4(), CreationInput: data}
+	r.store.battles = append(r.store.battles, b)
+	return b.ID, nil
+}
+
+// storedBattle finds a battle by its ID. The caller must hold the mutex of the store
+func (s *Store) storedBattle(id uuid.UUID) (storedBattle, bool) {
+	for _, b := range s.battles {
+		if b.ID == id {
+			return b, true
+		}
+	}
+	return storedBattle{}, false
+}
+
+// battle builds a battles.Battle from a stored one, reading its factions and commanders from the
+// store. The caller must hold the mutex of the store
+func (s *Store) battle(b storedBattle) battles.Battle {
+	res := battles.Battle{
+		ID:                  b.ID,
+		WikiID:              b.WikiID,
+		URL:                 b.URL,
+		Name:                b.Name,
+		PartOf:              b.PartOf,
+		Summary:             b.Summary,
+		StartDate:           b.StartDate,
+		EndDate:             b.EndDate,
+		Location:            b.Location,
+		Result:              b.Result,
+		TerritorialChanges:  b.TerritorialChanges,
+		Strength:            b.Strength,
+		Casualties:          b.Casualties,
+		CommandersByFaction: make(battles.CommandersByFaction),
+		Wikidata:            b.Wikidata,
+	}
+	for _, fID := range b.FactionsBySide.A {
+		res.Factions.A = append(res.Factions.A, s.battleFaction(b, fID))
+	}
+	for _, fID := range b.FactionsBySide.B {
+		res.Factions.B = append(res.Factions.B, s.battleFaction(b, fID))
+	}
+	for _, cID := range b.CommandersBySide.A {
+		c, _ := s.commander(cID)
+		res.Commanders.A = append(res.Commanders.A, c)
+	}
+	for _, cID := range b.CommandersBySide.B {
+		c, _ := s.commander(cID)
+		res.Commanders.B = append(res.Commanders.B, c)
+	}
+	for fID, cIDs := range b.CommandersByFaction {
+		for _, cID := range cIDs {
+			res.CommandersByFaction[fID] = append(res.CommandersByFaction[fID], cID)
+			if confidence := b.CommanderConfidences[cID]; confidence != "" {
+				if res.CommanderConfidences == nil {
+					res.CommanderConfidences = make(map[uuid.UUID]battles.Confidence)
+				}
+				res.CommanderConfidences[cID] = confidence
+			}
+		}
+	}
+	return res
+}
+
+// battleFaction returns a faction of a battle, with the flag under which it fought in it
+func (s *Store) battleFaction(b storedBattle, id uuid.UUID) factions.Faction {
+	f, _ := s.faction(id)
+	if flag := b.FlagsByFaction[id]; flag != "" {
+		f.Flag = flag
+	}
+	return f
+}
+
+// ids returns the set of all the IDs of both sides
+func ids(bySide battles.IDsBySide) map[uuid.UUID]bool {
+	res := make(map[uuid.UUID]bool)
+	for _, id := range append(append([]uuid.UUID{}, bySide.A...), bySide.B...) {
+		res[id] = true
+	}
+	return res
+}
+
+// containsAny returns true if any of the IDs of both sides is part of the given set
+func containsAny(bySide battles.IDsBySide, set map[uuid.UUID]bool) bool {
+	for id := range ids(bySide) {
+		if set[id] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,151 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/memory"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBattlesMemRepository(t *testing.T) {
+	t.Run("CreateOne", func(t *testing.T) {
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			s := memory.NewStore()
+			bs := memory.NewBattlesRepo(s, dates.YearScale)
+
+			input := mocks.BattleCreationInput()
+			input.URL = "not-a-url"
+			_, err := bs.CreateOne(input)
+			require.Error(t, err, "Creating battle with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+		})
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		s := mustSeedStore(t)
+		bs := memory.NewBattlesRepo(s, dates.YearScale)
+
+		b, err := bs.FindOne(context.Background(), battles.FindOneQuery{Name: mocks.Battle().Name})
+		require.NoError(t, err, "Finding battle by name")
+		expected := mocks.Battle()
+		assert.Equal(t, expected.URL, b.URL)
+		assert.Equal(t, expected.StartDate, b.StartDate)
+		assert.Equal(t, expected.Strength, b.Strength)
+		assert.Equal(t, expected.Wikidata, b.Wikidata)
+		assert.Len(t, b.Factions.A, len(expected.Factions.A))
+		assert.Len(t, b.Factions.B, len(expected.Factions.B))
+		assert.Len(t, b.Commanders.A, len(expected.Commanders.A))
+		assert.Len(t, b.Commanders.B, len(expected.Commanders.B))
+		assert.Len(t, b.CommanderConfidences, len(expected.CommanderConfidences))
+
+		_, err = bs.FindOne(context.Background(), battles.FindOneQuery{ID: uuid.NewV4()})
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("FindMany", func(t *testing.T) {
+		s := mustSeedStore(t)
+		bs := memory.NewBattlesRepo(s, dates.YearScale)
+		austerlitz, err := bs.FindOne(context.Background(), battles.FindOneQuery{Name: mocks.Battle().Name})
+		require.NoError(t, err, "Finding the Battle of Austerlitz")
+		napoleon, err := memory.NewCommandersRepo(s).FindOne(context.Background(), commanders.FindOneQuery{Name: mocks.Commander().Name})
+		require.NoError(t, err, "Finding Napoleon")
+		france, err := memory.NewFactionsRepo(s).FindOne(context.Background(), factions.FindOneQuery{Name: mocks.Faction().Name})
+		require.NoError(t, err, "Finding the First French Empire")
+
+		createBattle := func(wikiID int, name, date, latitude, longitude string) {
+			t.Helper()
+			historic, err := dates.New(date)
+			require.NoError(t, err, "Parsing date")
+			_, err = bs.CreateOne(battles.CreationInput{
+				WikiID:    wikiID,
+				URL:       "https://en.wikipedia.org/wiki/" + name,
+				Name:      name,
+				Summary:   name + " was fought during the Napoleonic Wars.",
+				StartDate: historic,
+				EndDate:   historic,
+				Location:  locations.Location{Place: "Europe", Latitude: latitude, Longitude: longitude},
+				Result:    "Inconclusive",
+			})
+			require.NoError(t, err, "Creating "+name)
+		}
+		createBattle(1, "Battle of Nearby", "1805-12-02", `49°10'0"N`, `16°50'0"E`)
+		createBattle(2, "Battle of Faraway", "1805-12-02", `36°10'0"N`, `6°2'0"W`)
+
+		cases := []struct {
+			description   string
+			query         battles.FindManyQuery
+			expectedNames []string
+		}{
+			{
+				description:   "By name",
+				query:         battles.FindManyQuery{Name: "Austerlitz"},
+				expectedNames: []string{"Battle of Austerlitz"},
+			},
+			{
+				description:   "By summary phrase",
+				query:         battles.FindManyQuery{Summary: "Napoleonic Wars"},
+				expectedNames: []string{"Battle of Austerlitz", "Battle of Nearby", "Battle of Faraway"},
+			},
+			{
+				description:   "By place",
+				query:         battles.FindManyQuery{Place: "Moravia"},
+				expectedNames: []string{"Battle of Austerlitz"},
+			},
+			{
+				description:   "By result",
+				query:         battles.FindManyQuery{Result: "Treaty of Pressburg"},
+				expectedNames: []string{"Battle of Austerlitz"},
+			},
+			{
+				description:   "By faction",
+				query:         battles.FindManyQuery{FactionID: france.ID},
+				expectedNames: []string{"Battle of Austerlitz"},
+			},
+			{
+				description:   "By commander",
+				query:         battles.FindManyQuery{CommanderID: napoleon.ID},
+				expectedNames: []string{"Battle of Austerlitz"},
+			},
+			{
+				description:   "By dates",
+				query:         battles.FindManyQuery{FromDate: dates.Historic{Year: 1806, Month: 1, Day: 1}},
+				expectedNames: nil,
+			},
+			{
+				description:   "Concurrent",
+				query:         battles.FindManyQuery{ConcurrentWith: austerlitz.ID},
+				expectedNames: []string{"Battle of Nearby", "Battle of Faraway"},
+			},
+			{
+				description:   "Concurrent within km",
+				query:         battles.FindManyQuery{ConcurrentWith: austerlitz.ID, WithinKm: 100},
+				expectedNames: []string{"Battle of Nearby"},
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				bb, pages, err := bs.FindMany(context.Background(), c.query, 1)
+				require.NoError(t, err, "Finding battles")
+				assert.Equal(t, 1, pages)
+				var names []string
+				for _, b := range bb {
+					names = append(names, b.Name)
+				}
+				assert.ElementsMatch(t, c.expectedNames, names)
+			})
+		}
+	})
+}
@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	uuid "github.com/satori/go.uuid"
+)
+
+// CommandersRepo is an in-memory implementation of commanders.Repository
+type CommandersRepo struct {
+	store     *Store
+	validator *validator.Validate
+}
+
+// NewCommandersRepo returns a pointer to a ready-to-use memory.CommandersRepo that keeps its
+// commanders in the given store
+func NewCommandersRepo(s *Store) *CommandersRepo {
+	return &CommandersRepo{store: s, validator: validator.New()}
+}
+
+// FindOne finds the first commander that matches the query. Commanders searched only by WikiID or
+// URL are also looked up among the aliases of those that were merged
+func (r *CommandersRepo) FindOne(_ context.Context, query commanders.FindOneQuery) (commanders.Commander, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	for _, c := range r.store.commanders {
+		if (query.ID == uuid.Nil || c.ID == query.ID) &&
+			(query.WikiID == 0 || c.WikiID == query.WikiID) &&
+			(query.Name == "" || c.Name == query.Name) &&
+			(query.URL == "" || c.URL == query.URL) {
+			return c, nil
+		}
+	}
+	if query.ID != uuid.Nil || query.Name != "" {
+		return commanders.Commander{}, domain.ErrNotFound
+	}
+	for _, a := range r.store.commanderAliases {
+		if (query.WikiID == 0 || a.WikiID == query.WikiID) && (query.URL == "" || a.URL == query.URL) {
+			return r.store.commander(a.CommanderID)
+		}
+	}
+	return commanders.Commander{}, domain.ErrNotFound
+}
+
+// FindMany does a paginated search of all commanders matching the given query
+func (r *CommandersRepo) FindMany(_ context.Context, query commanders.FindManyQuery, page int) ([]commanders.Commander, int, error) {
+	result := r.find(query)
+	from, to, pages := paginate(len(result), page)
+	return result[from:to], pages, nil
+}
+
+// Stream goes through all of the commanders matching the given query
+func (r *CommandersRepo) Stream(_ context.Context, query commanders.FindManyQuery, each func(commanders.Commander) error) error {
+	for _, record := range r.find(query) {
+		if err := each(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns all of the commanders matching the given query, sorted as FindMany does
+func (r *CommandersRepo) find(query commanders.FindManyQuery) []commanders.Commander {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	var cIDs map[uuid.UUID]bool
+	if query.FactionID != uuid.Nil {
+		fIDs := map[uuid.UUID]bool{query.FactionID: true}
+		if query.IncludeRelated {
+			fIDs = r.store.relatedFactionIDs(query.FactionID)
+		}
+		cIDs = make(map[uuid.UUID]bool)
+		for _, b := range r.store.battles {
+			for fID, ids := range b.CommandersByFaction {
+				if !fIDs[fID] {
+					continue
+				}
+				for _, cID := range ids {
+					cIDs[cID] = true
+				}
+			}
+		}
+	}
+
+	result := []commanders.Commander{}
+	for _, c := range r.store.commanders {
+		if (cIDs == nil || cIDs[c.ID]) && matches(c.Name, query.Name) && matches(c.Summary, query.Summary) {
+			result = append(result, c)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name > result[j].Name
+	})
+	return result
+}
+
+// CreateOne stores a commander. The operation returns the ID of the new commander
+func (r *CommandersRepo) CreateOne(data commanders.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating commander creation input")
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, c := range r.store.commanders {
+		if c.WikiID == data.WikiID || c.URL == data.URL {
+			return uuid.Nil, errors.Errorf("Creating a commander: commander with WikiID %d or URL %s already exists", data.WikiID, data.URL)
+		}
+	}
+	c := commanders.Commander{
+		ID:          uuid.NewV4(),
+		WikiID:      data.WikiID,
+		URL:         data.URL,
+		Name:        data.Name,
+		Summary:     data.Summary,
+		BirthDate:   data.BirthDate,
+		DeathDate:   data.DeathDate,
+		Allegiances: data.Allegiances,
+		Wikidata:    data.Wikidata,
+	}
+	r.store.commanders = append(r.store.commanders, c)
+	return c.ID, nil
+}
+
+// CreateAlias keeps the WikiID and URL of a duplicate commander as aliases of the one it was merged
+// into
+func (r *CommandersRepo) CreateAlias(data commanders.AliasCreationInput) error {
+	if err := r.validator.Struct(data); err != nil {
+		return errors.Wrap(err, "Validating commander alias creation input")
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, err := r.store.commander(data.CommanderID); err != nil {
+		return errors.Wrap(err, "Creating a commander alias")
+	}
+	r.store.commanderAliases = append(r.store.commanderAliases, data)
+	return nil
+}
+
+// commander finds a commander by its ID. The caller must hold the mutex of the store
+func (s *Store) commander(id uuid.UUID) (commanders.Commander, error) {
+	for _, c := range s.commanders {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return commanders.Commander{}, domain.ErrNotFound
+}
@@ -0,0 +1,40 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/memory"
+	"github.com/sasalatart/batcoms/domain/battles/battlestest"
+	"github.com/sasalatart/batcoms/domain/commanders/commanderstest"
+	"github.com/sasalatart/batcoms/domain/factions/factionstest"
+	"github.com/sasalatart/batcoms/pkg/dates"
+)
+
+// newRepositories returns a battlestest.Factory whose battles store their dates in the given scale
+func newRepositories(scale dates.NumScale) battlestest.Factory {
+	return func(t *testing.T) battlestest.Repositories {
+		s := memory.NewStore()
+		return battlestest.Repositories{
+			Factions:   memory.NewFactionsRepo(s),
+			Commanders: memory.NewCommandersRepo(s),
+			Battles:    memory.NewBattlesRepo(s, scale),
+		}
+	}
+}
+
+func TestBattlesMemRepositoryConformance(t *testing.T) {
+	t.Run("YearScale", func(t *testing.T) {
+		battlestest.TestRepository(t, newRepositories(dates.YearScale))
+	})
+	t.Run("JDNScale", func(t *testing.T) {
+		battlestest.TestRepository(t, newRepositories(dates.JDNScale))
+	})
+}
+
+func TestCommandersMemRepositoryConformance(t *testing.T) {
+	commanderstest.TestRepository(t, newRepositories(dates.YearScale))
+}
+
+func TestFactionsMemRepositoryConformance(t *testing.T) {
+	factionstest.TestRepository(t, newRepositories(dates.YearScale))
+}
@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"context"
+	"sort"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/factions"
+	uuid "github.com/satori/go.uuid"
+)
+
+// FactionsRepo is an in-memory implementation of factions.Repository
+type FactionsRepo struct {
+	store     *Store
+	validator *validator.Validate
+}
+
+// NewFactionsRepo returns a pointer to a ready-to-use memory.FactionsRepo that keeps its factions
+// in the given store
+func NewFactionsRepo(s *Store) *FactionsRepo {
+	return &FactionsRepo{store: s, validator: validator.New()}
+}
+
+// FindOne finds the first faction that matches the query. Factions searched only by WikiID or URL
+// are also looked up among the aliases of those that were merged
+func (r *FactionsRepo) FindOne(_ context.Context, query factions.FindOneQuery) (factions.Faction, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	for _, f := range r.store.factions {
+		if (query.ID == uuid.Nil || f.ID == query.ID) &&
+			(query.WikiID == 0 || f.WikiID == query.WikiID) &&
+			(query.Name == "" || f.Name == query.Name) &&
+			(query.URL == "" || f.URL == query.URL) {
+			return f, nil
+		}
+	}
+	if query.ID != uuid.Nil || query.Name != "" {
+		return factions.Faction{}, domain.ErrNotFound
+	}
+	for _, a := range r.store.factionAliases {
+		if (query.WikiID == 0 || a.WikiID == query.WikiID) && (query.URL == "" || a.URL == query.URL) {
+			return r.store.faction(a.FactionID)
+		}
+	}
+	return factions.Faction{}, domain.ErrNotFound
+}
+
+// FindMany does a paginated search of all factions matching the given query
+func (r *FactionsRepo) FindMany(_ context.Context, query factions.FindManyQuery, page int) ([]factions.Faction, int, error) {
+	result := r.find(query)
+	from, to, pages := paginate(len(result), page)
+	return result[from:to], pages, nil
+}
+
+// Stream goes through all of the factions matching the given query
+func (r *FactionsRepo) Stream(_ context.Context, query factions.FindManyQuery, each func(factions.Faction) error) error {
+	for _, record := range r.find(query) {
+		if err := each(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// find returns all of the factions matching the given query, sorted as FindMany does
+func (r *FactionsRepo) find(query factions.FindManyQuery) []factions.Faction {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	var fIDs map[uuid.UUID]bool
+	if query.CommanderID != uuid.Nil {
+		fIDs = make(map[uuid.UUID]bool)
+		for _, b := range r.store.battles {
+			for fID, cIDs := range b.CommandersByFaction {
+				for _, cID := range cIDs {
+					if cID == query.CommanderID {
+						fIDs[fID] = true
+					}
+				}
+			}
+		}
+	}
+
+	result := []factions.Faction{}
+	for _, f := range r.store.factions {
+		if (fIDs == nil || fIDs[f.ID]) && matches(f.Name, query.Name) && matches(f.Summary, query.Summary) {
+			result = append(result, f)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Name > result[j].Name
+	})
+	return result
+}
+
+// CreateOne stores a faction. The operation returns the ID of the new faction
+func (r *FactionsRepo) CreateOne(data factions.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating faction creation input")
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, f := range r.store.factions {
+		if f.WikiID == data.WikiID || f.URL == data.URL {
+			return uuid.Nil, errors.Errorf("Creating a faction: faction with WikiID %d or URL %s already exists", data.WikiID, data.URL)
+		}
+	}
+	f := factions.Faction{
+		ID:       uuid.NewV4(),
+		WikiID:   data.WikiID,
+		URL:      data.URL,
+		Name:     data.Name,
+		Summary:  data.Summary,
+		Flag:     data.Flag,
+		Wikidata: data.Wikidata,
+	}
+	r.store.factions = append(r.store.factions, f)
+	return f.ID, nil
+}
+
+// CreateRelationship stores how a faction is related to another one
+func (r *FactionsRepo) CreateRelationship(data factions.RelationshipCreationInput) error {
+	if err := r.validator.Struct(data); err != nil {
+		return errors.Wrap(err, "Validating faction relationship creation input")
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, id := range []uuid.UUID{data.FactionID, data.RelatedID} {
+		if _, err := r.store.faction(id); err != nil {
+			return errors.Wrap(err, "Creating a faction relationship")
+		}
+	}
+	r.store.relationships = append(r.store.relationships, data)
+	return nil
+}
+
+// CreateAlias keeps the WikiID and URL of a duplicate faction as aliases of the one it was merged
+// into
+func (r *FactionsRepo) CreateAlias(data factions.AliasCreationInput) error {
+	if err := r.validator.Struct(data); err != nil {
+		return errors.Wrap(err, "Validating faction alias creation input")
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	if _, err := r.store.faction(data.FactionID); err != nil {
+		return errors.Wrap(err, "Creating a faction alias")
+	}
+	r.store.factionAliases = append(r.store.factionAliases, data)
+	return nil
+}
+
+// faction finds a faction by its ID. The caller must hold the mutex of the store
+func (s *Store) faction(id uuid.UUID) (factions.Faction, error) {
+	for _, f := range s.factions {
+		if f.ID == id {
+			return f, nil
+		}
+	}
+	return factions.Faction{}, domain.ErrNotFound
+}
@@ -0,0 +1,116 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/memory"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFactionsMemRepository(t *testing.T) {
+	t.Run("CreateOne", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			s := memory.NewStore()
+			fs := memory.NewFactionsRepo(s)
+
+			input := mocks.FactionCreationInput()
+			id, err := fs.CreateOne(input)
+			require.NoError(t, err, "Creating faction with valid input")
+			assert.NotEqual(t, uuid.Nil, id, "Should generate an ID")
+
+			f, err := fs.FindOne(context.Background(), factions.FindOneQuery{ID: id})
+			require.NoError(t, err, "Finding the created faction")
+			expected := mocks.Faction()
+			expected.ID = id
+			assert.Equal(t, expected, f)
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			s := memory.NewStore()
+			fs := memory.NewFactionsRepo(s)
+
+			input := mocks.FactionCreationInput()
+			input.URL = "not-a-url"
+			_, err := fs.CreateOne(input)
+			require.Error(t, err, "Creating faction with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+		})
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		s := mustSeedStore(t)
+		fs := memory.NewFactionsRepo(s)
+
+		f, err := fs.FindOne(context.Background(), factions.FindOneQuery{Name: mocks.Faction().Name})
+		require.NoError(t, err, "Finding faction by name")
+		assert.Equal(t, mocks.Faction().URL, f.URL)
+
+		_, err = fs.FindOne(context.Background(), factions.FindOneQuery{Name: "Kingdom of Atlantis"})
+		assert.Equal(t, domain.ErrNotFound, err)
+
+		alias := factions.AliasCreationInput{
+			FactionID: f.ID,
+			WikiID:    1,
+			URL:       "https://en.wikipedia.org/wiki/French_Empire",
+		}
+		require.NoError(t, fs.CreateAlias(alias), "Creating faction alias")
+		aliased, err := fs.FindOne(context.Background(), factions.FindOneQuery{WikiID: alias.WikiID})
+		require.NoError(t, err, "Finding faction by the WikiID of an alias")
+		assert.Equal(t, f.ID, aliased.ID)
+	})
+
+	t.Run("FindMany", func(t *testing.T) {
+		s := mustSeedStore(t)
+		fs := memory.NewFactionsRepo(s)
+		napoleon, err := memory.NewCommandersRepo(s).FindOne(context.Background(), commanders.FindOneQuery{Name: mocks.Commander().Name})
+		require.NoError(t, err, "Finding Napoleon")
+
+		cases := []struct {
+			description   string
+			query         factions.FindManyQuery
+			expectedNames []string
+		}{
+			{
+				description:   "With no filters",
+				query:         factions.FindManyQuery{},
+				expectedNames: []string{mocks.Faction2().Name, mocks.Faction().Name, mocks.Faction3().Name},
+			},
+			{
+				description:   "By stemmed name",
+				query:         factions.FindManyQuery{Name: "empires"},
+				expectedNames: []string{mocks.Faction2().Name, mocks.Faction().Name, mocks.Faction3().Name},
+			},
+			{
+				description:   "By summary phrase",
+				query:         factions.FindManyQuery{Summary: "German Confederation"},
+				expectedNames: []string{mocks.Faction3().Name},
+			},
+			{
+				description:   "By commander",
+				query:         factions.FindManyQuery{CommanderID: napoleon.ID},
+				expectedNames: []string{mocks.Faction().Name},
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				ff, pages, err := fs.FindMany(context.Background(), c.query, 1)
+				require.NoError(t, err, "Finding factions")
+				assert.Equal(t, 1, pages)
+				var names []string
+				for _, f := range ff {
+					names = append(names, f.Name)
+				}
+				assert.Equal(t, c.expectedNames, names)
+			})
+		}
+	})
+}
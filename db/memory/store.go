@@ -0,0 +1,137 @@
+package memory
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Store holds the factions, commanders, battles and translations shared by the in-memory
+// repositories, so that each of them may filter by the relations kept by the others, just like
+// the tables of a database
+type Store struct {
+	mutex            sync.RWMutex
+	factions         []factions.Faction
+	factionAliases   []factions.AliasCreationInput
+	relationships    []factions.RelationshipCreationInput
+	commanders       []commanders.Commander
+	commanderAliases []commanders.AliasCreationInput
+	battles          []storedBattle
+	translations     []translations.Translation
+}
+
+// storedBattle keeps a battle as it was created, referring to its factions and commanders by their
+// IDs, so that these are always read from the Store
+type storedBattle struct {
+	ID uuid.UUID
+	battles.CreationInput
+}
+
+// NewStore returns a pointer to an empty, ready-to-use memory.Store
+func NewStore() *Store {
+	return new(Store)
+}
+
+// Reset empties the store
+func (s *Store) Reset() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.factions, s.factionAliases, s.relationships = nil, nil, nil
+	s.commanders, s.commanderAliases = nil, nil
+	s.battles, s.translations = nil, nil
+}
+
+// relatedFactionIDs returns the ID of a faction together with those of all the factions related to
+// it, following relationships in the same way as the database repositories: its children, its
+// members (when it is a coalition), and both its predecessors and successors
+func (s *Store) relatedFactionIDs(id uuid.UUID) map[uuid.UUID]bool {
+	related := map[uuid.UUID]bool{id: true}
+	for changed := true; changed; {
+		changed = false
+		for _, r := range s.relationships {
+			if related[r.RelatedID] && !related[r.FactionID] {
+				related[r.FactionID] = true
+				changed = true
+			}
+			if r.Kind == factions.PredecessorKind && related[r.FactionID] && !related[r.RelatedID] {
+				related[r.RelatedID] = true
+				changed = true
+			}
+		}
+	}
+	return related
+}
+
+var nonWordMatcher = regexp.MustCompile(`[^\pL\pN]+`)
+
+// suffixes are stripped from words before comparing them, as a rough approximation of stemming
+var suffixes = []string{"ing", "ed", "s"}
+
+// words splits the given text into lowercase words, without some of their common English suffixes
+func words(text string) []string {
+	var res []string
+	for _, w := range nonWordMatcher.Split(strings.ToLower(text), -1) {
+		if w == "" {
+			continue
+		}
+		for _, suffix := range suffixes {
+			if len(w) > len(suffix)+2 && strings.HasSuffix(w, suffix) {
+				w = strings.TrimSuffix(w, suffix)
+				break
+			}
+		}
+		if len(w) > 3 {
+			w = strings.TrimSuffix(w, "e")
+		}
+		res = append(res, w)
+	}
+	return res
+}
+
+// matches returns true if the given text contains the words of the given value as a phrase, or if
+// the value is empty
+func matches(text, value string) bool {
+	phrase := words(value)
+	if len(phrase) == 0 {
+		return true
+	}
+	all := words(text)
+	for i := 0; i+len(phrase) <= len(all); i++ {
+		found := true
+		for j, w := range phrase {
+			if all[i+j] != w {
+				found = false
+				break
+			}
+		}
+		if found {
+			return true
+		}
+	}
+	return false
+}
+
+// paginate returns the bounds of the given page among results of the given length, and the number
+// of pages, which is calculated in the same way as in the database repositories
+func paginate(length, page int) (int, int, int) {
+	pages := length/perPage + 1
+	from := (page - 1) * perPage
+	if from < 0 {
+		from = 0
+	} else if from > length {
+		from = length
+	}
+	to := from + perPage
+	if to > length {
+		to = length
+	}
+	return from, to, pages
+}
+
+const perPage = 50
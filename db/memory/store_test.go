@@ -0,0 +1,47 @@
+package memory_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/memory"
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/logger"
+)
+
+// mustSeedStore sets up a store with the mocked battle, together with its factions, commanders and
+// translations
+func mustSeedStore(t *testing.T) *memory.Store {
+	t.Helper()
+	s := memory.NewStore()
+	importedData := seeder.ImportedData{
+		WikiBattlesByID: map[string]wikibattles.Battle{
+			strconv.Itoa(mocks.WikiBattle().ID): mocks.WikiBattle(),
+		},
+		WikiFactionsByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiFaction().ID):  mocks.WikiFaction(),
+			strconv.Itoa(mocks.WikiFaction2().ID): mocks.WikiFaction2(),
+			strconv.Itoa(mocks.WikiFaction3().ID): mocks.WikiFaction3(),
+		},
+		WikiCommandersByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiCommander().ID):  mocks.WikiCommander(),
+			strconv.Itoa(mocks.WikiCommander2().ID): mocks.WikiCommander2(),
+			strconv.Itoa(mocks.WikiCommander3().ID): mocks.WikiCommander3(),
+			strconv.Itoa(mocks.WikiCommander4().ID): mocks.WikiCommander4(),
+			strconv.Itoa(mocks.WikiCommander5().ID): mocks.WikiCommander5(),
+		},
+	}
+	seeder.Seed(
+		&importedData,
+		memory.NewFactionsRepo(s),
+		memory.NewCommandersRepo(s),
+		memory.NewBattlesRepo(s, dates.YearScale),
+		memory.NewTranslationsRepo(s),
+		logger.NewDiscard(),
+	)
+	return s
+}
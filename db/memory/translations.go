@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"context"
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/translations"
+	uuid "github.com/satori/go.uuid"
+)
+
+// TranslationsRepo is an in-memory implementation of translations.Repository
+type TranslationsRepo struct {
+	store     *Store
+	validator *validator.Validate
+}
+
+// NewTranslationsRepo returns a pointer to a ready-to-use memory.TranslationsRepo that keeps its
+// translations in the given store
+func NewTranslationsRepo(s *Store) *TranslationsRepo {
+	return &TranslationsRepo{store: s, validator: validator.New()}
+}
+
+// FindMany finds the translations into the language of the query of all the entities with the given
+// IDs. Entities without such a translation are simply not included in the results
+func (r *TranslationsRepo) FindMany(_ context.Context, query translations.FindManyQuery) ([]translations.Translation, error) {
+	r.store.mutex.RLock()
+	defer r.store.mutex.RUnlock()
+
+	entityIDs := make(map[uuid.UUID]bool)
+	for _, id := range query.EntityIDs {
+		entityIDs[id] = true
+	}
+	result := []translations.Translation{}
+	for _, t := range r.store.translations {
+		if t.Language == query.Language && entityIDs[t.EntityID] {
+			result = append(result, t)
+		}
+	}
+	return result, nil
+}
+
+// CreateOne stores a translation. The operation returns the ID of the new translation
+func (r *TranslationsRepo) CreateOne(data translations.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating translation creation input")
+	}
+	r.store.mutex.Lock()
+	defer r.store.mutex.Unlock()
+
+	for _, t := range r.store.translations {
+		if t.EntityID == data.EntityID && t.Language == data.Language {
+			return uuid.Nil, errors.Errorf("Creating a translation: entity %s is already translated into %s", data.EntityID, data.Language)
+		}
+	}
+	r.store.translations = append(r.store.translations, translations.Translation{
+		EntityID: data.EntityID,
+		Language: data.Language,
+		Name:     data.Name,
+		Summary:  data.Summary,
+	})
+	return uuid.NewV4(), nil
+}
@@ -0,0 +1,103 @@
+package postgresql
+
+import (
+	"context"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	uuid "github.com/satori/go.uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeysRepository is the repository that abstracts access to the underlying database operations
+// used to query and mutate the keys issued to clients of the API. This implementation relies on GORM
+// and also executes validations before interacting with the database
+type APIKeysRepository struct {
+	db        *gorm.DB
+	validator *validator.Validate
+}
+
+// NewAPIKeysRepository returns a pointer to a ready-to-use postgresql.APIKeysRepository
+func NewAPIKeysRepository(db *gorm.DB) *APIKeysRepository {
+	return &APIKeysRepository{db, validator.New()}
+}
+
+// MigrateAPIKeys creates the table in which API keys are stored if it does not exist yet. Unlike the
+// tables of the dataset, it is never dropped, so that keys outlive reseeding the database
+func MigrateAPIKeys(db *gorm.DB) error {
+	db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" WITH SCHEMA public;`)
+	return errors.Wrap(db.AutoMigrate(&schema.APIKey{}), "Migrating API keys")
+}
+
+// FindOne finds the API key in the database that matches the query, whether it has been revoked or
+// not
+func (r *APIKeysRepository) FindOne(ctx context.Context, query apikeys.FindOneQuery) (apikeys.APIKey, error) {
+	k := &schema.APIKey{}
+	err := r.db.WithContext(ctx).Where(schema.APIKey{Base: schema.Base{ID: query.ID}, Hash: query.Hash}).First(k).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return apikeys.APIKey{}, domain.ErrNotFound
+	} else if err != nil {
+		return apikeys.APIKey{}, errors.Wrap(err, "Executing APIKeysRepository.FindOne")
+	}
+	return deserializeAPIKey(k), nil
+}
+
+// FindMany finds all API keys, from the newest to the oldest one
+func (r *APIKeysRepository) FindMany(ctx context.Context) ([]apikeys.APIKey, error) {
+	result := &[]schema.APIKey{}
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(result).Error; err != nil {
+		return []apikeys.APIKey{}, errors.Wrap(err, "Executing APIKeysRepository.FindMany")
+	}
+	keys := []apikeys.APIKey{}
+	for _, k := range *result {
+		keys = append(keys, deserializeAPIKey(&k))
+	}
+	return keys, nil
+}
+
+// CreateOne creates an API key in the database. The operation returns the ID of the new key
+func (r *APIKeysRepository) CreateOne(data apikeys.CreationInput) (uuid.UUID, error) {
+	if err := r.validator.Struct(data); err != nil {
+		return uuid.Nil, errors.Wrap(err, "Validating API key creation input")
+	}
+	k := &schema.APIKey{
+		Name:              data.Name,
+		Hash:              data.Hash,
+		RequestsPerMinute: data.RequestsPerMinute,
+		Burst:             data.Burst,
+	}
+	if err := r.db.Create(k).Error; err != nil {
+		return uuid.Nil, errors.Wrap(err, "Creating an API key")
+	}
+	return k.ID, nil
+}
+
+// Revoke marks the API key with the given ID as revoked, keeping the time at which it was first
+// revoked if it already was
+func (r *APIKeysRepository) Revoke(id uuid.UUID) error {
+	result := r.db.
+		Model(&schema.APIKey{}).
+		Where("id = ?", id).
+		Update("revoked_at", gorm.Expr("COALESCE(revoked_at, NOW())"))
+	if result.Error != nil {
+		return errors.Wrap(result.Error, "Revoking an API key")
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
+
+func deserializeAPIKey(k *schema.APIKey) apikeys.APIKey {
+	return apikeys.APIKey{
+		ID:                k.ID,
+		Name:              k.Name,
+		RequestsPerMinute: k.RequestsPerMinute,
+		Burst:             k.Burst,
+		CreatedAt:         k.CreatedAt,
+		RevokedAt:         k.RevokedAt,
+	}
+}
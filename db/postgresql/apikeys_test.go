@@ -0,0 +1,134 @@
+package postgresql_test
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPIKeysRepository(t *testing.T) {
+	t.Run("CreateOne", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			input := mocks.APIKeyCreationInput(mocks.APIKey())
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			mock.ExpectBegin()
+			mock.ExpectQuery(`^INSERT INTO "api_keys" (.*)`).
+				WithArgs(input.Name, input.Hash, input.RequestsPerMinute, input.Burst, sqlmock.AnyArg(), nil).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mocks.APIKey().ID))
+			mock.ExpectCommit()
+			repo := postgresql.NewAPIKeysRepository(db)
+
+			id, err := repo.CreateOne(input)
+			require.NoError(t, err, "Creating API key with valid input")
+			assert.Equal(t, mocks.APIKey().ID, id, "Should return the corresponding ID")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			input := mocks.APIKeyCreationInput(mocks.APIKey())
+			input.Hash = mocks.PlainAPIKey()
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			repo := postgresql.NewAPIKeysRepository(db)
+
+			_, err := repo.CreateOne(input)
+			require.Error(t, err, "Creating API key with an unhashed key")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		columns := []string{"id", "name", "hash", "requests_per_minute", "burst", "created_at", "revoked_at"}
+		revokedAt := time.Date(2020, time.November, 1, 0, 0, 0, 0, time.UTC)
+		cases := []struct {
+			description string
+			revokedAt   driver.Value
+			expected    func() apikeys.APIKey
+		}{
+			{
+				description: "Active",
+				revokedAt:   nil,
+				expected:    mocks.APIKey,
+			},
+			{
+				description: "Revoked",
+				revokedAt:   revokedAt,
+				expected: func() apikeys.APIKey {
+					k := mocks.APIKey()
+					k.RevokedAt = &revokedAt
+					return k
+				},
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				key := mocks.APIKey()
+				hash := apikeys.Hash(mocks.PlainAPIKey())
+				db, sqlDB, mock := mustSetupDB(t)
+				defer sqlDB.Close()
+				mock.ExpectQuery(`^SELECT \* FROM "api_keys" WHERE "api_keys"."hash" = \$1`).
+					WithArgs(hash).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(key.ID, key.Name, hash, key.RequestsPerMinute, key.Burst, key.CreatedAt, c.revokedAt))
+				repo := postgresql.NewAPIKeysRepository(db)
+
+				got, err := repo.FindOne(context.Background(), apikeys.FindOneQuery{Hash: hash})
+				require.NoError(t, err, "Finding API key")
+				assert.Equal(t, c.expected(), got, "Comparing with expected API key")
+				assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+			})
+		}
+
+		t.Run("NotFound", func(t *testing.T) {
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			mock.ExpectQuery(`^SELECT \* FROM "api_keys"`).WillReturnRows(sqlmock.NewRows(columns))
+			repo := postgresql.NewAPIKeysRepository(db)
+
+			_, err := repo.FindOne(context.Background(), apikeys.FindOneQuery{Hash: apikeys.Hash("unknown")})
+			assert.Equal(t, domain.ErrNotFound, err, "Should return domain.ErrNotFound")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+	})
+
+	t.Run("Revoke", func(t *testing.T) {
+		cases := []struct {
+			description  string
+			rowsAffected int64
+			expectedErr  error
+		}{
+			{description: "Existing", rowsAffected: 1, expectedErr: nil},
+			{description: "NotFound", rowsAffected: 0, expectedErr: domain.ErrNotFound},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				id := uuid.NewV4()
+				db, sqlDB, mock := mustSetupDB(t)
+				defer sqlDB.Close()
+				mock.ExpectBegin()
+				mock.ExpectExec(`^UPDATE "api_keys" SET "revoked_at"=COALESCE\(revoked_at, NOW\(\)\) WHERE id = \$1`).
+					WithArgs(id).
+					WillReturnResult(sqlmock.NewResult(0, c.rowsAffected))
+				mock.ExpectCommit()
+				repo := postgresql.NewAPIKeysRepository(db)
+
+				assert.Equal(t, c.expectedErr, repo.Revoke(id))
+				assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+			})
+		}
+	})
+}
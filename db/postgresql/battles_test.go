@@ -1,6 +1,7 @@
 package postgresql_test
 
 import (
+	"context"
 	"database/sql"
 	"database/sql/driver"
 	"encoding/json"
@@ -12,6 +13,7 @@ import (
 	"github.com/sasalatart/batcoms/db/postgresql"
 	"github.com/sasalatart/batcoms/domain/battles"
 	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/dates"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -29,6 +31,14 @@ func TestBattlesRepository(t *testing.T) {
 			require.NoError(t, err, "Stringifying strength")
 			casualties, err := json.Marshal(input.Casualties)
 			require.NoError(t, err, "Stringifying casualties")
+			startDate, err := json.Marshal(input.StartDate)
+			require.NoError(t, err, "Stringifying startDate")
+			endDate, err := json.Marshal(input.EndDate)
+			require.NoError(t, err, "Stringifying endDate")
+			wikidata, err := json.Marshal(input.Wikidata)
+			require.NoError(t, err, "Stringifying wikidata")
+			latitude, longitude, ok := input.Location.Coordinates()
+			require.True(t, ok, "Parsing coordinates")
 
 			mock.ExpectBegin()
 			mock.ExpectQuery(`^INSERT INTO "battles"`).
@@ -40,15 +50,20 @@ func TestBattlesRepository(t *testing.T) {
 					input.Summary,
 					input.StartDate.String(),
 					input.StartDate.ToNum(),
+					datatypes.JSON(startDate),
 					input.EndDate.String(),
 					input.EndDate.ToNum(),
+					datatypes.JSON(endDate),
 					input.Location.Place,
 					input.Location.Latitude,
 					input.Location.Longitude,
+					latitude,
+					longitude,
 					input.Result,
 					input.TerritorialChanges,
 					datatypes.JSON(strength),
 					datatypes.JSON(casualties),
+					datatypes.JSON(wikidata),
 				).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockUUID))
 
@@ -62,7 +77,7 @@ func TestBattlesRepository(t *testing.T) {
 
 			battleFactionsArgs := []driver.Value{}
 			for i := 0; i < len(input.FactionsBySide.A)+len(input.FactionsBySide.B); i++ {
-				battleFactionsArgs = append(battleFactionsArgs, mockUUID, sqlmock.AnyArg(), sqlmock.AnyArg())
+				battleFactionsArgs = append(battleFactionsArgs, mockUUID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg())
 			}
 			mock.ExpectExec(`^INSERT INTO "battle_factions"`).
 				WithArgs(battleFactionsArgs...).
@@ -71,7 +86,7 @@ func TestBattlesRepository(t *testing.T) {
 			battleCommanderFactionsArgs := []driver.Value{}
 			for _, cIDs := range input.CommandersByFaction {
 				for range cIDs {
-					battleCommanderFactionsArgs = append(battleCommanderFactionsArgs, mockUUID, sqlmock.AnyArg(), sqlmock.AnyArg())
+					battleCommanderFactionsArgs = append(battleCommanderFactionsArgs, mockUUID, sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg())
 				}
 			}
 			mock.ExpectExec(`^INSERT INTO "battle_commander_factions"`).
@@ -87,7 +102,7 @@ func TestBattlesRepository(t *testing.T) {
 			input := mocks.BattleCreationInput()
 			db, sqlDB, mock := mustSetupCreateOne(t, mockUUID, input)
 			defer sqlDB.Close()
-			repo := postgresql.NewBattlesRepository(db)
+			repo := postgresql.NewBattlesRepository(db, dates.YearScale)
 
 			id, err := repo.CreateOne(input)
 			require.NoError(t, err, "Creating battle with valid input")
@@ -100,7 +115,7 @@ func TestBattlesRepository(t *testing.T) {
 			input.URL = "not-a-url"
 			db, sqlDB, mock := mustSetupDB(t)
 			defer sqlDB.Close()
-			repo := postgresql.NewBattlesRepository(db)
+			repo := postgresql.NewBattlesRepository(db, dates.YearScale)
 
 			_, err := repo.CreateOne(input)
 			require.Error(t, err, "Creating battle with invalid input")
@@ -109,4 +124,28 @@ func TestBattlesRepository(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
 		})
 	})
+
+	t.Run("FindManyConcurrentWithinKm", func(t *testing.T) {
+		db, sqlDB, mock := mustSetupDB(t)
+		defer sqlDB.Close()
+		repo := postgresql.NewBattlesRepository(db, dates.YearScale)
+
+		// Austerlitz (49.13, 16.76) is about 560 km away from Ulm (48.40, 9.99), so the distance must
+		// be measured from the latitude and longitude of the reference battle, in that order
+		refID := uuid.NewV4()
+		const refLatitude, refLongitude = 49.133333, 16.766667
+		mock.ExpectQuery(`^SELECT \* FROM "battles" WHERE id = \$1`).
+			WithArgs(refID).
+			WillReturnRows(
+				sqlmock.NewRows([]string{"id", "start_date_num", "end_date_num", "latitude_num", "longitude_num"}).
+					AddRow(refID, 100, 100, refLatitude, refLongitude),
+			)
+		mock.ExpectQuery(`^SELECT count\(1\) FROM "battles"`).
+			WithArgs(refID, float64(100), float64(100), refLatitude, refLongitude, refLatitude, float64(500)).
+			WillReturnError(errors.New("stop"))
+
+		_, _, err := repo.FindMany(context.Background(), battles.FindManyQuery{ConcurrentWith: refID, WithinKm: 500}, 1)
+		assert.EqualError(t, err, "stop")
+		assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+	})
 }
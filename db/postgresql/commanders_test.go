@@ -2,6 +2,7 @@ package postgresql_test
 
 import (
 	"database/sql"
+	"encoding/json"
 	"testing"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -10,9 +11,11 @@ import (
 	"github.com/sasalatart/batcoms/db/postgresql"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/dates"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
 
@@ -20,9 +23,17 @@ func TestCommandersRepository(t *testing.T) {
 	t.Run("CreateOne", func(t *testing.T) {
 		mustSetupCreateOne := func(t *testing.T, mockUUID uuid.UUID, input commanders.CreationInput) (*gorm.DB, *sql.DB, sqlmock.Sqlmock) {
 			db, sqlDB, mock := mustSetupDB(t)
+			biography, err := json.Marshal(struct {
+				BirthDate   *dates.Historic         `json:"birthDate,omitempty"`
+				DeathDate   *dates.Historic         `json:"deathDate,omitempty"`
+				Allegiances []commanders.Allegiance `json:"allegiances,omitempty"`
+			}{input.BirthDate, input.DeathDate, input.Allegiances})
+			require.NoError(t, err, "Stringifying biography")
+			wikidata, err := json.Marshal(input.Wikidata)
+			require.NoError(t, err, "Stringifying wikidata")
 			mock.ExpectBegin()
 			mock.ExpectQuery(`^INSERT INTO "commanders" (.*)`).
-				WithArgs(input.WikiID, input.URL, input.Name, input.Summary).
+				WithArgs(input.WikiID, input.URL, input.Name, input.Summary, datatypes.JSON(biography), datatypes.JSON(wikidata)).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockUUID))
 			mock.ExpectCommit()
 			return db, sqlDB, mock
@@ -53,4 +64,38 @@ func TestCommandersRepository(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
 		})
 	})
+
+	t.Run("CreateAlias", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			input := commanders.AliasCreationInput{
+				CommanderID: mocks.Commander().ID,
+				WikiID:      2,
+				URL:         "https://en.wikipedia.org/wiki/Napoleon_Bonaparte",
+			}
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			mock.ExpectBegin()
+			mock.ExpectExec(`^INSERT INTO "commander_aliases" (.*)`).
+				WithArgs(input.WikiID, input.URL, input.CommanderID).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			repo := postgresql.NewCommandersRepository(db)
+
+			err := repo.CreateAlias(input)
+			require.NoError(t, err, "Creating commander alias with valid input")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			input := commanders.AliasCreationInput{CommanderID: mocks.Commander().ID, URL: "https://en.wikipedia.org/wiki/Napoleon_Bonaparte"}
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			repo := postgresql.NewCommandersRepository(db)
+
+			err := repo.CreateAlias(input)
+			require.Error(t, err, "Creating commander alias with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+	})
 }
@@ -0,0 +1,79 @@
+//go:build postgres
+// +build postgres
+
+package postgresql_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sasalatart/batcoms/config"
+	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/domain/battles/battlestest"
+	"github.com/sasalatart/batcoms/domain/commanders/commanderstest"
+	"github.com/sasalatart/batcoms/domain/factions/factionstest"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// mustConnectTestDB connects to the database of the test environment, failing the test when it is
+// not available. These tests are only built with the postgres tag, which is set within Docker
+func mustConnectTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	config.Setup()
+	var db *gorm.DB
+	func() {
+		defer func() {
+			if r := recover(); r != nil {
+				t.Fatalf("Test database not available: %s", r)
+			}
+		}()
+		db, _ = postgresql.Connect(postgresql.DefaultTestConfig())
+	}()
+	return db
+}
+
+// newRepositories returns a battlestest.Factory whose repositories work on their own schema within
+// a transaction that is rolled back when the test finishes, so that the test database is left as it
+// was for the integration tests, which may be running at the same time. Battles store their dates in
+// the given scale
+func newRepositories(db *gorm.DB, scale dates.NumScale) battlestest.Factory {
+	return func(t *testing.T) battlestest.Repositories {
+		tx := db.Begin()
+		require.NoError(t, tx.Error, "Beginning transaction")
+		t.Cleanup(func() { tx.Rollback() })
+
+		schema := "conformance_" + strings.ReplaceAll(uuid.NewV4().String(), "-", "")
+		require.NoError(t, tx.Exec(fmt.Sprintf("CREATE SCHEMA %s", schema)).Error, "Creating schema")
+		require.NoError(t, tx.Exec(fmt.Sprintf("SET LOCAL search_path TO %s, public", schema)).Error, "Using schema")
+		postgresql.Reset(tx)
+		return battlestest.Repositories{
+			Factions:   postgresql.NewFactionsRepository(tx),
+			Commanders: postgresql.NewCommandersRepository(tx),
+			Battles:    postgresql.NewBattlesRepository(tx, scale),
+		}
+	}
+}
+
+func TestBattlesRepositoryConformance(t *testing.T) {
+	db := mustConnectTestDB(t)
+	t.Run("YearScale", func(t *testing.T) {
+		battlestest.TestRepository(t, newRepositories(db, dates.YearScale))
+	})
+	t.Run("JDNScale", func(t *testing.T) {
+		battlestest.TestRepository(t, newRepositories(db, dates.JDNScale))
+	})
+}
+
+func TestCommandersRepositoryConformance(t *testing.T) {
+	db := mustConnectTestDB(t)
+	commanderstest.TestRepository(t, newRepositories(db, dates.YearScale))
+}
+
+func TestFactionsRepositoryConformance(t *testing.T) {
+	db := mustConnectTestDB(t)
+	factionstest.TestRepository(t, newRepositories(db, dates.YearScale))
+}
@@ -22,7 +22,7 @@ func TestFactionsRepository(t *testing.T) {
 			db, sqlDB, mock := mustSetupDB(t)
 			mock.ExpectBegin()
 			mock.ExpectQuery(`^INSERT INTO "factions" (.*)`).
-				WithArgs(input.WikiID, input.URL, input.Name, input.Summary).
+				WithArgs(input.WikiID, input.URL, input.Name, input.Summary, input.Flag, nil).
 				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockUUID))
 			mock.ExpectCommit()
 			return db, sqlDB, mock
@@ -53,4 +53,77 @@ func TestFactionsRepository(t *testing.T) {
 			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
 		})
 	})
+
+	t.Run("CreateRelationship", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			input := factions.RelationshipCreationInput{
+				FactionID: mocks.Faction().ID,
+				Kind:      factions.PredecessorKind,
+				RelatedID: mocks.Faction2().ID,
+			}
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			mock.ExpectBegin()
+			mock.ExpectExec(`^INSERT INTO "faction_relationships" (.*)`).
+				WithArgs(input.FactionID, input.RelatedID, string(input.Kind)).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			fs := postgresql.NewFactionsRepository(db)
+
+			err := fs.CreateRelationship(input)
+			require.NoError(t, err, "Creating faction relationship with valid input")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			cases := []factions.RelationshipCreationInput{
+				{FactionID: mocks.Faction().ID, Kind: "ally", RelatedID: mocks.Faction2().ID},
+				{FactionID: mocks.Faction().ID, Kind: factions.ParentKind},
+			}
+			for _, input := range cases {
+				db, sqlDB, mock := mustSetupDB(t)
+				fs := postgresql.NewFactionsRepository(db)
+
+				err := fs.CreateRelationship(input)
+				require.Error(t, err, "Creating faction relationship with invalid input %v", input)
+				_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+				assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+				assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+				sqlDB.Close()
+			}
+		})
+	})
+
+	t.Run("CreateAlias", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			input := factions.AliasCreationInput{
+				FactionID: mocks.Faction().ID,
+				WikiID:    1,
+				URL:       "https://en.wikipedia.org/wiki/Napoleonic_Empire",
+			}
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			mock.ExpectBegin()
+			mock.ExpectExec(`^INSERT INTO "faction_aliases" (.*)`).
+				WithArgs(input.WikiID, input.URL, input.FactionID).
+				WillReturnResult(sqlmock.NewResult(1, 1))
+			mock.ExpectCommit()
+			fs := postgresql.NewFactionsRepository(db)
+
+			err := fs.CreateAlias(input)
+			require.NoError(t, err, "Creating faction alias with valid input")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			input := factions.AliasCreationInput{FactionID: mocks.Faction().ID, WikiID: 1, URL: "not-a-url"}
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			fs := postgresql.NewFactionsRepository(db)
+
+			err := fs.CreateAlias(input)
+			require.Error(t, err, "Creating faction alias with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+	})
 }
@@ -2,13 +2,13 @@ package postgresql
 
 import (
 	"database/sql"
-	"encoding/json"
 	"fmt"
 
 	"github.com/pkg/errors"
-	"github.com/sasalatart/batcoms/db/postgresql/schema"
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/pkg/metrics"
+	"github.com/sasalatart/batcoms/pkg/tracing"
 	"github.com/spf13/viper"
-	"gorm.io/datatypes"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
@@ -39,17 +39,22 @@ func DefaultTestConfig() *ConnectionConfig {
 	return c
 }
 
-// Connect establishes a database connection to the PostgreSQL instance
+// Connect establishes a database connection to the PostgreSQL instance. It panics if the database can
+// not be reached
 func Connect(c *ConnectionConfig) (*gorm.DB, *sql.DB) {
+	db, sqlDB, err := Open(c)
+	if err != nil {
+		panic(err)
+	}
+	return db, sqlDB
+}
+
+// Open is like Connect, but returns an error instead of panicking, so that connecting may be retried
+func Open(c *ConnectionConfig) (*gorm.DB, *sql.DB, error) {
 	if c == nil {
 		c = defaultConfig()
 	}
 
-	handleError := func(err error) {
-		if err != nil {
-			panic(errors.Wrap(err, "Unable to connect to database"))
-		}
-	}
 	dsn := postgres.Open(fmt.Sprintf(
 		"host=%s port=%s user=%s dbname=%s password=%s sslmode=disable",
 		c.Host,
@@ -59,25 +64,40 @@ func Connect(c *ConnectionConfig) (*gorm.DB, *sql.DB) {
 		c.Pass,
 	))
 	db, err := gorm.Open(dsn, &gorm.Config{})
-	handleError(err)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
+	if err := metrics.InstrumentGORM(db, "postgresql"); err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
+	if err := tracing.InstrumentGORM(db, "postgresql"); err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
 	sqlDB, err := db.DB()
-	handleError(err)
-	return db, sqlDB
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
+	return db, sqlDB, nil
 }
 
-// Reset drops all existing tables, and automigrates them again
+// Reset drops all existing tables of the dataset, and automigrates them again. API keys are kept
 func Reset(db *gorm.DB) {
 	db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp" WITH SCHEMA public;`)
 	schemas := []interface{}{
+		&schema.FactionRelationship{},
+		&schema.FactionAlias{},
+		&schema.CommanderAlias{},
 		&schema.BattleCommanderFaction{},
 		&schema.BattleFaction{},
 		&schema.BattleCommander{},
 		&schema.Faction{},
 		&schema.Commander{},
 		&schema.Battle{},
+		&schema.Translation{},
 	}
 	db.Migrator().DropTable(schemas...)
 	db.AutoMigrate(schemas...)
+	MigrateAPIKeys(db)
 
 	db.Exec(`CREATE INDEX ts_factions_name_idx ON factions USING GIST(to_tsvector('english', name));`)
 	db.Exec(`CREATE INDEX ts_factions_summary_idx ON factions USING GIST(to_tsvector('english', summary));`)
@@ -90,27 +110,3 @@ func Reset(db *gorm.DB) {
 	db.Exec(`CREATE INDEX ts_battles_place_idx ON battles USING GIST(to_tsvector('english', place));`)
 	db.Exec(`CREATE INDEX ts_battles_result_idx ON battles USING GIST(to_tsvector('english', result));`)
 }
-
-func fromJSON(data datatypes.JSON, storeTo interface{}) error {
-	parsed, err := data.MarshalJSON()
-	if err != nil {
-		return err
-	}
-	if err := json.Unmarshal(parsed, storeTo); err != nil {
-		return err
-	}
-	return nil
-}
-
-func paginate(db *gorm.DB, page, perPage int) *gorm.DB {
-	return db.Offset((page - 1) * perPage).Limit(perPage)
-}
-
-func ts(db *gorm.DB, attribute, value string) *gorm.DB {
-	if value == "" {
-		return db
-	}
-	return db.Where(fmt.Sprintf("to_tsvector('english', %s) @@ phraseto_tsquery(?)", attribute), value)
-}
-
-const perPage = 50
@@ -0,0 +1,62 @@
+package postgresql
+
+import (
+	"fmt"
+
+	"github.com/sasalatart/batcoms/db/gormrepo"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"gorm.io/gorm"
+)
+
+// Dialect lets the GORM repositories search text with the full-text search of PostgreSQL, and
+// calculate distances with its trigonometric functions
+var Dialect = gormrepo.Dialect{
+	Match:    ts,
+	WithinKm: withinKm,
+	IDParam:  "CAST(? AS uuid)",
+}
+
+// NewFactionsRepository returns a pointer to a ready-to-use repository of factions stored in
+// PostgreSQL
+func NewFactionsRepository(db *gorm.DB) *gormrepo.FactionsRepository {
+	return gormrepo.NewFactionsRepository(db, Dialect)
+}
+
+// NewCommandersRepository returns a pointer to a ready-to-use repository of commanders stored in
+// PostgreSQL
+func NewCommandersRepository(db *gorm.DB) *gormrepo.CommandersRepository {
+	return gormrepo.NewCommandersRepository(db, Dialect)
+}
+
+// NewBattlesRepository returns a pointer to a ready-to-use repository of battles stored in
+// PostgreSQL, whose dates are stored and compared as numbers in the given scale
+func NewBattlesRepository(db *gorm.DB, scale dates.NumScale) *gormrepo.BattlesRepository {
+	return gormrepo.NewBattlesRepository(db, Dialect, scale)
+}
+
+// NewTranslationsRepository returns a pointer to a ready-to-use repository of translations stored
+// in PostgreSQL
+func NewTranslationsRepository(db *gorm.DB) *gormrepo.TranslationsRepository {
+	return gormrepo.NewTranslationsRepository(db)
+}
+
+// ts restricts the query to the rows whose attribute contains the given value as a phrase, by using
+// the full-text search of PostgreSQL
+func ts(db *gorm.DB, _, attribute, value string) *gorm.DB {
+	if value == "" {
+		return db
+	}
+	return db.Where(fmt.Sprintf("to_tsvector('english', %s) @@ phraseto_tsquery(?)", attribute), value)
+}
+
+// withinKm restricts the query to the battles at most km kilometres away from the given coordinates
+func withinKm(db *gorm.DB, latitude, longitude, km float64) *gorm.DB {
+	return db.Where(distanceKmSQL+" <= ?", latitude, longitude, latitude, km)
+}
+
+// distanceKmSQL calculates the great-circle distance in kilometres between the coordinates of each
+// battle and the ones supplied as arguments (latitude, longitude and latitude, in that order)
+const distanceKmSQL = `6371 * ACOS(LEAST(1, GREATEST(-1,
+	COS(RADIANS(?)) * COS(RADIANS(latitude_num)) * COS(RADIANS(longitude_num) - RADIANS(?)) +
+	SIN(RADIANS(?)) * SIN(RADIANS(latitude_num))
+)))`
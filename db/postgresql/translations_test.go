@@ -0,0 +1,70 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/domain/translations"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslationsRepository(t *testing.T) {
+	t.Run("CreateOne", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			mockUUID := uuid.NewV4()
+			input := mocks.TranslationCreationInput(mocks.BattleTranslation())
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			mock.ExpectBegin()
+			mock.ExpectQuery(`^INSERT INTO "translations" (.*)`).
+				WithArgs(input.EntityID, input.Language, input.Name, input.Summary).
+				WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(mockUUID))
+			mock.ExpectCommit()
+			repo := postgresql.NewTranslationsRepository(db)
+
+			id, err := repo.CreateOne(input)
+			require.NoError(t, err, "Creating translation with valid input")
+			assert.Equal(t, mockUUID, id, "Should return the corresponding ID")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			input := mocks.TranslationCreationInput(mocks.BattleTranslation())
+			input.Language = "spanish"
+			db, sqlDB, mock := mustSetupDB(t)
+			defer sqlDB.Close()
+			repo := postgresql.NewTranslationsRepository(db)
+
+			_, err := repo.CreateOne(input)
+			require.Error(t, err, "Creating translation with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+			assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+		})
+	})
+
+	t.Run("FindMany", func(t *testing.T) {
+		translation := mocks.BattleTranslation()
+		db, sqlDB, mock := mustSetupDB(t)
+		defer sqlDB.Close()
+		mock.ExpectQuery(`^SELECT \* FROM "translations" WHERE language = \$1 AND entity_id IN \(\$2\)`).
+			WithArgs(translation.Language, translation.EntityID).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "entity_id", "language", "name", "summary"}).
+				AddRow(uuid.NewV4(), translation.EntityID, translation.Language, translation.Name, translation.Summary))
+		repo := postgresql.NewTranslationsRepository(db)
+
+		got, err := repo.FindMany(context.Background(), translations.FindManyQuery{
+			Language:  translation.Language,
+			EntityIDs: []uuid.UUID{translation.EntityID},
+		})
+		require.NoError(t, err, "Finding translations")
+		assert.Equal(t, []translations.Translation{translation}, got, "Comparing with expected translations")
+		assert.NoError(t, mock.ExpectationsWereMet(), "Not all SQL expectations were met")
+	})
+}
@@ -0,0 +1,210 @@
+package seeder
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+)
+
+// Changes lists the battles, factions and commanders that were added or removed between two
+// scrapes, which are told apart by their WikiIDs, together with the battles whose details changed
+type Changes struct {
+	Battles    BattleChanges
+	Factions   EntityChanges
+	Commanders EntityChanges
+}
+
+// BattleChanges lists the battles that were added or removed between two scrapes, and those that
+// are in both of them but whose details changed, sorted by their WikiIDs
+type BattleChanges struct {
+	EntityChanges
+	Changed []BattleChange
+}
+
+// BattleChange lists the details that changed for a battle that is in two scrapes
+type BattleChange struct {
+	Entry
+	Fields []FieldChange
+}
+
+// FieldChange is the previous and current value of a detail of a battle, such as "Result" or
+// "Strength.A". Sides are written as the names of their factions or commanders, followed by their
+// WikiIDs
+type FieldChange struct {
+	Field    string
+	Previous string
+	Current  string
+}
+
+// EntityChanges lists the entities of one kind that were added or removed between two scrapes,
+// sorted by their WikiIDs
+type EntityChanges struct {
+	Added   []Entry
+	Removed []Entry
+}
+
+// Entry identifies a scraped battle, faction or commander
+type Entry struct {
+	WikiID int
+	Name   string
+	URL    string
+}
+
+// Empty returns whether nothing was added, removed or changed
+func (c Changes) Empty() bool {
+	for _, ec := range []EntityChanges{c.Battles.EntityChanges, c.Factions, c.Commanders} {
+		if len(ec.Added) > 0 || len(ec.Removed) > 0 {
+			return false
+		}
+	}
+	return len(c.Battles.Changed) == 0
+}
+
+// Compare returns the battles, factions and commanders that are in current but not in previous, and
+// those that are in previous but not in current. Battles that are in both of them are compared by
+// their dates, results, sides, strength and casualties
+func Compare(previous, current *ImportedData) Changes {
+	return Changes{
+		Battles: BattleChanges{
+			EntityChanges: compareEntries(battleEntries(previous), battleEntries(current)),
+			Changed:       compareBattles(previous, current),
+		},
+		Factions:   compareEntries(actorEntries(previous.WikiFactionsByID), actorEntries(current.WikiFactionsByID)),
+		Commanders: compareEntries(actorEntries(previous.WikiCommandersByID), actorEntries(current.WikiCommandersByID)),
+	}
+}
+
+func battleEntries(data *ImportedData) map[int]Entry {
+	entries := make(map[int]Entry, len(data.WikiBattlesByID))
+	for _, wb := range data.WikiBattlesByID {
+		entries[wb.ID] = Entry{WikiID: wb.ID, Name: wb.Name, URL: wb.URL}
+	}
+	return entries
+}
+
+func actorEntries(actorsByID map[string]wikiactors.Actor) map[int]Entry {
+	entries := make(map[int]Entry, len(actorsByID))
+	for _, a := range actorsByID {
+		entries[a.ID] = Entry{WikiID: a.ID, Name: a.Name, URL: a.URL}
+	}
+	return entries
+}
+
+func compareEntries(previous, current map[int]Entry) EntityChanges {
+	var changes EntityChanges
+	for id, e := range current {
+		if _, ok := previous[id]; !ok {
+			changes.Added = append(changes.Added, e)
+		}
+	}
+	for id, e := range previous {
+		if _, ok := current[id]; !ok {
+			changes.Removed = append(changes.Removed, e)
+		}
+	}
+	sortEntries(changes.Added)
+	sortEntries(changes.Removed)
+	return changes
+}
+
+func compareBattles(previous, current *ImportedData) []BattleChange {
+	previousByID := make(map[int]wikibattles.Battle, len(previous.WikiBattlesByID))
+	for _, wb := range previous.WikiBattlesByID {
+		previousByID[wb.ID] = wb
+	}
+	var changes []BattleChange
+	for _, wb := range current.WikiBattlesByID {
+		pwb, ok := previousByID[wb.ID]
+		if !ok {
+			continue
+		}
+		var fields fieldChanges
+		fields.compare("Date", pwb.Date, wb.Date)
+		fields.compare("Result", pwb.Result, wb.Result)
+		fields.compareSide("Factions.A", pwb.Factions.A, wb.Factions.A, previous.WikiFactionsByID, current.WikiFactionsByID)
+		fields.compareSide("Factions.B", pwb.Factions.B, wb.Factions.B, previous.WikiFactionsByID, current.WikiFactionsByID)
+		fields.compareSide("Commanders.A", pwb.Commanders.A, wb.Commanders.A, previous.WikiCommandersByID, current.WikiCommandersByID)
+		fields.compareSide("Commanders.B", pwb.Commanders.B, wb.Commanders.B, previous.WikiCommandersByID, current.WikiCommandersByID)
+		fields.compare("Strength.A", pwb.Strength.A, wb.Strength.A)
+		fields.compare("Strength.B", pwb.Strength.B, wb.Strength.B)
+		fields.compare("Strength.AB", pwb.Strength.AB, wb.Strength.AB)
+		fields.compare("Casualties.A", pwb.Casualties.A, wb.Casualties.A)
+		fields.compare("Casualties.B", pwb.Casualties.B, wb.Casualties.B)
+		fields.compare("Casualties.AB", pwb.Casualties.AB, wb.Casualties.AB)
+		if len(fields) > 0 {
+			changes = append(changes, BattleChange{
+				Entry:  Entry{WikiID: wb.ID, Name: wb.Name, URL: wb.URL},
+				Fields: fields,
+			})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].WikiID < changes[j].WikiID
+	})
+	return changes
+}
+
+// fieldChanges collects the details that changed for a battle that is in two scrapes
+type fieldChanges []FieldChange
+
+// compare adds the given field when its previous and current values differ
+func (fc *fieldChanges) compare(field, previous, current string) {
+	if previous != current {
+		*fc = append(*fc, FieldChange{Field: field, Previous: previous, Current: current})
+	}
+}
+
+// compareSide adds the given field when the actors of a side of the battle changed, regardless of
+// the order in which they were scraped
+func (fc *fieldChanges) compareSide(field string, previousIDs, currentIDs []int, previousActors, currentActors map[string]wikiactors.Actor) {
+	previousIDs, currentIDs = sortedIDs(previousIDs), sortedIDs(currentIDs)
+	if !equalIDs(previousIDs, currentIDs) {
+		fc.compare(field, sideNames(previousIDs, previousActors), sideNames(currentIDs, currentActors))
+	}
+}
+
+// equalIDs returns whether both slices contain the same WikiIDs in the same order
+func equalIDs(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// sortedIDs returns a sorted copy of the WikiIDs of the actors of a side of a battle, so that sides
+// are compared regardless of the order in which they were scraped
+func sortedIDs(ids []int) []int {
+	sorted := append([]int(nil), ids...)
+	sort.Ints(sorted)
+	return sorted
+}
+
+// sideNames writes the actors of a side of a battle as their names followed by their WikiIDs, or as
+// their WikiIDs alone when they are not found
+func sideNames(ids []int, actorsByID map[string]wikiactors.Actor) string {
+	names := make([]string, 0, len(ids))
+	for _, id := range ids {
+		key := strconv.Itoa(id)
+		if a, ok := actorsByID[key]; ok {
+			names = append(names, fmt.Sprintf("%s (%d)", a.Name, id))
+		} else {
+			names = append(names, key)
+		}
+	}
+	return strings.Join(names, "; ")
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].WikiID < entries[j].WikiID
+	})
+}
@@ -0,0 +1,75 @@
+package seeder_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompare(t *testing.T) {
+	previousBattle := mocks.WikiBattle()
+	previous := seeder.ImportedData{
+		WikiBattlesByID: map[string]wikibattles.Battle{
+			strconv.Itoa(previousBattle.ID): previousBattle,
+		},
+		WikiFactionsByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiFaction().ID):  mocks.WikiFaction(),
+			strconv.Itoa(mocks.WikiFaction2().ID): mocks.WikiFaction2(),
+			strconv.Itoa(mocks.WikiFaction3().ID): mocks.WikiFaction3(),
+		},
+		WikiCommandersByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiCommander().ID):  mocks.WikiCommander(),
+			strconv.Itoa(mocks.WikiCommander2().ID): mocks.WikiCommander2(),
+		},
+	}
+
+	currentBattle := mocks.WikiBattle()
+	currentBattle.Result = "French victory"
+	currentBattle.Strength.B = "85,000"
+	currentBattle.Factions.B = []int{mocks.WikiFaction3().ID, mocks.WikiFaction2().ID}
+	currentBattle.Commanders.A = nil
+	addedBattle := mocks.WikiBattle()
+	addedBattle.ID = 1
+	addedBattle.Name = "Battle of Ulm"
+	addedBattle.URL = "https://en.wikipedia.org/wiki/Battle_of_Ulm"
+	current := seeder.ImportedData{
+		WikiBattlesByID: map[string]wikibattles.Battle{
+			strconv.Itoa(currentBattle.ID): currentBattle,
+			strconv.Itoa(addedBattle.ID):   addedBattle,
+		},
+		WikiFactionsByID: previous.WikiFactionsByID,
+		WikiCommandersByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiCommander2().ID): mocks.WikiCommander2(),
+		},
+	}
+
+	changes := seeder.Compare(&previous, &current)
+	assert.Equal(t, []seeder.Entry{{WikiID: 1, Name: addedBattle.Name, URL: addedBattle.URL}}, changes.Battles.Added)
+	assert.Empty(t, changes.Battles.Removed)
+	assert.Empty(t, changes.Factions.Added)
+	assert.Empty(t, changes.Factions.Removed)
+	assert.Empty(t, changes.Commanders.Added)
+	assert.Equal(t, []seeder.Entry{{
+		WikiID: mocks.WikiCommander().ID,
+		Name:   mocks.WikiCommander().Name,
+		URL:    mocks.WikiCommander().URL,
+	}}, changes.Commanders.Removed)
+
+	commanderA := mocks.WikiCommander().Name + " (" + strconv.Itoa(mocks.WikiCommander().ID) + ")"
+	assert.Equal(t, []seeder.BattleChange{{
+		Entry: seeder.Entry{WikiID: currentBattle.ID, Name: currentBattle.Name, URL: currentBattle.URL},
+		Fields: []seeder.FieldChange{
+			{Field: "Result", Previous: previousBattle.Result, Current: "French victory"},
+			{Field: "Commanders.A", Previous: commanderA, Current: ""},
+			{Field: "Strength.B", Previous: previousBattle.Strength.B, Current: "85,000"},
+		},
+	}}, changes.Battles.Changed, "Should not report sides whose actors were reordered")
+	assert.False(t, changes.Empty())
+
+	assert.True(t, seeder.Compare(&current, &current).Empty())
+}
@@ -0,0 +1,36 @@
+package seeder
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/io/json"
+)
+
+// ImportMerges reads the reviewed merges of duplicate actors stored in the given file. No merges
+// are returned if the file does not exist, as reviewing duplicates is optional
+func ImportMerges(fileName string) ([]wikiactors.Merge, error) {
+	merges := []wikiactors.Merge{}
+	if _, err := os.Stat(fileName); err != nil {
+		return merges, nil
+	}
+	if err := json.Import(fileName, &merges); err != nil {
+		return nil, errors.Wrap(err, "Importing actor merges")
+	}
+	return merges, nil
+}
+
+// ImportRelationships reads the faction relationships stored in the given file. No relationships
+// are returned if the file does not exist, as relating factions is optional
+func ImportRelationships(fileName string) ([]factions.RelationshipMapping, error) {
+	mappings := []factions.RelationshipMapping{}
+	if _, err := os.Stat(fileName); err != nil {
+		return mappings, nil
+	}
+	if err := json.Import(fileName, &mappings); err != nil {
+		return nil, errors.Wrap(err, "Importing faction relationships")
+	}
+	return mappings, nil
+}
@@ -0,0 +1,55 @@
+package seeder
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/pkg/logger"
+)
+
+// FlagDownloader is the interface through which the images of flags may be stored locally
+type FlagDownloader interface {
+	Download(path string) (string, error)
+}
+
+// DownloadFlags stores the images of all the flags found in the ImportedData, including those
+// under which factions fought in each battle. Flags that could not be downloaded are logged and
+// skipped, in which case they are served from Wikimedia Commons instead
+func DownloadFlags(data *ImportedData, d FlagDownloader, l logger.Interface) {
+	log := logger.From(l).Named("seeder")
+	set := make(map[string]bool)
+	for _, wf := range data.WikiFactionsByID {
+		if wf.Flag != "" {
+			set[wf.Flag] = true
+		}
+	}
+	for _, wb := range data.WikiBattlesByID {
+		for _, flag := range wb.Flags {
+			set[flag] = true
+		}
+	}
+	paths := make([]string, 0, len(set))
+	for path := range set {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	downloaded := 0
+	start := time.Now()
+	for _, path := range paths {
+		flagLog := log.With(logger.KV("flag", path))
+		flagLog.Debug("Downloading flag")
+		if _, err := d.Download(path); err != nil {
+			flagLog.Error(errors.Wrap(err, "Error downloading flag"))
+			continue
+		}
+		downloaded++
+	}
+	observe("flags", downloaded, len(paths), start)
+	log.With(
+		logger.KV("downloaded", downloaded),
+		logger.KV("total", len(paths)),
+		logger.KV("duration_ms", time.Since(start).Milliseconds()),
+	).Info("Finished downloading flags")
+}
@@ -0,0 +1,37 @@
+package seeder_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/logger"
+)
+
+func TestDownloadFlags(t *testing.T) {
+	const battleFlag = "/thumb/6/62/Flag_of_France_%281794%E2%80%931815%2C_1830%E2%80%931958%29.svg/23px-Flag.svg.png"
+	wb := mocks.WikiBattle()
+	wb.Flags[mocks.WikiFaction2().ID] = battleFlag
+	importedData := seeder.ImportedData{
+		WikiBattlesByID: map[string]wikibattles.Battle{
+			strconv.Itoa(wb.ID): wb,
+		},
+		WikiFactionsByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiFaction().ID):  mocks.WikiFaction(),
+			strconv.Itoa(mocks.WikiFaction2().ID): mocks.WikiFaction2(),
+		},
+	}
+
+	d := new(mocks.FlagDownloader)
+	d.On("Download", mocks.WikiFaction().Flag).Return("flags/Flag_of_France.svg", nil)
+	d.On("Download", battleFlag).Return("", errors.New("Not found"))
+
+	seeder.DownloadFlags(&importedData, d, logger.New(ioutil.Discard, ioutil.Discard))
+	d.AssertExpectations(t)
+	d.AssertNumberOfCalls(t, "Download", 2)
+}
@@ -0,0 +1,138 @@
+package seeder
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/logger"
+)
+
+// aliasesMap maps the WikiIDs of canonical actors to the duplicates that were merged into them
+type aliasesMap map[int][]wikiactors.Actor
+
+// Merge applies reviewed merges of duplicate actors to the ImportedData before it is seeded. Each
+// duplicate is removed, its occurrences in battles are replaced by the canonical actor, and it is
+// kept so that its WikiID and URL are seeded as aliases of the canonical one. Merges referring to
+// actors that were not scraped are logged and skipped, as these may have changed since reviewed.
+// Listing the canonical actor among its own duplicates has no effect
+func Merge(data *ImportedData, merges []wikiactors.Merge, l logger.Interface) {
+	log := logger.From(l).Named("seeder")
+	if data.aliases == nil {
+		data.aliases = map[wikiactors.Kind]aliasesMap{
+			wikiactors.FactionKind:   make(aliasesMap),
+			wikiactors.CommanderKind: make(aliasesMap),
+		}
+	}
+	for _, m := range merges {
+		actors := data.actors(m.Kind)
+		if actors == nil {
+			log.With(logger.KV("wikiID", m.Canonical)).Error(
+				fmt.Errorf("Unknown kind %d of actors to merge into %d", m.Kind, m.Canonical),
+			)
+			continue
+		}
+		canonical, ok := actors[strconv.Itoa(m.Canonical)]
+		if !ok {
+			log.With(logger.KV("wikiID", m.Canonical)).Error(
+				fmt.Errorf("Canonical actor with WikiID %d not found", m.Canonical),
+			)
+			continue
+		}
+		for _, duplicateID := range m.Duplicates {
+			if duplicateID == m.Canonical {
+				continue
+			}
+			duplicate, ok := actors[strconv.Itoa(duplicateID)]
+			if !ok {
+				log.With(logger.KV("wikiID", duplicateID)).Error(
+					fmt.Errorf("Duplicate actor with WikiID %d not found", duplicateID),
+				)
+				continue
+			}
+			for language, l := range duplicate.Localizations {
+				if _, translated := canonical.Localizations[language]; !translated {
+					if canonical.Localizations == nil {
+						canonical.Localizations = make(map[string]wikiactors.Localization)
+					}
+					canonical.Localizations[language] = l
+				}
+			}
+			aliases := data.aliases[m.Kind]
+			aliases[m.Canonical] = append(aliases[m.Canonical], duplicate)
+			aliases[m.Canonical] = append(aliases[m.Canonical], aliases[duplicateID]...)
+			delete(aliases, duplicateID)
+			delete(actors, strconv.Itoa(duplicateID))
+			data.replaceInBattles(m.Kind, duplicateID, m.Canonical)
+		}
+		actors[strconv.Itoa(m.Canonical)] = canonical
+	}
+}
+
+func (d *ImportedData) actors(kind wikiactors.Kind) map[string]wikiactors.Actor {
+	switch kind {
+	case wikiactors.FactionKind:
+		return d.WikiFactionsByID
+	case wikiactors.CommanderKind:
+		return d.WikiCommandersByID
+	default:
+		return nil
+	}
+}
+
+// replaceInBattles replaces the WikiID of a duplicate actor by that of its canonical one in all of
+// the battles in which it took part
+func (d *ImportedData) replaceInBattles(kind wikiactors.Kind, from, to int) {
+	for key, wb := range d.WikiBattlesByID {
+		if kind == wikiactors.FactionKind {
+			wb.Factions.A = replaceWikiID(wb.Factions.A, from, to)
+			wb.Factions.B = replaceWikiID(wb.Factions.B, from, to)
+			if cIDs, ok := wb.CommandersByFaction[from]; ok {
+				delete(wb.CommandersByFaction, from)
+				wb.CommandersByFaction[to] = unique(append(wb.CommandersByFaction[to], cIDs...))
+			}
+			if flag, ok := wb.Flags[from]; ok {
+				delete(wb.Flags, from)
+				if _, saved := wb.Flags[to]; !saved {
+					wb.Flags[to] = flag
+				}
+			}
+		} else {
+			wb.Commanders.A = replaceWikiID(wb.Commanders.A, from, to)
+			wb.Commanders.B = replaceWikiID(wb.Commanders.B, from, to)
+			for fID, cIDs := range wb.CommandersByFaction {
+				wb.CommandersByFaction[fID] = replaceWikiID(cIDs, from, to)
+			}
+			if confidence, ok := wb.CommanderConfidences[from]; ok {
+				delete(wb.CommanderConfidences, from)
+				if _, saved := wb.CommanderConfidences[to]; !saved {
+					wb.CommanderConfidences[to] = confidence
+				}
+			}
+		}
+		d.WikiBattlesByID[key] = wb
+	}
+}
+
+func replaceWikiID(ids []int, from, to int) []int {
+	res := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if id == from {
+			id = to
+		}
+		res = append(res, id)
+	}
+	return unique(res)
+}
+
+func unique(ids []int) []int {
+	seen := make(map[int]bool)
+	res := []int{}
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			res = append(res, id)
+		}
+	}
+	return res
+}
@@ -0,0 +1,109 @@
+package seeder_test
+
+import (
+	"io/ioutil"
+	"strconv"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMerge(t *testing.T) {
+	duplicateFaction := mocks.WikiFaction()
+	duplicateFaction.ID = 1
+	duplicateFaction.URL = "https://en.wikipedia.org/wiki/Napoleonic_Empire"
+	duplicateCommander := mocks.WikiCommander()
+	duplicateCommander.ID = 2
+	duplicateCommander.URL = "https://en.wikipedia.org/wiki/Napoleon_Bonaparte"
+	duplicateCommander.Localizations = map[string]wikiactors.Localization{
+		"fr": {Name: "Napoléon Ier"},
+	}
+
+	wb := mocks.WikiBattle()
+	wb.Factions.A = append(wb.Factions.A, duplicateFaction.ID)
+	wb.Commanders.A = append(wb.Commanders.A, duplicateCommander.ID)
+	wb.CommandersByFaction[duplicateFaction.ID] = []int{duplicateCommander.ID}
+
+	importedData := seeder.ImportedData{
+		WikiBattlesByID: map[string]wikibattles.Battle{
+			strconv.Itoa(wb.ID): wb,
+		},
+		WikiFactionsByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiFaction().ID):  mocks.WikiFaction(),
+			strconv.Itoa(mocks.WikiFaction2().ID): mocks.WikiFaction2(),
+			strconv.Itoa(mocks.WikiFaction3().ID): mocks.WikiFaction3(),
+			strconv.Itoa(duplicateFaction.ID):     duplicateFaction,
+		},
+		WikiCommandersByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiCommander().ID):  mocks.WikiCommander(),
+			strconv.Itoa(mocks.WikiCommander2().ID): mocks.WikiCommander2(),
+			strconv.Itoa(mocks.WikiCommander3().ID): mocks.WikiCommander3(),
+			strconv.Itoa(mocks.WikiCommander4().ID): mocks.WikiCommander4(),
+			strconv.Itoa(mocks.WikiCommander5().ID): mocks.WikiCommander5(),
+			strconv.Itoa(duplicateCommander.ID):     duplicateCommander,
+		},
+	}
+	merges := []wikiactors.Merge{
+		{Kind: wikiactors.FactionKind, Canonical: mocks.WikiFaction().ID, Duplicates: []int{mocks.WikiFaction().ID, duplicateFaction.ID}},
+		{Kind: wikiactors.CommanderKind, Canonical: mocks.WikiCommander().ID, Duplicates: []int{duplicateCommander.ID}},
+		{Kind: wikiactors.CommanderKind, Canonical: 3, Duplicates: []int{mocks.WikiCommander2().ID}},
+	}
+
+	seeder.Merge(&importedData, merges, logger.New(ioutil.Discard, ioutil.Discard))
+	assert.Equal(t, mocks.WikiBattle(), importedData.WikiBattlesByID[strconv.Itoa(wb.ID)], "Battles should refer to canonical actors")
+	assert.Len(t, importedData.WikiFactionsByID, 3, "Duplicate factions should be removed")
+	assert.Len(t, importedData.WikiCommandersByID, 5, "Duplicate commanders should be removed")
+
+	canonicalCommander := importedData.WikiCommandersByID[strconv.Itoa(mocks.WikiCommander().ID)]
+	assert.Equal(t, duplicateCommander.Localizations["fr"], canonicalCommander.Localizations["fr"], "Missing localizations should be taken from duplicates")
+	assert.Equal(t, mocks.WikiCommander().Localizations["es"], canonicalCommander.Localizations["es"], "Existing localizations should be kept")
+
+	fr := new(mocks.FactionsRepository)
+	fr.On("CreateOne", mocks.FactionCreationInput()).Return(mocks.Faction().ID, nil)
+	fr.On("CreateOne", mocks.FactionCreationInput2()).Return(mocks.Faction2().ID, nil)
+	fr.On("CreateOne", mocks.FactionCreationInput3()).Return(mocks.Faction3().ID, nil)
+	fr.On("CreateAlias", factions.AliasCreationInput{
+		FactionID: mocks.Faction().ID,
+		WikiID:    duplicateFaction.ID,
+		URL:       duplicateFaction.URL,
+	}).Return(nil)
+
+	cr := new(mocks.CommandersRepository)
+	cr.On("CreateOne", mocks.CommanderCreationInput()).Return(mocks.Commander().ID, nil)
+	cr.On("CreateOne", mocks.CommanderCreationInput2()).Return(mocks.Commander2().ID, nil)
+	cr.On("CreateOne", mocks.CommanderCreationInput3()).Return(mocks.Commander3().ID, nil)
+	cr.On("CreateOne", mocks.CommanderCreationInput4()).Return(mocks.Commander4().ID, nil)
+	cr.On("CreateOne", mocks.CommanderCreationInput5()).Return(mocks.Commander5().ID, nil)
+	cr.On("CreateAlias", commanders.AliasCreationInput{
+		CommanderID: mocks.Commander().ID,
+		WikiID:      duplicateCommander.ID,
+		URL:         duplicateCommander.URL,
+	}).Return(nil)
+
+	br := new(mocks.BattlesRepository)
+	br.On("CreateOne", mocks.BattleCreationInput()).Return(mocks.Battle().ID, nil)
+
+	tr := new(mocks.TranslationsRepository)
+	tr.On("CreateOne", mocks.TranslationCreationInput(mocks.CommanderTranslation())).Return(uuid.NewV4(), nil)
+	tr.On("CreateOne", mocks.TranslationCreationInput(mocks.BattleTranslation())).Return(uuid.NewV4(), nil)
+	tr.On("CreateOne", translations.CreationInput{
+		EntityID: mocks.Commander().ID,
+		Language: "fr",
+		Name:     duplicateCommander.Localizations["fr"].Name,
+	}).Return(uuid.NewV4(), nil)
+
+	seeder.Seed(&importedData, fr, cr, br, tr, logger.New(ioutil.Discard, ioutil.Discard))
+	fr.AssertExpectations(t)
+	cr.AssertExpectations(t)
+	br.AssertExpectations(t)
+	tr.AssertExpectations(t)
+}
@@ -0,0 +1,49 @@
+package seeder
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/pkg/logger"
+)
+
+// Relate stores the relationships between factions described by the given mappings, which are
+// maintained by hand. Factions are found by the URLs of their Wikipedia articles, so they must have
+// been seeded beforehand. Mappings whose factions can not be found are logged and skipped
+func Relate(mappings []factions.RelationshipMapping, r factions.Repository, l logger.Interface) {
+	log := logger.From(l).Named("seeder")
+	related := 0
+	start := time.Now()
+	for _, m := range mappings {
+		mappingLog := log.With(logger.KV("url", m.Faction), logger.KV("related_url", m.Related))
+		mappingLog.Debug("Relating factions")
+		faction, err := r.FindOne(context.Background(), factions.FindOneQuery{URL: m.Faction})
+		if err != nil {
+			mappingLog.Error(errors.Wrap(err, "Error finding faction"))
+			continue
+		}
+		relatedFaction, err := r.FindOne(context.Background(), factions.FindOneQuery{URL: m.Related})
+		if err != nil {
+			mappingLog.Error(errors.Wrap(err, "Error finding related faction"))
+			continue
+		}
+		input := factions.RelationshipCreationInput{
+			FactionID: faction.ID,
+			Kind:      m.Kind,
+			RelatedID: relatedFaction.ID,
+		}
+		if err := r.CreateRelationship(input); err != nil {
+			mappingLog.Error(errors.Wrap(err, "Error relating factions"))
+			continue
+		}
+		related++
+	}
+	observe("relationships", related, len(mappings), start)
+	log.With(
+		logger.KV("related", related),
+		logger.KV("total", len(mappings)),
+		logger.KV("duration_ms", time.Since(start).Milliseconds()),
+	).Info("Finished relating factions")
+}
@@ -0,0 +1,36 @@
+package seeder_test
+
+import (
+	"io/ioutil"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRelate(t *testing.T) {
+	const unknownURL = "https://en.wikipedia.org/wiki/Unknown"
+	mappings := []factions.RelationshipMapping{
+		{Faction: mocks.Faction().URL, Kind: factions.PredecessorKind, Related: mocks.Faction2().URL},
+		{Faction: mocks.Faction3().URL, Kind: factions.ParentKind, Related: unknownURL},
+	}
+
+	fr := new(mocks.FactionsRepository)
+	for _, f := range []factions.Faction{mocks.Faction(), mocks.Faction2(), mocks.Faction3()} {
+		fr.On("FindOne", mock.Anything, factions.FindOneQuery{URL: f.URL}).Return(f, nil)
+	}
+	fr.On("FindOne", mock.Anything, factions.FindOneQuery{URL: unknownURL}).Return(factions.Faction{}, domain.ErrNotFound)
+	fr.On("CreateRelationship", factions.RelationshipCreationInput{
+		FactionID: mocks.Faction().ID,
+		Kind:      factions.PredecessorKind,
+		RelatedID: mocks.Faction2().ID,
+	}).Return(nil)
+
+	seeder.Relate(mappings, fr, logger.New(ioutil.Discard, ioutil.Discard))
+	fr.AssertExpectations(t)
+	fr.AssertNumberOfCalls(t, "CreateRelationship", 1)
+}
@@ -0,0 +1,328 @@
+package seeder
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	stdjson "encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/pkg/io/json"
+)
+
+// Names of the files of each release of the scraped data, which are stored together in a directory
+// named after its version
+const (
+	DataFileName      = "data.json"
+	ChangelogFileName = "CHANGELOG.md"
+	ManifestFileName  = "manifest.json"
+)
+
+// Manifest describes a release of the scraped data: which version of it this is and which one came
+// before, the format and language of its data, when it was scraped and released, how many battles,
+// factions and commanders it contains, and the SHA-256 checksums of its files
+type Manifest struct {
+	Version       string
+	Previous      string `json:",omitempty"`
+	SchemaVersion int
+	Language      string
+	ScrapedAt     time.Time
+	ReleasedAt    time.Time
+	Counts        Counts
+	Checksums     map[string]string
+}
+
+// Counts are the number of battles, factions and commanders in a release
+type Counts struct {
+	Battles    int
+	Factions   int
+	Commanders int
+}
+
+var versionMatcher = regexp.MustCompile(`^[\w.-]+$`)
+
+// CheckSchemaVersion returns an error if the data was exported in a format that the seeder does not
+// understand. Data exported before formats were versioned is read as the first version of them
+func (d *ImportedData) CheckSchemaVersion() error {
+	if d.SchemaVersion != 0 && d.SchemaVersion != wikibattles.SchemaVersion {
+		return errors.Errorf("Unknown schema version %d, must be %d", d.SchemaVersion, wikibattles.SchemaVersion)
+	}
+	return nil
+}
+
+// CheckSchemaVersionOf is like CheckSchemaVersion, but for the data stored in dataFileName. Its
+// schema version is exported before anything else, so the rest of the data is not read, and
+// unknown formats are rejected before spending time and memory on importing them
+func CheckSchemaVersionOf(dataFileName string) error {
+	f, err := os.Open(dataFileName)
+	if err != nil {
+		return errors.Wrapf(err, "Opening file %s", dataFileName)
+	}
+	defer f.Close()
+
+	decoder := stdjson.NewDecoder(bufio.NewReader(f))
+	if _, err := decoder.Token(); err != nil {
+		return errors.Wrapf(err, "Reading file %s", dataFileName)
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return errors.Wrapf(err, "Reading file %s", dataFileName)
+		}
+		if key == "SchemaVersion" {
+			data := new(ImportedData)
+			if err := decoder.Decode(&data.SchemaVersion); err != nil {
+				return errors.Wrapf(err, "Reading the schema version in %s", dataFileName)
+			}
+			return data.CheckSchemaVersion()
+		}
+		var skipped stdjson.RawMessage
+		if err := decoder.Decode(&skipped); err != nil {
+			return errors.Wrapf(err, "Reading file %s", dataFileName)
+		}
+	}
+	return nil
+}
+
+// Release packages the data stored in dataFileName as a new release inside releasesDir, with the
+// given version, or with the date in which the data was scraped (such as "2020.10.19") when empty.
+// Releases of data scraped on the same date are numbered after the first one (such as
+// "2020.10.19.2"). Its changelog lists what changed since the latest release in releasesDir, if
+// any. Its files are written into a temporary directory that is only renamed after the version
+// once all of them have been written, so that failed releases leave nothing behind
+func Release(dataFileName, releasesDir, version string) (Manifest, error) {
+	if err := CheckSchemaVersionOf(dataFileName); err != nil {
+		return Manifest{}, err
+	}
+	data := new(ImportedData)
+	if err := json.Import(dataFileName, data); err != nil {
+		return Manifest{}, errors.Wrap(err, "Importing data")
+	}
+	contents, err := ioutil.ReadFile(dataFileName)
+	if err != nil {
+		return Manifest{}, errors.Wrapf(err, "Reading file %s", dataFileName)
+	}
+
+	if version == "" && data.ScrapedAt.IsZero() {
+		return Manifest{}, errors.New("A version is required for data that does not state when it was scraped")
+	}
+	if version == "" {
+		version = nextDateVersion(releasesDir, data.ScrapedAt)
+	}
+	if !versionMatcher.MatchString(version) {
+		return Manifest{}, errors.Errorf("Invalid version %q, must only contain letters, digits, dots, dashes and underscores", version)
+	}
+	dir := filepath.Join(releasesDir, version)
+	if _, err := os.Stat(dir); err == nil {
+		return Manifest{}, errors.Errorf("Release %s already exists", version)
+	}
+
+	previous, err := LatestRelease(releasesDir)
+	if err != nil {
+		return Manifest{}, err
+	}
+	var changes Changes
+	if previous != nil {
+		previousData := new(ImportedData)
+		if err := json.Import(filepath.Join(releasesDir, previous.Version, DataFileName), previousData); err != nil {
+			return Manifest{}, errors.Wrapf(err, "Importing release %s", previous.Version)
+		}
+		changes = Compare(previousData, data)
+	}
+
+	m := Manifest{
+		Version:       version,
+		SchemaVersion: wikibattles.SchemaVersion,
+		Language:      "en",
+		ScrapedAt:     data.ScrapedAt,
+		ReleasedAt:    time.Now().UTC(),
+		Counts:        countsOf(data),
+		Checksums:     make(map[string]string),
+	}
+	if data.Language != "" {
+		m.Language = data.Language
+	}
+	if previous != nil {
+		m.Previous = previous.Version
+	}
+	changelog := []byte(Changelog(m, previous, changes))
+
+	if err := os.MkdirAll(releasesDir, 0755); err != nil {
+		return Manifest{}, errors.Wrapf(err, "Creating %s", releasesDir)
+	}
+	tmpDir, err := ioutil.TempDir(releasesDir, "."+version+".")
+	if err != nil {
+		return Manifest{}, errors.Wrapf(err, "Creating a temporary directory inside %s", releasesDir)
+	}
+	defer os.RemoveAll(tmpDir)
+	for name, contents := range map[string][]byte{DataFileName: contents, ChangelogFileName: changelog} {
+		if err := ioutil.WriteFile(filepath.Join(tmpDir, name), contents, 0644); err != nil {
+			return Manifest{}, errors.Wrapf(err, "Writing %s", name)
+		}
+		m.Checksums[name] = checksum(contents)
+	}
+	if err := json.Export(filepath.Join(tmpDir, ManifestFileName), m); err != nil {
+		return Manifest{}, err
+	}
+	if err := os.Chmod(tmpDir, 0755); err != nil {
+		return Manifest{}, errors.Wrapf(err, "Changing the permissions of %s", tmpDir)
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return Manifest{}, errors.Wrapf(err, "Moving release %s into place", version)
+	}
+	return m, nil
+}
+
+// nextDateVersion returns the version of the next release of data scraped at the given time, which
+// is its date, followed by how many releases of data scraped on the same date there would be
+// (starting from 2) when there already are some in releasesDir
+func nextDateVersion(releasesDir string, scrapedAt time.Time) string {
+	date := scrapedAt.Format("2006.01.02")
+	version := date
+	for n := 2; ; n++ {
+		if _, err := os.Stat(filepath.Join(releasesDir, version)); err != nil {
+			return version
+		}
+		version = fmt.Sprintf("%s.%d", date, n)
+	}
+}
+
+// LatestRelease returns the manifest of the latest release inside releasesDir, or nil if there are
+// none yet. Hidden directories, such as those of releases still being written, are skipped
+func LatestRelease(releasesDir string) (*Manifest, error) {
+	entries, err := ioutil.ReadDir(releasesDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "Reading %s", releasesDir)
+	}
+	var latest *Manifest
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), ".") {
+			continue
+		}
+		fileName := filepath.Join(releasesDir, e.Name(), ManifestFileName)
+		if _, err := os.Stat(fileName); !e.IsDir() || err != nil {
+			continue
+		}
+		m, err := ImportManifest(fileName)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil || m.ReleasedAt.After(latest.ReleasedAt) {
+			latest = &m
+		}
+	}
+	return latest, nil
+}
+
+// ImportManifest reads the manifest of a release stored in the given file
+func ImportManifest(fileName string) (Manifest, error) {
+	var m Manifest
+	if err := json.Import(fileName, &m); err != nil {
+		return Manifest{}, errors.Wrap(err, "Importing manifest")
+	}
+	return m, nil
+}
+
+// Verify returns an error if the data stored in dataFileName is not the one of the release, given
+// that its checksum does not match the one in the manifest, or if the seeder does not understand
+// its format
+func (m Manifest) Verify(dataFileName string) error {
+	if m.SchemaVersion != wikibattles.SchemaVersion {
+		return errors.Errorf("Unknown schema version %d in release %s, must be %d", m.SchemaVersion, m.Version, wikibattles.SchemaVersion)
+	}
+	contents, err := ioutil.ReadFile(dataFileName)
+	if err != nil {
+		return errors.Wrapf(err, "Reading file %s", dataFileName)
+	}
+	if expected := m.Checksums[DataFileName]; checksum(contents) != expected {
+		return errors.Errorf("Checksum of %s does not match the one of release %s (%s)", dataFileName, m.Version, expected)
+	}
+	return nil
+}
+
+// Changelog describes the release of the given manifest in Markdown, listing the battles,
+// factions and commanders that were added or removed since the previous one, if any, and the
+// details that changed for the rest of its battles
+func Changelog(m Manifest, previous *Manifest, c Changes) string {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "# %s\n\n", m.Version)
+	fmt.Fprintf(&b, "Scraped from the %q edition of Wikipedia", m.Language)
+	if !m.ScrapedAt.IsZero() {
+		fmt.Fprintf(&b, " at %s", m.ScrapedAt.Format(time.RFC3339))
+	}
+	fmt.Fprintf(&b, ", with %d battles, %d factions and %d commanders.\n", m.Counts.Battles, m.Counts.Factions, m.Counts.Commanders)
+	if previous == nil {
+		b.WriteString("\nThis is the first release.\n")
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "\nChanges since %s:\n\n", previous.Version)
+	b.WriteString("| | " + previous.Version + " | " + m.Version + " |\n|---|---:|---:|\n")
+	fmt.Fprintf(&b, "| Battles | %d | %d |\n", previous.Counts.Battles, m.Counts.Battles)
+	fmt.Fprintf(&b, "| Factions | %d | %d |\n", previous.Counts.Factions, m.Counts.Factions)
+	fmt.Fprintf(&b, "| Commanders | %d | %d |\n", previous.Counts.Commanders, m.Counts.Commanders)
+	if c.Empty() {
+		b.WriteString("\nNo battles, factions or commanders were added, removed or changed.\n")
+	}
+	for _, section := range []struct {
+		title   string
+		changes EntityChanges
+	}{
+		{"Battles", c.Battles.EntityChanges},
+		{"Factions", c.Factions},
+		{"Commanders", c.Commanders},
+	} {
+		writeEntries(&b, section.title+" added", section.changes.Added)
+		writeEntries(&b, section.title+" removed", section.changes.Removed)
+	}
+	if len(c.Battles.Changed) > 0 {
+		b.WriteString("\n## Battles changed\n\n")
+		for _, bc := range c.Battles.Changed {
+			fields := make([]string, 0, len(bc.Fields))
+			for _, f := range bc.Fields {
+				fields = append(fields, f.Field)
+			}
+			fmt.Fprintf(&b, "- %s: %s\n", entryLink(bc.Entry), strings.Join(fields, ", "))
+		}
+	}
+	return b.String()
+}
+
+func writeEntries(b *bytes.Buffer, title string, entries []Entry) {
+	if len(entries) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n## %s\n\n", title)
+	for _, e := range entries {
+		fmt.Fprintf(b, "- %s\n", entryLink(e))
+	}
+}
+
+func entryLink(e Entry) string {
+	return fmt.Sprintf("[%s](%s) (%d)", strings.ReplaceAll(e.Name, "]", `\]`), e.URL, e.WikiID)
+}
+
+func countsOf(data *ImportedData) Counts {
+	return Counts{
+		Battles:    len(data.WikiBattlesByID),
+		Factions:   len(data.WikiFactionsByID),
+		Commanders: len(data.WikiCommandersByID),
+	}
+}
+
+func checksum(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
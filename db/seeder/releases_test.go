@@ -0,0 +1,163 @@
+package seeder_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/io/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReleases(t *testing.T) {
+	dir, err := ioutil.TempDir("", "releases")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	releasesDir := filepath.Join(dir, "releases")
+
+	previousData := seeder.ImportedData{
+		SchemaVersion: wikibattles.SchemaVersion,
+		ScrapedAt:     time.Date(2020, time.October, 1, 12, 0, 0, 0, time.UTC),
+		Language:      "en",
+		WikiBattlesByID: map[string]wikibattles.Battle{
+			strconv.Itoa(mocks.WikiBattle().ID): mocks.WikiBattle(),
+		},
+		WikiFactionsByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiFaction().ID):  mocks.WikiFaction(),
+			strconv.Itoa(mocks.WikiFaction2().ID): mocks.WikiFaction2(),
+		},
+		WikiCommandersByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiCommander().ID): mocks.WikiCommander(),
+		},
+	}
+	currentData := previousData
+	currentData.ScrapedAt = time.Date(2020, time.October, 19, 12, 0, 0, 0, time.UTC)
+	changedBattle := mocks.WikiBattle()
+	changedBattle.Result = "French victory"
+	currentData.WikiBattlesByID = map[string]wikibattles.Battle{
+		strconv.Itoa(changedBattle.ID): changedBattle,
+	}
+	currentData.WikiFactionsByID = map[string]wikiactors.Actor{
+		strconv.Itoa(mocks.WikiFaction().ID):  mocks.WikiFaction(),
+		strconv.Itoa(mocks.WikiFaction3().ID): mocks.WikiFaction3(),
+	}
+	previousFileName := filepath.Join(dir, "previous.json")
+	currentFileName := filepath.Join(dir, "current.json")
+	require.NoError(t, json.Export(previousFileName, previousData))
+	require.NoError(t, json.Export(currentFileName, currentData))
+
+	t.Run("Compare", func(t *testing.T) {
+		changes := seeder.Compare(&previousData, &currentData)
+		assert.Empty(t, changes.Battles.Added)
+		assert.Empty(t, changes.Battles.Removed)
+		assert.Equal(t, []seeder.Entry{{
+			WikiID: mocks.WikiFaction3().ID,
+			Name:   mocks.WikiFaction3().Name,
+			URL:    mocks.WikiFaction3().URL,
+		}}, changes.Factions.Added)
+		assert.Equal(t, []seeder.Entry{{
+			WikiID: mocks.WikiFaction2().ID,
+			Name:   mocks.WikiFaction2().Name,
+			URL:    mocks.WikiFaction2().URL,
+		}}, changes.Factions.Removed)
+		require.Len(t, changes.Battles.Changed, 1)
+		assert.Equal(t, changedBattle.ID, changes.Battles.Changed[0].WikiID)
+		assert.Equal(t, []seeder.FieldChange{
+			{Field: "Result", Previous: mocks.WikiBattle().Result, Current: "French victory"},
+		}, changes.Battles.Changed[0].Fields)
+		assert.False(t, changes.Empty())
+		assert.True(t, seeder.Compare(&currentData, &currentData).Empty())
+	})
+
+	t.Run("Release", func(t *testing.T) {
+		first, err := seeder.Release(previousFileName, releasesDir, "")
+		require.NoError(t, err, "Releasing the first version")
+		assert.Equal(t, "2020.10.01", first.Version)
+		assert.Empty(t, first.Previous)
+		assert.Equal(t, seeder.Counts{Battles: 1, Factions: 2, Commanders: 1}, first.Counts)
+
+		_, err = seeder.Release(previousFileName, releasesDir, "2020.10.01")
+		assert.EqualError(t, err, "Release 2020.10.01 already exists")
+		_, err = seeder.Release(currentFileName, releasesDir, "../v2")
+		assert.Error(t, err, "Should reject versions that are not directory names")
+
+		second, err := seeder.Release(currentFileName, releasesDir, "v2")
+		require.NoError(t, err, "Releasing the second version")
+		assert.Equal(t, "2020.10.01", second.Previous)
+		assert.Equal(t, wikibattles.SchemaVersion, second.SchemaVersion)
+		assert.Equal(t, currentData.ScrapedAt, second.ScrapedAt)
+
+		latest, err := seeder.LatestRelease(releasesDir)
+		require.NoError(t, err)
+		assert.Equal(t, "v2", latest.Version)
+
+		m, err := seeder.ImportManifest(filepath.Join(releasesDir, "v2", seeder.ManifestFileName))
+		require.NoError(t, err)
+		assert.Equal(t, second.Checksums, m.Checksums)
+		assert.NoError(t, m.Verify(filepath.Join(releasesDir, "v2", seeder.DataFileName)))
+		assert.Error(t, m.Verify(previousFileName), "Should reject data of other releases")
+
+		changelog, err := ioutil.ReadFile(filepath.Join(releasesDir, "v2", seeder.ChangelogFileName))
+		require.NoError(t, err)
+		assert.Contains(t, string(changelog), "# v2\n")
+		assert.Contains(t, string(changelog), "| Factions | 2 | 2 |\n")
+		assert.Contains(t, string(changelog), "## Factions added\n\n- ["+mocks.WikiFaction3().Name+"]")
+		assert.Contains(t, string(changelog), "## Factions removed\n\n- ["+mocks.WikiFaction2().Name+"]")
+		assert.Contains(t, string(changelog), "## Battles changed\n\n- ["+mocks.WikiBattle().Name+"]")
+		assert.Contains(t, string(changelog), "("+strconv.Itoa(mocks.WikiBattle().ID)+"): Result\n")
+		assert.NotContains(t, string(changelog), "## Battles added")
+
+		entries, err := ioutil.ReadDir(releasesDir)
+		require.NoError(t, err)
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		assert.Equal(t, []string{"2020.10.01", "v2"}, names, "Should not leave temporary directories behind")
+	})
+
+	t.Run("SameDate", func(t *testing.T) {
+		sameDateDir := filepath.Join(dir, "same-date")
+		first, err := seeder.Release(previousFileName, sameDateDir, "")
+		require.NoError(t, err, "Releasing the first version")
+		second, err := seeder.Release(previousFileName, sameDateDir, "")
+		require.NoError(t, err, "Releasing data scraped on the same date")
+		third, err := seeder.Release(previousFileName, sameDateDir, "")
+		require.NoError(t, err, "Releasing data scraped on the same date again")
+
+		assert.Equal(t, "2020.10.01", first.Version)
+		assert.Equal(t, "2020.10.01.2", second.Version)
+		assert.Equal(t, first.Version, second.Previous)
+		assert.Equal(t, "2020.10.01.3", third.Version)
+		assert.Equal(t, second.Version, third.Previous)
+	})
+
+	t.Run("UnknownSchemaVersion", func(t *testing.T) {
+		data := currentData
+		data.SchemaVersion = wikibattles.SchemaVersion + 1
+		assert.EqualError(t, data.CheckSchemaVersion(), "Unknown schema version 2, must be 1")
+		data.SchemaVersion = 0
+		assert.NoError(t, data.CheckSchemaVersion(), "Should read unversioned data as the first version")
+
+		unknownFileName := filepath.Join(dir, "unknown.json")
+		data.SchemaVersion = wikibattles.SchemaVersion + 1
+		require.NoError(t, json.Export(unknownFileName, data))
+		assert.EqualError(t, seeder.CheckSchemaVersionOf(unknownFileName), "Unknown schema version 2, must be 1")
+		assert.NoError(t, seeder.CheckSchemaVersionOf(currentFileName))
+		_, err := seeder.Release(unknownFileName, filepath.Join(dir, "unknown"), "")
+		assert.EqualError(t, err, "Unknown schema version 2, must be 1")
+		_, err = os.Stat(filepath.Join(dir, "unknown"))
+		assert.True(t, os.IsNotExist(err), "Should not create the releases directory")
+
+		m := seeder.Manifest{Version: "v3", SchemaVersion: wikibattles.SchemaVersion + 1}
+		assert.EqualError(t, m.Verify(currentFileName), "Unknown schema version 2 in release v3, must be 1")
+	})
+}
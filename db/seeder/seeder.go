@@ -2,120 +2,151 @@ package seeder
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sasalatart/batcoms/domain/battles"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
 	"github.com/sasalatart/batcoms/domain/wikiactors"
 	"github.com/sasalatart/batcoms/domain/wikibattles"
 	"github.com/sasalatart/batcoms/pkg/dates"
 	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/metrics"
 	uuid "github.com/satori/go.uuid"
 )
 
 // ImportedData contains scraped battles and actors that have been read from a previously exported
 // file. These have been indexed by their Wikipedia IDs
 type ImportedData struct {
+	SchemaVersion      int                           `json:"SchemaVersion"`
+	ScrapedAt          time.Time                     `json:"ScrapedAt"`
+	Language           string                        `json:"Language"`
 	WikiBattlesByID    map[string]wikibattles.Battle `json:"BattlesByID"`
 	WikiFactionsByID   map[string]wikiactors.Actor   `json:"FactionsByID"`
 	WikiCommandersByID map[string]wikiactors.Actor   `json:"CommandersByID"`
+	aliases            map[wikiactors.Kind]aliasesMap
 }
 
 // idsMap maps WikiIDs to their corresponding UUIDs
 type idsMap map[int]uuid.UUID
 
 type seeder struct {
-	importedData     *ImportedData
-	factionsWriter   factions.Writer
-	commandersWriter commanders.Writer
-	battlesWriter    battles.Writer
-	logger           logger.Interface
+	importedData       *ImportedData
+	factionsWriter     factions.Writer
+	commandersWriter   commanders.Writer
+	battlesWriter      battles.Writer
+	translationsWriter translations.Writer
+	logger             *logger.Logger
+	lifespans          map[int]commanders.Commander
 }
 
-// Seed fills factions, commanders and battles data stores with the available ImportedData
+// Seed fills factions, commanders and battles data stores with the available ImportedData, together
+// with the translations of their names and summaries into other languages
 func Seed(
 	importedData *ImportedData,
 	factionsWriter factions.Writer,
 	commandersWriter commanders.Writer,
 	battlesWriter battles.Writer,
-	logger logger.Interface,
+	translationsWriter translations.Writer,
+	l logger.Interface,
 ) {
-	service := seeder{importedData, factionsWriter, commandersWriter, battlesWriter, logger}
+	service := seeder{
+		importedData,
+		factionsWriter,
+		commandersWriter,
+		battlesWriter,
+		translationsWriter,
+		logger.From(l).Named("seeder"),
+		make(map[int]commanders.Commander),
+	}
 	service.battles(service.factions(), service.commanders())
 }
 
 func (s *seeder) factions() idsMap {
 	fIDsByWikiID := make(idsMap)
-	current := 0
-	total := len(s.importedData.WikiFactionsByID)
+	start := time.Now()
 	for _, wf := range s.importedData.WikiFactionsByID {
-		fmt.Printf("\rSeeding factions (%d/%d)", current, total)
-		current++
+		s.withActor(wf).Debug("Seeding faction")
 		input := factions.CreationInput{
-			WikiID:  wf.ID,
-			URL:     wf.URL,
-			Name:    wf.Name,
-			Summary: wf.Extract,
+			WikiID:   wf.ID,
+			URL:      wf.URL,
+			Name:     wf.Name,
+			Summary:  wf.Extract,
+			Flag:     wf.Flag,
+			Wikidata: wf.Wikidata,
 		}
 		if fID, err := s.factionsWriter.CreateOne(input); err != nil {
-			s.logger.Error(errors.Wrapf(err, "Error creating faction with URL %s", input.URL))
+			s.withActor(wf).Error(errors.Wrap(err, "Error creating faction"))
 		} else {
 			fIDsByWikiID[wf.ID] = fID
+			s.actorTranslations(fID, wf.Localizations)
+			s.factionAliases(fID, wf.ID)
 		}
 	}
-	s.logger.Info("\nFinished seeding factions\n")
+	s.finished("factions", len(fIDsByWikiID), len(s.importedData.WikiFactionsByID), start)
 	return fIDsByWikiID
 }
 
 func (s *seeder) commanders() idsMap {
 	cIDsByWikiID := make(idsMap)
-	current := 0
-	total := len(s.importedData.WikiCommandersByID)
+	start := time.Now()
 	for _, wc := range s.importedData.WikiCommandersByID {
-		fmt.Printf("\rSeeding commanders (%d/%d)", current, total)
-		current++
+		s.withActor(wc).Debug("Seeding commander")
+		c := s.lifespan(wc)
+		s.lifespans[wc.ID] = c
 		input := commanders.CreationInput{
-			WikiID:  wc.ID,
-			URL:     wc.URL,
-			Name:    wc.Name,
-			Summary: wc.Extract,
+			WikiID:      wc.ID,
+			URL:         wc.URL,
+			Name:        wc.Name,
+			Summary:     wc.Extract,
+			BirthDate:   c.BirthDate,
+			DeathDate:   c.DeathDate,
+			Allegiances: s.allegiances(wc),
+			Wikidata:    wc.Wikidata,
 		}
 		if cID, err := s.commandersWriter.CreateOne(input); err != nil {
-			s.logger.Error(errors.Wrapf(err, "Error creating commander with URL %s", input.URL))
+			s.withActor(wc).Error(errors.Wrap(err, "Error creating commander"))
 		} else {
 			cIDsByWikiID[wc.ID] = cID
+			s.actorTranslations(cID, wc.Localizations)
+			s.commanderAliases(cID, wc.ID)
 		}
 	}
-	s.logger.Info("\nFinished seeding commanders\n")
+	s.finished("commanders", len(cIDsByWikiID), len(s.importedData.WikiCommandersByID), start)
 	return cIDsByWikiID
 }
 
 func (s *seeder) battles(fIDsByWikiID, cIDsByWikiID idsMap) {
-	current := 0
-	total := len(s.importedData.WikiBattlesByID)
+	seeded := 0
+	start := time.Now()
 	for _, wb := range s.importedData.WikiBattlesByID {
-		fmt.Printf("\rSeeding battles (%d/%d)", current, total)
-		current++
-		dates, err := dates.Parse(wb.Date)
+		log := s.logger.With(logger.KV("wikiID", wb.ID), logger.KV("url", wb.URL))
+		log.Debug("Seeding battle")
+		dates, err := dates.ParseIn(s.importedData.Language, wb.Date)
 		if err != nil {
-			s.logger.Error(errors.Wrapf(err, "Error parsing date %q", wb.Date))
+			log.Error(errors.Wrapf(err, "Error parsing date %q", wb.Date))
 			continue
 		}
+		s.checkLifespans(wb, dates[0], dates[len(dates)-1])
 		input := battles.CreationInput{
-			WikiID:              wb.ID,
-			URL:                 wb.URL,
-			Name:                wb.Name,
-			PartOf:              wb.PartOf,
-			Summary:             wb.Extract,
-			StartDate:           dates[0],
-			EndDate:             dates[len(dates)-1],
-			Location:            wb.Location,
-			Result:              wb.Result,
-			TerritorialChanges:  wb.TerritorialChanges,
-			Strength:            wb.Strength,
-			Casualties:          wb.Casualties,
-			CommandersByFaction: make(battles.CommandersByFaction),
+			WikiID:               wb.ID,
+			URL:                  wb.URL,
+			Name:                 wb.Name,
+			PartOf:               wb.PartOf,
+			Summary:              wb.Extract,
+			StartDate:            dates[0],
+			EndDate:              dates[len(dates)-1],
+			Location:             wb.Location,
+			Result:               wb.Result,
+			TerritorialChanges:   wb.TerritorialChanges,
+			Strength:             wb.Strength,
+			Casualties:           wb.Casualties,
+			CommandersByFaction:  make(battles.CommandersByFaction),
+			FlagsByFaction:       make(map[uuid.UUID]string),
+			CommanderConfidences: make(map[uuid.UUID]battles.Confidence),
+			Wikidata:             wb.Wikidata,
 		}
 		input.FactionsBySide.A = s.translateWikiIDs(wb.Factions.A, fIDsByWikiID)
 		input.FactionsBySide.B = s.translateWikiIDs(wb.Factions.B, fIDsByWikiID)
@@ -125,11 +156,158 @@ func (s *seeder) battles(fIDsByWikiID, cIDsByWikiID idsMap) {
 			fID := fIDsByWikiID[sfWikiID]
 			input.CommandersByFaction[fID] = s.translateWikiIDs(scWikiIDs, cIDsByWikiID)
 		}
-		if _, err := s.battlesWriter.CreateOne(input); err != nil {
-			s.logger.Error(errors.Wrapf(err, "Error creating battle with URL %s", input.URL))
+		for scWikiID, confidence := range wb.CommanderConfidences {
+			if cID, ok := cIDsByWikiID[scWikiID]; ok {
+				input.CommanderConfidences[cID] = battles.Confidence(confidence)
+			}
+		}
+		for sfWikiID, flag := range wb.Flags {
+			if fID, ok := fIDsByWikiID[sfWikiID]; ok {
+				input.FlagsByFaction[fID] = flag
+			}
+		}
+		bID, err := s.battlesWriter.CreateOne(input)
+		if err != nil {
+			log.Error(errors.Wrap(err, "Error creating battle"))
+			continue
+		}
+		seeded++
+		for language, l := range wb.Localizations {
+			s.translation(bID, language, l.Name, l.Extract)
+		}
+	}
+	s.finished("battles", seeded, len(s.importedData.WikiBattlesByID), start)
+}
+
+// observe records how many of the given entities were stored, out of how many there were, and how
+// long it took
+func observe(entities string, stored, total int, start time.Time) {
+	metrics.SeederSeeded.WithLabelValues(entities).Add(float64(stored))
+	metrics.SeederFailures.WithLabelValues(entities).Add(float64(total - stored))
+	metrics.SeederDuration.WithLabelValues(entities).Set(time.Since(start).Seconds())
+}
+
+func (s *seeder) withActor(wa wikiactors.Actor) *logger.Logger {
+	return s.logger.With(logger.KV("wikiID", wa.ID), logger.KV("url", wa.URL))
+}
+
+// finished logs how many of the given entities were seeded, out of how many were imported
+func (s *seeder) finished(entities string, seeded, total int, start time.Time) {
+	observe(entities, seeded, total, start)
+	s.logger.With(
+		logger.KV("seeded", seeded),
+		logger.KV("total", total),
+		logger.KV("duration_ms", time.Since(start).Milliseconds()),
+	).Info("Finished seeding " + entities)
+}
+
+// lifespan returns a commanders.Commander with only the dates of birth and death of a scraped
+// commander, those that could not be parsed being left as nil
+func (s *seeder) lifespan(wc wikiactors.Actor) commanders.Commander {
+	c := commanders.Commander{}
+	if wc.Biography == nil {
+		return c
+	}
+	c.BirthDate = s.lifeDate(wc.Biography.Born)
+	c.DeathDate = s.lifeDate(wc.Biography.Died)
+	return c
+}
+
+// lifeDate parses the date of birth or death of a commander, as written in their infobox. Dates
+// that were already scraped in a machine-readable format are preferred
+func (s *seeder) lifeDate(text string) *dates.Historic {
+	if text == "" {
+		return nil
+	}
+	if h, err := dates.ParseFormatted(text); err == nil {
+		return &h
+	}
+	found, err := dates.ParseIn(s.importedData.Language, text)
+	if err != nil {
+		s.logger.Error(errors.Wrapf(err, "Error parsing date %q", text))
+		return nil
+	}
+	return &found[0]
+}
+
+func (s *seeder) allegiances(wc wikiactors.Actor) []commanders.Allegiance {
+	if wc.Biography == nil {
+		return nil
+	}
+	var res []commanders.Allegiance
+	for _, a := range wc.Biography.Allegiances {
+		allegiance := commanders.Allegiance{Name: a.Name}
+		if period, err := dates.ParseIn(s.importedData.Language, a.Period); a.Period != "" && err == nil {
+			allegiance.From = &period[0]
+			allegiance.To = &period[len(period)-1]
+		}
+		res = append(res, allegiance)
+	}
+	return res
+}
+
+// checkLifespans logs the commanders of a battle that could not have fought in it, because they
+// were born after it ended or died before it started. These are usually the result of links that
+// were resolved to the wrong article while scraping
+func (s *seeder) checkLifespans(wb wikibattles.Battle, start, end dates.Historic) {
+	for _, side := range [][]int{wb.Commanders.A, wb.Commanders.B} {
+		for _, wikiID := range side {
+			c, ok := s.lifespans[wikiID]
+			if !ok {
+				continue
+			}
+			if err := c.CheckLifespan(start, end); err != nil {
+				s.logger.With(logger.KV("wikiID", wikiID), logger.KV("url", wb.URL)).Error(
+					errors.Wrap(err, "Commander could not have fought in battle"),
+				)
+			}
+		}
+	}
+}
+
+// factionAliases seeds the WikiIDs and URLs of the duplicates merged into a faction as its aliases
+func (s *seeder) factionAliases(id uuid.UUID, wikiID int) {
+	for _, a := range s.importedData.aliases[wikiactors.FactionKind][wikiID] {
+		input := factions.AliasCreationInput{FactionID: id, WikiID: a.ID, URL: a.URL}
+		if err := s.factionsWriter.CreateAlias(input); err != nil {
+			s.logger.With(logger.KV("wikiID", a.ID), logger.KV("url", a.URL)).Error(
+				errors.Wrapf(err, "Error creating alias for faction %s", id),
+			)
+		}
+	}
+}
+
+// commanderAliases seeds the WikiIDs and URLs of the duplicates merged into a commander as its
+// aliases
+func (s *seeder) commanderAliases(id uuid.UUID, wikiID int) {
+	for _, a := range s.importedData.aliases[wikiactors.CommanderKind][wikiID] {
+		input := commanders.AliasCreationInput{CommanderID: id, WikiID: a.ID, URL: a.URL}
+		if err := s.commandersWriter.CreateAlias(input); err != nil {
+			s.logger.With(logger.KV("wikiID", a.ID), logger.KV("url", a.URL)).Error(
+				errors.Wrapf(err, "Error creating alias for commander %s", id),
+			)
 		}
 	}
-	s.logger.Info("\nFinished seeding battles\n")
+}
+
+func (s *seeder) actorTranslations(id uuid.UUID, localizations map[string]wikiactors.Localization) {
+	for language, l := range localizations {
+		s.translation(id, language, l.Name, l.Extract)
+	}
+}
+
+func (s *seeder) translation(id uuid.UUID, language, name, summary string) {
+	input := translations.CreationInput{
+		EntityID: id,
+		Language: language,
+		Name:     name,
+		Summary:  summary,
+	}
+	if _, err := s.translationsWriter.CreateOne(input); err != nil {
+		s.logger.With(logger.KV("language", language)).Error(
+			errors.Wrapf(err, "Error creating translation for %s", id),
+		)
+	}
 }
 
 func (s *seeder) translateWikiIDs(from []int, idsMapper idsMap) []uuid.UUID {
@@ -138,7 +316,7 @@ func (s *seeder) translateWikiIDs(from []int, idsMapper idsMap) []uuid.UUID {
 		if id, ok := idsMapper[wikiID]; ok {
 			result = append(result, id)
 		} else {
-			s.logger.Error(fmt.Errorf("ID not found for WikiID %d", wikiID))
+			s.logger.With(logger.KV("wikiID", wikiID)).Error(fmt.Errorf("ID not found for WikiID %d", wikiID))
 		}
 	}
 	return result
@@ -10,6 +10,7 @@ import (
 	"github.com/sasalatart/batcoms/domain/wikibattles"
 	"github.com/sasalatart/batcoms/mocks"
 	"github.com/sasalatart/batcoms/pkg/logger"
+	uuid "github.com/satori/go.uuid"
 )
 
 func TestSeeder(t *testing.T) {
@@ -46,8 +47,13 @@ func TestSeeder(t *testing.T) {
 	br := new(mocks.BattlesRepository)
 	br.On("CreateOne", mocks.BattleCreationInput()).Return(mocks.Battle().ID, nil)
 
-	seeder.Seed(&importedData, fr, cr, br, logger.New(ioutil.Discard, ioutil.Discard))
+	tr := new(mocks.TranslationsRepository)
+	tr.On("CreateOne", mocks.TranslationCreationInput(mocks.CommanderTranslation())).Return(uuid.NewV4(), nil)
+	tr.On("CreateOne", mocks.TranslationCreationInput(mocks.BattleTranslation())).Return(uuid.NewV4(), nil)
+
+	seeder.Seed(&importedData, fr, cr, br, tr, logger.New(ioutil.Discard, ioutil.Discard))
 	fr.AssertExpectations(t)
 	cr.AssertExpectations(t)
 	br.AssertExpectations(t)
+	tr.AssertExpectations(t)
 }
@@ -0,0 +1,89 @@
+//go:build sqlite_fts5
+// +build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/sqlite"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandersRepository(t *testing.T) {
+	t.Run("FindOne", func(t *testing.T) {
+		db, sqlDB := mustSeedDB(t)
+		defer sqlDB.Close()
+		cs := sqlite.NewCommandersRepository(db)
+
+		c, err := cs.FindOne(context.Background(), commanders.FindOneQuery{URL: mocks.Commander().URL})
+		require.NoError(t, err, "Finding commander by URL")
+		expected := mocks.Commander()
+		expected.ID = c.ID
+		assert.Equal(t, expected, c)
+
+		_, err = cs.FindOne(context.Background(), commanders.FindOneQuery{WikiID: 1})
+		assert.Equal(t, domain.ErrNotFound, err)
+	})
+
+	t.Run("FindMany", func(t *testing.T) {
+		db, sqlDB := mustSeedDB(t)
+		defer sqlDB.Close()
+		cs := sqlite.NewCommandersRepository(db)
+		fs := sqlite.NewFactionsRepository(db)
+		austria, err := fs.FindOne(context.Background(), factions.FindOneQuery{Name: mocks.Faction3().Name})
+		require.NoError(t, err, "Finding the Austrian Empire")
+		russia, err := fs.FindOne(context.Background(), factions.FindOneQuery{Name: mocks.Faction2().Name})
+		require.NoError(t, err, "Finding the Russian Empire")
+		require.NoError(t, fs.CreateRelationship(factions.RelationshipCreationInput{
+			FactionID: russia.ID,
+			RelatedID: austria.ID,
+			Kind:      factions.ParentKind,
+		}), "Relating the Russian Empire to the Austrian one")
+
+		cases := []struct {
+			description   string
+			query         commanders.FindManyQuery
+			expectedNames []string
+		}{
+			{
+				description:   "By name",
+				query:         commanders.FindManyQuery{Name: "napoleon"},
+				expectedNames: []string{mocks.Commander().Name},
+			},
+			{
+				description:   "By name and summary",
+				query:         commanders.FindManyQuery{Name: "alexander", Summary: "emperor"},
+				expectedNames: []string{mocks.Commander2().Name},
+			},
+			{
+				description:   "By faction",
+				query:         commanders.FindManyQuery{FactionID: austria.ID},
+				expectedNames: []string{mocks.Commander5().Name, mocks.Commander4().Name},
+			},
+			{
+				description:   "By faction, including related ones",
+				query:         commanders.FindManyQuery{FactionID: austria.ID, IncludeRelated: true},
+				expectedNames: []string{mocks.Commander3().Name, mocks.Commander5().Name, mocks.Commander4().Name, mocks.Commander2().Name},
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				cc, pages, err := cs.FindMany(context.Background(), c.query, 1)
+				require.NoError(t, err, "Finding commanders")
+				assert.Equal(t, 1, pages)
+				var names []string
+				for _, c := range cc {
+					names = append(names, c.Name)
+				}
+				assert.Equal(t, c.expectedNames, names)
+			})
+		}
+	})
+}
@@ -0,0 +1,44 @@
+//go:build sqlite_fts5
+// +build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/sqlite"
+	"github.com/sasalatart/batcoms/domain/battles/battlestest"
+	"github.com/sasalatart/batcoms/domain/commanders/commanderstest"
+	"github.com/sasalatart/batcoms/domain/factions/factionstest"
+	"github.com/sasalatart/batcoms/pkg/dates"
+)
+
+// newRepositories returns a battlestest.Factory whose battles store their dates in the given scale
+func newRepositories(scale dates.NumScale) battlestest.Factory {
+	return func(t *testing.T) battlestest.Repositories {
+		db, sqlDB := mustSetupDB(t)
+		t.Cleanup(func() { sqlDB.Close() })
+		return battlestest.Repositories{
+			Factions:   sqlite.NewFactionsRepository(db),
+			Commanders: sqlite.NewCommandersRepository(db),
+			Battles:    sqlite.NewBattlesRepository(db, scale),
+		}
+	}
+}
+
+func TestBattlesRepositoryConformance(t *testing.T) {
+	t.Run("YearScale", func(t *testing.T) {
+		battlestest.TestRepository(t, newRepositories(dates.YearScale))
+	})
+	t.Run("JDNScale", func(t *testing.T) {
+		battlestest.TestRepository(t, newRepositories(dates.JDNScale))
+	})
+}
+
+func TestCommandersRepositoryConformance(t *testing.T) {
+	commanderstest.TestRepository(t, newRepositories(dates.YearScale))
+}
+
+func TestFactionsRepositoryConformance(t *testing.T) {
+	factionstest.TestRepository(t, newRepositories(dates.YearScale))
+}
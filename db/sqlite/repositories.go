@@ -0,0 +1,65 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sasalatart/batcoms/db/gormrepo"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"gorm.io/gorm"
+)
+
+// Dialect lets the GORM repositories search text with the FTS5 tables created by Reset, and
+// calculate distances with the distance_km function registered together with the driver
+var Dialect = gormrepo.Dialect{
+	Match:    match,
+	WithinKm: withinKm,
+	IDParam:  "?",
+}
+
+// NewFactionsRepository returns a pointer to a ready-to-use repository of factions stored in SQLite
+func NewFactionsRepository(db *gorm.DB) *gormrepo.FactionsRepository {
+	return gormrepo.NewFactionsRepository(db, Dialect)
+}
+
+// NewCommandersRepository returns a pointer to a ready-to-use repository of commanders stored in
+// SQLite
+func NewCommandersRepository(db *gorm.DB) *gormrepo.CommandersRepository {
+	return gormrepo.NewCommandersRepository(db, Dialect)
+}
+
+// NewBattlesRepository returns a pointer to a ready-to-use repository of battles stored in SQLite,
+// whose dates are stored and compared as numbers in the given scale
+func NewBattlesRepository(db *gorm.DB, scale dates.NumScale) *gormrepo.BattlesRepository {
+	return gormrepo.NewBattlesRepository(db, Dialect, scale)
+}
+
+// NewTranslationsRepository returns a pointer to a ready-to-use repository of translations stored
+// in SQLite
+func NewTranslationsRepository(db *gorm.DB) *gormrepo.TranslationsRepository {
+	return gormrepo.NewTranslationsRepository(db)
+}
+
+// match restricts the query to the rows of the table whose attribute contains the given value as a
+// phrase, by using the FTS5 table of the former
+func match(db *gorm.DB, table, attribute, value string) *gorm.DB {
+	if value == "" {
+		return db
+	}
+	phrase := `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+	return db.Where(
+		fmt.Sprintf("%s.rowid IN (SELECT rowid FROM %s_fts WHERE %s_fts MATCH ?)", table, table, table),
+		fmt.Sprintf("%s : %s", attribute, phrase),
+	)
+}
+
+// withinKm restricts the query to the battles at most km kilometres away from the given coordinates
+func withinKm(db *gorm.DB, latitude, longitude, km float64) *gorm.DB {
+	return db.Where(distanceKmSQL+" <= ?", latitude, longitude, km)
+}
+
+// distanceKmSQL calculates the great-circle distance in kilometres between the coordinates of each
+// battle and the ones supplied as arguments (latitude and longitude, in that order), by using the
+// distance_km function registered together with the driver. Battles without coordinates are skipped
+const distanceKmSQL = `latitude_num IS NOT NULL AND longitude_num IS NOT NULL AND
+	distance_km(?, ?, latitude_num, longitude_num)`
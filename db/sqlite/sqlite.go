@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/db/gormrepo/schema"
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/pkg/metrics"
+	"github.com/sasalatart/batcoms/pkg/tracing"
+	"github.com/spf13/viper"
+	gormsqlite "gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+)
+
+// driverName is the name under which the SQLite driver is registered together with the functions
+// that the repositories need and SQLite lacks
+const driverName = "sqlite3_batcoms"
+
+func init() {
+	sql.Register(driverName, &sqlite3.SQLiteDriver{
+		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+			return conn.RegisterFunc("distance_km", locations.DistanceKm, true)
+		},
+	})
+}
+
+// dialector is the GORM SQLite dialector, but opening connections with the driver registered above
+type dialector struct {
+	gormsqlite.Dialector
+}
+
+func (d dialector) Initialize(db *gorm.DB) (err error) {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{LastInsertIDReversed: true})
+	db.ConnPool, err = sql.Open(driverName, d.DSN)
+	return err
+}
+
+// DefaultTestPath exposes the path of the database file used by the default test environment
+func DefaultTestPath() string {
+	return viper.GetString("SQLITE_PATH_TEST")
+}
+
+// Connect opens the SQLite database stored in the file at the given path, creating it if it does
+// not exist yet. When no path is given, the one in the SQLITE_PATH config is used. Full-text search
+// requires building with the sqlite_fts5 tag. It panics if the database can not be opened
+func Connect(path string) (*gorm.DB, *sql.DB) {
+	db, sqlDB, err := Open(path)
+	if err != nil {
+		panic(err)
+	}
+	return db, sqlDB
+}
+
+// Open is like Connect, but returns an error instead of panicking, so that connecting may be retried
+func Open(path string) (*gorm.DB, *sql.DB, error) {
+	if path == "" {
+		path = viper.GetString("SQLITE_PATH")
+	}
+
+	dsn := fmt.Sprintf("file:%s?_foreign_keys=on&_busy_timeout=5000", path)
+	db, err := gorm.Open(dialector{gormsqlite.Dialector{DSN: dsn}}, &gorm.Config{})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
+	if err := metrics.InstrumentGORM(db, "sqlite"); err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
+	if err := tracing.InstrumentGORM(db, "sqlite"); err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Unable to connect to database")
+	}
+	return db, sqlDB, nil
+}
+
+// Reset drops all existing tables, and automigrates them again. Names, summaries, places and
+// results are also indexed into FTS5 tables, which are kept up to date by triggers. It panics if
+// SQLite was built without FTS5
+func Reset(db *gorm.DB) {
+	schemas := []interface{}{
+		&schema.FactionRelationship{},
+		&schema.FactionAlias{},
+		&schema.CommanderAlias{},
+		&schema.BattleCommanderFaction{},
+		&schema.BattleFaction{},
+		&schema.BattleCommander{},
+		&schema.Faction{},
+		&schema.Commander{},
+		&schema.Battle{},
+		&schema.Translation{},
+	}
+	for _, table := range []string{"factions", "commanders", "battles"} {
+		db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %s_fts;", table))
+	}
+	db.Migrator().DropTable(schemas...)
+	db.AutoMigrate(schemas...)
+
+	for table, columns := range map[string][]string{
+		"factions":   {"name", "summary"},
+		"commanders": {"name", "summary"},
+		"battles":    {"name", "summary", "place", "result"},
+	} {
+		if err := createFTS(db, table, columns...); err != nil {
+			panic(errors.Wrap(err, "Creating FTS5 tables, which requires building with -tags sqlite_fts5"))
+		}
+	}
+}
+
+// createFTS creates an external content FTS5 table named after the given one, which indexes the
+// given columns, together with the triggers that keep it in sync
+func createFTS(db *gorm.DB, table string, columns ...string) error {
+	cols := strings.Join(columns, ", ")
+	newCols := "new." + strings.Join(columns, ", new.")
+	oldCols := "old." + strings.Join(columns, ", old.")
+	err := db.Exec(fmt.Sprintf(
+		`CREATE VIRTUAL TABLE %s_fts USING fts5(%s, content='%s', tokenize='porter unicode61 remove_diacritics 2');`,
+		table, cols, table,
+	)).Error
+	if err != nil {
+		return err
+	}
+	db.Exec(fmt.Sprintf(
+		`CREATE TRIGGER %s_fts_insert AFTER INSERT ON %s BEGIN
+			INSERT INTO %s_fts(rowid, %s) VALUES (new.rowid, %s);
+		END;`,
+		table, table, table, cols, newCols,
+	))
+	db.Exec(fmt.Sprintf(
+		`CREATE TRIGGER %s_fts_delete AFTER DELETE ON %s BEGIN
+			INSERT INTO %s_fts(%s_fts, rowid, %s) VALUES ('delete', old.rowid, %s);
+		END;`,
+		table, table, table, table, cols, oldCols,
+	))
+	db.Exec(fmt.Sprintf(
+		`CREATE TRIGGER %s_fts_update AFTER UPDATE ON %s BEGIN
+			INSERT INTO %s_fts(%s_fts, rowid, %s) VALUES ('delete', old.rowid, %s);
+			INSERT INTO %s_fts(rowid, %s) VALUES (new.rowid, %s);
+		END;`,
+		table, table, table, table, cols, oldCols, table, cols, newCols,
+	))
+	return nil
+}
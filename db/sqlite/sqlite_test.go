@@ -0,0 +1,60 @@
+//go:build sqlite_fts5
+// +build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"database/sql"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/seeder"
+	"github.com/sasalatart/batcoms/db/sqlite"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"gorm.io/gorm"
+)
+
+func mustSetupDB(t *testing.T) (*gorm.DB, *sql.DB) {
+	t.Helper()
+	db, sqlDB := sqlite.Connect(filepath.Join(t.TempDir(), "batcoms_test.db"))
+	sqlite.Reset(db)
+	return db, sqlDB
+}
+
+// mustSeedDB sets up a database with the mocked battle, together with its factions, commanders and
+// translations
+func mustSeedDB(t *testing.T) (*gorm.DB, *sql.DB) {
+	t.Helper()
+	db, sqlDB := mustSetupDB(t)
+	importedData := seeder.ImportedData{
+		WikiBattlesByID: map[string]wikibattles.Battle{
+			strconv.Itoa(mocks.WikiBattle().ID): mocks.WikiBattle(),
+		},
+		WikiFactionsByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiFaction().ID):  mocks.WikiFaction(),
+			strconv.Itoa(mocks.WikiFaction2().ID): mocks.WikiFaction2(),
+			strconv.Itoa(mocks.WikiFaction3().ID): mocks.WikiFaction3(),
+		},
+		WikiCommandersByID: map[string]wikiactors.Actor{
+			strconv.Itoa(mocks.WikiCommander().ID):  mocks.WikiCommander(),
+			strconv.Itoa(mocks.WikiCommander2().ID): mocks.WikiCommander2(),
+			strconv.Itoa(mocks.WikiCommander3().ID): mocks.WikiCommander3(),
+			strconv.Itoa(mocks.WikiCommander4().ID): mocks.WikiCommander4(),
+			strconv.Itoa(mocks.WikiCommander5().ID): mocks.WikiCommander5(),
+		},
+	}
+	seeder.Seed(
+		&importedData,
+		sqlite.NewFactionsRepository(db),
+		sqlite.NewCommandersRepository(db),
+		sqlite.NewBattlesRepository(db, dates.YearScale),
+		sqlite.NewTranslationsRepository(db),
+		logger.NewDiscard(),
+	)
+	return db, sqlDB
+}
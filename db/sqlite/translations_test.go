@@ -0,0 +1,36 @@
+//go:build sqlite_fts5
+// +build sqlite_fts5
+
+package sqlite_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sasalatart/batcoms/db/sqlite"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/translations"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslationsRepository(t *testing.T) {
+	db, sqlDB := mustSeedDB(t)
+	defer sqlDB.Close()
+	ts := sqlite.NewTranslationsRepository(db)
+	b, err := sqlite.NewBattlesRepository(db, dates.YearScale).FindOne(context.Background(), battles.FindOneQuery{Name: mocks.Battle().Name})
+	require.NoError(t, err, "Finding the Battle of Austerlitz")
+
+	res, err := ts.FindMany(context.Background(), translations.FindManyQuery{Language: "es", EntityIDs: []uuid.UUID{b.ID, uuid.NewV4()}})
+	require.NoError(t, err, "Finding translations")
+	expected := mocks.BattleTranslation()
+	expected.EntityID = b.ID
+	assert.Equal(t, []translations.Translation{expected}, res)
+
+	res, err = ts.FindMany(context.Background(), translations.FindManyQuery{Language: "fr", EntityIDs: []uuid.UUID{b.ID}})
+	require.NoError(t, err, "Finding translations into a language without them")
+	assert.Empty(t, res)
+}
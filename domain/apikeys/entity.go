@@ -0,0 +1,47 @@
+package apikeys
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"time"
+
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+// APIKey identifies a client of the API, and sets how many requests it may make. Only the hash of
+// the key itself is stored, as it is handed to its client once when issued
+type APIKey struct {
+	ID                uuid.UUID  `json:"id"`
+	Name              string     `json:"name"`
+	RequestsPerMinute int        `json:"requestsPerMinute"`
+	Burst             int        `json:"burst"`
+	CreatedAt         time.Time  `json:"createdAt"`
+	RevokedAt         *time.Time `json:"revokedAt,omitempty"`
+}
+
+// Revoked returns true if the key may no longer be used
+func (k APIKey) Revoked() bool {
+	return k.RevokedAt != nil
+}
+
+// prefix is prepended to generated keys, so that they may be told apart from other secrets
+const prefix = "bck_"
+
+// Generate returns a new random key, that must be handed to its client and stored hashed
+func Generate() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", errors.Wrap(err, "Generating API key")
+	}
+	return prefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns the hash under which key is stored. Keys are random enough for a fast hash to be safe,
+// which allows them to be looked up by it on every request
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,26 @@
+package apikeys_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate(t *testing.T) {
+	key, err := apikeys.Generate()
+	require.NoError(t, err, "Generating API key")
+	assert.True(t, strings.HasPrefix(key, "bck_"), "Should be prefixed")
+	assert.Len(t, key, len("bck_")+43, "Should encode 32 random bytes")
+
+	other, err := apikeys.Generate()
+	require.NoError(t, err, "Generating API key")
+	assert.NotEqual(t, key, other, "Should generate different keys")
+}
+
+func TestHash(t *testing.T) {
+	assert.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", apikeys.Hash("abc"))
+	assert.Len(t, apikeys.Hash("bck_key"), 64, "Should fit the stored hash")
+}
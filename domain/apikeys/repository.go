@@ -0,0 +1,43 @@
+package apikeys
+
+import (
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository is the interface through which API keys may be read and written
+type Repository interface {
+	Reader
+	Writer
+}
+
+// Reader is the interface through which API keys may be read. Queries are scoped to the given
+// context, such as the one of the request that triggered them, so that they are traced as part of
+// it
+type Reader interface {
+	FindOne(ctx context.Context, query FindOneQuery) (APIKey, error)
+	FindMany(ctx context.Context) ([]APIKey, error)
+}
+
+// Writer is the interface through which API keys may be written
+type Writer interface {
+	CreateOne(data CreationInput) (uuid.UUID, error)
+	Revoke(id uuid.UUID) error
+}
+
+// FindOneQuery is used to refine the filters when finding one API key, either by its ID or by the
+// hash of the key itself
+type FindOneQuery struct {
+	ID   uuid.UUID
+	Hash string
+}
+
+// CreationInput is a struct that contains all of the data required to create an API key. This
+// includes annotations required by validations
+type CreationInput struct {
+	Name              string `validate:"required"`
+	Hash              string `validate:"required,len=64"`
+	RequestsPerMinute int    `validate:"required,min=1"`
+	Burst             int    `validate:"required,min=1"`
+}
@@ -0,0 +1,456 @@
+// Package battlestest provides a conformance suite that any implementation of battles.Repository
+// should pass, so that all of them behave in the same way regardless of where battles are stored
+package battlestest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepository runs the conformance suite against the battles repositories returned by
+// newRepositories. Battles created from the mocks must also be found as the mocks describe them
+func TestRepository(t *testing.T, newRepositories Factory) {
+	t.Run("CreateOne", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			r := newRepositories(t)
+			expected := mockBattle(t, r)
+
+			input := mocks.BattleCreationInput()
+			input.FactionsBySide = battles.IDsBySide{A: factionIDs(expected.Factions.A), B: factionIDs(expected.Factions.B)}
+			input.CommandersBySide = battles.IDsBySide{A: commanderIDs(expected.Commanders.A), B: commanderIDs(expected.Commanders.B)}
+			input.CommandersByFaction = expected.CommandersByFaction
+			input.CommanderConfidences = expected.CommanderConfidences
+			id, err := r.Battles.CreateOne(input)
+			require.NoError(t, err, "Creating battle with valid input")
+			assert.NotEqual(t, uuid.Nil, id, "Should generate an ID")
+
+			b, err := r.Battles.FindOne(context.Background(), battles.FindOneQuery{ID: id})
+			require.NoError(t, err, "Finding the created battle")
+			expected.ID = id
+			assertBattle(t, expected, b)
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			r := newRepositories(t)
+
+			input := mocks.BattleCreationInput()
+			input.FactionsBySide, input.CommandersBySide = battles.IDsBySide{}, battles.IDsBySide{}
+			input.CommandersByFaction, input.CommanderConfidences = nil, nil
+			input.URL = "not-a-url"
+			_, err := r.Battles.CreateOne(input)
+			require.Error(t, err, "Creating battle with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+
+			_, err = r.Battles.FindOne(context.Background(), battles.FindOneQuery{Name: input.Name})
+			assert.Equal(t, domain.ErrNotFound, err, "Should not create the battle")
+		})
+		t.Run("WithExistingURL", func(t *testing.T) {
+			r := newRepositories(t)
+			fx := Seed(t, r)
+
+			existing, err := r.Battles.FindOne(context.Background(), battles.FindOneQuery{ID: fx.Battles["Battle of Austerlitz"]})
+			require.NoError(t, err, "Finding the Battle of Austerlitz")
+			_, err = r.Battles.CreateOne(battles.CreationInput{
+				WikiID:    9999,
+				URL:       existing.URL,
+				Name:      "Battle of the Three Emperors",
+				Summary:   existing.Summary,
+				StartDate: existing.StartDate,
+				EndDate:   existing.EndDate,
+				Result:    existing.Result,
+			})
+			assert.Error(t, err, "Creating battle with the URL of an existing one")
+		})
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		r := newRepositories(t)
+		fx := Seed(t, r)
+		id := fx.Battles["Battle of Austerlitz"]
+
+		queries := map[string]battles.FindOneQuery{
+			"ByID":   {ID: id},
+			"ByName": {Name: "Battle of Austerlitz"},
+			"ByURL":  {URL: wikiURL("Battle of Austerlitz")},
+		}
+		for description, query := range queries {
+			t.Run(description, func(t *testing.T) {
+				b, err := r.Battles.FindOne(context.Background(), query)
+				require.NoError(t, err, "Finding battle")
+				assert.Equal(t, id, b.ID)
+				assert.Equal(t, "Battle of Austerlitz", b.Name)
+				assert.Equal(t, "War of the Third Coalition", b.PartOf)
+				assert.Equal(t, dates.Historic{Year: 1805, Month: 12, Day: 2}.ToNum(), b.StartDate.ToNum())
+				assert.Equal(t, []uuid.UUID{fx.Factions["First French Empire"]}, factionIDs(b.Factions.A))
+				assert.ElementsMatch(t, []uuid.UUID{fx.Factions["Russian Empire"], fx.Factions["Austrian Empire"]}, factionIDs(b.Factions.B))
+				assert.Equal(t, []uuid.UUID{fx.Commanders["Napoleon"]}, commanderIDs(b.Commanders.A))
+				assert.Equal(t, []uuid.UUID{fx.Commanders["Mikhail Kutuzov"]}, commanderIDs(b.Commanders.B))
+				assert.Equal(t, battles.CommandersByFaction{
+					fx.Factions["First French Empire"]: {fx.Commanders["Napoleon"]},
+					fx.Factions["Russian Empire"]:      {fx.Commanders["Mikhail Kutuzov"]},
+				}, b.CommandersByFaction)
+			})
+		}
+
+		t.Run("NotFound", func(t *testing.T) {
+			for _, query := range []battles.FindOneQuery{
+				{ID: uuid.NewV4()},
+				{Name: "Battle of Atlantis"},
+				{URL: wikiURL("Battle of Atlantis")},
+			} {
+				_, err := r.Battles.FindOne(context.Background(), query)
+				assert.Equal(t, domain.ErrNotFound, err)
+			}
+		})
+	})
+
+	t.Run("FindMany", func(t *testing.T) {
+		r := newRepositories(t)
+		fx := Seed(t, r)
+
+		cases := []struct {
+			description   string
+			query         battles.FindManyQuery
+			expectedNames []string
+		}{
+			{
+				description: "With no filters",
+				query:       battles.FindManyQuery{},
+				expectedNames: []string{
+					"First Battle of Stockach",
+					"Ulm Campaign",
+					"Battle of Elchingen",
+					"Battle of Verona",
+					"Battle of Trafalgar",
+					"Battle of Austerlitz",
+					"Battle of Athos",
+					"Battle of Borodino",
+				},
+			},
+			{
+				description:   "By name",
+				query:         battles.FindManyQuery{Name: "austerlitz"},
+				expectedNames: []string{"Battle of Austerlitz"},
+			},
+			{
+				description:   "By summary phrase",
+				query:         battles.FindManyQuery{Summary: "Napoleonic Wars"},
+				expectedNames: []string{"Battle of Trafalgar", "Battle of Austerlitz", "Battle of Borodino"},
+			},
+			{
+				description:   "By stemmed summary",
+				query:         battles.FindManyQuery{Summary: "engagement"},
+				expectedNames: []string{"Battle of Trafalgar", "Battle of Austerlitz"},
+			},
+			{
+				description:   "By place",
+				query:         battles.FindManyQuery{Place: "Swabia"},
+				expectedNames: []string{"First Battle of Stockach", "Ulm Campaign", "Battle of Elchingen"},
+			},
+			{
+				description: "By result",
+				query:       battles.FindManyQuery{Result: "French victory"},
+				expectedNames: []string{
+					"Ulm Campaign",
+					"Battle of Elchingen",
+					"Battle of Verona",
+					"Battle of Austerlitz",
+					"Battle of Borodino",
+				},
+			},
+			{
+				description:   "By faction",
+				query:         battles.FindManyQuery{FactionID: fx.Factions["Austrian Empire"]},
+				expectedNames: []string{"Ulm Campaign", "Battle of Elchingen", "Battle of Verona", "Battle of Austerlitz"},
+			},
+			{
+				description:   "By faction, without related ones",
+				query:         battles.FindManyQuery{FactionID: fx.Factions["Holy Roman Empire"]},
+				expectedNames: []string{"First Battle of Stockach"},
+			},
+			{
+				description: "By faction, including related ones",
+				query:       battles.FindManyQuery{FactionID: fx.Factions["Holy Roman Empire"], IncludeRelated: true},
+				expectedNames: []string{
+					"First Battle of Stockach",
+					"Ulm Campaign",
+					"Battle of Elchingen",
+					"Battle of Verona",
+					"Battle of Austerlitz",
+				},
+			},
+			{
+				description:   "By commander",
+				query:         battles.FindManyQuery{CommanderID: fx.Commanders["Mikhail Kutuzov"]},
+				expectedNames: []string{"Battle of Austerlitz", "Battle of Borodino"},
+			},
+			{
+				description: "From date",
+				query:       battles.FindManyQuery{FromDate: dates.Historic{Year: 1805, Month: 10, Day: 1}},
+				expectedNames: []string{
+					"Battle of Elchingen",
+					"Battle of Verona",
+					"Battle of Trafalgar",
+					"Battle of Austerlitz",
+					"Battle of Athos",
+					"Battle of Borodino",
+				},
+			},
+			{
+				description: "To date",
+				query:       battles.FindManyQuery{ToDate: dates.Historic{Year: 1805, Month: 12, Day: 31}},
+				expectedNames: []string{
+					"First Battle of Stockach",
+					"Ulm Campaign",
+					"Battle of Elchingen",
+					"Battle of Verona",
+					"Battle of Trafalgar",
+					"Battle of Austerlitz",
+				},
+			},
+			{
+				description: "Between dates",
+				query: battles.FindManyQuery{
+					FromDate: dates.Historic{Year: 1805, Month: 10, Day: 1},
+					ToDate:   dates.Historic{Year: 1805, Month: 12, Day: 31},
+				},
+				expectedNames: []string{"Battle of Elchingen", "Battle of Verona", "Battle of Trafalgar", "Battle of Austerlitz"},
+			},
+			{
+				description:   "Concurrent",
+				query:         battles.FindManyQuery{ConcurrentWith: fx.Battles["Ulm Campaign"]},
+				expectedNames: []string{"Battle of Elchingen", "Battle of Verona"},
+			},
+			{
+				description:   "Concurrent within km",
+				query:         battles.FindManyQuery{ConcurrentWith: fx.Battles["Ulm Campaign"], WithinKm: 100},
+				expectedNames: []string{"Battle of Elchingen"},
+			},
+			{
+				description:   "Concurrent within km of a battle without coordinates",
+				query:         battles.FindManyQuery{ConcurrentWith: fx.Battles["First Battle of Stockach"], WithinKm: 100},
+				expectedNames: nil,
+			},
+			{
+				description:   "Related",
+				query:         battles.FindManyQuery{RelatedTo: fx.Battles["Battle of Athos"]},
+				expectedNames: []string{"Battle of Austerlitz", "Battle of Borodino"},
+			},
+			{
+				description: "By many filters",
+				query: battles.FindManyQuery{
+					FactionID: fx.Factions["First French Empire"],
+					Result:    "French victory",
+					ToDate:    dates.Historic{Year: 1805, Month: 12, Day: 31},
+				},
+				expectedNames: []string{"Ulm Campaign", "Battle of Elchingen", "Battle of Verona", "Battle of Austerlitz"},
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				bb, pages, err := r.Battles.FindMany(context.Background(), c.query, 1)
+				require.NoError(t, err, "Finding battles")
+				assert.Equal(t, 1, pages)
+				var names []string
+				for _, b := range bb {
+					names = append(names, b.Name)
+				}
+				assert.Equal(t, c.expectedNames, names)
+			})
+		}
+	})
+
+	t.Run("Omit", func(t *testing.T) {
+		r := newRepositories(t)
+		Seed(t, r)
+
+		cases := []struct {
+			description        string
+			omit               battles.Relations
+			expectedFactions   bool
+			expectedCommanders bool
+		}{
+			{description: "Nothing", omit: battles.Relations{}, expectedFactions: true, expectedCommanders: true},
+			{description: "Factions", omit: battles.Relations{Factions: true}, expectedFactions: false, expectedCommanders: true},
+			{description: "Commanders", omit: battles.Relations{Commanders: true}, expectedFactions: true, expectedCommanders: false},
+			{description: "Both", omit: battles.Relations{Factions: true, Commanders: true}, expectedFactions: false, expectedCommanders: false},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				bb, _, err := r.Battles.FindMany(context.Background(), battles.FindManyQuery{Name: "austerlitz", Omit: c.omit}, 1)
+				require.NoError(t, err, "Finding battles")
+				require.Len(t, bb, 1)
+				b := bb[0]
+				assert.Equal(t, "Battle of Austerlitz", b.Name, "Should still find the battle")
+				assert.Equal(t, c.expectedFactions, len(b.Factions.A)+len(b.Factions.B) > 0, "Factions")
+				assert.Equal(t, c.expectedCommanders, len(b.Commanders.A)+len(b.Commanders.B) > 0, "Commanders")
+				assert.Equal(t, c.expectedFactions && c.expectedCommanders, len(b.CommandersByFaction) > 0, "CommandersByFaction")
+
+				found, err := r.Battles.FindOne(context.Background(), battles.FindOneQuery{ID: b.ID, Omit: c.omit})
+				require.NoError(t, err, "Finding one battle")
+				assert.Equal(t, b, found, "Should omit the same relations when finding one battle")
+			})
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		r := newRepositories(t)
+		SeedPaginated(t, r)
+
+		for page, expectedLength := range map[int]int{1: 50, 2: PaginatedCount - 50, 3: 0} {
+			bb, pages, err := r.Battles.FindMany(context.Background(), battles.FindManyQuery{Name: PaginatedWord}, page)
+			require.NoError(t, err, "Finding page %d of battles", page)
+			assert.Equal(t, 2, pages, "Pages when finding page %d", page)
+			assert.Len(t, bb, expectedLength, "Battles in page %d", page)
+		}
+	})
+
+	t.Run("Any", func(t *testing.T) {
+		r := newRepositories(t)
+		seeded, err := r.Battles.Any(context.Background())
+		require.NoError(t, err, "Checking whether any battle exists while empty")
+		assert.False(t, seeded, "Should not find any battle while empty")
+
+		Seed(t, r)
+		seeded, err = r.Battles.Any(context.Background())
+		require.NoError(t, err, "Checking whether any battle exists once seeded")
+		assert.True(t, seeded, "Should find battles once seeded")
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		r := newRepositories(t)
+		SeedPaginated(t, r)
+		query := battles.FindManyQuery{Name: PaginatedWord}
+
+		var paginated []battles.Battle
+		for page := 1; page <= 2; page++ {
+			bb, _, err := r.Battles.FindMany(context.Background(), query, page)
+			require.NoError(t, err, "Finding page %d of battles", page)
+			paginated = append(paginated, bb...)
+		}
+		var streamed []battles.Battle
+		err := r.Battles.Stream(context.Background(), query, func(b battles.Battle) error {
+			streamed = append(streamed, b)
+			return nil
+		})
+		require.NoError(t, err, "Streaming battles")
+		assert.Len(t, streamed, PaginatedCount)
+		assert.Equal(t, paginated, streamed, "Should stream the same battles as the pages, in the same order")
+
+		errStop := errors.New("stop")
+		var count int
+		err = r.Battles.Stream(context.Background(), query, func(b battles.Battle) error {
+			count++
+			return errStop
+		})
+		assert.Equal(t, errStop, err, "Should return the error of each")
+		assert.Equal(t, 1, count, "Should stop at the first error")
+	})
+}
+
+// mockBattle stores the factions and commanders of mocks.Battle, returning the latter with the IDs
+// with which they were stored
+func mockBattle(t *testing.T, r Repositories) battles.Battle {
+	t.Helper()
+	expected := mocks.Battle()
+	fIDs := make(map[uuid.UUID]uuid.UUID)
+	createFactions := func(ff []factions.Faction) {
+		for i, f := range ff {
+			id, err := r.Factions.CreateOne(factions.CreationInput{
+				WikiID:   f.WikiID,
+				URL:      f.URL,
+				Name:     f.Name,
+				Summary:  f.Summary,
+				Flag:     f.Flag,
+				Wikidata: f.Wikidata,
+			})
+			require.NoError(t, err, "Creating faction %s", f.Name)
+			fIDs[f.ID] = id
+			ff[i].ID = id
+		}
+	}
+	createFactions(expected.Factions.A)
+	createFactions(expected.Factions.B)
+
+	cIDs := make(map[uuid.UUID]uuid.UUID)
+	createCommanders := func(cc []commanders.Commander) {
+		for i, c := range cc {
+			id, err := r.Commanders.CreateOne(commanders.CreationInput{
+				WikiID:      c.WikiID,
+				URL:         c.URL,
+				Name:        c.Name,
+				Summary:     c.Summary,
+				BirthDate:   c.BirthDate,
+				DeathDate:   c.DeathDate,
+				Allegiances: c.Allegiances,
+				Wikidata:    c.Wikidata,
+			})
+			require.NoError(t, err, "Creating commander %s", c.Name)
+			cIDs[c.ID] = id
+			cc[i].ID = id
+		}
+	}
+	createCommanders(expected.Commanders.A)
+	createCommanders(expected.Commanders.B)
+
+	byFaction := make(battles.CommandersByFaction)
+	for fID, ids := range expected.CommandersByFaction {
+		for _, cID := range ids {
+			byFaction[fIDs[fID]] = append(byFaction[fIDs[fID]], cIDs[cID])
+		}
+	}
+	expected.CommandersByFaction = byFaction
+	confidences := make(map[uuid.UUID]battles.Confidence)
+	for cID, confidence := range expected.CommanderConfidences {
+		confidences[cIDs[cID]] = confidence
+	}
+	expected.CommanderConfidences = confidences
+	return expected
+}
+
+// assertBattle asserts that the actual battle equals the expected one, regardless of the order in
+// which the factions and commanders of each side were stored
+func assertBattle(t *testing.T, expected, actual battles.Battle) {
+	t.Helper()
+	assert.ElementsMatch(t, expected.Factions.A, actual.Factions.A, "Factions of side A")
+	assert.ElementsMatch(t, expected.Factions.B, actual.Factions.B, "Factions of side B")
+	assert.ElementsMatch(t, expected.Commanders.A, actual.Commanders.A, "Commanders of side A")
+	assert.ElementsMatch(t, expected.Commanders.B, actual.Commanders.B, "Commanders of side B")
+	for fID, cIDs := range expected.CommandersByFaction {
+		assert.ElementsMatch(t, cIDs, actual.CommandersByFaction[fID], "Commanders of faction %s", fID)
+	}
+	assert.Len(t, actual.CommandersByFaction, len(expected.CommandersByFaction))
+
+	expected.Factions, actual.Factions = battles.FactionsBySide{}, battles.FactionsBySide{}
+	expected.Commanders, actual.Commanders = battles.CommandersBySide{}, battles.CommandersBySide{}
+	expected.CommandersByFaction, actual.CommandersByFaction = nil, nil
+	assert.Equal(t, expected, actual)
+}
+
+func factionIDs(ff []factions.Faction) []uuid.UUID {
+	var res []uuid.UUID
+	for _, f := range ff {
+		res = append(res, f.ID)
+	}
+	return res
+}
+
+func commanderIDs(cc []commanders.Commander) []uuid.UUID {
+	var res []uuid.UUID
+	for _, c := range cc {
+		res = append(res, c.ID)
+	}
+	return res
+}
@@ -0,0 +1,316 @@
+package battlestest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Repositories groups the repositories of a single backend, given that battles refer to the
+// factions and commanders stored in it
+type Repositories struct {
+	Factions   factions.Repository
+	Commanders commanders.Repository
+	Battles    battles.Repository
+}
+
+// Factory returns the repositories of a new and empty backend. It is called once per subtest, so
+// implementations may release what they set up through t.Cleanup
+type Factory func(t *testing.T) Repositories
+
+// Fixtures holds the IDs of the factions, commanders and battles stored by Seed, indexed by their
+// names
+type Fixtures struct {
+	Factions   map[string]uuid.UUID
+	Commanders map[string]uuid.UUID
+	Battles    map[string]uuid.UUID
+}
+
+// PaginatedWord is part of the names of the factions, commanders and battles stored by
+// SeedPaginated, so that they may be searched without matching any of those stored by Seed
+const PaginatedWord = "Paginated"
+
+// PaginatedCount is the number of factions, commanders and battles stored by SeedPaginated, which
+// is enough for their results to span two pages
+const PaginatedCount = 55
+
+type factionFixture struct {
+	name    string
+	summary string
+}
+
+var factionFixtures = []factionFixture{
+	{"First French Empire", "The First French Empire was ruled by Napoleon."},
+	{"Russian Empire", "The Russian Empire was ruled by the Romanov dynasty."},
+	{"Austrian Empire", "The Austrian Empire was ruled by the Habsburg monarchy."},
+	{"Holy Roman Empire", "The Holy Roman Empire was ruled by the Habsburg monarchy until its dissolution."},
+	{"Kingdom of Great Britain", "Great Britain was a sovereign state in Western Europe."},
+	{"Ottoman Empire", "The Ottoman Empire was ruled by the Ottoman dynasty."},
+}
+
+type commanderFixture struct {
+	name    string
+	summary string
+}
+
+var commanderFixtures = []commanderFixture{
+	{"Napoleon", "Napoleon Bonaparte was Emperor of the French."},
+	{"Karl Mack von Leiberich", "Karl Mack von Leiberich was an Austrian general."},
+	{"Michel Ney", "Michel Ney was a Marshal of the Empire."},
+	{"Archduke Charles", "Archduke Charles was an Austrian field marshal."},
+	{"Jean-Baptiste Jourdan", "Jean-Baptiste Jourdan was a Marshal of the Empire."},
+	{"Horatio Nelson", "Horatio Nelson was a British flag officer in the Royal Navy."},
+	{"Pierre-Charles Villeneuve", "Pierre-Charles Villeneuve was a French naval officer."},
+	{"Mikhail Kutuzov", "Mikhail Kutuzov was a field marshal of the Russian Empire."},
+	{"Dmitry Senyavin", "Dmitry Senyavin was a Russian admiral."},
+}
+
+type battleFixture struct {
+	name      string
+	partOf    string
+	summary   string
+	startDate dates.Historic
+	endDate   dates.Historic
+	location  locations.Location
+	result    string
+	// factions and commanders are indexed by side, and then by the name of each faction
+	factions   [2][]string
+	commanders map[string][]string
+}
+
+var battleFixtures = []battleFixture{
+	{
+		name:      "First Battle of Stockach",
+		partOf:    "War of the Second Coalition",
+		summary:   "The First Battle of Stockach was fought during the French Revolutionary Wars.",
+		startDate: dates.Historic{Year: 1799, Month: 3, Day: 25},
+		endDate:   dates.Historic{Year: 1799, Month: 3, Day: 26},
+		location:  locations.Location{Place: "Stockach, Swabia"},
+		result:    "Austrian victory",
+		factions:  [2][]string{{"Holy Roman Empire"}, {"First French Empire"}},
+		commanders: map[string][]string{
+			"Holy Roman Empire":   {"Archduke Charles"},
+			"First French Empire": {"Jean-Baptiste Jourdan"},
+		},
+	},
+	{
+		name:      "Ulm Campaign",
+		partOf:    "War of the Third Coalition",
+		summary:   "The Ulm Campaign was a series of French and Bavarian military maneuvers.",
+		startDate: dates.Historic{Year: 1805, Month: 9, Day: 25},
+		endDate:   dates.Historic{Year: 1805, Month: 10, Day: 20},
+		location:  locations.Location{Place: "Ulm, Swabia", Latitude: `48°24'0"N`, Longitude: `9°59'0"E`},
+		result:    "Decisive French victory",
+		factions:  [2][]string{{"First French Empire"}, {"Austrian Empire"}},
+		commanders: map[string][]string{
+			"First French Empire": {"Napoleon"},
+			"Austrian Empire":     {"Karl Mack von Leiberich"},
+		},
+	},
+	{
+		name:      "Battle of Elchingen",
+		partOf:    "War of the Third Coalition",
+		summary:   "The Battle of Elchingen was fought during the Ulm Campaign.",
+		startDate: dates.Historic{Year: 1805, Month: 10, Day: 14},
+		endDate:   dates.Historic{Year: 1805, Month: 10, Day: 14},
+		location:  locations.Location{Place: "Elchingen, Swabia", Latitude: `48°27'0"N`, Longitude: `10°6'0"E`},
+		result:    "French victory",
+		factions:  [2][]string{{"First French Empire"}, {"Austrian Empire"}},
+		commanders: map[string][]string{
+			"First French Empire": {"Michel Ney"},
+		},
+	},
+	{
+		name:      "Battle of Verona",
+		partOf:    "War of the Third Coalition",
+		summary:   "The Battle of Verona was fought during the Italian campaign.",
+		startDate: dates.Historic{Year: 1805, Month: 10, Day: 18},
+		endDate:   dates.Historic{Year: 1805, Month: 10, Day: 18},
+		location:  locations.Location{Place: "Verona, Venetia", Latitude: `45°26'0"N`, Longitude: `10°59'0"E`},
+		result:    "French victory",
+		factions:  [2][]string{{"First French Empire"}, {"Austrian Empire"}},
+		commanders: map[string][]string{
+			"Austrian Empire": {"Archduke Charles"},
+		},
+	},
+	{
+		name:      "Battle of Trafalgar",
+		partOf:    "War of the Third Coalition",
+		summary:   "The Battle of Trafalgar was a naval engagement of the Napoleonic Wars.",
+		startDate: dates.Historic{Year: 1805, Month: 10, Day: 21},
+		endDate:   dates.Historic{Year: 1805, Month: 10, Day: 21},
+		location:  locations.Location{Place: "Cape Trafalgar, Spain", Latitude: `36°17'0"N`, Longitude: `6°15'0"W`},
+		result:    "Decisive British victory",
+		factions:  [2][]string{{"Kingdom of Great Britain"}, {"First French Empire"}},
+		commanders: map[string][]string{
+			"Kingdom of Great Britain": {"Horatio Nelson"},
+			"First French Empire":      {"Pierre-Charles Villeneuve"},
+		},
+	},
+	{
+		name:      "Battle of Austerlitz",
+		partOf:    "War of the Third Coalition",
+		summary:   "The Battle of Austerlitz was one of the most decisive engagements of the Napoleonic Wars.",
+		startDate: dates.Historic{Year: 1805, Month: 12, Day: 2},
+		endDate:   dates.Historic{Year: 1805, Month: 12, Day: 2},
+		location:  locations.Location{Place: "Austerlitz, Moravia", Latitude: `49°8'0"N`, Longitude: `16°46'0"E`},
+		result:    "Decisive French victory",
+		factions:  [2][]string{{"First French Empire"}, {"Russian Empire", "Austrian Empire"}},
+		commanders: map[string][]string{
+			"First French Empire": {"Napoleon"},
+			"Russian Empire":      {"Mikhail Kutuzov"},
+		},
+	},
+	{
+		name:      "Battle of Athos",
+		partOf:    "Russo-Turkish War",
+		summary:   "The Battle of Athos was fought in the Aegean Sea.",
+		startDate: dates.Historic{Year: 1807, Month: 6, Day: 30},
+		endDate:   dates.Historic{Year: 1807, Month: 7, Day: 1},
+		location:  locations.Location{Place: "Mount Athos, Aegean Sea"},
+		result:    "Russian victory",
+		factions:  [2][]string{{"Russian Empire"}, {"Ottoman Empire"}},
+		commanders: map[string][]string{
+			"Russian Empire": {"Dmitry Senyavin"},
+		},
+	},
+	{
+		name:      "Battle of Borodino",
+		partOf:    "French invasion of Russia",
+		summary:   "The Battle of Borodino was the bloodiest action of the Napoleonic Wars.",
+		startDate: dates.Historic{Year: 1812, Month: 9, Day: 7},
+		endDate:   dates.Historic{Year: 1812, Month: 9, Day: 7},
+		location:  locations.Location{Place: "Borodino, Russian Empire", Latitude: `55°31'0"N`, Longitude: `35°49'0"E`},
+		result:    "French victory",
+		factions:  [2][]string{{"First French Empire"}, {"Russian Empire"}},
+		commanders: map[string][]string{
+			"First French Empire": {"Napoleon"},
+			"Russian Empire":      {"Mikhail Kutuzov"},
+		},
+	},
+}
+
+// Seed stores a small set of factions, commanders and battles of the Napoleonic Wars, which are
+// related to each other in a way that every filter of the repositories selects a different subset
+// of them. The Austrian Empire is also stored as the successor of the Holy Roman Empire
+func Seed(t *testing.T, r Repositories) Fixtures {
+	t.Helper()
+	fx := Fixtures{
+		Factions:   make(map[string]uuid.UUID),
+		Commanders: make(map[string]uuid.UUID),
+		Battles:    make(map[string]uuid.UUID),
+	}
+
+	for i, f := range factionFixtures {
+		id, err := r.Factions.CreateOne(factions.CreationInput{
+			WikiID:  i + 1,
+			URL:     wikiURL(f.name),
+			Name:    f.name,
+			Summary: f.summary,
+		})
+		require.NoError(t, err, "Creating faction %s", f.name)
+		fx.Factions[f.name] = id
+	}
+	require.NoError(t, r.Factions.CreateRelationship(factions.RelationshipCreationInput{
+		FactionID: fx.Factions["Austrian Empire"],
+		Kind:      factions.PredecessorKind,
+		RelatedID: fx.Factions["Holy Roman Empire"],
+	}), "Relating the Austrian Empire to the Holy Roman Empire")
+
+	for i, c := range commanderFixtures {
+		id, err := r.Commanders.CreateOne(commanders.CreationInput{
+			WikiID:  i + 1,
+			URL:     wikiURL(c.name),
+			Name:    c.name,
+			Summary: c.summary,
+		})
+		require.NoError(t, err, "Creating commander %s", c.name)
+		fx.Commanders[c.name] = id
+	}
+
+	for i, b := range battleFixtures {
+		input := battles.CreationInput{
+			WikiID:              i + 1,
+			URL:                 wikiURL(b.name),
+			Name:                b.name,
+			PartOf:              b.partOf,
+			Summary:             b.summary,
+			StartDate:           b.startDate,
+			EndDate:             b.endDate,
+			Location:            b.location,
+			Result:              b.result,
+			CommandersByFaction: make(battles.CommandersByFaction),
+		}
+		for side, names := range b.factions {
+			for _, fName := range names {
+				fID := fx.Factions[fName]
+				var cIDs []uuid.UUID
+				for _, cName := range b.commanders[fName] {
+					cIDs = append(cIDs, fx.Commanders[cName])
+				}
+				if side == 0 {
+					input.FactionsBySide.A = append(input.FactionsBySide.A, fID)
+					input.CommandersBySide.A = append(input.CommandersBySide.A, cIDs...)
+				} else {
+					input.FactionsBySide.B = append(input.FactionsBySide.B, fID)
+					input.CommandersBySide.B = append(input.CommandersBySide.B, cIDs...)
+				}
+				if len(cIDs) > 0 {
+					input.CommandersByFaction[fID] = cIDs
+				}
+			}
+		}
+		id, err := r.Battles.CreateOne(input)
+		require.NoError(t, err, "Creating battle %s", b.name)
+		fx.Battles[b.name] = id
+	}
+	return fx
+}
+
+// SeedPaginated stores PaginatedCount factions, commanders and battles, all of which include the
+// PaginatedWord in their names
+func SeedPaginated(t *testing.T, r Repositories) {
+	t.Helper()
+	for i := 1; i <= PaginatedCount; i++ {
+		name := fmt.Sprintf("%s %d", PaginatedWord, i)
+		fID, err := r.Factions.CreateOne(factions.CreationInput{
+			WikiID: 1000 + i,
+			URL:    wikiURL(name + " faction"),
+			Name:   name + " faction",
+		})
+		require.NoError(t, err, "Creating faction %d", i)
+		cID, err := r.Commanders.CreateOne(commanders.CreationInput{
+			WikiID: 1000 + i,
+			URL:    wikiURL(name + " commander"),
+			Name:   name + " commander",
+		})
+		require.NoError(t, err, "Creating commander %d", i)
+		_, err = r.Battles.CreateOne(battles.CreationInput{
+			WikiID:              1000 + i,
+			URL:                 wikiURL(name + " battle"),
+			Name:                name + " battle",
+			Summary:             name + " battle was fought.",
+			StartDate:           dates.Historic{Year: 1000 + i},
+			EndDate:             dates.Historic{Year: 1000 + i},
+			Location:            locations.Location{Place: "Europe"},
+			Result:              "Inconclusive",
+			FactionsBySide:      battles.IDsBySide{A: []uuid.UUID{fID}},
+			CommandersBySide:    battles.IDsBySide{A: []uuid.UUID{cID}},
+			CommandersByFaction: battles.CommandersByFaction{fID: {cID}},
+		})
+		require.NoError(t, err, "Creating battle %d", i)
+	}
+}
+
+// wikiURL returns the URL of the English Wikipedia article with the given title
+func wikiURL(title string) string {
+	return "https://en.wikipedia.org/wiki/" + strings.ReplaceAll(title, " ", "_")
+}
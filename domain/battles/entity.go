@@ -6,6 +6,7 @@ import (
 	"github.com/sasalatart/batcoms/domain/locations"
 	"github.com/sasalatart/batcoms/domain/statistics"
 	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -28,6 +29,10 @@ type Battle struct {
 	Factions            FactionsBySide         `json:"factions"`
 	Commanders          CommandersBySide       `json:"commanders"`
 	CommandersByFaction CommandersByFaction    `json:"commandersByFaction"`
+	// CommanderConfidences tells how certain it is that each commander in CommandersByFaction
+	// fought under the faction to which it was assigned
+	CommanderConfidences map[uuid.UUID]Confidence `json:"commanderConfidences,omitempty"`
+	Wikidata             *wikidata.Facts          `json:"wikidata,omitempty"`
 }
 
 // FactionsBySide groups all of the factions that participated in a battle into the two opposing
@@ -54,3 +59,20 @@ type IDsBySide struct {
 // CommandersByFaction is a map that indexes the IDs of commanders according to the faction to which
 // they belong during a specific battle
 type CommandersByFaction map[uuid.UUID][]uuid.UUID
+
+// Without returns a copy of the battle where the given relations have been emptied, as if they had
+// not been loaded. CommandersByFaction and CommanderConfidences relate factions with commanders, so
+// they are emptied together with either of them
+func (b Battle) Without(omit Relations) Battle {
+	if omit.Factions {
+		b.Factions = FactionsBySide{}
+	}
+	if omit.Commanders {
+		b.Commanders = CommandersBySide{}
+	}
+	if omit.Factions || omit.Commanders {
+		b.CommandersByFaction = make(CommandersByFaction)
+		b.CommanderConfidences = nil
+	}
+	return b
+}
@@ -1,9 +1,12 @@
 package battles
 
 import (
+	"context"
+
 	"github.com/sasalatart/batcoms/domain/locations"
 	"github.com/sasalatart/batcoms/domain/statistics"
 	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -13,10 +16,16 @@ type Repository interface {
 	Writer
 }
 
-// Reader is the interface through which battles may be read
+// Reader is the interface through which battles may be read. Queries are scoped to the given
+// context, such as the one of the request that triggered them, so that they are traced as part of
+// it. Stream goes through all of the battles matching a query without paginating them, in the same
+// order as FindMany, and stops at the first error returned by each. Any tells whether at least one
+// battle is stored, without counting them
 type Reader interface {
-	FindOne(query FindOneQuery) (Battle, error)
-	FindMany(query FindManyQuery, page int) ([]Battle, int, error)
+	FindOne(ctx context.Context, query FindOneQuery) (Battle, error)
+	FindMany(ctx context.Context, query FindManyQuery, page int) ([]Battle, int, error)
+	Stream(ctx context.Context, query FindManyQuery, each func(Battle) error) error
+	Any(ctx context.Context) (bool, error)
 }
 
 // Writer is the interface through which battles may be written
@@ -24,41 +33,64 @@ type Writer interface {
 	CreateOne(data CreationInput) (uuid.UUID, error)
 }
 
-// FindOneQuery is used to refine the filters when finding one battle
+// FindOneQuery is used to refine the filters when finding one battle. Omit lists the relations
+// that need not be loaded together with the battle found
 type FindOneQuery struct {
 	ID   uuid.UUID
 	Name string
 	URL  string
+	Omit Relations
 }
 
-// FindManyQuery is used to refine the filters when finding many battles
+// FindManyQuery is used to refine the filters when finding many battles. ConcurrentWith restricts
+// the search to other battles whose dates overlap with those of the battle with the given ID, and
+// WithinKm further restricts them to those fought at most that many kilometres away from it.
+// RelatedTo restricts the search to other battles that share at least one faction or commander
+// with the battle with the given ID, or that were part of the same conflict. IncludeRelated extends
+// FactionID to the factions related to it, such as its predecessors, successors and children. Omit
+// lists the relations that need not be loaded together with the battles found
 type FindManyQuery struct {
-	FactionID   uuid.UUID
-	CommanderID uuid.UUID
-	Name        string
-	Summary     string
-	Place       string
-	Result      string
-	FromDate    dates.Historic
-	ToDate      dates.Historic
+	FactionID      uuid.UUID
+	IncludeRelated bool
+	CommanderID    uuid.UUID
+	Name           string
+	Summary        string
+	Place          string
+	Result         string
+	FromDate       dates.Historic
+	ToDate         dates.Historic
+	ConcurrentWith uuid.UUID
+	WithinKm       float64
+	RelatedTo      uuid.UUID
+	Omit           Relations
+}
+
+// Relations refers to the factions and commanders of battles, such as when telling which of them
+// need not be loaded
+type Relations struct {
+	Factions   bool
+	Commanders bool
 }
 
 // CreationInput is a struct that contains all of the data required to create a battle. This
 // includes annotations required by validations
 type CreationInput struct {
-	WikiID              int    `validate:"required"`
-	URL                 string `validate:"required,url"`
-	Name                string `validate:"required"`
-	PartOf              string
-	Summary             string         `validate:"required"`
-	StartDate           dates.Historic `validate:"required"`
-	EndDate             dates.Historic `validate:"required"`
-	Location            locations.Location
-	Result              string `validate:"required"`
-	TerritorialChanges  string
-	Strength            statistics.SideNumbers
-	Casualties          statistics.SideNumbers
-	FactionsBySide      IDsBySide
-	CommandersBySide    IDsBySide
-	CommandersByFaction CommandersByFaction
+	WikiID               int    `validate:"required"`
+	URL                  string `validate:"required,url"`
+	Name                 string `validate:"required"`
+	PartOf               string
+	Summary              string         `validate:"required"`
+	StartDate            dates.Historic `validate:"required"`
+	EndDate              dates.Historic `validate:"required"`
+	Location             locations.Location
+	Result               string `validate:"required"`
+	TerritorialChanges   string
+	Strength             statistics.SideNumbers
+	Casualties           statistics.SideNumbers
+	FactionsBySide       IDsBySide
+	CommandersBySide     IDsBySide
+	CommandersByFaction  CommandersByFaction
+	CommanderConfidences map[uuid.UUID]Confidence
+	FlagsByFaction       map[uuid.UUID]string
+	Wikidata             *wikidata.Facts
 }
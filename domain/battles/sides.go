@@ -0,0 +1,91 @@
+package battles
+
+import (
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Sides is an alternative representation of the factions and commanders of a battle, where each
+// commander is nested into the faction under which they fought
+type Sides struct {
+	A Side `json:"a"`
+	B Side `json:"b"`
+}
+
+// Side groups the factions of one of the sides of a battle, each with its commanders, together
+// with the commanders of that side whose faction is unknown
+type Side struct {
+	Factions             []FactionWithCommanders `json:"factions"`
+	UnassignedCommanders []commanders.Commander  `json:"unassignedCommanders"`
+}
+
+// FactionWithCommanders is a faction together with the commanders that fought under it in a battle
+type FactionWithCommanders struct {
+	factions.Faction
+	Commanders []AssignedCommander `json:"commanders"`
+}
+
+// AssignedCommander is a commander together with how certain it is that they fought under the
+// faction in which they are nested
+type AssignedCommander struct {
+	commanders.Commander
+	Confidence Confidence `json:"confidence,omitempty"`
+}
+
+// Confidence represents how certain it is that a commander fought under the faction to which it was
+// assigned in a battle
+type Confidence string
+
+const (
+	// FlagConfidence means that the commander was shown with the same flag as the faction
+	FlagConfidence Confidence = "flag"
+	// SingleFactionConfidence means that the faction was the only one in the side of the commander,
+	// which is less reliable, as factions are often omitted from infoboxes
+	SingleFactionConfidence Confidence = "singleFaction"
+)
+
+// Sides returns the factions and commanders of the battle grouped by side, with commanders nested
+// into their factions according to CommandersByFaction
+func (b Battle) Sides() Sides {
+	factionIDsByCommander := make(map[uuid.UUID][]uuid.UUID)
+	for fID, cIDs := range b.CommandersByFaction {
+		for _, cID := range cIDs {
+			factionIDsByCommander[cID] = append(factionIDsByCommander[cID], fID)
+		}
+	}
+
+	side := func(ff []factions.Faction, cc []commanders.Commander) Side {
+		res := Side{
+			Factions:             []FactionWithCommanders{},
+			UnassignedCommanders: []commanders.Commander{},
+		}
+		indexes := make(map[uuid.UUID]int)
+		for i, f := range ff {
+			indexes[f.ID] = i
+			res.Factions = append(res.Factions, FactionWithCommanders{Faction: f, Commanders: []AssignedCommander{}})
+		}
+		for _, c := range cc {
+			assigned := false
+			for _, fID := range factionIDsByCommander[c.ID] {
+				i, ok := indexes[fID]
+				if !ok {
+					continue
+				}
+				res.Factions[i].Commanders = append(res.Factions[i].Commanders, AssignedCommander{
+					Commander:  c,
+					Confidence: b.CommanderConfidences[c.ID],
+				})
+				assigned = true
+			}
+			if !assigned {
+				res.UnassignedCommanders = append(res.UnassignedCommanders, c)
+			}
+		}
+		return res
+	}
+	return Sides{
+		A: side(b.Factions.A, b.Commanders.A),
+		B: side(b.Factions.B, b.Commanders.B),
+	}
+}
@@ -0,0 +1,67 @@
+package battles_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSides(t *testing.T) {
+	assigned := func(c commanders.Commander, b battles.Battle) battles.AssignedCommander {
+		return battles.AssignedCommander{Commander: c, Confidence: b.CommanderConfidences[c.ID]}
+	}
+
+	t.Run("AllAssigned", func(t *testing.T) {
+		b := mocks.Battle()
+		sides := b.Sides()
+
+		assert.Equal(t, battles.Side{
+			Factions: []battles.FactionWithCommanders{
+				{Faction: mocks.Faction(), Commanders: []battles.AssignedCommander{assigned(mocks.Commander(), b)}},
+			},
+			UnassignedCommanders: []commanders.Commander{},
+		}, sides.A)
+		assert.Equal(t, battles.Side{
+			Factions: []battles.FactionWithCommanders{
+				{Faction: mocks.Faction2(), Commanders: []battles.AssignedCommander{
+					assigned(mocks.Commander2(), b),
+					assigned(mocks.Commander3(), b),
+				}},
+				{Faction: mocks.Faction3(), Commanders: []battles.AssignedCommander{
+					assigned(mocks.Commander4(), b),
+					assigned(mocks.Commander5(), b),
+				}},
+			},
+			UnassignedCommanders: []commanders.Commander{},
+		}, sides.B)
+	})
+
+	t.Run("SomeUnassigned", func(t *testing.T) {
+		b := mocks.Battle()
+		b.CommandersByFaction = battles.CommandersByFaction{
+			mocks.Faction3().ID: []uuid.UUID{mocks.Commander4().ID},
+		}
+		sides := b.Sides()
+
+		assert.Equal(t, []battles.AssignedCommander{}, sides.A.Factions[0].Commanders)
+		assert.Equal(t, []commanders.Commander{mocks.Commander()}, sides.A.UnassignedCommanders)
+		assert.Equal(t, []battles.AssignedCommander{}, sides.B.Factions[0].Commanders)
+		assert.Equal(t, []battles.AssignedCommander{assigned(mocks.Commander4(), b)}, sides.B.Factions[1].Commanders)
+		assert.Equal(t, []commanders.Commander{mocks.Commander2(), mocks.Commander3(), mocks.Commander5()}, sides.B.UnassignedCommanders)
+	})
+
+	t.Run("NoFactions", func(t *testing.T) {
+		b := mocks.Battle()
+		b.Factions = battles.FactionsBySide{A: []factions.Faction{}, B: []factions.Faction{}}
+		sides := b.Sides()
+
+		assert.Empty(t, sides.A.Factions)
+		assert.Equal(t, b.Commanders.A, sides.A.UnassignedCommanders)
+		assert.Equal(t, b.Commanders.B, sides.B.UnassignedCommanders)
+	})
+}
@@ -0,0 +1,242 @@
+// Package commanderstest provides a conformance suite that any implementation of
+// commanders.Repository should pass, so that all of them behave in the same way regardless of where
+// commanders are stored
+package commanderstest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/battles/battlestest"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepository runs the conformance suite against the commanders repositories returned by
+// newRepositories, which also includes the repositories of factions and battles given that
+// commanders are searched by the factions under which they fought. Commanders created from the
+// mocks must also be found as the mocks describe them
+func TestRepository(t *testing.T, newRepositories battlestest.Factory) {
+	t.Run("CreateOne", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			r := newRepositories(t)
+
+			id, err := r.Commanders.CreateOne(mocks.CommanderCreationInput())
+			require.NoError(t, err, "Creating commander with valid input")
+			assert.NotEqual(t, uuid.Nil, id, "Should generate an ID")
+
+			c, err := r.Commanders.FindOne(context.Background(), commanders.FindOneQuery{ID: id})
+			require.NoError(t, err, "Finding the created commander")
+			expected := mocks.Commander()
+			expected.ID = id
+			assert.Equal(t, expected, c)
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			r := newRepositories(t)
+
+			input := mocks.CommanderCreationInput()
+			input.URL = "not-a-url"
+			_, err := r.Commanders.CreateOne(input)
+			require.Error(t, err, "Creating commander with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+
+			_, err = r.Commanders.FindOne(context.Background(), commanders.FindOneQuery{Name: input.Name})
+			assert.Equal(t, domain.ErrNotFound, err, "Should not create the commander")
+		})
+		t.Run("WithInvalidAllegiance", func(t *testing.T) {
+			r := newRepositories(t)
+
+			input := mocks.CommanderCreationInput()
+			input.Allegiances = []commanders.Allegiance{{Name: ""}}
+			_, err := r.Commanders.CreateOne(input)
+			require.Error(t, err, "Creating commander with an allegiance without name")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+		})
+		t.Run("WithExistingURL", func(t *testing.T) {
+			r := newRepositories(t)
+
+			_, err := r.Commanders.CreateOne(mocks.CommanderCreationInput())
+			require.NoError(t, err, "Creating commander")
+			input := mocks.CommanderCreationInput()
+			input.WikiID++
+			_, err = r.Commanders.CreateOne(input)
+			assert.Error(t, err, "Creating commander with the URL of an existing one")
+		})
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		r := newRepositories(t)
+		fx := battlestest.Seed(t, r)
+		id := fx.Commanders["Mikhail Kutuzov"]
+		alias := commanders.AliasCreationInput{
+			CommanderID: id,
+			WikiID:      9999,
+			URL:         "https://en.wikipedia.org/wiki/Prince_Kutuzov",
+		}
+		require.NoError(t, r.Commanders.CreateAlias(alias), "Creating commander alias")
+
+		queries := map[string]commanders.FindOneQuery{
+			"ByID":          {ID: id},
+			"ByWikiID":      {WikiID: 8},
+			"ByName":        {Name: "Mikhail Kutuzov"},
+			"ByURL":         {URL: "https://en.wikipedia.org/wiki/Mikhail_Kutuzov"},
+			"ByAliasWikiID": {WikiID: alias.WikiID},
+			"ByAliasURL":    {URL: alias.URL},
+		}
+		for description, query := range queries {
+			t.Run(description, func(t *testing.T) {
+				c, err := r.Commanders.FindOne(context.Background(), query)
+				require.NoError(t, err, "Finding commander")
+				assert.Equal(t, id, c.ID)
+				assert.Equal(t, "Mikhail Kutuzov", c.Name)
+				assert.Equal(t, "https://en.wikipedia.org/wiki/Mikhail_Kutuzov", c.URL)
+			})
+		}
+
+		t.Run("NotFound", func(t *testing.T) {
+			for _, query := range []commanders.FindOneQuery{
+				{ID: uuid.NewV4()},
+				{WikiID: 1234},
+				{Name: "King Arthur"},
+				{URL: "https://en.wikipedia.org/wiki/King_Arthur"},
+			} {
+				_, err := r.Commanders.FindOne(context.Background(), query)
+				assert.Equal(t, domain.ErrNotFound, err)
+			}
+		})
+	})
+
+	t.Run("FindMany", func(t *testing.T) {
+		r := newRepositories(t)
+		fx := battlestest.Seed(t, r)
+
+		cases := []struct {
+			description   string
+			query         commanders.FindManyQuery
+			expectedNames []string
+		}{
+			{
+				description: "With no filters",
+				query:       commanders.FindManyQuery{},
+				expectedNames: []string{
+					"Pierre-Charles Villeneuve",
+					"Napoleon",
+					"Mikhail Kutuzov",
+					"Michel Ney",
+					"Karl Mack von Leiberich",
+					"Jean-Baptiste Jourdan",
+					"Horatio Nelson",
+					"Dmitry Senyavin",
+					"Archduke Charles",
+				},
+			},
+			{
+				description:   "By name",
+				query:         commanders.FindManyQuery{Name: "napoleon"},
+				expectedNames: []string{"Napoleon"},
+			},
+			{
+				description:   "By summary phrase",
+				query:         commanders.FindManyQuery{Summary: "field marshal"},
+				expectedNames: []string{"Mikhail Kutuzov", "Archduke Charles"},
+			},
+			{
+				description:   "By stemmed summary",
+				query:         commanders.FindManyQuery{Summary: "marshals"},
+				expectedNames: []string{"Mikhail Kutuzov", "Michel Ney", "Jean-Baptiste Jourdan", "Archduke Charles"},
+			},
+			{
+				description:   "By name and summary",
+				query:         commanders.FindManyQuery{Name: "karl", Summary: "austrian"},
+				expectedNames: []string{"Karl Mack von Leiberich"},
+			},
+			{
+				description: "By faction",
+				query:       commanders.FindManyQuery{FactionID: fx.Factions["First French Empire"]},
+				expectedNames: []string{
+					"Pierre-Charles Villeneuve",
+					"Napoleon",
+					"Michel Ney",
+					"Jean-Baptiste Jourdan",
+				},
+			},
+			{
+				description:   "By faction, without related ones",
+				query:         commanders.FindManyQuery{FactionID: fx.Factions["Holy Roman Empire"]},
+				expectedNames: []string{"Archduke Charles"},
+			},
+			{
+				description:   "By faction, including related ones",
+				query:         commanders.FindManyQuery{FactionID: fx.Factions["Holy Roman Empire"], IncludeRelated: true},
+				expectedNames: []string{"Karl Mack von Leiberich", "Archduke Charles"},
+			},
+			{
+				description:   "By faction without commanders",
+				query:         commanders.FindManyQuery{FactionID: fx.Factions["Ottoman Empire"]},
+				expectedNames: nil,
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				cc, pages, err := r.Commanders.FindMany(context.Background(), c.query, 1)
+				require.NoError(t, err, "Finding commanders")
+				assert.Equal(t, 1, pages)
+				var names []string
+				for _, c := range cc {
+					names = append(names, c.Name)
+				}
+				assert.Equal(t, c.expectedNames, names)
+			})
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		r := newRepositories(t)
+		battlestest.SeedPaginated(t, r)
+
+		for page, expectedLength := range map[int]int{1: 50, 2: battlestest.PaginatedCount - 50, 3: 0} {
+			cc, pages, err := r.Commanders.FindMany(context.Background(), commanders.FindManyQuery{Name: battlestest.PaginatedWord}, page)
+			require.NoError(t, err, "Finding page %d of commanders", page)
+			assert.Equal(t, 2, pages, "Pages when finding page %d", page)
+			assert.Len(t, cc, expectedLength, "Commanders in page %d", page)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		r := newRepositories(t)
+		battlestest.SeedPaginated(t, r)
+		query := commanders.FindManyQuery{Name: battlestest.PaginatedWord}
+
+		var paginated []commanders.Commander
+		for page := 1; page <= 2; page++ {
+			cc, _, err := r.Commanders.FindMany(context.Background(), query, page)
+			require.NoError(t, err, "Finding page %d of commanders", page)
+			paginated = append(paginated, cc...)
+		}
+		var streamed []commanders.Commander
+		err := r.Commanders.Stream(context.Background(), query, func(c commanders.Commander) error {
+			streamed = append(streamed, c)
+			return nil
+		})
+		require.NoError(t, err, "Streaming commanders")
+		assert.Len(t, streamed, battlestest.PaginatedCount)
+		assert.Equal(t, paginated, streamed, "Should stream the same commanders as the pages, in the same order")
+
+		errStop := errors.New("stop")
+		var count int
+		err = r.Commanders.Stream(context.Background(), query, func(c commanders.Commander) error {
+			count++
+			return errStop
+		})
+		assert.Equal(t, errStop, err, "Should return the error of each")
+		assert.Equal(t, 1, count, "Should stop at the first error")
+	})
+}
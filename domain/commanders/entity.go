@@ -1,12 +1,50 @@
 package commanders
 
-import uuid "github.com/satori/go.uuid"
+import (
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	uuid "github.com/satori/go.uuid"
+)
 
 // Commander represents a military leader involved in one or more battles in history
 type Commander struct {
-	ID      uuid.UUID `json:"id"`
-	WikiID  int       `json:"wikiID"`
-	URL     string    `json:"url"`
-	Name    string    `json:"name"`
-	Summary string    `json:"summary"`
+	ID          uuid.UUID       `json:"id"`
+	WikiID      int             `json:"wikiID"`
+	URL         string          `json:"url"`
+	Name        string          `json:"name"`
+	Summary     string          `json:"summary"`
+	BirthDate   *dates.Historic `json:"birthDate,omitempty"`
+	DeathDate   *dates.Historic `json:"deathDate,omitempty"`
+	Allegiances []Allegiance    `json:"allegiances,omitempty"`
+	Wikidata    *wikidata.Facts `json:"wikidata,omitempty"`
+}
+
+// Allegiance represents a state or faction served by a commander. From and To are only set when
+// the period in which it was served is known
+type Allegiance struct {
+	Name string          `validate:"required" json:"name"`
+	From *dates.Historic `json:"from,omitempty"`
+	To   *dates.Historic `json:"to,omitempty"`
+}
+
+// ErrFoughtBeforeBirth is used to communicate that a commander took part in a battle that ended
+// before they were born
+const ErrFoughtBeforeBirth = domain.Error("Fought before being born")
+
+// ErrFoughtAfterDeath is used to communicate that a commander took part in a battle that started
+// after they died
+const ErrFoughtAfterDeath = domain.Error("Fought after having died")
+
+// CheckLifespan returns an error if the commander could not have fought in a battle that took place
+// between the given dates, judging by their dates of birth and death. The uncertainty of all dates
+// is taken into account, so only clear contradictions are reported
+func (c Commander) CheckLifespan(start, end dates.Historic) error {
+	if c.BirthDate != nil && end.Latest().ToEnd().ToNum() < c.BirthDate.Earliest().ToBeginning().ToNum() {
+		return ErrFoughtBeforeBirth
+	}
+	if c.DeathDate != nil && start.Earliest().ToBeginning().ToNum() > c.DeathDate.Latest().ToEnd().ToNum() {
+		return ErrFoughtAfterDeath
+	}
+	return nil
 }
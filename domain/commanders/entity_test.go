@@ -0,0 +1,72 @@
+package commanders_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckLifespan(t *testing.T) {
+	napoleon := commanders.Commander{
+		BirthDate: &dates.Historic{Year: 1769, Month: 8, Day: 15},
+		DeathDate: &dates.Historic{Year: 1821, Month: 5, Day: 5},
+	}
+	cases := []struct {
+		commander commanders.Commander
+		start     dates.Historic
+		end       dates.Historic
+		expected  error
+	}{
+		{
+			commander: napoleon,
+			start:     dates.Historic{Year: 1805, Month: 12, Day: 2},
+			end:       dates.Historic{Year: 1805, Month: 12, Day: 2},
+			expected:  nil,
+		},
+		{
+			commander: napoleon,
+			start:     dates.Historic{Year: 1704, Month: 8, Day: 13},
+			end:       dates.Historic{Year: 1704, Month: 8, Day: 13},
+			expected:  commanders.ErrFoughtBeforeBirth,
+		},
+		{
+			commander: napoleon,
+			start:     dates.Historic{Year: 1870, Month: 9, Day: 1},
+			end:       dates.Historic{Year: 1870, Month: 9, Day: 2},
+			expected:  commanders.ErrFoughtAfterDeath,
+		},
+		{
+			commander: napoleon,
+			start:     dates.Historic{Year: 1821},
+			end:       dates.Historic{Year: 1821},
+			expected:  nil,
+		},
+		{
+			commander: commanders.Commander{
+				BirthDate: &dates.Historic{
+					Year:      1500,
+					IsBCE:     true,
+					Precision: dates.CenturyPrecision,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1500, IsBCE: true},
+						Latest:   dates.Historic{Year: 1401, IsBCE: true},
+					},
+				},
+			},
+			start:    dates.Historic{Year: 1457, Month: 4, Day: 16, IsBCE: true},
+			end:      dates.Historic{Year: 1457, Month: 4, Day: 16, IsBCE: true},
+			expected: nil,
+		},
+		{
+			commander: commanders.Commander{},
+			start:     dates.Historic{Year: 1805, Month: 12, Day: 2},
+			end:       dates.Historic{Year: 1805, Month: 12, Day: 2},
+			expected:  nil,
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.commander.CheckLifespan(c.start, c.end), "Checking %v – %v", c.start, c.end)
+	}
+}
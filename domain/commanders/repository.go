@@ -1,6 +1,12 @@
 package commanders
 
-import uuid "github.com/satori/go.uuid"
+import (
+	"context"
+
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	uuid "github.com/satori/go.uuid"
+)
 
 // Repository is the interface through which commanders may be read and written
 type Repository interface {
@@ -8,36 +14,58 @@ type Repository interface {
 	Writer
 }
 
-// Reader is the interface through which commanders may be read
+// Reader is the interface through which commanders may be read. Queries are scoped to the given
+// context, such as the one of the request that triggered them, so that they are traced as part of
+// it. Stream goes through all of the commanders matching a query without paginating them, in the
+// same order as FindMany, and stops at the first error returned by each
 type Reader interface {
-	FindOne(query FindOneQuery) (Commander, error)
-	FindMany(query FindManyQuery, page int) ([]Commander, int, error)
+	FindOne(ctx context.Context, query FindOneQuery) (Commander, error)
+	FindMany(ctx context.Context, query FindManyQuery, page int) ([]Commander, int, error)
+	Stream(ctx context.Context, query FindManyQuery, each func(Commander) error) error
 }
 
 // Writer is the interface through which commanders may be written
 type Writer interface {
 	CreateOne(data CreationInput) (uuid.UUID, error)
+	CreateAlias(data AliasCreationInput) error
 }
 
-// FindOneQuery is used to refine the filters when finding one commander
+// FindOneQuery is used to refine the filters when finding one commander. When searching by WikiID
+// or URL, those of commanders that have been merged into another one resolve to the latter
 type FindOneQuery struct {
-	ID   uuid.UUID
-	Name string
-	URL  string
+	ID     uuid.UUID
+	WikiID int
+	Name   string
+	URL    string
 }
 
-// FindManyQuery is used to refine the filters when finding many commanders
+// FindManyQuery is used to refine the filters when finding many commanders. IncludeRelated extends
+// FactionID to the factions related to it, such as its predecessors, successors and children
 type FindManyQuery struct {
-	FactionID uuid.UUID
-	Name      string
-	Summary   string
+	FactionID      uuid.UUID
+	IncludeRelated bool
+	Name           string
+	Summary        string
 }
 
 // CreationInput is a struct that contains all of the data required to create a commander. This
 // includes annotations required by validations
 type CreationInput struct {
-	WikiID  int    `validate:"required"`
-	URL     string `validate:"required,url"`
-	Name    string `validate:"required"`
-	Summary string
+	WikiID      int    `validate:"required"`
+	URL         string `validate:"required,url"`
+	Name        string `validate:"required"`
+	Summary     string
+	BirthDate   *dates.Historic
+	DeathDate   *dates.Historic
+	Allegiances []Allegiance `validate:"dive"`
+	Wikidata    *wikidata.Facts
+}
+
+// AliasCreationInput is a struct that contains all of the data required to keep the WikiID and URL
+// of a duplicate commander as aliases of the one it was merged into. This includes annotations
+// required by validations
+type AliasCreationInput struct {
+	CommanderID uuid.UUID `validate:"required"`
+	WikiID      int       `validate:"required"`
+	URL         string    `validate:"required,url"`
 }
@@ -0,0 +1,230 @@
+// Package factionstest provides a conformance suite that any implementation of factions.Repository
+// should pass, so that all of them behave in the same way regardless of where factions are stored
+package factionstest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-playground/validator"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/battles/battlestest"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRepository runs the conformance suite against the factions repositories returned by
+// newRepositories, which also includes the repositories of commanders and battles given that
+// factions are searched by the commanders that fought under them. Factions created from the mocks
+// must also be found as the mocks describe them
+func TestRepository(t *testing.T, newRepositories battlestest.Factory) {
+	t.Run("CreateOne", func(t *testing.T) {
+		t.Run("WithValidInput", func(t *testing.T) {
+			r := newRepositories(t)
+
+			id, err := r.Factions.CreateOne(mocks.FactionCreationInput())
+			require.NoError(t, err, "Creating faction with valid input")
+			assert.NotEqual(t, uuid.Nil, id, "Should generate an ID")
+
+			f, err := r.Factions.FindOne(context.Background(), factions.FindOneQuery{ID: id})
+			require.NoError(t, err, "Finding the created faction")
+			expected := mocks.Faction()
+			expected.ID = id
+			assert.Equal(t, expected, f)
+		})
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			r := newRepositories(t)
+
+			input := mocks.FactionCreationInput()
+			input.URL = "not-a-url"
+			_, err := r.Factions.CreateOne(input)
+			require.Error(t, err, "Creating faction with invalid input")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+
+			_, err = r.Factions.FindOne(context.Background(), factions.FindOneQuery{Name: input.Name})
+			assert.Equal(t, domain.ErrNotFound, err, "Should not create the faction")
+		})
+		t.Run("WithExistingURL", func(t *testing.T) {
+			r := newRepositories(t)
+
+			_, err := r.Factions.CreateOne(mocks.FactionCreationInput())
+			require.NoError(t, err, "Creating faction")
+			input := mocks.FactionCreationInput()
+			input.WikiID++
+			_, err = r.Factions.CreateOne(input)
+			assert.Error(t, err, "Creating faction with the URL of an existing one")
+		})
+	})
+
+	t.Run("CreateRelationship", func(t *testing.T) {
+		t.Run("WithInvalidInput", func(t *testing.T) {
+			r := newRepositories(t)
+			fx := battlestest.Seed(t, r)
+
+			err := r.Factions.CreateRelationship(factions.RelationshipCreationInput{
+				FactionID: fx.Factions["Austrian Empire"],
+				Kind:      "ally",
+				RelatedID: fx.Factions["Russian Empire"],
+			})
+			require.Error(t, err, "Creating relationship of an unknown kind")
+			_, isValidationError := errors.Cause(err).(validator.ValidationErrors)
+			assert.True(t, isValidationError, "Error should be a validator.ValidationErrors")
+		})
+	})
+
+	t.Run("FindOne", func(t *testing.T) {
+		r := newRepositories(t)
+		fx := battlestest.Seed(t, r)
+		id := fx.Factions["Austrian Empire"]
+		alias := factions.AliasCreationInput{
+			FactionID: id,
+			WikiID:    9999,
+			URL:       "https://en.wikipedia.org/wiki/Empire_of_Austria",
+		}
+		require.NoError(t, r.Factions.CreateAlias(alias), "Creating faction alias")
+
+		queries := map[string]factions.FindOneQuery{
+			"ByID":          {ID: id},
+			"ByWikiID":      {WikiID: 3},
+			"ByName":        {Name: "Austrian Empire"},
+			"ByURL":         {URL: "https://en.wikipedia.org/wiki/Austrian_Empire"},
+			"ByAliasWikiID": {WikiID: alias.WikiID},
+			"ByAliasURL":    {URL: alias.URL},
+		}
+		for description, query := range queries {
+			t.Run(description, func(t *testing.T) {
+				f, err := r.Factions.FindOne(context.Background(), query)
+				require.NoError(t, err, "Finding faction")
+				assert.Equal(t, id, f.ID)
+				assert.Equal(t, "Austrian Empire", f.Name)
+				assert.Equal(t, "https://en.wikipedia.org/wiki/Austrian_Empire", f.URL)
+			})
+		}
+
+		t.Run("NotFound", func(t *testing.T) {
+			for _, query := range []factions.FindOneQuery{
+				{ID: uuid.NewV4()},
+				{WikiID: 1234},
+				{Name: "Kingdom of Atlantis"},
+				{URL: "https://en.wikipedia.org/wiki/Kingdom_of_Atlantis"},
+			} {
+				_, err := r.Factions.FindOne(context.Background(), query)
+				assert.Equal(t, domain.ErrNotFound, err)
+			}
+		})
+	})
+
+	t.Run("FindMany", func(t *testing.T) {
+		r := newRepositories(t)
+		fx := battlestest.Seed(t, r)
+
+		cases := []struct {
+			description   string
+			query         factions.FindManyQuery
+			expectedNames []string
+		}{
+			{
+				description: "With no filters",
+				query:       factions.FindManyQuery{},
+				expectedNames: []string{
+					"Russian Empire",
+					"Ottoman Empire",
+					"Kingdom of Great Britain",
+					"Holy Roman Empire",
+					"First French Empire",
+					"Austrian Empire",
+				},
+			},
+			{
+				description: "By stemmed name",
+				query:       factions.FindManyQuery{Name: "empires"},
+				expectedNames: []string{
+					"Russian Empire",
+					"Ottoman Empire",
+					"Holy Roman Empire",
+					"First French Empire",
+					"Austrian Empire",
+				},
+			},
+			{
+				description:   "By summary phrase",
+				query:         factions.FindManyQuery{Summary: "Habsburg monarchy"},
+				expectedNames: []string{"Holy Roman Empire", "Austrian Empire"},
+			},
+			{
+				description:   "By commander",
+				query:         factions.FindManyQuery{CommanderID: fx.Commanders["Archduke Charles"]},
+				expectedNames: []string{"Holy Roman Empire", "Austrian Empire"},
+			},
+			{
+				description:   "By commander and name",
+				query:         factions.FindManyQuery{CommanderID: fx.Commanders["Archduke Charles"], Name: "holy"},
+				expectedNames: []string{"Holy Roman Empire"},
+			},
+			{
+				description:   "By commander without factions",
+				query:         factions.FindManyQuery{CommanderID: uuid.NewV4()},
+				expectedNames: nil,
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				ff, pages, err := r.Factions.FindMany(context.Background(), c.query, 1)
+				require.NoError(t, err, "Finding factions")
+				assert.Equal(t, 1, pages)
+				var names []string
+				for _, f := range ff {
+					names = append(names, f.Name)
+				}
+				assert.Equal(t, c.expectedNames, names)
+			})
+		}
+	})
+
+	t.Run("Pagination", func(t *testing.T) {
+		r := newRepositories(t)
+		battlestest.SeedPaginated(t, r)
+
+		for page, expectedLength := range map[int]int{1: 50, 2: battlestest.PaginatedCount - 50, 3: 0} {
+			ff, pages, err := r.Factions.FindMany(context.Background(), factions.FindManyQuery{Name: battlestest.PaginatedWord}, page)
+			require.NoError(t, err, "Finding page %d of factions", page)
+			assert.Equal(t, 2, pages, "Pages when finding page %d", page)
+			assert.Len(t, ff, expectedLength, "Factions in page %d", page)
+		}
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		r := newRepositories(t)
+		battlestest.SeedPaginated(t, r)
+		query := factions.FindManyQuery{Name: battlestest.PaginatedWord}
+
+		var paginated []factions.Faction
+		for page := 1; page <= 2; page++ {
+			ff, _, err := r.Factions.FindMany(context.Background(), query, page)
+			require.NoError(t, err, "Finding page %d of factions", page)
+			paginated = append(paginated, ff...)
+		}
+		var streamed []factions.Faction
+		err := r.Factions.Stream(context.Background(), query, func(f factions.Faction) error {
+			streamed = append(streamed, f)
+			return nil
+		})
+		require.NoError(t, err, "Streaming factions")
+		assert.Len(t, streamed, battlestest.PaginatedCount)
+		assert.Equal(t, paginated, streamed, "Should stream the same factions as the pages, in the same order")
+
+		errStop := errors.New("stop")
+		var count int
+		err = r.Factions.Stream(context.Background(), query, func(f factions.Faction) error {
+			count++
+			return errStop
+		})
+		assert.Equal(t, errStop, err, "Should return the error of each")
+		assert.Equal(t, 1, count, "Should stop at the first error")
+	})
+}
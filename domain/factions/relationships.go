@@ -0,0 +1,34 @@
+package factions
+
+import uuid "github.com/satori/go.uuid"
+
+// RelationshipKind represents the way in which a faction is related to another one
+type RelationshipKind string
+
+const (
+	// ParentKind means that the related faction contained the faction, such as an empire and one of
+	// its kingdoms
+	ParentKind RelationshipKind = "parent"
+	// PredecessorKind means that the related faction was succeeded by the faction, such as a kingdom
+	// and the republic that replaced it
+	PredecessorKind RelationshipKind = "predecessor"
+	// CoalitionKind means that the faction was a member of the related faction, which is a coalition
+	CoalitionKind RelationshipKind = "coalition"
+)
+
+// RelationshipMapping relates two factions identified by the URLs of their Wikipedia articles. The
+// relationships between factions are maintained by hand as a list of these, given that the URLs
+// stay the same after each scrape
+type RelationshipMapping struct {
+	Faction string           `json:"faction"`
+	Kind    RelationshipKind `json:"kind"`
+	Related string           `json:"related"`
+}
+
+// RelationshipCreationInput is a struct that contains all of the data required to relate a faction
+// to another one. This includes annotations required by validations
+type RelationshipCreationInput struct {
+	FactionID uuid.UUID        `validate:"required"`
+	Kind      RelationshipKind `validate:"required,oneof=parent predecessor coalition"`
+	RelatedID uuid.UUID        `validate:"required"`
+}
@@ -1,6 +1,11 @@
 package factions
 
-import uuid "github.com/satori/go.uuid"
+import (
+	"context"
+
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	uuid "github.com/satori/go.uuid"
+)
 
 // Repository is the interface through which factions may be read and written
 type Repository interface {
@@ -8,22 +13,30 @@ type Repository interface {
 	Writer
 }
 
-// Reader is the interface through which factions may be read
+// Reader is the interface through which factions may be read. Queries are scoped to the given
+// context, such as the one of the request that triggered them, so that they are traced as part of
+// it. Stream goes through all of the factions matching a query without paginating them, in the same
+// order as FindMany, and stops at the first error returned by each
 type Reader interface {
-	FindOne(query FindOneQuery) (Faction, error)
-	FindMany(query FindManyQuery, page int) ([]Faction, int, error)
+	FindOne(ctx context.Context, query FindOneQuery) (Faction, error)
+	FindMany(ctx context.Context, query FindManyQuery, page int) ([]Faction, int, error)
+	Stream(ctx context.Context, query FindManyQuery, each func(Faction) error) error
 }
 
 // Writer is the interface through which factions may be written
 type Writer interface {
 	CreateOne(data CreationInput) (uuid.UUID, error)
+	CreateRelationship(data RelationshipCreationInput) error
+	CreateAlias(data AliasCreationInput) error
 }
 
-// FindOneQuery is used to refine the filters when finding one faction
+// FindOneQuery is used to refine the filters when finding one faction. When searching by WikiID or
+// URL, those of factions that have been merged into another one resolve to the latter
 type FindOneQuery struct {
-	ID   uuid.UUID
-	Name string
-	URL  string
+	ID     uuid.UUID
+	WikiID int
+	Name   string
+	URL    string
 }
 
 // FindManyQuery is used to refine the filters when finding many factions
@@ -36,8 +49,19 @@ type FindManyQuery struct {
 // CreationInput is a struct that contains all of the data required to create a faction. This
 // includes annotations required by validations
 type CreationInput struct {
-	WikiID  int    `validate:"required"`
-	URL     string `validate:"required,url"`
-	Name    string `validate:"required"`
-	Summary string
+	WikiID   int    `validate:"required"`
+	URL      string `validate:"required,url"`
+	Name     string `validate:"required"`
+	Summary  string
+	Flag     string
+	Wikidata *wikidata.Facts
+}
+
+// AliasCreationInput is a struct that contains all of the data required to keep the WikiID and URL
+// of a duplicate faction as aliases of the one it was merged into. This includes annotations
+// required by validations
+type AliasCreationInput struct {
+	FactionID uuid.UUID `validate:"required"`
+	WikiID    int       `validate:"required"`
+	URL       string    `validate:"required,url"`
 }
@@ -0,0 +1,58 @@
+package locations
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// coordinateMatcher matches coordinates written in degrees, minutes and seconds, such as "49°8′N",
+// "45°19′00″N" or "32°34′59.38″N". Minutes and seconds are optional, and both typographic and ASCII
+// primes are accepted
+var coordinateMatcher = regexp.MustCompile(`^(\d+(?:\.\d+)?)°\s*(?:(\d+(?:\.\d+)?)[′'])?\s*(?:(\d+(?:\.\d+)?)(?:″|"|′′|''))?\s*([NSEW])$`)
+
+// Coordinates translates the Latitude and Longitude of the location into signed decimal degrees,
+// where south latitudes and west longitudes are negative. The last returned value is false when
+// any of them is missing or can not be understood
+func (l Location) Coordinates() (float64, float64, bool) {
+	lat, latOK := parseCoordinate(l.Latitude, "N", "S")
+	lon, lonOK := parseCoordinate(l.Longitude, "E", "W")
+	if !latOK || !lonOK {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// DistanceKm calculates the great-circle distance in kilometres between two coordinates given in
+// signed decimal degrees, by using the spherical law of cosines
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	rad := func(deg float64) float64 {
+		return deg * math.Pi / 180
+	}
+	cos := math.Cos(rad(lat1))*math.Cos(rad(lat2))*math.Cos(rad(lon2)-rad(lon1)) +
+		math.Sin(rad(lat1))*math.Sin(rad(lat2))
+	return 6371 * math.Acos(math.Max(-1, math.Min(1, cos)))
+}
+
+func parseCoordinate(text, positive, negative string) (float64, bool) {
+	matches := coordinateMatcher.FindStringSubmatch(strings.TrimSpace(text))
+	if matches == nil || (matches[4] != positive && matches[4] != negative) {
+		return 0, false
+	}
+	var result float64
+	for i, divisor := range []float64{1, 60, 3600} {
+		if matches[i+1] == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(matches[i+1], 64)
+		if err != nil {
+			return 0, false
+		}
+		result += value / divisor
+	}
+	if matches[4] == negative {
+		result = -result
+	}
+	return result, true
+}
@@ -0,0 +1,81 @@
+package locations_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoordinates(t *testing.T) {
+	cases := []struct {
+		location    locations.Location
+		expectedLat float64
+		expectedLon float64
+		expectedOK  bool
+	}{
+		{
+			location:    locations.Location{Latitude: "49°8′N", Longitude: "16°46′E"},
+			expectedLat: 49.1333,
+			expectedLon: 16.7667,
+			expectedOK:  true,
+		},
+		{
+			location:    locations.Location{Latitude: "45°19′00″N", Longitude: "9°30′00″E"},
+			expectedLat: 45.3167,
+			expectedLon: 9.5,
+			expectedOK:  true,
+		},
+		{
+			location:    locations.Location{Latitude: "32°34′59.38″N", Longitude: "35°10′55.51″E"},
+			expectedLat: 32.5832,
+			expectedLon: 35.1821,
+			expectedOK:  true,
+		},
+		{
+			location:    locations.Location{Latitude: "49°8'0\"N", Longitude: "16°46'0\"E"},
+			expectedLat: 49.1333,
+			expectedLon: 16.7667,
+			expectedOK:  true,
+		},
+		{
+			location:    locations.Location{Latitude: "33°54′S", Longitude: "151°12′W"},
+			expectedLat: -33.9,
+			expectedLon: -151.2,
+			expectedOK:  true,
+		},
+		{
+			location:   locations.Location{Latitude: "16°46′E", Longitude: "49°8′N"},
+			expectedOK: false,
+		},
+		{
+			location:   locations.Location{Latitude: "49°8′N"},
+			expectedOK: false,
+		},
+		{
+			location:   locations.Location{},
+			expectedOK: false,
+		},
+	}
+	for _, c := range cases {
+		lat, lon, ok := c.location.Coordinates()
+		assert.Equal(t, c.expectedOK, ok, "Parsing %q, %q", c.location.Latitude, c.location.Longitude)
+		assert.InDelta(t, c.expectedLat, lat, 0.0001, "Latitude of %q", c.location.Latitude)
+		assert.InDelta(t, c.expectedLon, lon, 0.0001, "Longitude of %q", c.location.Longitude)
+	}
+}
+
+func TestDistanceKm(t *testing.T) {
+	cases := []struct {
+		lat1, lon1, lat2, lon2 float64
+		expected               float64
+	}{
+		{lat1: 49.1333, lon1: 16.7667, lat2: 49.1333, lon2: 16.7667, expected: 0},
+		{lat1: 49.1333, lon1: 16.7667, lat2: 45.3167, lon2: 9.5, expected: 693},
+		{lat1: 0, lon1: 0, lat2: 0, lon2: 180, expected: 20015},
+	}
+	for _, c := range cases {
+		distance := locations.DistanceKm(c.lat1, c.lon1, c.lat2, c.lon2)
+		assert.InDelta(t, c.expected, distance, 1, "Distance between (%v, %v) and (%v, %v)", c.lat1, c.lon1, c.lat2, c.lon2)
+	}
+}
@@ -9,6 +9,8 @@ type Summary struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Extract     string `json:"extract"`
+	// RedirectURL is the URL of the article that the requested one redirects to, if any
+	RedirectURL string `json:"-"`
 }
 
 // ErrNoSummary is used to communicate that no summary could be obtained from Wikipedia's API
@@ -16,3 +18,6 @@ const ErrNoSummary = domain.Error("No summary found")
 
 // ErrNotWiki is used to communicate that the URL did not correspond to a Wiki page
 const ErrNotWiki = domain.Error("Not a Wiki page")
+
+// ErrNoLanguageLink is used to communicate that a Wikipedia page has no version in another language
+const ErrNoLanguageLink = domain.Error("No interlanguage link found")
@@ -2,11 +2,14 @@ package summaries
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/pkg/metrics"
 	"github.com/sasalatart/batcoms/pkg/strclean"
 )
 
@@ -25,6 +28,7 @@ func Fetch(url string) (Summary, error) {
 		return summary, errors.Wrapf(err, "GET %s failed", summaryURL)
 	}
 	defer resp.Body.Close()
+	metrics.ScraperSummaries.Inc()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
@@ -36,8 +40,75 @@ func Fetch(url string) (Summary, error) {
 	if strings.Contains(strings.ToLower(summary.Title), "not found") || strings.Contains(summary.Type, "not_found") {
 		return summary, ErrNoSummary
 	}
+	summary.RedirectURL = redirectURL(resp, summaryURL)
 	summary.Title = strclean.Apply(summary.Title)
 	summary.Description = strclean.Apply(summary.Description)
 	summary.Extract = strclean.Apply(summary.Extract)
 	return summary, nil
 }
+
+// redirectURL returns the URL of the article that the summary API redirected a request to, or an
+// empty string if it was not redirected
+func redirectURL(resp *http.Response, summaryURL string) string {
+	requested, err := url.Parse(summaryURL)
+	if err != nil {
+		return ""
+	}
+	requested.Fragment = ""
+	final := *resp.Request.URL
+	final.Fragment = ""
+	if final.String() == requested.String() {
+		return ""
+	}
+	return strings.Replace(final.String(), "/api/rest_v1/page/summary/", "/wiki/", 1)
+}
+
+// langLinks is the subset of the response of Wikipedia's langlinks API used to find the version of
+// a page in another language edition
+type langLinks struct {
+	Query struct {
+		Pages []struct {
+			LangLinks []struct {
+				URL string `json:"url"`
+			} `json:"langlinks"`
+		} `json:"pages"`
+	} `json:"query"`
+}
+
+// FetchIn is like Fetch, but for the version of the Wikipedia URL page written in another language,
+// identified by its ISO 639-1 code, as found through its interlanguage links
+func FetchIn(pageURL, language string) (Summary, error) {
+	parts := strings.SplitN(pageURL, "/wiki/", 2)
+	if len(parts) != 2 {
+		return Summary{}, ErrNotWiki
+	}
+	title, err := url.PathUnescape(parts[1])
+	if err != nil {
+		return Summary{}, errors.Wrapf(err, "Unescaping title of %s", pageURL)
+	}
+
+	langLinksURL := fmt.Sprintf(
+		"%s/w/api.php?action=query&prop=langlinks&llprop=url&format=json&formatversion=2&lllang=%s&titles=%s",
+		parts[0],
+		url.QueryEscape(language),
+		url.QueryEscape(title),
+	)
+	resp, err := http.Get(langLinksURL)
+	if err != nil {
+		return Summary{}, errors.Wrapf(err, "GET %s failed", langLinksURL)
+	}
+	defer resp.Body.Close()
+
+	links := langLinks{}
+	if err = json.NewDecoder(resp.Body).Decode(&links); err != nil {
+		return Summary{}, errors.Wrapf(err, "Unmarshalling response from %s", langLinksURL)
+	}
+	for _, page := range links.Query.Pages {
+		for _, link := range page.LangLinks {
+			if link.URL != "" {
+				return Fetch(link.URL)
+			}
+		}
+	}
+	return Summary{}, ErrNoLanguageLink
+}
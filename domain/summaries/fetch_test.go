@@ -31,6 +31,13 @@ func TestFetchSummary(t *testing.T) {
 		assert.EqualError(t, errors.Cause(err), summaries.ErrNoSummary.Error(), "Error should be a summaries.ErrNoSummary")
 	})
 
+	t.Run("InOtherLanguage", func(t *testing.T) {
+		t.Parallel()
+		got, err := summaries.FetchIn("https://en.wikipedia.org/wiki/Battle_of_Austerlitz", "es")
+		require.NoError(t, err, "Fetching summary in Spanish for valid URL")
+		assert.Equal(t, "Batalla de Austerlitz", got.Title, "Comparing obtained title with expected one")
+	})
+
 	t.Run("NonWikiURL", func(t *testing.T) {
 		t.Parallel()
 		_, err := summaries.Fetch("https://i-do-not-exist.org")
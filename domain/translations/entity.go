@@ -0,0 +1,30 @@
+package translations
+
+import uuid "github.com/satori/go.uuid"
+
+// Translation stores the name and summary of a faction, commander or battle in a language other
+// than the DefaultLanguage, as found in other language editions of Wikipedia
+type Translation struct {
+	EntityID uuid.UUID `json:"entityID"`
+	Language string    `json:"language"`
+	Name     string    `json:"name"`
+	Summary  string    `json:"summary"`
+}
+
+// DefaultLanguage is the language in which factions, commanders and battles are stored, and the one
+// used when no translation exists for the requested language
+const DefaultLanguage = "en"
+
+// Languages are the ISO 639-1 codes of the languages in which content may be served, starting with
+// the DefaultLanguage
+var Languages = []string{DefaultLanguage, "es", "fr", "de"}
+
+// IsSupported returns true if content may be served in the given language
+func IsSupported(language string) bool {
+	for _, l := range Languages {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package translations
+
+import (
+	"context"
+
+	uuid "github.com/satori/go.uuid"
+)
+
+// Repository is the interface through which translations may be read and written
+type Repository interface {
+	Reader
+	Writer
+}
+
+// Reader is the interface through which translations may be read. Queries are scoped to the given
+// context, such as the one of the request that triggered them, so that they are traced as part of
+// it
+type Reader interface {
+	FindMany(ctx context.Context, query FindManyQuery) ([]Translation, error)
+}
+
+// Writer is the interface through which translations may be written
+type Writer interface {
+	CreateOne(data CreationInput) (uuid.UUID, error)
+}
+
+// FindManyQuery is used to find the translations of the factions, commanders or battles with the
+// given IDs into a language
+type FindManyQuery struct {
+	Language  string
+	EntityIDs []uuid.UUID
+}
+
+// CreationInput is a struct that contains all of the data required to create a translation. This
+// includes annotations required by validations
+type CreationInput struct {
+	EntityID uuid.UUID `validate:"required"`
+	Language string    `validate:"required,len=2"`
+	Name     string    `validate:"required"`
+	Summary  string
+}
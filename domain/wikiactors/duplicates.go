@@ -0,0 +1,218 @@
+package wikiactors
+
+import (
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Duplicates is a group of actors of the same kind that are likely to be the same entity, together
+// with the reasons why they were grouped. These are only candidates, and must be reviewed before
+// being merged
+type Duplicates struct {
+	Kind    Kind
+	IDs     []int
+	Reasons []string
+}
+
+// Merge describes actors that have been reviewed and approved as being the same entity. Those with
+// the Duplicate IDs are merged into the one with the Canonical ID, keeping their IDs and URLs as
+// aliases of it
+type Merge struct {
+	Kind       Kind  `json:"kind"`
+	Canonical  int   `json:"canonical"`
+	Duplicates []int `json:"duplicates"`
+}
+
+var parenthesesMatcher = regexp.MustCompile(`\s*\([^)]*\)`)
+var nonAlphanumericMatcher = regexp.MustCompile(`[^a-z0-9]+`)
+
+var diacriticsReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ñ", "n", "ç", "c", "ß", "ss",
+)
+
+// nameStopWords are words that are too common in the names of actors to relate them on their own
+var nameStopWords = map[string]bool{
+	"the": true, "of": true, "and": true, "de": true, "la": true, "von": true,
+	"empire": true, "kingdom": true, "republic": true, "army": true, "forces": true,
+}
+
+// FindDuplicates groups the given actors that are likely to be duplicates of each other. Actors
+// are grouped when their normalized names are the same, when their URLs point to the same article
+// (which happens with links to different sections of it), when they redirect to the same article or
+// one redirects to the article of the other, when they refer to the same Wikidata item, or, for
+// factions, when they share a flag and at least one significant word of their names. Groupings are
+// transitive, and actors of different kinds are never grouped together
+func FindDuplicates(actors []Actor) []Duplicates {
+	actors = append([]Actor{}, actors...)
+	sort.Slice(actors, func(i, j int) bool {
+		if actors[i].Kind != actors[j].Kind {
+			return actors[i].Kind < actors[j].Kind
+		}
+		return actors[i].ID < actors[j].ID
+	})
+
+	parents := make([]int, len(actors))
+	for i := range parents {
+		parents[i] = i
+	}
+	var find func(i int) int
+	find = func(i int) int {
+		if parents[i] != i {
+			parents[i] = find(parents[i])
+		}
+		return parents[i]
+	}
+	reasons := make(map[int]map[string]bool)
+	union := func(i, j int, reason string) {
+		a, b := find(i), find(j)
+		if a > b {
+			a, b = b, a
+		}
+		parents[b] = a
+		if reasons[a] == nil {
+			reasons[a] = make(map[string]bool)
+		}
+		reasons[a][reason] = true
+		for r := range reasons[b] {
+			reasons[a][r] = true
+		}
+	}
+
+	signals := []struct {
+		reason string
+		key    func(a Actor) string
+	}{
+		{"same name", func(a Actor) string { return normalizeName(a.Name) }},
+		{"same article", func(a Actor) string { return articleTitle(a.URL) }},
+		{"same Wikidata item", func(a Actor) string {
+			if a.Wikidata == nil {
+				return ""
+			}
+			return a.Wikidata.QID
+		}},
+	}
+	for _, signal := range signals {
+		firstByKey := make(map[Kind]map[string]int)
+		for i, a := range actors {
+			key := signal.key(a)
+			if key == "" {
+				continue
+			}
+			if firstByKey[a.Kind] == nil {
+				firstByKey[a.Kind] = make(map[string]int)
+			}
+			if first, seen := firstByKey[a.Kind][key]; seen {
+				union(first, i, signal.reason)
+			} else {
+				firstByKey[a.Kind][key] = i
+			}
+		}
+	}
+
+	// Redirect targets are keyed together with the articles of all actors, so that redirects are
+	// grouped both with each other and with the actors of the articles they point to
+	firstByArticle := make(map[Kind]map[string]int)
+	for i, a := range actors {
+		if firstByArticle[a.Kind] == nil {
+			firstByArticle[a.Kind] = make(map[string]int)
+		}
+		if key := articleTitle(a.URL); key != "" {
+			if _, seen := firstByArticle[a.Kind][key]; !seen {
+				firstByArticle[a.Kind][key] = i
+			}
+		}
+	}
+	for i, a := range actors {
+		key := articleTitle(a.RedirectURL)
+		if key == "" {
+			continue
+		}
+		if first, seen := firstByArticle[a.Kind][key]; seen {
+			union(first, i, "same redirect target")
+		} else {
+			firstByArticle[a.Kind][key] = i
+		}
+	}
+
+	for i, a := range actors {
+		if a.Kind != FactionKind || a.Flag == "" {
+			continue
+		}
+		for j := i + 1; j < len(actors); j++ {
+			b := actors[j]
+			if b.Kind == FactionKind && b.Flag == a.Flag && shareSignificantWord(a.Name, b.Name) {
+				union(i, j, "same flag")
+			}
+		}
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i, a := range actors {
+		root := find(i)
+		if _, seen := groups[root]; !seen {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], a.ID)
+	}
+
+	res := []Duplicates{}
+	for _, root := range roots {
+		if len(groups[root]) < 2 {
+			continue
+		}
+		d := Duplicates{Kind: actors[root].Kind, IDs: groups[root]}
+		for r := range reasons[root] {
+			d.Reasons = append(d.Reasons, r)
+		}
+		sort.Strings(d.Reasons)
+		res = append(res, d)
+	}
+	return res
+}
+
+// normalizeName simplifies the name of an actor so that different spellings of it can be compared.
+// Example: "The Ottoman Empire (1299–1922)" becomes "ottoman empire"
+func normalizeName(name string) string {
+	res := strings.ToLower(parenthesesMatcher.ReplaceAllString(name, ""))
+	res = diacriticsReplacer.Replace(res)
+	res = strings.TrimSpace(nonAlphanumericMatcher.ReplaceAllString(res, " "))
+	return strings.TrimPrefix(res, "the ")
+}
+
+// articleTitle returns the normalized title of the Wikipedia article an URL points to, ignoring
+// fragments, so that links to sections of the same article share it
+func articleTitle(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || !strings.Contains(u.Path, "/wiki/") {
+		return ""
+	}
+	title := path.Base(u.Path)
+	if unescaped, err := url.PathUnescape(title); err == nil {
+		title = unescaped
+	}
+	return u.Host + "/" + normalizeName(strings.ReplaceAll(title, "_", " "))
+}
+
+func shareSignificantWord(a, b string) bool {
+	words := make(map[string]bool)
+	for _, w := range strings.Fields(normalizeName(a)) {
+		if !nameStopWords[w] {
+			words[w] = true
+		}
+	}
+	for _, w := range strings.Fields(normalizeName(b)) {
+		if words[w] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package wikiactors_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	faction := func(id int, url, name, flag string) wikiactors.Actor {
+		return wikiactors.Actor{Kind: wikiactors.FactionKind, ID: id, URL: url, Name: name, Flag: flag}
+	}
+	commander := func(id int, url, name string, facts *wikidata.Facts) wikiactors.Actor {
+		return wikiactors.Actor{Kind: wikiactors.CommanderKind, ID: id, URL: url, Name: name, Wikidata: facts}
+	}
+	const ottomanFlag = "/thumb/8/8e/Flag_of_the_Ottoman_Empire.svg/23px-Flag_of_the_Ottoman_Empire.svg.png"
+
+	cases := []struct {
+		name     string
+		actors   []wikiactors.Actor
+		expected []wikiactors.Duplicates
+	}{
+		{
+			name: "Same normalized name",
+			actors: []wikiactors.Actor{
+				faction(2, "https://en.wikipedia.org/wiki/Ottoman_Empire", "Ottoman Empire", ""),
+				faction(1, "https://en.wikipedia.org/wiki/Ottoman_Turks", "The Ottoman Empire (1299–1922)", ""),
+				faction(3, "https://en.wikipedia.org/wiki/Safavid_Iran", "Safavid Iran", ""),
+			},
+			expected: []wikiactors.Duplicates{
+				{Kind: wikiactors.FactionKind, IDs: []int{1, 2}, Reasons: []string{"same name"}},
+			},
+		},
+		{
+			name: "Same article and flag",
+			actors: []wikiactors.Actor{
+				faction(1, "https://en.wikipedia.org/wiki/Ottoman_Empire", "Ottoman Empire", ottomanFlag),
+				faction(2, "https://en.wikipedia.org/wiki/Ottoman_empire#Military", "Ottoman military", ""),
+				faction(3, "https://en.wikipedia.org/wiki/Ottoman_Navy", "Ottoman Navy", ottomanFlag),
+				faction(4, "https://en.wikipedia.org/wiki/Crimean_Khanate", "Crimean Khanate", ottomanFlag),
+			},
+			expected: []wikiactors.Duplicates{
+				{Kind: wikiactors.FactionKind, IDs: []int{1, 2, 3}, Reasons: []string{"same article", "same flag"}},
+			},
+		},
+		{
+			name: "Same Wikidata item",
+			actors: []wikiactors.Actor{
+				commander(10, "https://en.wikipedia.org/wiki/Napoleon", "Napoleon", &wikidata.Facts{QID: "Q517"}),
+				commander(11, "https://en.wikipedia.org/wiki/Napoleon_Bonaparte", "Napoleon Bonaparte", &wikidata.Facts{QID: "Q517"}),
+				commander(12, "https://en.wikipedia.org/wiki/Napoleon_III", "Napoleon III", &wikidata.Facts{QID: "Q7721"}),
+			},
+			expected: []wikiactors.Duplicates{
+				{Kind: wikiactors.CommanderKind, IDs: []int{10, 11}, Reasons: []string{"same Wikidata item"}},
+			},
+		},
+		{
+			name: "Same redirect target",
+			actors: []wikiactors.Actor{
+				{Kind: wikiactors.FactionKind, ID: 1, URL: "https://en.wikipedia.org/wiki/Sublime_Porte", Name: "Sublime Porte", RedirectURL: "https://en.wikipedia.org/wiki/Ottoman_Empire"},
+				{Kind: wikiactors.FactionKind, ID: 2, URL: "https://en.wikipedia.org/wiki/Turkish_Empire", Name: "Turkish Empire", RedirectURL: "https://en.wikipedia.org/wiki/Ottoman_Empire"},
+				{Kind: wikiactors.FactionKind, ID: 3, URL: "https://en.wikipedia.org/wiki/Safavid_Iran", Name: "Safavid Iran"},
+			},
+			expected: []wikiactors.Duplicates{
+				{Kind: wikiactors.FactionKind, IDs: []int{1, 2}, Reasons: []string{"same redirect target"}},
+			},
+		},
+		{
+			name: "Redirect to the article of another actor",
+			actors: []wikiactors.Actor{
+				commander(10, "https://en.wikipedia.org/wiki/Napoleon", "Napoleon", nil),
+				{Kind: wikiactors.CommanderKind, ID: 11, URL: "https://en.wikipedia.org/wiki/Bonaparte", Name: "Bonaparte", RedirectURL: "https://en.wikipedia.org/wiki/Napoleon"},
+				{Kind: wikiactors.FactionKind, ID: 12, URL: "https://en.wikipedia.org/wiki/Bonaparte_(faction)", Name: "Bonapartists", RedirectURL: "https://en.wikipedia.org/wiki/Napoleon"},
+			},
+			expected: []wikiactors.Duplicates{
+				{Kind: wikiactors.CommanderKind, IDs: []int{10, 11}, Reasons: []string{"same redirect target"}},
+			},
+		},
+		{
+			name: "Different kinds",
+			actors: []wikiactors.Actor{
+				faction(1, "https://en.wikipedia.org/wiki/Saladin", "Saladin", ""),
+				commander(2, "https://en.wikipedia.org/wiki/Saladin", "Saladin", nil),
+			},
+			expected: []wikiactors.Duplicates{},
+		},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, wikiactors.FindDuplicates(c.actors), c.name)
+	}
+}
@@ -1,15 +1,45 @@
 package wikiactors
 
+import "github.com/sasalatart/batcoms/pkg/wikidata"
+
 // Actor stores the details of an entity that participated in a battle as scraped from
-// Wikipedia. These can be factions or commanders
+// Wikipedia. These can be factions or commanders. RedirectURL is only set when URL redirects to
+// another article
 type Actor struct {
-	Kind        Kind   `validate:"required"`
-	ID          int    `validate:"required,min=1"`
-	URL         string `validate:"required,url"`
-	Flag        string
+	Kind          Kind   `validate:"required"`
+	ID            int    `validate:"required,min=1"`
+	URL           string `validate:"required,url"`
+	RedirectURL   string `json:",omitempty"`
+	Flag          string
+	Name          string `validate:"required"`
+	Description   string
+	Extract       string                  `validate:"required"`
+	Localizations map[string]Localization `json:",omitempty"`
+	Wikidata      *wikidata.Facts         `json:",omitempty"`
+	Biography     *Biography              `json:",omitempty"`
+}
+
+// Biography stores the details of the life of a commander as found in the infobox of their own
+// Wikipedia article. Dates are kept as written, so that they can be parsed later on
+type Biography struct {
+	Born        string       `json:",omitempty"`
+	Died        string       `json:",omitempty"`
+	Allegiances []Allegiance `json:",omitempty"`
+}
+
+// Allegiance stores a state or faction served by a commander, together with the period in which it
+// was served (such as "1804–1815"), if any
+type Allegiance struct {
+	Name   string `validate:"required"`
+	Period string `json:",omitempty"`
+}
+
+// Localization stores the name and summary of an actor as found in another language edition of
+// Wikipedia
+type Localization struct {
 	Name        string `validate:"required"`
 	Description string
-	Extract     string `validate:"required"`
+	Extract     string
 }
 
 // Kind represents the kind of an actor in a battle
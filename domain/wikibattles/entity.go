@@ -3,25 +3,44 @@ package wikibattles
 import (
 	"github.com/sasalatart/batcoms/domain/locations"
 	"github.com/sasalatart/batcoms/domain/statistics"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
 )
 
+// SchemaVersion is the version of the format in which scraped battles are exported together with
+// their factions and commanders. It must be increased whenever that format changes in a way that
+// the seeder can not read
+const SchemaVersion = 1
+
 // Battle stores all the details regarding a specific battle as scraped from Wikipedia
 type Battle struct {
-	ID                  int    `validate:"required,min=1"`
-	URL                 string `validate:"required,url"`
-	Name                string `validate:"required"`
-	PartOf              string
-	Description         string
-	Extract             string `validate:"required"`
-	Date                string `validate:"required"`
-	Location            locations.Location
-	Result              string `validate:"required"`
-	TerritorialChanges  string
-	Strength            statistics.SideNumbers
-	Casualties          statistics.SideNumbers
-	Factions            SideActors
-	Commanders          SideActors
-	CommandersByFaction CommandersByFaction
+	ID                   int    `validate:"required,min=1"`
+	URL                  string `validate:"required,url"`
+	Name                 string `validate:"required"`
+	PartOf               string
+	Description          string
+	Extract              string `validate:"required"`
+	Date                 string `validate:"required"`
+	Location             locations.Location
+	Result               string `validate:"required"`
+	TerritorialChanges   string
+	Strength             statistics.SideNumbers
+	Casualties           statistics.SideNumbers
+	Factions             SideActors
+	Commanders           SideActors
+	CommandersByFaction  CommandersByFaction
+	CommanderConfidences map[int]Confidence      `json:",omitempty"`
+	Flags                map[int]string          `json:",omitempty"`
+	Localizations        map[string]Localization `json:",omitempty"`
+	Wikidata             *wikidata.Facts         `json:",omitempty"`
+}
+
+// Localization stores the name and summary of a battle as found in another language edition of
+// Wikipedia, linked to the scraped one through interlanguage links
+type Localization struct {
+	URL         string `validate:"required,url"`
+	Name        string `validate:"required"`
+	Description string
+	Extract     string
 }
 
 // SideActors groups actors' WikiIDs into each side of a battle. The struct may be used for either
@@ -35,9 +54,34 @@ type SideActors struct {
 // in a specific battle into their corresponding faction WikiIDs
 type CommandersByFaction map[int][]int
 
+// Confidence represents how certain it is that a commander fought under the faction to which it was
+// assigned in a battle
+type Confidence string
+
+const (
+	// FlagConfidence means that the commander was shown with the same flag as the faction
+	FlagConfidence Confidence = "flag"
+	// SingleFactionConfidence means that the faction was the only one in the side of the commander,
+	// which is less reliable, as factions are often omitted from infoboxes
+	SingleFactionConfidence Confidence = "singleFaction"
+)
+
 // BattleItem stores the name and URL of a battle after being scraped from Wikipedia's indexed
 // list of battles
 type BattleItem struct {
 	Name string `validate:"required"`
 	URL  string `validate:"required,url"`
 }
+
+// Assign records that the commander with the given WikiID fought under the faction with the given
+// WikiID, and how certain that is
+func (b *Battle) Assign(factionID, commanderID int, confidence Confidence) {
+	if b.CommandersByFaction == nil {
+		b.CommandersByFaction = make(CommandersByFaction)
+	}
+	if b.CommanderConfidences == nil {
+		b.CommanderConfidences = make(map[int]Confidence)
+	}
+	b.CommandersByFaction[factionID] = append(b.CommandersByFaction[factionID], commanderID)
+	b.CommanderConfidences[commanderID] = confidence
+}
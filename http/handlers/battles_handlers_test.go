@@ -2,6 +2,7 @@ package handlers_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"net/http"
 	"testing"
 
@@ -13,6 +14,9 @@ import (
 	"github.com/sasalatart/batcoms/mocks"
 	"github.com/sasalatart/batcoms/pkg/dates"
 	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestBattlesHandlers(t *testing.T) {
@@ -22,7 +26,7 @@ func TestBattlesHandlers(t *testing.T) {
 		t.Run("ValidPersistedUUID", func(t *testing.T) {
 			battleMock := mocks.Battle()
 			app, _, _, battlesRepoMock := appWithReposMocks()
-			battlesRepoMock.On("FindOne", battles.FindOneQuery{
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
 				ID: battleMock.ID,
 			}).Return(battleMock, nil)
 
@@ -32,10 +36,32 @@ func TestBattlesHandlers(t *testing.T) {
 			})
 		})
 
+		t.Run("WithoutRenderedRelations", func(t *testing.T) {
+			battleMock := mocks.Battle().Without(battles.Relations{Factions: true, Commanders: true})
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID:   battleMock.ID,
+				Omit: battles.Relations{Factions: true, Commanders: true},
+			}).Return(battleMock, nil)
+
+			route := "/battles/" + battleMock.ID.String() + "?expand=factions&fields=id,name"
+			httptest.AssertFiberGET(t, app, route, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+			})
+		})
+
+		t.Run("WithInvalidFields", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			route := "/battles/" + mocks.Battle().ID.String() + "?fields=bogus"
+			httptest.AssertFiberGET(t, app, route, http.StatusBadRequest, func(*http.Response) {
+				battlesRepoMock.AssertNotCalled(t, "FindOne")
+			})
+		})
+
 		t.Run("ValidNonPersistedUUID", func(t *testing.T) {
 			uuid := uuid.NewV4()
 			app, _, _, battlesRepoMock := appWithReposMocks()
-			battlesRepoMock.On("FindOne", battles.FindOneQuery{
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
 				ID: uuid,
 			}).Return(battles.Battle{}, domain.ErrNotFound)
 
@@ -48,6 +74,106 @@ func TestBattlesHandlers(t *testing.T) {
 			httptest.AssertFailedFiberGET(t, app, "/battles/invalid-uuid", http.StatusBadRequest, "Invalid BattleID")
 			battlesRepoMock.AssertNotCalled(t, "FindOne")
 		})
+
+		t.Run("AsJSONLD", func(t *testing.T) {
+			battleMock := mocks.Battle()
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID: battleMock.ID,
+			}).Return(battleMock, nil)
+
+			headers := map[string]string{"Accept": "application/ld+json"}
+			route := "http://example.com/battles/" + battleMock.ID.String()
+			httptest.AssertFiberGETWithHeaders(t, app, route, headers, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertJSONLD(t, res, httptest.JSONLDNode{
+					ID:   "http://example.com/battles/" + battleMock.ID.String(),
+					Type: "Event",
+					SameAs: []string{
+						"https://en.wikipedia.org/wiki/Battle_of_Austerlitz",
+						"http://www.wikidata.org/entity/Q134114",
+					},
+				})
+			})
+		})
+
+		t.Run("AsJSONLDWithFields", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			headers := map[string]string{"Accept": "application/ld+json"}
+			route := "/battles/" + mocks.Battle().ID.String() + "?fields=id"
+			httptest.AssertFiberGETWithHeaders(t, app, route, headers, http.StatusBadRequest, func(res *http.Response) {
+				battlesRepoMock.AssertNotCalled(t, "FindOne")
+				httptest.AssertErrorMessage(t, res, "Invalid fields, these can not be selected for JSON-LD")
+			})
+		})
+
+		t.Run("WithDateFormat", func(t *testing.T) {
+			battleMock := mocks.Battle()
+			cases := []struct {
+				dateFormat        string
+				expectedStartDate string
+				expectedEndDate   string
+			}{
+				{
+					dateFormat:        "iso",
+					expectedStartDate: battleMock.StartDate.ISO(),
+					expectedEndDate:   battleMock.EndDate.ISO(),
+				},
+				{
+					dateFormat:        "edtf",
+					expectedStartDate: battleMock.StartDate.EDTF(),
+					expectedEndDate:   battleMock.EndDate.EDTF(),
+				},
+			}
+			for _, c := range cases {
+				app, _, _, battlesRepoMock := appWithReposMocks()
+				battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+					ID: battleMock.ID,
+				}).Return(battleMock, nil)
+
+				route := "/battles/" + battleMock.ID.String() + "?dateFormat=" + c.dateFormat
+				httptest.AssertFiberGET(t, app, route, http.StatusOK, func(res *http.Response) {
+					battlesRepoMock.AssertExpectations(t)
+					httptest.AssertJSONBattleDates(t, res, c.expectedStartDate, c.expectedEndDate)
+				})
+			}
+		})
+
+		t.Run("WithInvalidDateFormat", func(t *testing.T) {
+			battleMock := mocks.Battle()
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID: battleMock.ID,
+			}).Return(battleMock, nil)
+
+			route := "/battles/" + battleMock.ID.String() + "?dateFormat=x"
+			httptest.AssertFailedFiberGET(t, app, route, http.StatusBadRequest, "Invalid dateFormat, must be object, iso or edtf")
+		})
+
+		t.Run("WithExpand", func(t *testing.T) {
+			battleMock := mocks.Battle()
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID: battleMock.ID,
+			}).Return(battleMock, nil)
+
+			route := "/battles/" + battleMock.ID.String() + "?expand=sides&dateFormat=iso"
+			httptest.AssertFiberGET(t, app, route, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertJSONBattleSides(t, res, battleMock.Sides())
+			})
+		})
+
+		t.Run("WithInvalidExpand", func(t *testing.T) {
+			battleMock := mocks.Battle()
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID: battleMock.ID,
+			}).Return(battleMock, nil)
+
+			route := "/battles/" + battleMock.ID.String() + "?expand=x"
+			httptest.AssertFailedFiberGET(t, app, route, http.StatusBadRequest, "Invalid expand, must be a comma-separated list of factions, commanders and sides")
+		})
 	})
 
 	t.Run("GET /battles", func(t *testing.T) {
@@ -64,7 +190,7 @@ func TestBattlesHandlers(t *testing.T) {
 		for _, c := range cases {
 			t.Run(c.description, func(t *testing.T) {
 				app, _, _, battlesRepoMock := appWithReposMocks()
-				battlesRepoMock.On("FindMany", c.calledWith, page).
+				battlesRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
 					Return(battlesMock, pagesMock, nil)
 				httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
 					battlesRepoMock.AssertExpectations(t)
@@ -73,6 +199,18 @@ func TestBattlesHandlers(t *testing.T) {
 				})
 			})
 		}
+		t.Run("WithDateFormat", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindMany", mock.Anything, battles.FindManyQuery{}, page).
+				Return(battlesMock, pagesMock, nil)
+			httptest.AssertFiberGET(t, app, baseURL+"&dateFormat=iso", http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertHeaderPages(t, res, pagesMock)
+				httptest.AssertJSONBattlesDates(t, res, [][2]string{
+					{battlesMock[0].StartDate.ISO(), battlesMock[0].EndDate.ISO()},
+				})
+			})
+		})
 		for _, c := range buildInvalidDatesCases(baseURL) {
 			t.Run(c.description, func(t *testing.T) {
 				app, _, _, battlesRepoMock := appWithReposMocks()
@@ -82,6 +220,126 @@ func TestBattlesHandlers(t *testing.T) {
 				})
 			})
 		}
+		t.Run("WithFieldsAndExpand", func(t *testing.T) {
+			cases := []struct {
+				description    string
+				query          string
+				expectedOmit   battles.Relations
+				expectedFields []string
+			}{
+				{
+					description:    "Fields without relations",
+					query:          "&fields=id,name,startDate,location",
+					expectedOmit:   battles.Relations{Factions: true, Commanders: true},
+					expectedFields: []string{"id", "name", "startDate", "location"},
+				},
+				{
+					description:    "Fields with relations",
+					query:          "&fields=id,commanders",
+					expectedOmit:   battles.Relations{Factions: true},
+					expectedFields: []string{"id", "commanders"},
+				},
+				{
+					description:    "Fields with commandersByFaction",
+					query:          "&fields=id,commandersByFaction",
+					expectedOmit:   battles.Relations{},
+					expectedFields: []string{"id", "commandersByFaction"},
+				},
+				{
+					description:    "Expand factions",
+					query:          "&expand=factions&fields=id,factions,commanders,commandersByFaction",
+					expectedOmit:   battles.Relations{Commanders: true},
+					expectedFields: []string{"id", "factions"},
+				},
+				{
+					description:    "Expand factions and commanders",
+					query:          "&expand=factions,commanders&fields=id,factions,commanders,commandersByFaction,sides",
+					expectedOmit:   battles.Relations{},
+					expectedFields: []string{"id", "factions", "commanders", "commandersByFaction"},
+				},
+				{
+					description:    "Expand sides",
+					query:          "&expand=sides&fields=id,factions,sides",
+					expectedOmit:   battles.Relations{},
+					expectedFields: []string{"id", "sides"},
+				},
+			}
+			for _, c := range cases {
+				t.Run(c.description, func(t *testing.T) {
+					app, _, _, battlesRepoMock := appWithReposMocks()
+					battlesRepoMock.On("FindMany", mock.Anything, battles.FindManyQuery{Omit: c.expectedOmit}, page).
+						Return(battlesMock, pagesMock, nil)
+					httptest.AssertFiberGET(t, app, baseURL+c.query, http.StatusOK, func(res *http.Response) {
+						battlesRepoMock.AssertExpectations(t)
+						httptest.AssertJSONFields(t, res, c.expectedFields)
+					})
+				})
+			}
+		})
+		t.Run("WithInvalidFields", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			httptest.AssertFiberGET(t, app, baseURL+"&fields=id,bogus", http.StatusBadRequest, func(res *http.Response) {
+				battlesRepoMock.AssertNotCalled(t, "FindMany")
+				body, err := ioutil.ReadAll(res.Body)
+				require.NoError(t, err, "Reading body")
+				assert.Contains(t, string(body), "Invalid fields, must be a comma-separated list of ")
+				assert.Contains(t, string(body), "factions, id, location, name")
+			})
+		})
+		t.Run("AsCSV", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("Stream", mock.Anything, battles.FindManyQuery{Result: "french"}).
+				Return(battlesMock, nil)
+			headers := map[string]string{"Accept": "text/csv"}
+			route := baseURL + "&result=french&expand=sides&fields=id"
+			httptest.AssertFiberGETWithHeaders(t, app, route, headers, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertCSVColumn(t, res, "factionsB", []string{
+					mocks.Faction2().Name + "; " + mocks.Faction3().Name,
+				})
+			})
+		})
+		t.Run("AsNDJSON", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("Stream", mock.Anything, battles.FindManyQuery{}).
+				Return(battlesMock, nil)
+			headers := map[string]string{"Accept": "application/x-ndjson, application/json"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL, headers, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertNDJSON(t, res, battlesMock[0])
+			})
+		})
+		t.Run("AsNDJSONWithFieldsAndExpand", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("Stream", mock.Anything, battles.FindManyQuery{Omit: battles.Relations{Commanders: true}}).
+				Return(battlesMock, nil)
+			headers := map[string]string{"Accept": "application/x-ndjson"}
+			route := baseURL + "&expand=factions&fields=id,factions,commanders"
+			httptest.AssertFiberGETWithHeaders(t, app, route, headers, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertNDJSON(t, res, map[string]interface{}{
+					"id":       battlesMock[0].ID,
+					"factions": battlesMock[0].Factions,
+				})
+			})
+		})
+		t.Run("AsNDJSONWithInvalidFields", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			headers := map[string]string{"Accept": "application/x-ndjson"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL+"&fields=bogus", headers, http.StatusBadRequest, func(res *http.Response) {
+				battlesRepoMock.AssertNotCalled(t, "Stream")
+			})
+		})
+		t.Run("AsJSONWhenPreferred", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindMany", mock.Anything, battles.FindManyQuery{}, page).
+				Return(battlesMock, pagesMock, nil)
+			headers := map[string]string{"Accept": "application/json, text/csv"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL, headers, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertJSONBattles(t, res, battlesMock)
+			})
+		})
 	})
 
 	t.Run("GET /factions/:factionID/battles", func(t *testing.T) {
@@ -105,10 +363,10 @@ func TestBattlesHandlers(t *testing.T) {
 			for _, c := range cases {
 				t.Run(c.description, func(t *testing.T) {
 					app, factionsRepoMock, _, battlesRepoMock := appWithReposMocks()
-					factionsRepoMock.On("FindOne", factions.FindOneQuery{
+					factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
 						ID: factionMock.ID,
 					}).Return(factionMock, nil)
-					battlesRepoMock.On("FindMany", c.calledWith, page).
+					battlesRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
 						Return(battlesMock, pagesMock, nil)
 
 					httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
@@ -118,10 +376,34 @@ func TestBattlesHandlers(t *testing.T) {
 					})
 				})
 			}
+			t.Run("WithIncludeRelated", func(t *testing.T) {
+				app, factionsRepoMock, _, battlesRepoMock := appWithReposMocks()
+				factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
+					ID: factionMock.ID,
+				}).Return(factionMock, nil)
+				battlesRepoMock.On("FindMany", mock.Anything, battles.FindManyQuery{
+					FactionID:      factionMock.ID,
+					IncludeRelated: true,
+				}, page).Return(battlesMock, pagesMock, nil)
+
+				httptest.AssertFiberGET(t, app, fromFactionURL+"&includeRelated=true", http.StatusOK, func(res *http.Response) {
+					battlesRepoMock.AssertExpectations(t)
+					httptest.AssertJSONBattles(t, res, battlesMock)
+				})
+			})
+			t.Run("WithInvalidIncludeRelated", func(t *testing.T) {
+				app, factionsRepoMock, _, battlesRepoMock := appWithReposMocks()
+				factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
+					ID: factionMock.ID,
+				}).Return(factionMock, nil)
+
+				httptest.AssertFailedFiberGET(t, app, fromFactionURL+"&includeRelated=x", http.StatusBadRequest, "Invalid includeRelated, must be true or false")
+				battlesRepoMock.AssertNotCalled(t, "FindMany")
+			})
 			for _, c := range buildInvalidDatesCases(fromFactionURL) {
 				t.Run(c.description, func(t *testing.T) {
 					app, factionsRepoMock, _, battlesRepoMock := appWithReposMocks()
-					factionsRepoMock.On("FindOne", factions.FindOneQuery{
+					factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
 						ID: factionMock.ID,
 					}).Return(factionMock, nil)
 
@@ -137,7 +419,7 @@ func TestBattlesHandlers(t *testing.T) {
 		t.Run("ValidNonPersistedFactionUUID", func(t *testing.T) {
 			uuid := uuid.NewV4()
 			app, factionsRepoMock, _, battlesRepoMock := appWithReposMocks()
-			factionsRepoMock.On("FindOne", factions.FindOneQuery{
+			factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
 				ID: uuid,
 			}).Return(factions.Faction{}, domain.ErrNotFound)
 
@@ -175,10 +457,10 @@ func TestBattlesHandlers(t *testing.T) {
 			for _, c := range cases {
 				t.Run(c.description, func(t *testing.T) {
 					app, _, commandersRepoMock, battlesRepoMock := appWithReposMocks()
-					commandersRepoMock.On("FindOne", commanders.FindOneQuery{
+					commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
 						ID: commanderMock.ID,
 					}).Return(commanderMock, nil)
-					battlesRepoMock.On("FindMany", c.calledWith, page).
+					battlesRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
 						Return(battlesMock, pagesMock, nil)
 
 					httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
@@ -191,7 +473,7 @@ func TestBattlesHandlers(t *testing.T) {
 			for _, c := range buildInvalidDatesCases(fromCommanderURL) {
 				t.Run(c.description, func(t *testing.T) {
 					app, _, commandersRepoMock, battlesRepoMock := appWithReposMocks()
-					commandersRepoMock.On("FindOne", commanders.FindOneQuery{
+					commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
 						ID: commanderMock.ID,
 					}).Return(commanderMock, nil)
 
@@ -207,7 +489,7 @@ func TestBattlesHandlers(t *testing.T) {
 		t.Run("ValidNonPersistedCommanderUUID", func(t *testing.T) {
 			uuid := uuid.NewV4()
 			app, _, commandersRepoMock, battlesRepoMock := appWithReposMocks()
-			commandersRepoMock.On("FindOne", commanders.FindOneQuery{
+			commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
 				ID: uuid,
 			}).Return(commanders.Commander{}, domain.ErrNotFound)
 
@@ -223,6 +505,126 @@ func TestBattlesHandlers(t *testing.T) {
 			battlesRepoMock.AssertNotCalled(t, "FindMany")
 		})
 	})
+
+	t.Run("GET /battles/:battleID/concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		const page = 2
+		baseURL := func(battleID string) string {
+			return fmt.Sprintf("/battles/%s/concurrent?page=%d", battleID, page)
+		}
+
+		t.Run("ValidPersistedUUID", func(t *testing.T) {
+			const pagesMock = 3
+			battleMock := mocks.Battle()
+			battlesMock := []battles.Battle{mocks.Battle()}
+			fromBattleURL := baseURL(battleMock.ID.String())
+
+			cases := []battlesTableCase{
+				{
+					description: "With no filters",
+					url:         fromBattleURL,
+					calledWith:  battles.FindManyQuery{ConcurrentWith: battleMock.ID},
+				},
+				{
+					description: "With withinKm filter",
+					url:         fromBattleURL + "&withinKm=150.5",
+					calledWith:  battles.FindManyQuery{ConcurrentWith: battleMock.ID, WithinKm: 150.5},
+				},
+			}
+			for _, c := range cases {
+				t.Run(c.description, func(t *testing.T) {
+					app, _, _, battlesRepoMock := appWithReposMocks()
+					battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+						ID: battleMock.ID,
+					}).Return(battleMock, nil)
+					battlesRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
+						Return(battlesMock, pagesMock, nil)
+
+					httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
+						battlesRepoMock.AssertExpectations(t)
+						httptest.AssertHeaderPages(t, res, pagesMock)
+						httptest.AssertJSONBattles(t, res, battlesMock)
+					})
+				})
+			}
+			for _, withinKm := range []string{"x", "0", "-10"} {
+				t.Run("With invalid withinKm "+withinKm, func(t *testing.T) {
+					app, _, _, battlesRepoMock := appWithReposMocks()
+					battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+						ID: battleMock.ID,
+					}).Return(battleMock, nil)
+
+					url := fromBattleURL + "&withinKm=" + withinKm
+					httptest.AssertFailedFiberGET(t, app, url, http.StatusBadRequest, "Invalid withinKm, must be a positive number")
+					battlesRepoMock.AssertNotCalled(t, "FindMany")
+				})
+			}
+		})
+
+		t.Run("ValidNonPersistedUUID", func(t *testing.T) {
+			uuid := uuid.NewV4()
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID: uuid,
+			}).Return(battles.Battle{}, domain.ErrNotFound)
+
+			httptest.AssertFailedFiberGET(t, app, baseURL(uuid.String()), http.StatusNotFound, "Battle not found")
+			battlesRepoMock.AssertNotCalled(t, "FindMany")
+		})
+
+		t.Run("InvalidUUID", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			httptest.AssertFailedFiberGET(t, app, baseURL("invalid-uuid"), http.StatusBadRequest, "Invalid BattleID")
+			battlesRepoMock.AssertNotCalled(t, "FindOne")
+			battlesRepoMock.AssertNotCalled(t, "FindMany")
+		})
+	})
+
+	t.Run("GET /battles/:battleID/related", func(t *testing.T) {
+		t.Parallel()
+
+		const page = 2
+		baseURL := func(battleID string) string {
+			return fmt.Sprintf("/battles/%s/related?page=%d", battleID, page)
+		}
+
+		t.Run("ValidPersistedUUID", func(t *testing.T) {
+			const pagesMock = 3
+			battleMock := mocks.Battle()
+			battlesMock := []battles.Battle{mocks.Battle()}
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID: battleMock.ID,
+			}).Return(battleMock, nil)
+			battlesRepoMock.On("FindMany", mock.Anything, battles.FindManyQuery{RelatedTo: battleMock.ID}, page).
+				Return(battlesMock, pagesMock, nil)
+
+			httptest.AssertFiberGET(t, app, baseURL(battleMock.ID.String()), http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				httptest.AssertHeaderPages(t, res, pagesMock)
+				httptest.AssertJSONBattles(t, res, battlesMock)
+			})
+		})
+
+		t.Run("ValidNonPersistedUUID", func(t *testing.T) {
+			uuid := uuid.NewV4()
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{
+				ID: uuid,
+			}).Return(battles.Battle{}, domain.ErrNotFound)
+
+			httptest.AssertFailedFiberGET(t, app, baseURL(uuid.String()), http.StatusNotFound, "Battle not found")
+			battlesRepoMock.AssertNotCalled(t, "FindMany")
+		})
+
+		t.Run("InvalidUUID", func(t *testing.T) {
+			app, _, _, battlesRepoMock := appWithReposMocks()
+			httptest.AssertFailedFiberGET(t, app, baseURL("invalid-uuid"), http.StatusBadRequest, "Invalid BattleID")
+			battlesRepoMock.AssertNotCalled(t, "FindOne")
+			battlesRepoMock.AssertNotCalled(t, "FindMany")
+		})
+	})
 }
 
 type battlesTableCase struct {
@@ -286,6 +688,34 @@ func buildBattlesCases(baseURL string, decorateQuery func(battles.FindManyQuery)
 				ToDate: dates.Historic{Year: 1821, Month: 12, Day: 31},
 			}),
 		},
+		{
+			description: "With ISO 8601 fromDate filter",
+			url:         baseURL + "&fromDate=-1456-04-16",
+			calledWith: decorateQuery(battles.FindManyQuery{
+				FromDate: dates.Historic{Year: 1457, Month: 4, Day: 16, IsBCE: true},
+			}),
+		},
+		{
+			description: "With EDTF interval fromDate filter",
+			url:         baseURL + "&fromDate=1805-12/1806",
+			calledWith: decorateQuery(battles.FindManyQuery{
+				FromDate: dates.Historic{Year: 1805, Month: 12, Day: 1},
+			}),
+		},
+		{
+			description: "With EDTF interval toDate filter",
+			url:         baseURL + "&toDate=1805/1806",
+			calledWith: decorateQuery(battles.FindManyQuery{
+				ToDate: dates.Historic{Year: 1806, Month: 12, Day: 31},
+			}),
+		},
+		{
+			description: "With approximate EDTF toDate filter",
+			url:         baseURL + "&toDate=1821-05~",
+			calledWith: decorateQuery(battles.FindManyQuery{
+				ToDate: dates.Historic{Year: 1821, Month: 5, Day: 31, Approximate: true},
+			}),
+		},
 		{
 			description: "With name, summary, place, result, fromDate and toDate filters",
 			url: baseURL +
@@ -314,8 +744,8 @@ type invalidDatesTableCase struct {
 }
 
 func buildInvalidDatesCases(baseURL string) []invalidDatesTableCase {
-	const invalidFromDateMessage = "Invalid fromDate, must be in YYYY-MM-DD format"
-	const invalidToDateMessage = "Invalid toDate, must be in YYYY-MM-DD format"
+	const invalidFromDateMessage = "Invalid fromDate, must be in YYYY-MM-DD, ISO 8601 or EDTF format"
+	const invalidToDateMessage = "Invalid toDate, must be in YYYY-MM-DD, ISO 8601 or EDTF format"
 	return []invalidDatesTableCase{
 		{
 			description:     "Invalid fromDate",
@@ -11,6 +11,7 @@ import (
 	"github.com/sasalatart/batcoms/http/httptest"
 	"github.com/sasalatart/batcoms/mocks"
 	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
 )
 
 func TestCommandersHandlers(t *testing.T) {
@@ -20,7 +21,7 @@ func TestCommandersHandlers(t *testing.T) {
 		t.Run("ValidPersistedUUID", func(t *testing.T) {
 			commanderMock := mocks.Commander()
 			app, _, commandersRepoMock, _ := appWithReposMocks()
-			commandersRepoMock.On("FindOne", commanders.FindOneQuery{
+			commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
 				ID: commanderMock.ID,
 			}).Return(commanderMock, nil)
 
@@ -30,10 +31,32 @@ func TestCommandersHandlers(t *testing.T) {
 			})
 		})
 
+		t.Run("AsJSONLD", func(t *testing.T) {
+			commanderMock := mocks.Commander()
+			app, _, commandersRepoMock, _ := appWithReposMocks()
+			commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
+				ID: commanderMock.ID,
+			}).Return(commanderMock, nil)
+
+			headers := map[string]string{"Accept": "application/ld+json"}
+			route := "http://example.com/commanders/" + commanderMock.ID.String()
+			httptest.AssertFiberGETWithHeaders(t, app, route, headers, http.StatusOK, func(res *http.Response) {
+				commandersRepoMock.AssertExpectations(t)
+				httptest.AssertJSONLD(t, res, httptest.JSONLDNode{
+					ID:   "http://example.com/commanders/" + commanderMock.ID.String(),
+					Type: "Person",
+					SameAs: []string{
+						"https://en.wikipedia.org/wiki/Emperor_Napoleon_I",
+						"http://www.wikidata.org/entity/Q517",
+					},
+				})
+			})
+		})
+
 		t.Run("ValidNonPersistedUUID", func(t *testing.T) {
 			uuid := uuid.NewV4()
 			app, _, commandersRepoMock, _ := appWithReposMocks()
-			commandersRepoMock.On("FindOne", commanders.FindOneQuery{
+			commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
 				ID: uuid,
 			}).Return(commanders.Commander{}, domain.ErrNotFound)
 
@@ -62,7 +85,7 @@ func TestCommandersHandlers(t *testing.T) {
 		for _, c := range cases {
 			t.Run(c.description, func(t *testing.T) {
 				app, _, commandersRepoMock, _ := appWithReposMocks()
-				commandersRepoMock.On("FindMany", c.calledWith, page).
+				commandersRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
 					Return(commandersMock, pagesMock, nil)
 				httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
 					commandersRepoMock.AssertExpectations(t)
@@ -71,6 +94,32 @@ func TestCommandersHandlers(t *testing.T) {
 				})
 			})
 		}
+		t.Run("AsCSV", func(t *testing.T) {
+			app, _, commandersRepoMock, _ := appWithReposMocks()
+			commandersRepoMock.On("Stream", mock.Anything, commanders.FindManyQuery{}).
+				Return(commandersMock, nil)
+			headers := map[string]string{"Accept": "text/csv"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL, headers, http.StatusOK, func(res *http.Response) {
+				commandersRepoMock.AssertExpectations(t)
+				httptest.AssertCSVColumn(t, res, "allegiances", []string{
+					"Kingdom of France; French First Republic; First French Empire",
+					"",
+				})
+			})
+		})
+		t.Run("AsNDJSONWithFields", func(t *testing.T) {
+			app, _, commandersRepoMock, _ := appWithReposMocks()
+			commandersRepoMock.On("Stream", mock.Anything, commanders.FindManyQuery{}).
+				Return(commandersMock, nil)
+			headers := map[string]string{"Accept": "application/x-ndjson"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL+"&fields=name", headers, http.StatusOK, func(res *http.Response) {
+				commandersRepoMock.AssertExpectations(t)
+				httptest.AssertNDJSON(t, res,
+					map[string]string{"name": commandersMock[0].Name},
+					map[string]string{"name": commandersMock[1].Name},
+				)
+			})
+		})
 	})
 
 	t.Run("GET /factions/:factionID/commanders", func(t *testing.T) {
@@ -93,10 +142,10 @@ func TestCommandersHandlers(t *testing.T) {
 			for _, c := range cases {
 				t.Run(c.description, func(t *testing.T) {
 					app, factionsRepoMock, commandersRepoMock, _ := appWithReposMocks()
-					factionsRepoMock.On("FindOne", factions.FindOneQuery{
+					factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
 						ID: factionMock.ID,
 					}).Return(factionMock, nil)
-					commandersRepoMock.On("FindMany", c.calledWith, page).
+					commandersRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
 						Return(commandersMock, pagesMock, nil)
 
 					httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
@@ -106,12 +155,38 @@ func TestCommandersHandlers(t *testing.T) {
 					})
 				})
 			}
+			t.Run("WithIncludeRelated", func(t *testing.T) {
+				app, factionsRepoMock, commandersRepoMock, _ := appWithReposMocks()
+				factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
+					ID: factionMock.ID,
+				}).Return(factionMock, nil)
+				commandersRepoMock.On("FindMany", mock.Anything, commanders.FindManyQuery{
+					FactionID:      factionMock.ID,
+					IncludeRelated: true,
+				}, page).Return(commandersMock, pagesMock, nil)
+
+				url := baseURL(factionMock.ID.String()) + "&includeRelated=true"
+				httptest.AssertFiberGET(t, app, url, http.StatusOK, func(res *http.Response) {
+					commandersRepoMock.AssertExpectations(t)
+					httptest.AssertJSONCommanders(t, res, commandersMock)
+				})
+			})
+			t.Run("WithInvalidIncludeRelated", func(t *testing.T) {
+				app, factionsRepoMock, commandersRepoMock, _ := appWithReposMocks()
+				factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
+					ID: factionMock.ID,
+				}).Return(factionMock, nil)
+
+				url := baseURL(factionMock.ID.String()) + "&includeRelated=x"
+				httptest.AssertFailedFiberGET(t, app, url, http.StatusBadRequest, "Invalid includeRelated, must be true or false")
+				commandersRepoMock.AssertNotCalled(t, "FindMany")
+			})
 		})
 
 		t.Run("ValidNonPersistedFactionUUID", func(t *testing.T) {
 			uuid := uuid.NewV4()
 			app, factionsRepoMock, commandersRepoMock, _ := appWithReposMocks()
-			factionsRepoMock.On("FindOne", factions.FindOneQuery{
+			factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
 				ID: uuid,
 			}).Return(factions.Faction{}, domain.ErrNotFound)
 
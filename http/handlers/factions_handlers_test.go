@@ -1,16 +1,26 @@
 package handlers_test
 
 import (
+	"errors"
 	"fmt"
+	"io/ioutil"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/sasalatart/batcoms/domain"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/factions"
+	bhttp "github.com/sasalatart/batcoms/http"
 	"github.com/sasalatart/batcoms/http/httptest"
 	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/flags"
 	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFactionsHandlers(t *testing.T) {
@@ -20,7 +30,7 @@ func TestFactionsHandlers(t *testing.T) {
 		t.Run("ValidPersistedUUID", func(t *testing.T) {
 			factionMock := mocks.Faction()
 			app, factionsRepoMock, _, _ := appWithReposMocks()
-			factionsRepoMock.On("FindOne", factions.FindOneQuery{
+			factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
 				ID: factionMock.ID,
 			}).Return(factionMock, nil)
 
@@ -33,7 +43,7 @@ func TestFactionsHandlers(t *testing.T) {
 		t.Run("ValidNonPersistedUUID", func(t *testing.T) {
 			uuid := uuid.NewV4()
 			app, factionsRepoMock, _, _ := appWithReposMocks()
-			factionsRepoMock.On("FindOne", factions.FindOneQuery{
+			factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
 				ID: uuid,
 			}).Return(factions.Faction{}, domain.ErrNotFound)
 
@@ -48,6 +58,59 @@ func TestFactionsHandlers(t *testing.T) {
 		})
 	})
 
+	t.Run("GET /factions/:factionID/flag", func(t *testing.T) {
+		t.Parallel()
+
+		t.Run("NotDownloaded", func(t *testing.T) {
+			factionMock := mocks.Faction()
+			app, factionsRepoMock, _, _ := appWithReposMocks()
+			factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
+				ID: factionMock.ID,
+			}).Return(factionMock, nil)
+
+			httptest.AssertFiberGET(t, app, "/factions/"+factionMock.ID.String()+"/flag", http.StatusFound, func(res *http.Response) {
+				factionsRepoMock.AssertExpectations(t)
+				assert.Equal(t, "https://upload.wikimedia.org/wikipedia/commons/c/c3/Flag_of_France.svg", res.Header.Get("Location"))
+				assert.Equal(t, "public, max-age=604800", res.Header.Get("Cache-Control"))
+			})
+		})
+
+		t.Run("Downloaded", func(t *testing.T) {
+			dir, err := ioutil.TempDir("", "flags")
+			require.NoError(t, err, "Creating flags directory")
+			defer os.RemoveAll(dir)
+			factionMock := mocks.Faction()
+			location, _ := flags.Local(dir, factionMock.Flag)
+			require.NoError(t, os.MkdirAll(filepath.Dir(location), 0755), "Creating flag directory")
+			image := []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`)
+			err = ioutil.WriteFile(location, image, 0644)
+			require.NoError(t, err, "Storing flag")
+
+			app, factionsRepoMock := appWithFlagsDir(dir)
+			factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
+				ID: factionMock.ID,
+			}).Return(factionMock, nil)
+
+			httptest.AssertFiberGET(t, app, "/factions/"+factionMock.ID.String()+"/flag", http.StatusOK, func(res *http.Response) {
+				assert.Equal(t, "public, max-age=604800", res.Header.Get("Cache-Control"))
+				assert.Equal(t, "image/svg+xml", res.Header.Get("Content-Type"))
+				body, err := ioutil.ReadAll(res.Body)
+				require.NoError(t, err, "Reading response body")
+				assert.Equal(t, image, body)
+			})
+		})
+
+		t.Run("WithoutFlag", func(t *testing.T) {
+			factionMock := mocks.Faction2()
+			app, factionsRepoMock, _, _ := appWithReposMocks()
+			factionsRepoMock.On("FindOne", mock.Anything, factions.FindOneQuery{
+				ID: factionMock.ID,
+			}).Return(factionMock, nil)
+
+			httptest.AssertFailedFiberGET(t, app, "/factions/"+factionMock.ID.String()+"/flag", http.StatusNotFound, "Flag not found")
+		})
+	})
+
 	t.Run("GET /factions", func(t *testing.T) {
 		t.Parallel()
 
@@ -62,7 +125,7 @@ func TestFactionsHandlers(t *testing.T) {
 		for _, c := range cases {
 			t.Run(c.description, func(t *testing.T) {
 				app, factionsRepoMock, _, _ := appWithReposMocks()
-				factionsRepoMock.On("FindMany", c.calledWith, page).
+				factionsRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
 					Return(factionsMock, pagesMock, nil)
 				httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
 					factionsRepoMock.AssertExpectations(t)
@@ -71,6 +134,93 @@ func TestFactionsHandlers(t *testing.T) {
 				})
 			})
 		}
+		t.Run("WithFields", func(t *testing.T) {
+			app, factionsRepoMock, _, _ := appWithReposMocks()
+			factionsRepoMock.On("FindMany", mock.Anything, factions.FindManyQuery{}, page).
+				Return(factionsMock, pagesMock, nil)
+			httptest.AssertFiberGET(t, app, baseURL+"&fields=id,name", http.StatusOK, func(res *http.Response) {
+				factionsRepoMock.AssertExpectations(t)
+				httptest.AssertJSONFields(t, res, []string{"id", "name"})
+			})
+		})
+		t.Run("AsCSV", func(t *testing.T) {
+			app, factionsRepoMock, _, _ := appWithReposMocks()
+			factionsRepoMock.On("Stream", mock.Anything, factions.FindManyQuery{Name: "french"}).
+				Return(factionsMock, nil)
+			headers := map[string]string{"Accept": "text/csv"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL+"&name=french", headers, http.StatusOK, func(res *http.Response) {
+				factionsRepoMock.AssertExpectations(t)
+				factionsRepoMock.AssertNotCalled(t, "FindMany")
+				assert.Empty(t, res.Header.Get("x-pages"), "Exports should not be paginated")
+				httptest.AssertCSVColumn(t, res, "name", []string{factionsMock[0].Name, factionsMock[1].Name})
+			})
+		})
+		t.Run("AsNDJSON", func(t *testing.T) {
+			app, factionsRepoMock, _, _ := appWithReposMocks()
+			factionsRepoMock.On("Stream", mock.Anything, factions.FindManyQuery{}).
+				Return(factionsMock, nil)
+			headers := map[string]string{"Accept": "application/x-ndjson"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL, headers, http.StatusOK, func(res *http.Response) {
+				factionsRepoMock.AssertExpectations(t)
+				httptest.AssertNDJSON(t, res, factionsMock[0], factionsMock[1])
+			})
+		})
+		t.Run("AsCSVWithFailingQuery", func(t *testing.T) {
+			app, factionsRepoMock, _, _ := appWithReposMocks()
+			factionsRepoMock.On("Stream", mock.Anything, factions.FindManyQuery{}).
+				Return([]factions.Faction{}, errors.New("connection refused"))
+			headers := map[string]string{"Accept": "text/csv"}
+			httptest.AssertFiberGETWithHeaders(t, app, baseURL, headers, http.StatusInternalServerError, func(res *http.Response) {
+				assert.NotEqual(t, "text/csv; charset=utf-8", res.Header.Get("Content-Type"))
+			})
+		})
+		t.Run("AsCSVFailingMidway", func(t *testing.T) {
+			factionsRepoMock := new(mocks.FactionsRepository)
+			factionsRepoMock.On("Stream", mock.Anything, factions.FindManyQuery{}).
+				Return(factionsMock, errors.New("connection lost"))
+			logged := make(errorsLogger, 1)
+			app := bhttp.Setup(
+				factionsRepoMock,
+				new(mocks.CommandersRepository),
+				new(mocks.BattlesRepository),
+				new(mocks.TranslationsRepository),
+				bhttp.Options{Logger: logged},
+			)
+			req, err := http.NewRequest(http.MethodGet, baseURL, nil)
+			require.NoError(t, err, "Building request")
+			req.Header.Set("Accept", "text/csv")
+			_, err = app.Test(req, -1)
+			assert.EqualError(t, err, "connection lost", "Should abort the response")
+			select {
+			case err := <-logged:
+				assert.Contains(t, err.Error(), "Streaming export: connection lost")
+			case <-time.After(time.Second):
+				t.Error("Should log the error that aborted the export")
+			}
+		})
+		t.Run("AsJSONLD", func(t *testing.T) {
+			app, factionsRepoMock, _, _ := appWithReposMocks()
+			factionsRepoMock.On("FindMany", mock.Anything, factions.FindManyQuery{}, page).
+				Return(factionsMock, pagesMock, nil)
+			headers := map[string]string{"Accept": "application/ld+json"}
+			route := "http://example.com" + baseURL
+			httptest.AssertFiberGETWithHeaders(t, app, route, headers, http.StatusOK, func(res *http.Response) {
+				factionsRepoMock.AssertExpectations(t)
+				httptest.AssertHeaderPages(t, res, pagesMock)
+				httptest.AssertJSONLD(t, res,
+					httptest.JSONLDNode{
+						ID:     "http://example.com/factions/" + factionsMock[0].ID.String(),
+						Type:   "Organization",
+						SameAs: []string{"https://en.wikipedia.org/wiki/French_First_Empire"},
+					},
+					httptest.JSONLDNode{
+						ID:     "http://example.com/factions/" + factionsMock[1].ID.String(),
+						Type:   "Organization",
+						SameAs: []string{"https://en.wikipedia.org/wiki/Imperial_Russia"},
+					},
+				)
+			})
+		})
 	})
 
 	t.Run("GET /commanders/:commanderID/factions", func(t *testing.T) {
@@ -93,10 +243,10 @@ func TestFactionsHandlers(t *testing.T) {
 			for _, c := range cases {
 				t.Run(c.description, func(t *testing.T) {
 					app, factionsRepoMock, commandersRepoMock, _ := appWithReposMocks()
-					commandersRepoMock.On("FindOne", commanders.FindOneQuery{
+					commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
 						ID: commanderMock.ID,
 					}).Return(commanderMock, nil)
-					factionsRepoMock.On("FindMany", c.calledWith, page).
+					factionsRepoMock.On("FindMany", mock.Anything, c.calledWith, page).
 						Return(factionsMock, pagesMock, nil)
 
 					httptest.AssertFiberGET(t, app, c.url, http.StatusOK, func(res *http.Response) {
@@ -111,7 +261,7 @@ func TestFactionsHandlers(t *testing.T) {
 		t.Run("ValidNonPersistedCommanderUUID", func(t *testing.T) {
 			uuid := uuid.NewV4()
 			app, factionsRepoMock, commandersRepoMock, _ := appWithReposMocks()
-			commandersRepoMock.On("FindOne", commanders.FindOneQuery{
+			commandersRepoMock.On("FindOne", mock.Anything, commanders.FindOneQuery{
 				ID: uuid,
 			}).Return(commanders.Commander{}, domain.ErrNotFound)
 
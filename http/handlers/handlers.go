@@ -5,70 +5,131 @@ import (
 	"github.com/sasalatart/batcoms/domain/battles"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
 	"github.com/sasalatart/batcoms/http/middleware"
 )
 
 // Register registers all factions, commanders and battles routes together with their handlers in
-// the given *fiber.App
-func Register(app *fiber.App, fr factions.Reader, cr commanders.Reader, br battles.Reader) {
-	app.Get("/factions/:factionID",
+// the given *fiber.App. Each handler is traced within a span of its own. Flags downloaded into
+// flagsDir are served from there
+func Register(app *fiber.App, fr factions.Reader, cr commanders.Reader, br battles.Reader, tr translations.Reader, flagsDir string) {
+	get := func(path string, handlers ...fiber.Handler) {
+		app.Get(path, middleware.Traced(handlers...)...)
+	}
+
+	get("/factions/:factionID",
+		middleware.WithFields(factions.Faction{}),
 		middleware.WithFaction(fr),
+		middleware.WithTranslations(tr, "faction"),
 		middleware.JSONFrom("faction"),
 	)
 
-	app.Get("/factions",
+	get("/factions/:factionID/flag",
+		middleware.WithFaction(fr),
+		middleware.ServeFlag(flagsDir),
+	)
+
+	get("/factions",
 		middleware.WithPage(),
+		middleware.WithFields(factions.Faction{}),
 		middleware.WithFactions(fr),
+		middleware.WithTranslations(tr, "factions"),
 		middleware.JSONFrom("factions"),
 	)
 
-	app.Get("/commanders/:commanderID/factions",
+	get("/commanders/:commanderID/factions",
 		middleware.WithPage(),
+		middleware.WithFields(factions.Faction{}),
 		middleware.WithCommander(cr),
 		middleware.WithFactions(fr),
+		middleware.WithTranslations(tr, "factions"),
 		middleware.JSONFrom("factions"),
 	)
 
-	app.Get("/commanders/:commanderID",
+	get("/commanders/:commanderID",
+		middleware.WithFields(commanders.Commander{}),
 		middleware.WithCommander(cr),
+		middleware.WithTranslations(tr, "commander"),
 		middleware.JSONFrom("commander"),
 	)
 
-	app.Get("/commanders",
+	get("/commanders",
 		middleware.WithPage(),
+		middleware.WithFields(commanders.Commander{}),
 		middleware.WithCommanders(cr),
+		middleware.WithTranslations(tr, "commanders"),
 		middleware.JSONFrom("commanders"),
 	)
 
-	app.Get("/factions/:factionID/commanders",
+	get("/factions/:factionID/commanders",
 		middleware.WithPage(),
+		middleware.WithFields(commanders.Commander{}),
 		middleware.WithFaction(fr),
 		middleware.WithCommanders(cr),
+		middleware.WithTranslations(tr, "commanders"),
 		middleware.JSONFrom("commanders"),
 	)
 
-	app.Get("/battles/:battleID",
+	get("/battles/:battleID",
+		middleware.WithFields(battles.Battle{}),
 		middleware.WithBattle(br),
+		middleware.WithTranslations(tr, "battle"),
+		middleware.WithExpand("battle"),
+		middleware.WithDateFormat("battle"),
 		middleware.JSONFrom("battle"),
 	)
 
-	app.Get("/battles",
+	get("/battles/:battleID/concurrent",
+		middleware.WithPage(),
+		middleware.WithFields(battles.Battle{}),
+		middleware.WithBattle(br),
+		middleware.WithConcurrentBattles(br),
+		middleware.WithTranslations(tr, "battles"),
+		middleware.WithExpand("battles"),
+		middleware.WithDateFormat("battles"),
+		middleware.JSONFrom("battles"),
+	)
+
+	get("/battles/:battleID/related",
+		middleware.WithPage(),
+		middleware.WithFields(battles.Battle{}),
+		middleware.WithBattle(br),
+		middleware.WithRelatedBattles(br),
+		middleware.WithTranslations(tr, "battles"),
+		middleware.WithExpand("battles"),
+		middleware.WithDateFormat("battles"),
+		middleware.JSONFrom("battles"),
+	)
+
+	get("/battles",
 		middleware.WithPage(),
+		middleware.WithFields(battles.Battle{}),
 		middleware.WithBattles(br),
+		middleware.WithTranslations(tr, "battles"),
+		middleware.WithExpand("battles"),
+		middleware.WithDateFormat("battles"),
 		middleware.JSONFrom("battles"),
 	)
 
-	app.Get("/factions/:factionID/battles",
+	get("/factions/:factionID/battles",
 		middleware.WithPage(),
+		middleware.WithFields(battles.Battle{}),
 		middleware.WithFaction(fr),
 		middleware.WithBattles(br),
+		middleware.WithTranslations(tr, "battles"),
+		middleware.WithExpand("battles"),
+		middleware.WithDateFormat("battles"),
 		middleware.JSONFrom("battles"),
 	)
 
-	app.Get("/commanders/:commanderID/battles",
+	get("/commanders/:commanderID/battles",
 		middleware.WithPage(),
+		middleware.WithFields(battles.Battle{}),
 		middleware.WithCommander(cr),
 		middleware.WithBattles(br),
+		middleware.WithTranslations(tr, "battles"),
+		middleware.WithExpand("battles"),
+		middleware.WithDateFormat("battles"),
 		middleware.JSONFrom("battles"),
 	)
 }
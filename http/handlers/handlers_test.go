@@ -7,9 +7,30 @@ import (
 )
 
 func appWithReposMocks() (*fiber.App, *mocks.FactionsRepository, *mocks.CommandersRepository, *mocks.BattlesRepository) {
+	app, factionsRepoMock, commandersRepoMock, battlesRepoMock, _ := appWithTranslationsMock()
+	return app, factionsRepoMock, commandersRepoMock, battlesRepoMock
+}
+
+func appWithTranslationsMock() (*fiber.App, *mocks.FactionsRepository, *mocks.CommandersRepository, *mocks.BattlesRepository, *mocks.TranslationsRepository) {
 	factionsRepoMock := new(mocks.FactionsRepository)
 	commandersRepoMock := new(mocks.CommandersRepository)
 	battlesRepoMock := new(mocks.BattlesRepository)
-	app := http.Setup(factionsRepoMock, commandersRepoMock, battlesRepoMock, true)
-	return app, factionsRepoMock, commandersRepoMock, battlesRepoMock
+	translationsRepoMock := new(mocks.TranslationsRepository)
+	app := http.Setup(factionsRepoMock, commandersRepoMock, battlesRepoMock, translationsRepoMock, http.Options{})
+	return app, factionsRepoMock, commandersRepoMock, battlesRepoMock, translationsRepoMock
+}
+
+func appWithFlagsDir(dir string) (*fiber.App, *mocks.FactionsRepository) {
+	factionsRepoMock := new(mocks.FactionsRepository)
+	app := http.Setup(factionsRepoMock, new(mocks.CommandersRepository), new(mocks.BattlesRepository), new(mocks.TranslationsRepository), http.Options{FlagsDir: dir})
+	return app, factionsRepoMock
+}
+
+// errorsLogger is a logger.Interface that sends the errors it logs through itself
+type errorsLogger chan error
+
+func (l errorsLogger) Info(string) {}
+
+func (l errorsLogger) Error(err error) {
+	l <- err
 }
@@ -0,0 +1,100 @@
+package handlers_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	bhttp "github.com/sasalatart/batcoms/http"
+	"github.com/sasalatart/batcoms/http/httptest"
+	"github.com/sasalatart/batcoms/http/middleware"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthHandlers(t *testing.T) {
+	t.Run("GET /healthz", func(t *testing.T) {
+		app, _, _, battlesRepoMock := appWithReposMocks()
+		httptest.AssertFiberGET(t, app, "/healthz", http.StatusOK, func(res *http.Response) {
+			assertReadiness(t, res, "ok", nil)
+		})
+		battlesRepoMock.AssertNotCalled(t, "Any", mock.Anything)
+	})
+
+	t.Run("GET /readyz", func(t *testing.T) {
+		failingCheck := middleware.Check{
+			Name: "database",
+			Run:  func(context.Context) error { return errors.New("connection refused") },
+		}
+		passingCheck := middleware.Check{
+			Name: "database",
+			Run:  func(context.Context) error { return nil },
+		}
+		cases := []struct {
+			description    string
+			seeded         bool
+			check          middleware.Check
+			expectedStatus int
+			expectedChecks map[string]string
+		}{
+			{
+				description:    "Ready",
+				seeded:         true,
+				check:          passingCheck,
+				expectedStatus: http.StatusOK,
+				expectedChecks: map[string]string{"seeded": "ok", "database": "ok"},
+			},
+			{
+				description:    "NotSeeded",
+				seeded:         false,
+				check:          passingCheck,
+				expectedStatus: http.StatusServiceUnavailable,
+				expectedChecks: map[string]string{"seeded": "No battles have been seeded", "database": "ok"},
+			},
+			{
+				description:    "FailingCheck",
+				seeded:         true,
+				check:          failingCheck,
+				expectedStatus: http.StatusServiceUnavailable,
+				expectedChecks: map[string]string{"seeded": "ok", "database": "connection refused"},
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				battlesRepoMock := new(mocks.BattlesRepository)
+				battlesRepoMock.On("Any", mock.Anything).Return(c.seeded, nil)
+				app := bhttp.Setup(
+					new(mocks.FactionsRepository),
+					new(mocks.CommandersRepository),
+					battlesRepoMock,
+					new(mocks.TranslationsRepository),
+					bhttp.Options{Checks: []middleware.Check{c.check}},
+				)
+
+				httptest.AssertFiberGET(t, app, "/readyz", c.expectedStatus, func(res *http.Response) {
+					battlesRepoMock.AssertExpectations(t)
+					expectedStatus := "ok"
+					if c.expectedStatus != http.StatusOK {
+						expectedStatus = "unavailable"
+					}
+					assertReadiness(t, res, expectedStatus, c.expectedChecks)
+				})
+			})
+		}
+	})
+}
+
+func assertReadiness(t *testing.T, res *http.Response, expectedStatus string, expectedChecks map[string]string) {
+	t.Helper()
+	var body struct {
+		Status string
+		Checks map[string]string
+	}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&body), "Decoding body")
+	assert.Equal(t, expectedStatus, body.Status)
+	assert.Equal(t, expectedChecks, body.Checks)
+}
@@ -0,0 +1,32 @@
+package handlers_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/sasalatart/batcoms/http/httptest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetricsHandlers(t *testing.T) {
+	t.Run("GET /metrics", func(t *testing.T) {
+		app, factionsRepoMock, _, _ := appWithReposMocks()
+		httptest.AssertFailedFiberGET(t, app, "/factions/invalid-uuid", http.StatusBadRequest, "Invalid FactionID")
+		httptest.AssertFailedFiberGET(t, app, "/unknown", http.StatusNotFound, "Cannot GET /unknown")
+		factionsRepoMock.AssertNotCalled(t, "FindOne")
+
+		httptest.AssertFiberGET(t, app, "/metrics", http.StatusOK, func(res *http.Response) {
+			assert.Contains(t, res.Header.Get("Content-Type"), "text/plain")
+			body, err := ioutil.ReadAll(res.Body)
+			require.NoError(t, err, "Reading body")
+			metrics := string(body)
+
+			assert.Contains(t, metrics, `batcoms_http_requests_total{method="GET",route="/factions/:factionID",status="400"}`)
+			assert.Contains(t, metrics, `batcoms_http_requests_total{method="GET",route="unmatched",status="404"}`)
+			assert.Contains(t, metrics, `batcoms_http_request_duration_seconds_count{method="GET",route="/factions/:factionID"}`)
+			assert.Contains(t, metrics, "go_goroutines")
+		})
+	})
+}
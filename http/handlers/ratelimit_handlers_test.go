@@ -0,0 +1,149 @@
+package handlers_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	"github.com/sasalatart/batcoms/domain/factions"
+	bhttp "github.com/sasalatart/batcoms/http"
+	"github.com/sasalatart/batcoms/http/httptest"
+	"github.com/sasalatart/batcoms/http/middleware"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/sasalatart/batcoms/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRateLimitHandlers(t *testing.T) {
+	anonymous := ratelimit.Limit{PerMinute: 1, Burst: 2}
+	limits := middleware.RateLimits{Anonymous: anonymous, PerIP: ratelimit.Limit{PerMinute: 1, Burst: 100}}
+	setupWith := func(apiKeysRepoMock *mocks.APIKeysRepository, limits middleware.RateLimits, proxyHeader string) *fiber.App {
+		factionsRepoMock := new(mocks.FactionsRepository)
+		factionsRepoMock.On("FindMany", mock.Anything, factions.FindManyQuery{}, 1).Return([]factions.Faction{mocks.Faction()}, 1, nil)
+		return bhttp.Setup(
+			factionsRepoMock,
+			new(mocks.CommandersRepository),
+			new(mocks.BattlesRepository),
+			new(mocks.TranslationsRepository),
+			bhttp.Options{
+				RateLimit:   middleware.WithRateLimit(apiKeysRepoMock, limits, time.Minute),
+				ProxyHeader: proxyHeader,
+			},
+		)
+	}
+	setup := func(apiKeysRepoMock *mocks.APIKeysRepository) *fiber.App {
+		return setupWith(apiKeysRepoMock, limits, "")
+	}
+	keyQuery := apikeys.FindOneQuery{Hash: apikeys.Hash(mocks.PlainAPIKey())}
+	withKey := map[string]string{middleware.APIKeyHeader: mocks.PlainAPIKey()}
+
+	t.Run("Anonymous", func(t *testing.T) {
+		app := setup(new(mocks.APIKeysRepository))
+		for remaining := 1; remaining >= 0; remaining-- {
+			httptest.AssertFiberGET(t, app, "/factions", http.StatusOK, func(res *http.Response) {
+				httptest.AssertHeadersRateLimit(t, res, anonymous.Burst, remaining)
+			})
+		}
+		httptest.AssertFiberGET(t, app, "/factions", http.StatusTooManyRequests, func(res *http.Response) {
+			httptest.AssertErrorMessage(t, res, "Rate limit exceeded")
+			httptest.AssertHeadersRateLimit(t, res, anonymous.Burst, 0)
+			assert.Equal(t, "60", res.Header.Get(fiber.HeaderRetryAfter))
+		})
+		httptest.AssertFiberGET(t, app, "/healthz", http.StatusOK, func(res *http.Response) {
+			assert.Empty(t, res.Header.Get(middleware.RateLimitLimitHeader), "Should not limit health checks")
+		})
+	})
+
+	t.Run("WithAPIKey", func(t *testing.T) {
+		apiKeysRepoMock := new(mocks.APIKeysRepository)
+		apiKeysRepoMock.On("FindOne", mock.Anything, keyQuery).Return(mocks.APIKey(), nil).Once()
+		app := setup(apiKeysRepoMock)
+
+		burst := mocks.APIKey().Burst
+		for remaining := burst - 1; remaining >= 0; remaining-- {
+			httptest.AssertFiberGETWithHeaders(t, app, "/factions", withKey, http.StatusOK, func(res *http.Response) {
+				httptest.AssertHeadersRateLimit(t, res, burst, remaining)
+			})
+		}
+		httptest.AssertFiberGETWithHeaders(t, app, "/factions", withKey, http.StatusTooManyRequests, func(res *http.Response) {
+			httptest.AssertErrorMessage(t, res, "Rate limit exceeded")
+		})
+		httptest.AssertFiberGET(t, app, "/factions", http.StatusOK, func(res *http.Response) {
+			httptest.AssertHeadersRateLimit(t, res, anonymous.Burst, 1)
+		})
+		apiKeysRepoMock.AssertExpectations(t)
+	})
+
+	t.Run("WithAPIKeyOverIPLimit", func(t *testing.T) {
+		apiKeysRepoMock := new(mocks.APIKeysRepository)
+		apiKeysRepoMock.On("FindOne", mock.Anything, keyQuery).Return(mocks.APIKey(), nil).Once()
+		perIP := ratelimit.Limit{PerMinute: 1, Burst: mocks.APIKey().Burst - 1}
+		app := setupWith(apiKeysRepoMock, middleware.RateLimits{Anonymous: anonymous, PerIP: perIP}, "")
+
+		for i := 0; i < perIP.Burst; i++ {
+			httptest.AssertFiberGETWithHeaders(t, app, "/factions", withKey, http.StatusOK, func(*http.Response) {})
+		}
+		httptest.AssertFiberGETWithHeaders(t, app, "/factions", withKey, http.StatusTooManyRequests, func(res *http.Response) {
+			httptest.AssertErrorMessage(t, res, "Rate limit exceeded")
+			httptest.AssertHeadersRateLimit(t, res, perIP.Burst, 0)
+		})
+		httptest.AssertFiberGET(t, app, "/factions", http.StatusTooManyRequests, func(*http.Response) {})
+	})
+
+	t.Run("WithProxyHeader", func(t *testing.T) {
+		app := setupWith(new(mocks.APIKeysRepository), limits, "X-Real-IP")
+		first := map[string]string{"X-Real-IP": "203.0.113.1"}
+		for i := 0; i < anonymous.Burst; i++ {
+			httptest.AssertFiberGETWithHeaders(t, app, "/factions", first, http.StatusOK, func(*http.Response) {})
+		}
+		httptest.AssertFiberGETWithHeaders(t, app, "/factions", first, http.StatusTooManyRequests, func(*http.Response) {})
+
+		second := map[string]string{"X-Real-IP": "203.0.113.2"}
+		httptest.AssertFiberGETWithHeaders(t, app, "/factions", second, http.StatusOK, func(res *http.Response) {
+			httptest.AssertHeadersRateLimit(t, res, anonymous.Burst, anonymous.Burst-1)
+		})
+	})
+
+	t.Run("WithInvalidAPIKey", func(t *testing.T) {
+		revoked := mocks.APIKey()
+		revokedAt := time.Now()
+		revoked.RevokedAt = &revokedAt
+		cases := []struct {
+			description string
+			found       apikeys.APIKey
+			err         error
+		}{
+			{description: "Unknown", found: apikeys.APIKey{}, err: domain.ErrNotFound},
+			{description: "Revoked", found: revoked, err: nil},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				apiKeysRepoMock := new(mocks.APIKeysRepository)
+				apiKeysRepoMock.On("FindOne", mock.Anything, keyQuery).Return(c.found, c.err)
+				app := setup(apiKeysRepoMock)
+
+				for remaining := 1; remaining >= 0; remaining-- {
+					httptest.AssertFiberGETWithHeaders(t, app, "/factions", withKey, http.StatusUnauthorized, func(res *http.Response) {
+						httptest.AssertErrorMessage(t, res, "Invalid API key")
+						httptest.AssertHeadersRateLimit(t, res, anonymous.Burst, remaining)
+					})
+				}
+				httptest.AssertFiberGETWithHeaders(t, app, "/factions", withKey, http.StatusTooManyRequests, func(res *http.Response) {
+					httptest.AssertErrorMessage(t, res, "Rate limit exceeded")
+				})
+			})
+		}
+	})
+
+	t.Run("WithFailingLookup", func(t *testing.T) {
+		apiKeysRepoMock := new(mocks.APIKeysRepository)
+		apiKeysRepoMock.On("FindOne", mock.Anything, keyQuery).Return(apikeys.APIKey{}, errors.New("connection refused"))
+		app := setup(apiKeysRepoMock)
+		httptest.AssertFiberGETWithHeaders(t, app, "/factions", withKey, http.StatusInternalServerError, func(*http.Response) {})
+	})
+}
@@ -0,0 +1,67 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/http/httptest"
+	"github.com/sasalatart/batcoms/http/middleware"
+	"github.com/sasalatart/batcoms/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracingHandlers(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	const remoteSpanID = "00f067aa0ba902b7"
+	headers := map[string]string{"traceparent": "00-" + traceID + "-" + remoteSpanID + "-01"}
+
+	t.Run("GET /factions", func(t *testing.T) {
+		app, factionsRepoMock, _, _ := appWithReposMocks()
+		factionsMock := []factions.Faction{mocks.Faction()}
+		factionsRepoMock.On("FindMany", mock.Anything, factions.FindManyQuery{}, 1).Return(factionsMock, 1, nil)
+
+		httptest.AssertFiberGETWithHeaders(t, app, "/factions", headers, http.StatusOK, func(res *http.Response) {
+			assert.Equal(t, traceID, res.Header.Get(middleware.TraceIDHeader))
+		})
+
+		spansByName := make(map[string]sdktrace.ReadOnlySpan)
+		for _, span := range recorder.Ended() {
+			assert.Equal(t, traceID, span.SpanContext().TraceID().String(), "Should continue the incoming trace")
+			spansByName[span.Name()] = span
+		}
+		request, ok := spansByName["GET /factions"]
+		require.True(t, ok, "Should trace the request")
+		assert.Equal(t, remoteSpanID, request.Parent().SpanID().String())
+
+		parent := request
+		for _, name := range []string{"WithPage", "WithFields", "WithFactions", "WithTranslations", "JSONFrom"} {
+			span, ok := spansByName[name]
+			require.True(t, ok, "Should trace "+name)
+			assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), name+" should follow "+parent.Name())
+			parent = span
+		}
+	})
+
+	t.Run("Without traceparent", func(t *testing.T) {
+		app, factionsRepoMock, _, _ := appWithReposMocks()
+		httptest.AssertFiberGET(t, app, "/factions/invalid-uuid", http.StatusBadRequest, func(res *http.Response) {
+			started := res.Header.Get(middleware.TraceIDHeader)
+			assert.Len(t, started, 32, "Should start a new trace")
+			assert.NotEqual(t, traceID, started)
+		})
+		factionsRepoMock.AssertNotCalled(t, "FindOne")
+	})
+}
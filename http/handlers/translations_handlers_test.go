@@ -0,0 +1,133 @@
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
+	"github.com/sasalatart/batcoms/http/httptest"
+	"github.com/sasalatart/batcoms/mocks"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTranslationsHandlers(t *testing.T) {
+	battleMock := mocks.Battle()
+	battleIDs := []uuid.UUID{battleMock.ID}
+	for _, f := range append(append([]factions.Faction{}, battleMock.Factions.A...), battleMock.Factions.B...) {
+		battleIDs = append(battleIDs, f.ID)
+	}
+	for _, c := range append(append([]commanders.Commander{}, battleMock.Commanders.A...), battleMock.Commanders.B...) {
+		battleIDs = append(battleIDs, c.ID)
+	}
+
+	translatedBattle := mocks.Battle()
+	translatedBattle.Name = mocks.BattleTranslation().Name
+	translatedBattle.Summary = mocks.BattleTranslation().Summary
+	translatedBattle.Commanders.A[0].Name = mocks.CommanderTranslation().Name
+	translatedBattle.Commanders.A[0].Summary = mocks.CommanderTranslation().Summary
+
+	cases := []struct {
+		description string
+		route       string
+		headers     map[string]string
+		language    string
+		served      string
+		found       []translations.Translation
+		expected    battles.Battle
+	}{
+		{
+			description: "WithoutPreferences",
+			route:       "/battles/" + battleMock.ID.String(),
+			language:    "en",
+			expected:    battleMock,
+		},
+		{
+			description: "WithLangQueryParam",
+			route:       "/battles/" + battleMock.ID.String() + "?lang=es",
+			language:    "es",
+			found:       []translations.Translation{mocks.BattleTranslation(), mocks.CommanderTranslation()},
+			expected:    translatedBattle,
+		},
+		{
+			description: "WithAcceptLanguageHeader",
+			route:       "/battles/" + battleMock.ID.String(),
+			headers:     map[string]string{"Accept-Language": "es-CL, es;q=0.9, en;q=0.8"},
+			language:    "es",
+			found:       []translations.Translation{mocks.BattleTranslation(), mocks.CommanderTranslation()},
+			expected:    translatedBattle,
+		},
+		{
+			description: "WithUnsupportedAcceptLanguageHeader",
+			route:       "/battles/" + battleMock.ID.String(),
+			headers:     map[string]string{"Accept-Language": "it-IT, it"},
+			language:    "en",
+			expected:    battleMock,
+		},
+		{
+			description: "WithoutTranslations",
+			route:       "/battles/" + battleMock.ID.String() + "?lang=fr",
+			language:    "fr",
+			served:      "en",
+			found:       []translations.Translation{},
+			expected:    battleMock,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			app, _, _, battlesRepoMock, translationsRepoMock := appWithTranslationsMock()
+			battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{ID: battleMock.ID}).Return(battleMock, nil)
+			if c.found != nil {
+				translationsRepoMock.On("FindMany", mock.Anything, translations.FindManyQuery{
+					Language:  c.language,
+					EntityIDs: battleIDs,
+				}).Return(c.found, nil)
+			}
+
+			httptest.AssertFiberGETWithHeaders(t, app, c.route, c.headers, http.StatusOK, func(res *http.Response) {
+				battlesRepoMock.AssertExpectations(t)
+				translationsRepoMock.AssertExpectations(t)
+				if c.found == nil {
+					translationsRepoMock.AssertNotCalled(t, "FindMany")
+				}
+				served := c.served
+				if served == "" {
+					served = c.language
+				}
+				assert.Equal(t, served, res.Header.Get("Content-Language"), "Content-Language header")
+				httptest.AssertJSONBattle(t, res, c.expected)
+			})
+		})
+	}
+
+	t.Run("WithInvalidLangQueryParam", func(t *testing.T) {
+		app, _, _, battlesRepoMock, translationsRepoMock := appWithTranslationsMock()
+		battlesRepoMock.On("FindOne", mock.Anything, battles.FindOneQuery{ID: battleMock.ID}).Return(battleMock, nil)
+		route := "/battles/" + battleMock.ID.String() + "?lang=xx"
+		httptest.AssertFailedFiberGET(t, app, route, http.StatusBadRequest, "Invalid lang, must be one of en, es, fr, de")
+		translationsRepoMock.AssertNotCalled(t, "FindMany")
+	})
+
+	t.Run("GET /commanders", func(t *testing.T) {
+		commandersMock := []commanders.Commander{mocks.Commander(), mocks.Commander2()}
+		app, _, commandersRepoMock, _, translationsRepoMock := appWithTranslationsMock()
+		commandersRepoMock.On("FindMany", mock.Anything, commanders.FindManyQuery{}, 1).Return(commandersMock, 1, nil)
+		translationsRepoMock.On("FindMany", mock.Anything, translations.FindManyQuery{
+			Language:  "es",
+			EntityIDs: []uuid.UUID{mocks.Commander().ID, mocks.Commander2().ID},
+		}).Return([]translations.Translation{mocks.CommanderTranslation()}, nil)
+
+		expected := []commanders.Commander{mocks.Commander(), mocks.Commander2()}
+		expected[0].Name = mocks.CommanderTranslation().Name
+		expected[0].Summary = mocks.CommanderTranslation().Summary
+		httptest.AssertFiberGET(t, app, "/commanders?lang=es", http.StatusOK, func(res *http.Response) {
+			translationsRepoMock.AssertExpectations(t)
+			assert.Equal(t, "es", res.Header.Get("Content-Language"), "Content-Language header")
+			httptest.AssertJSONCommanders(t, res, expected)
+		})
+	})
+}
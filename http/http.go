@@ -1,20 +1,67 @@
 package http
 
 import (
+	"time"
+
 	"github.com/gofiber/fiber/v2"
-	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
 	"github.com/sasalatart/batcoms/domain/battles"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
 	"github.com/sasalatart/batcoms/http/handlers"
+	"github.com/sasalatart/batcoms/http/middleware"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/metrics"
 )
 
-// Setup sets up a new fiber server, registers middleware, route handlers, and returns a pointer to it
-func Setup(fr factions.Reader, cr commanders.Reader, br battles.Reader, debug bool) *fiber.App {
-	app := fiber.New()
+// readinessTimeout is how long the checks of /readyz may take before the API is reported as not ready
+const readinessTimeout = 2 * time.Second
+
+// Options configure the server returned by Setup. Their zero value is a valid configuration
+type Options struct {
+	// FlagsDir is the directory from which downloaded flags are served
+	FlagsDir string
+	// RateLimit is run before the handlers of every route other than the health and metrics ones,
+	// unless it is nil
+	RateLimit func(*fiber.Ctx) error
+	// ProxyHeader is the header from which the IP of clients is read, instead of from the address of
+	// the connection. It must only be set when the API is behind a trusted proxy that overwrites it
+	// with a single IP, such as X-Real-IP, as clients could otherwise choose their IP at will
+	ProxyHeader string
+	// Logger logs every request, and discards them if nil
+	Logger logger.Interface
+	// Checks must pass, besides battles having been seeded, for the API to be reported as ready
+	Checks []middleware.Check
+}
+
+// Setup sets up a new fiber server, registers middleware, route handlers, and returns a pointer to it.
+// Requests are traced, logged, and their metrics are served under /metrics. The API is reported as
+// alive under /healthz, and as ready under /readyz once battles have been seeded and all the checks
+// of the given options pass
+func Setup(
+	fr factions.Reader,
+	cr commanders.Reader,
+	br battles.Reader,
+	tr translations.Reader,
+	o Options,
+) *fiber.App {
+	l := o.Logger
+	if l == nil {
+		l = logger.NewDiscard()
+	}
+	app := fiber.New(fiber.Config{ProxyHeader: o.ProxyHeader})
+	app.Use(middleware.WithTracing())
+	app.Use(middleware.WithRequestLogger(l))
+	app.Use(middleware.WithMetrics())
 	app.Use(recover.New())
-	app.Use(logger.New())
-	handlers.Register(app, fr, cr, br)
+	app.Get("/metrics", middleware.ServeMetrics(metrics.Registry))
+	app.Get("/healthz", middleware.ServeHealth())
+	readiness := append([]middleware.Check{middleware.SeededCheck(br)}, o.Checks...)
+	app.Get("/readyz", middleware.ServeReadiness(readinessTimeout, readiness...))
+	if o.RateLimit != nil {
+		app.Use(o.RateLimit)
+	}
+	handlers.Register(app, fr, cr, br, tr, o.FlagsDir)
 	return app
 }
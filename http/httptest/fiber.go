@@ -11,9 +11,18 @@ import (
 // AssertFiberGET asserts that the specified route, when handled by the given *fiber.App, renders
 // the specified status and satisfies the given assertResponse function
 func AssertFiberGET(t *testing.T, app *fiber.App, route string, status int, assertResponse func(*http.Response)) {
+	t.Helper()
+	AssertFiberGETWithHeaders(t, app, route, nil, status, assertResponse)
+}
+
+// AssertFiberGETWithHeaders is like AssertFiberGET, but sends the given headers with the request
+func AssertFiberGETWithHeaders(t *testing.T, app *fiber.App, route string, headers map[string]string, status int, assertResponse func(*http.Response)) {
 	t.Helper()
 	req, err := http.NewRequest("GET", route, nil)
 	require.NoError(t, err, route)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
 	res, err := app.Test(req, -1)
 	require.NoError(t, err, route)
 	defer res.Body.Close()
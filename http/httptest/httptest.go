@@ -1,15 +1,18 @@
 package httptest
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"testing"
 
 	"github.com/sasalatart/batcoms/domain/battles"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/pkg/linkeddata"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -90,6 +93,110 @@ func AssertJSONBattles(t *testing.T, res *http.Response, expectedBattles []battl
 	assert.Equal(t, expectedBattles, *battlesFromBody, "Comparing body with expected battles")
 }
 
+// AssertJSONBattleDates asserts that the given *http.Response contains a JSON-serialized battle
+// whose startDate and endDate are the specified strings
+func AssertJSONBattleDates(t *testing.T, res *http.Response, expectedStartDate, expectedEndDate string) {
+	t.Helper()
+	battleFromBody := new(struct {
+		StartDate string `json:"startDate"`
+		EndDate   string `json:"endDate"`
+	})
+	err := json.NewDecoder(res.Body).Decode(battleFromBody)
+	require.NoError(t, err, "Decoding body into battle dates")
+	assert.Equal(t, expectedStartDate, battleFromBody.StartDate, "Comparing body with expected startDate")
+	assert.Equal(t, expectedEndDate, battleFromBody.EndDate, "Comparing body with expected endDate")
+}
+
+// AssertJSONBattleSides asserts that the given response has a JSON battle whose sides match the
+// expected ones, and whose factions, commanders and commandersByFaction have been left out
+func AssertJSONBattleSides(t *testing.T, res *http.Response, expectedSides battles.Sides) {
+	t.Helper()
+	battleFromBody := new(struct {
+		Factions            json.RawMessage `json:"factions"`
+		Commanders          json.RawMessage `json:"commanders"`
+		CommandersByFaction json.RawMessage `json:"commandersByFaction"`
+		Sides               battles.Sides   `json:"sides"`
+	})
+	err := json.NewDecoder(res.Body).Decode(battleFromBody)
+	require.NoError(t, err, "Decoding body into battle sides")
+	assert.Nil(t, battleFromBody.Factions, "Expecting factions to be left out")
+	assert.Nil(t, battleFromBody.Commanders, "Expecting commanders to be left out")
+	assert.Nil(t, battleFromBody.CommandersByFaction, "Expecting commandersByFaction to be left out")
+	assert.Equal(t, expectedSides, battleFromBody.Sides, "Comparing body with expected sides")
+}
+
+// AssertJSONBattlesDates is like AssertJSONBattleDates, but for a slice of battles. Each of the
+// expected elements holds the startDate and endDate of a battle
+func AssertJSONBattlesDates(t *testing.T, res *http.Response, expectedDates [][2]string) {
+	t.Helper()
+	battlesFromBody := new([]struct {
+		StartDate string `json:"startDate"`
+		EndDate   string `json:"endDate"`
+	})
+	err := json.NewDecoder(res.Body).Decode(battlesFromBody)
+	require.NoError(t, err, "Decoding body into battles dates")
+	var datesFromBody [][2]string
+	for _, b := range *battlesFromBody {
+		datesFromBody = append(datesFromBody, [2]string{b.StartDate, b.EndDate})
+	}
+	assert.Equal(t, expectedDates, datesFromBody, "Comparing body with expected battles dates")
+}
+
+// AssertJSONFields asserts that the given *http.Response contains a JSON-serialized slice of
+// entities, each one of which has exactly the expected fields, in any order
+func AssertJSONFields(t *testing.T, res *http.Response, expectedFields []string) {
+	t.Helper()
+	var entities []map[string]json.RawMessage
+	err := json.NewDecoder(res.Body).Decode(&entities)
+	require.NoError(t, err, "Decoding body into entities slice")
+	require.NotEmpty(t, entities, "Body should contain entities")
+	for _, e := range entities {
+		fields := make([]string, 0, len(e))
+		for f := range e {
+			fields = append(fields, f)
+		}
+		assert.ElementsMatch(t, expectedFields, fields, "Comparing fields with expected ones")
+	}
+}
+
+// AssertCSVColumn asserts that the given *http.Response contains a CSV export whose column with the
+// given name has the expected values, one for each record after the header
+func AssertCSVColumn(t *testing.T, res *http.Response, column string, expectedValues []string) {
+	t.Helper()
+	assert.True(t, strings.HasPrefix(res.Header.Get("Content-Type"), "text/csv"), "Content-Type should be CSV")
+	records, err := csv.NewReader(res.Body).ReadAll()
+	require.NoError(t, err, "Reading CSV records from body")
+	require.NotEmpty(t, records, "Body should contain a header")
+	index := -1
+	for i, name := range records[0] {
+		if name == column {
+			index = i
+		}
+	}
+	require.NotEqualf(t, -1, index, "Header should contain the column %q", column)
+	values := make([]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		values = append(values, record[index])
+	}
+	assert.Equal(t, expectedValues, values, "Comparing column %q with expected values", column)
+}
+
+// AssertNDJSON asserts that the given *http.Response contains an NDJSON export with one line for
+// each of the expected entities, in the same order, each one of which is JSON-equivalent to them
+func AssertNDJSON(t *testing.T, res *http.Response, expectedEntities ...interface{}) {
+	t.Helper()
+	assert.True(t, strings.HasPrefix(res.Header.Get("Content-Type"), "application/x-ndjson"), "Content-Type should be NDJSON")
+	contents, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err, "Reading from response body")
+	lines := strings.Split(strings.TrimSuffix(string(contents), "\n"), "\n")
+	require.Len(t, lines, len(expectedEntities), "Body should contain one line per entity")
+	for i, e := range expectedEntities {
+		expected, err := json.Marshal(e)
+		require.NoError(t, err, "Encoding expected entity")
+		assert.JSONEq(t, string(expected), lines[i], "Comparing line %d with expected entity", i)
+	}
+}
+
 // AssertHeaderPages asserts that the given *http.Response has the expected "x-pages" header value
 func AssertHeaderPages(t *testing.T, res *http.Response, expectedPages int) {
 	t.Helper()
@@ -97,3 +204,62 @@ func AssertHeaderPages(t *testing.T, res *http.Response, expectedPages int) {
 	got := res.Header.Get("x-pages")
 	assert.Equal(t, expected, got, "Comparing with the expected 'x-pages' header")
 }
+
+// AssertHeadersRateLimit asserts that the given *http.Response has the expected "X-RateLimit-Limit"
+// and "X-RateLimit-Remaining" header values
+func AssertHeadersRateLimit(t *testing.T, res *http.Response, expectedLimit, expectedRemaining int) {
+	t.Helper()
+	assert.Equal(t, fmt.Sprint(expectedLimit), res.Header.Get("X-RateLimit-Limit"), "Comparing with the expected 'X-RateLimit-Limit' header")
+	assert.Equal(t, fmt.Sprint(expectedRemaining), res.Header.Get("X-RateLimit-Remaining"), "Comparing with the expected 'X-RateLimit-Remaining' header")
+}
+
+// JSONLDNode identifies a node described in a JSON-LD document by its @id and @type, and by the
+// IRIs it is linked with through owl:sameAs
+type JSONLDNode struct {
+	ID     string
+	Type   string
+	SameAs []string
+}
+
+// AssertJSONLD asserts that the given *http.Response contains a JSON-LD document that describes the
+// expected nodes in order, either at its top level when there is only one, or in its "@graph"
+func AssertJSONLD(t *testing.T, res *http.Response, expectedNodes ...JSONLDNode) {
+	t.Helper()
+	assert.Equal(t, linkeddata.MIMEType, res.Header.Get("Content-Type"), "Content-Type should be JSON-LD")
+	var document struct {
+		Context map[string]string `json:"@context"`
+		Graph   []json.RawMessage `json:"@graph"`
+	}
+	contents, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err, "Reading from response body")
+	require.NoError(t, json.Unmarshal(contents, &document), "Decoding JSON-LD document")
+	assert.Equal(t, linkeddata.SchemaOrg, document.Context["@vocab"], "Should describe terms with schema.org")
+
+	nodes := document.Graph
+	if nodes == nil {
+		nodes = []json.RawMessage{contents}
+	}
+	require.Len(t, nodes, len(expectedNodes), "Comparing the number of nodes described")
+	type link struct {
+		ID string `json:"@id"`
+	}
+	for i, raw := range nodes {
+		var node struct {
+			ID     string          `json:"@id"`
+			Type   string          `json:"@type"`
+			SameAs json.RawMessage `json:"owl:sameAs"`
+		}
+		require.NoError(t, json.Unmarshal(raw, &node), "Decoding node")
+		var links []link
+		if len(node.SameAs) > 0 && json.Unmarshal(node.SameAs, &links) != nil {
+			var single link
+			require.NoError(t, json.Unmarshal(node.SameAs, &single), "Decoding owl:sameAs")
+			links = []link{single}
+		}
+		actual := JSONLDNode{ID: node.ID, Type: node.Type}
+		for _, link := range links {
+			actual.SameAs = append(actual.SameAs, link.ID)
+		}
+		assert.Equal(t, expectedNodes[i], actual, "Comparing node %d with the expected one", i)
+	}
+}
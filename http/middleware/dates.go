@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/pkg/dates"
+)
+
+var dateFormatters = map[string]func(dates.Historic) string{
+	"iso":  dates.Historic.ISO,
+	"edtf": dates.Historic.EDTF,
+}
+
+// WithDateFormat middleware parses the optional "dateFormat" query parameter. It may be "object"
+// (the default), "iso" or "edtf". For the last two, the battle or battles stored into ctx.Locals
+// under the given key are replaced by representations whose startDate and endDate are strings in
+// ISO 8601 or EDTF format
+func WithDateFormat(key string) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		dateFormat := ctx.Query("dateFormat", "object")
+		if dateFormat == "object" {
+			return ctx.Next()
+		}
+		format, ok := dateFormatters[dateFormat]
+		if !ok {
+			return newErrBadRequest("Invalid dateFormat, must be object, iso or edtf")
+		}
+
+		mapBattleViews(ctx, key, func(v battleView) battleView {
+			v.StartDate = format(v.Battle.StartDate)
+			v.EndDate = format(v.Battle.EndDate)
+			return v
+		})
+		return ctx.Next()
+	}
+}
+
+// parseDateQuery parses the given query parameter as a date in any of the formats supported by
+// dates.ParseFormatted, and returns the earliest or latest day at which it may have happened
+func parseDateQuery(ctx *fiber.Ctx, param string, earliest bool) (dates.Historic, error) {
+	date, err := dates.ParseFormatted(ctx.Query(param))
+	if err != nil {
+		return dates.Historic{}, newErrBadRequest("Invalid " + param + ", must be in YYYY-MM-DD, ISO 8601 or EDTF format")
+	}
+	if earliest {
+		return date.Earliest().ToBeginning(), nil
+	}
+	return date.Latest().ToEnd(), nil
+}
@@ -7,6 +7,17 @@ import (
 	"github.com/gofiber/fiber/v2"
 )
 
+// handleError passes err to the error handler of the app, as fiber does once the middleware chain
+// returns, so that middleware wrapping others may know the status that is going to be sent
+func handleError(ctx *fiber.Ctx, err error) {
+	if err == nil {
+		return
+	}
+	if handlerErr := ctx.App().Config().ErrorHandler(ctx, err); handlerErr != nil {
+		ctx.Status(fiber.StatusInternalServerError)
+	}
+}
+
 func newErrBadRequest(message string) error {
 	return &fiber.Error{Code: http.StatusBadRequest, Message: message}
 }
@@ -14,3 +25,11 @@ func newErrBadRequest(message string) error {
 func newErrNotFound(resource string) error {
 	return &fiber.Error{Code: http.StatusNotFound, Message: fmt.Sprintf("%s not found", resource)}
 }
+
+func newErrUnauthorized(message string) error {
+	return &fiber.Error{Code: http.StatusUnauthorized, Message: message}
+}
+
+func newErrTooManyRequests() error {
+	return &fiber.Error{Code: http.StatusTooManyRequests, Message: "Rate limit exceeded"}
+}
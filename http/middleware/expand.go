@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain/battles"
+)
+
+// expansion tells which relations of battles are included in responses, and whether these are also
+// grouped by side
+type expansion struct {
+	factions   bool
+	commanders bool
+	sides      bool
+}
+
+// defaultExpansion is the one used when the "expand" query parameter is absent
+var defaultExpansion = expansion{factions: true, commanders: true}
+
+// expansionFromQuery parses the optional "expand" query parameter, which is a comma-separated list
+// of factions, commanders and sides, falling back to the defaultExpansion
+func expansionFromQuery(ctx *fiber.Ctx) (expansion, error) {
+	if ctx.Query("expand") == "" {
+		return defaultExpansion, nil
+	}
+	var e expansion
+	for _, relation := range strings.Split(ctx.Query("expand"), ",") {
+		switch strings.TrimSpace(relation) {
+		case "factions":
+			e.factions = true
+		case "commanders":
+			e.commanders = true
+		case "sides":
+			e.sides = true
+		default:
+			return expansion{}, newErrBadRequest("Invalid expand, must be a comma-separated list of factions, commanders and sides")
+		}
+	}
+	return e, nil
+}
+
+// omitFromQuery returns the relations of battles that are not going to be rendered, according to
+// the optional "expand" query parameter and the fields stored by WithFields, so that these need not
+// be loaded
+func omitFromQuery(ctx *fiber.Ctx) (battles.Relations, error) {
+	e, err := expansionFromQuery(ctx)
+	if err != nil {
+		return battles.Relations{}, err
+	}
+	requested := fieldsFromLocals(ctx)
+	wanted := func(field string) bool {
+		if requested == nil {
+			return true
+		}
+		for _, f := range requested {
+			if f == field {
+				return true
+			}
+		}
+		return false
+	}
+	both := e.factions && e.commanders && (wanted("commandersByFaction") || wanted("commanderConfidences"))
+	sides := e.sides && wanted("sides")
+	return battles.Relations{
+		Factions:   !(e.factions && wanted("factions") || both || sides),
+		Commanders: !(e.commanders && wanted("commanders") || both || sides),
+	}, nil
+}
+
+// WithExpand middleware parses the optional "expand" query parameter, which is a comma-separated
+// list of factions, commanders and sides. When present, only the relations listed are rendered for
+// the battle or battles stored into ctx.Locals under the given key, with their commandersByFaction
+// and commanderConfidences only rendered together with both factions and commanders. Sides group
+// the factions of each side, each one embedding the commanders that fought under it together with
+// how confident that assignment is, and list commanders whose faction is unknown apart
+func WithExpand(key string) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		e, err := expansionFromQuery(ctx)
+		if err != nil {
+			return err
+		}
+		if e == defaultExpansion {
+			return ctx.Next()
+		}
+
+		mapBattleViews(ctx, key, e.apply)
+		return ctx.Next()
+	}
+}
+
+// apply changes the given view so that only the relations of the expansion are rendered
+func (e expansion) apply(v battleView) battleView {
+	if e.sides {
+		sides := v.Battle.Sides()
+		v.Sides = &sides
+	}
+	if !e.factions {
+		v.Factions = nil
+	}
+	if !e.commanders {
+		v.Commanders = nil
+	}
+	if !e.factions || !e.commanders {
+		v.CommandersByFaction = nil
+		v.CommanderConfidences = nil
+	}
+	return v
+}
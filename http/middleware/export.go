@@ -0,0 +1,245 @@
+package middleware
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/tracing"
+	"go.opentelemetry.io/otel/codes"
+)
+
+// MIMETextCSV and MIMEApplicationNDJSON are the media types in which lists of factions, commanders
+// and battles may be exported, instead of being rendered as paginated JSON
+const (
+	MIMETextCSV           = "text/csv"
+	MIMEApplicationNDJSON = "application/x-ndjson"
+)
+
+// exportTypeFromHeader negotiates, from the Accept header, whether a list is exported as CSV or
+// NDJSON. It returns an empty string when it should be rendered as paginated JSON instead
+func exportTypeFromHeader(ctx *fiber.Ctx) string {
+	return acceptedType(ctx, MIMETextCSV, MIMEApplicationNDJSON)
+}
+
+// acceptedType negotiates, from the Accept header, which of the given media types is served instead
+// of JSON. It returns an empty string when none of them is accepted, or when JSON or any media type
+// is accepted before them. As with ctx.Accepts, quality values are not taken into account, and
+// media types are preferred in the order they are listed
+func acceptedType(ctx *fiber.Ctx, offers ...string) string {
+	ctx.Vary(fiber.HeaderAccept)
+	for _, spec := range strings.Split(ctx.Get(fiber.HeaderAccept), ",") {
+		mediaType := strings.TrimSpace(strings.Split(spec, ";")[0])
+		switch mediaType {
+		case fiber.MIMEApplicationJSON, "application/*", "*/*":
+			return ""
+		}
+		for _, offer := range offers {
+			if mediaType == offer {
+				return offer
+			}
+		}
+	}
+	return ""
+}
+
+// streamExport streams, without paginating them, all of the entities that produce passes to write,
+// which are of the same type as entity, in the given export type. NDJSON exports keep only the
+// fields stored by WithFields, while CSV ones have a fixed set of columns. The response is only
+// sent once the first entity has been produced, or once produce has returned, so that errors that
+// happen before then are returned as usual. Errors (and panics) that happen later can only abort
+// the response, so they are recorded in the trace of the request and logged instead
+func streamExport(ctx *fiber.Ctx, exportType string, entity interface{}, produce func(write func(interface{}) error) error) error {
+	fields := fieldsFromLocals(ctx)
+	spanCtx, log := traceContext(ctx), loggerFromLocals(ctx).With(logger.KV("path", ctx.Path()))
+	if traceID := traceIDFromLocals(ctx); traceID != "" {
+		log = log.With(logger.KV("trace_id", traceID))
+	}
+
+	started := make(chan error, 1)
+	var once sync.Once
+	start := func(err error) (first bool) {
+		once.Do(func() {
+			started <- err
+			first = true
+		})
+		return first
+	}
+	pr, pw := io.Pipe()
+	go func() {
+		_, span := tracing.Tracer().Start(spanCtx, "streamExport")
+		defer span.End()
+
+		var err error
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("Panicked while exporting: %v", r)
+			}
+			pw.CloseWithError(err)
+			if !start(err) && err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				log.Error(errors.Wrap(err, "Streaming export"))
+			}
+		}()
+		produceStarting := func(write func(interface{}) error) error {
+			return produce(func(value interface{}) error {
+				start(nil)
+				return write(value)
+			})
+		}
+		if exportType == MIMETextCSV {
+			err = writeCSV(pw, entity, produceStarting)
+		} else {
+			err = writeNDJSON(pw, fields, produceStarting)
+		}
+	}()
+	if err := <-started; err != nil {
+		return err
+	}
+	ctx.Set(fiber.HeaderContentType, exportType+"; charset=utf-8")
+	ctx.Context().SetBodyStream(pr, -1)
+	return nil
+}
+
+// streamBattles streams the battles matching the given query. CSV exports always include their
+// factions and commanders, which are flattened into columns, while NDJSON ones only render the
+// relations in the optional "expand" query parameter
+func streamBattles(ctx *fiber.Ctx, r battles.Reader, query battles.FindManyQuery, exportType string) error {
+	e, err := expansionFromQuery(ctx)
+	if err != nil {
+		return err
+	}
+	if exportType == MIMETextCSV {
+		query.Omit = battles.Relations{}
+	}
+	spanCtx := traceContext(ctx)
+	return streamExport(ctx, exportType, battleView{}, func(write func(interface{}) error) error {
+		return r.Stream(spanCtx, query, func(b battles.Battle) error {
+			return write(e.apply(newBattleView(b)))
+		})
+	})
+}
+
+func writeCSV(w io.Writer, entity interface{}, produce func(write func(interface{}) error) error) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader(entity)); err != nil {
+		return err
+	}
+	err := produce(func(value interface{}) error {
+		return cw.Write(csvRecord(value))
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func writeNDJSON(w io.Writer, fields []string, produce func(write func(interface{}) error) error) error {
+	bw := bufio.NewWriter(w)
+	encoder := json.NewEncoder(bw)
+	err := produce(func(value interface{}) error {
+		if fields != nil {
+			selected, err := selectFields(value, fields)
+			if err != nil {
+				return err
+			}
+			value = selected
+		}
+		return encoder.Encode(value)
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+var (
+	factionCSVHeader   = []string{"id", "wikiID", "url", "name", "summary", "flag"}
+	commanderCSVHeader = []string{"id", "wikiID", "url", "name", "summary", "birthDate", "deathDate", "allegiances"}
+	battleCSVHeader    = []string{
+		"id", "wikiID", "url", "name", "partOf", "summary", "startDate", "endDate",
+		"place", "latitude", "longitude", "result", "territorialChanges",
+		"strengthA", "strengthB", "strengthAB", "casualtiesA", "casualtiesB", "casualtiesAB",
+		"factionsA", "factionsB", "commandersA", "commandersB",
+	}
+)
+
+// csvHeader returns the names of the columns in which entities of the same type as the given one
+// are exported
+func csvHeader(entity interface{}) []string {
+	switch entity.(type) {
+	case factions.Faction:
+		return factionCSVHeader
+	case commanders.Commander:
+		return commanderCSVHeader
+	case battleView:
+		return battleCSVHeader
+	}
+	return nil
+}
+
+// csvRecord flattens the given faction, commander or battle view into the columns of its CSV
+// header. Dates are formatted as ISO 8601, and the names of the factions and commanders of each
+// side of battles, as well as those of the allegiances of commanders, are separated by semicolons
+func csvRecord(value interface{}) []string {
+	switch v := value.(type) {
+	case factions.Faction:
+		return []string{v.ID.String(), fmt.Sprint(v.WikiID), v.URL, v.Name, v.Summary, v.Flag}
+	case commanders.Commander:
+		allegiances := make([]string, 0, len(v.Allegiances))
+		for _, a := range v.Allegiances {
+			allegiances = append(allegiances, a.Name)
+		}
+		return []string{
+			v.ID.String(), fmt.Sprint(v.WikiID), v.URL, v.Name, v.Summary,
+			isoOrEmpty(v.BirthDate), isoOrEmpty(v.DeathDate), strings.Join(allegiances, "; "),
+		}
+	case battleView:
+		b := v.Battle
+		return []string{
+			b.ID.String(), fmt.Sprint(b.WikiID), b.URL, b.Name, b.PartOf, b.Summary,
+			b.StartDate.ISO(), b.EndDate.ISO(),
+			b.Location.Place, b.Location.Latitude, b.Location.Longitude, b.Result, b.TerritorialChanges,
+			b.Strength.A, b.Strength.B, b.Strength.AB, b.Casualties.A, b.Casualties.B, b.Casualties.AB,
+			factionNames(b.Factions.A), factionNames(b.Factions.B),
+			commanderNames(b.Commanders.A), commanderNames(b.Commanders.B),
+		}
+	}
+	return nil
+}
+
+func isoOrEmpty(date *dates.Historic) string {
+	if date == nil {
+		return ""
+	}
+	return date.ISO()
+}
+
+func factionNames(fs []factions.Faction) string {
+	names := make([]string, 0, len(fs))
+	for _, f := range fs {
+		names = append(names, f.Name)
+	}
+	return strings.Join(names, "; ")
+}
+
+func commanderNames(cs []commanders.Commander) string {
+	names := make([]string, 0, len(cs))
+	for _, c := range cs {
+		names = append(names, c.Name)
+	}
+	return strings.Join(names, "; ")
+}
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/pkg/linkeddata"
+)
+
+// WithFields middleware parses the optional "fields" query parameter, which is a comma-separated
+// list of the fields to render for each entity, validates that entities of the same type as the
+// given one have them, and then stores them into ctx.Locals under the key "fields". Fields are not
+// taken into account when exporting CSV, and are rejected when JSON-LD is accepted, as its
+// documents always describe entities in full
+func WithFields(entity interface{}) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		fields := fieldsFromQuery(ctx)
+		if fields == nil || exportTypeFromHeader(ctx) == MIMETextCSV {
+			return ctx.Next()
+		}
+		if acceptedType(ctx, linkeddata.MIMEType) != "" {
+			return newErrBadRequest("Invalid fields, these can not be selected for JSON-LD")
+		}
+		if err := validateFields(entity, fields); err != nil {
+			return err
+		}
+		ctx.Locals("fields", fields)
+		return ctx.Next()
+	}
+}
+
+// fieldsFromQuery parses the optional "fields" query parameter, which is a comma-separated list of
+// the fields to render for each entity. It returns nil when absent
+func fieldsFromQuery(ctx *fiber.Ctx) []string {
+	if ctx.Query("fields") == "" {
+		return nil
+	}
+	var fields []string
+	for _, f := range strings.Split(ctx.Query("fields"), ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+// fieldsFromLocals returns the fields stored into ctx.Locals by WithFields, or nil if none were
+func fieldsFromLocals(ctx *fiber.Ctx) []string {
+	fields, _ := ctx.Locals("fields").([]string)
+	return fields
+}
+
+// validateFields reports the given fields as a bad request unless the entity, which may also be a
+// slice of them, has all of them
+func validateFields(entity interface{}, fields []string) error {
+	known := jsonFields(selectableType(entity))
+	for _, f := range fields {
+		if !known[f] {
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return newErrBadRequest("Invalid fields, must be a comma-separated list of " + strings.Join(names, ", "))
+		}
+	}
+	return nil
+}
+
+// selectFields returns the JSON representation of value, which may be an entity or a slice of them,
+// where only the given fields of each entity are kept
+func selectFields(value interface{}, fields []string) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	project := func(entity map[string]json.RawMessage) map[string]json.RawMessage {
+		projected := make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			if v, ok := entity[f]; ok {
+				projected[f] = v
+			}
+		}
+		return projected
+	}
+	if reflect.ValueOf(value).Kind() == reflect.Slice {
+		var entities []map[string]json.RawMessage
+		if err := json.Unmarshal(data, &entities); err != nil {
+			return nil, err
+		}
+		projected := make([]map[string]json.RawMessage, 0, len(entities))
+		for _, e := range entities {
+			projected = append(projected, project(e))
+		}
+		return projected, nil
+	}
+	var entity map[string]json.RawMessage
+	if err := json.Unmarshal(data, &entity); err != nil {
+		return nil, err
+	}
+	return project(entity), nil
+}
+
+// selectableType returns the type of the entities whose fields may be selected from value. Battles
+// may be rendered as views, so all of their fields may be selected even when some were not expanded
+func selectableType(value interface{}) reflect.Type {
+	switch value.(type) {
+	case battles.Battle, []battles.Battle:
+		return reflect.TypeOf(battleView{})
+	}
+	return reflect.TypeOf(value)
+}
+
+// jsonFields returns the names of the JSON fields of the structs of type t, or of those contained in
+// it when it is a slice or a pointer. Fields of embedded structs are included as their own
+func jsonFields(t reflect.Type) map[string]bool {
+	fields := make(map[string]bool)
+	if t == nil {
+		return fields
+	}
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return fields
+	}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name := strings.Split(f.Tag.Get("json"), ",")[0]
+		switch {
+		case name == "-" || (f.PkgPath != "" && !f.Anonymous):
+			continue
+		case f.Anonymous && name == "":
+			for embedded := range jsonFields(f.Type) {
+				fields[embedded] = true
+			}
+		case name == "":
+			fields[f.Name] = true
+		default:
+			fields[name] = true
+		}
+	}
+	return fields
+}
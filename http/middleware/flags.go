@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/pkg/flags"
+)
+
+// flagsCacheControl lets clients cache flags for a week, as their images rarely change
+const flagsCacheControl = "public, max-age=604800"
+
+// ServeFlag responds with the image of the flag of the faction stored in ctx.Locals under the key
+// "faction". Flags that have been downloaded into dir are served from disk, while the rest are
+// redirected to Wikimedia Commons
+func ServeFlag(dir string) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		faction := ctx.Locals("faction").(factions.Faction)
+		if faction.Flag == "" {
+			return newErrNotFound("Flag")
+		}
+		ctx.Set(fiber.HeaderCacheControl, flagsCacheControl)
+		if location, downloaded := flags.Local(dir, faction.Flag); downloaded {
+			return ctx.SendFile(location)
+		}
+		return ctx.Redirect(flags.URL(faction.Flag), http.StatusFound)
+	}
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain/battles"
+)
+
+// Check is one of the conditions that must hold for the API to be ready to serve requests
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// SeededCheck returns a Check that fails unless at least one battle has been stored, given that an
+// empty dataset is as useless to clients as an unreachable one. It only asks whether any exists, so
+// that it stays cheap however many battles there are
+func SeededCheck(r battles.Reader) Check {
+	return Check{
+		Name: "seeded",
+		Run: func(ctx context.Context) error {
+			seeded, err := r.Any(ctx)
+			if err != nil {
+				return err
+			}
+			if !seeded {
+				return errors.New("No battles have been seeded")
+			}
+			return nil
+		},
+	}
+}
+
+// ServeHealth renders whether the process is alive, which is the case as long as it can respond
+func ServeHealth() func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		return ctx.JSON(fiber.Map{"status": "ok"})
+	}
+}
+
+// ServeReadiness runs the given checks and renders the result of each one of them, with a 503 status
+// if any failed or did not finish within timeout
+func ServeReadiness(timeout time.Duration, checks ...Check) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		checkCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+
+		status, results := "ok", make(map[string]string, len(checks))
+		for _, c := range checks {
+			results[c.Name] = "ok"
+			if err := c.Run(checkCtx); err != nil {
+				status, results[c.Name] = "unavailable", err.Error()
+			}
+		}
+		if status != "ok" {
+			ctx.Status(fiber.StatusServiceUnavailable)
+		}
+		return ctx.JSON(fiber.Map{"status": status, "checks": results})
+	}
+}
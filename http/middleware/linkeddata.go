@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/pkg/linkeddata"
+)
+
+// linkedDataFrom returns the JSON-LD document that describes the faction, commander or battle (or
+// slice of them) in the given value, identified by the IRIs under which they are served
+func linkedDataFrom(ctx *fiber.Ctx, value interface{}) map[string]interface{} {
+	m := linkeddata.NewMapper(ctx.BaseURL())
+	var nodes []linkeddata.Node
+	switch v := value.(type) {
+	case factions.Faction:
+		nodes = append(nodes, m.Faction(v))
+	case []factions.Faction:
+		for _, f := range v {
+			nodes = append(nodes, m.Faction(f))
+		}
+	case commanders.Commander:
+		nodes = append(nodes, m.Commander(v))
+	case []commanders.Commander:
+		for _, c := range v {
+			nodes = append(nodes, m.Commander(c))
+		}
+	case battles.Battle:
+		nodes = append(nodes, m.Battle(v))
+	case battleView:
+		nodes = append(nodes, m.Battle(v.Battle))
+	case []battles.Battle:
+		for _, b := range v {
+			nodes = append(nodes, m.Battle(b))
+		}
+	case []battleView:
+		for _, b := range v {
+			nodes = append(nodes, m.Battle(b.Battle))
+		}
+	}
+	return linkeddata.Document(nodes...)
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/pkg/logger"
+)
+
+const loggerKey = "logger"
+
+// WithRequestLogger middleware logs every request once it has been handled, with its method, path,
+// status, IP, how long it took and the ID of its trace, if any. Errors returned by the next handlers
+// are handled here, so that the logged status is the one that is sent. Requests that fail with a 5xx
+// status are logged as errors. The logger is also set into ctx.Locals, for the next handlers to log
+// what happens after they return, such as while streaming responses
+func WithRequestLogger(l logger.Interface) func(*fiber.Ctx) error {
+	log := logger.From(l).Named("http")
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+		ctx.Locals(loggerKey, log)
+		err := ctx.Next()
+		handleError(ctx, err)
+
+		status := ctx.Response().StatusCode()
+		fields := []logger.Field{
+			logger.KV("method", ctx.Method()),
+			logger.KV("path", ctx.Path()),
+			logger.KV("status", status),
+			logger.KV("ip", ctx.IP()),
+			logger.KV("duration_ms", time.Since(start).Milliseconds()),
+		}
+		if traceID := traceIDFromLocals(ctx); traceID != "" {
+			fields = append(fields, logger.KV("trace_id", traceID))
+		}
+		requestLog := log.With(fields...)
+		if status < fiber.StatusInternalServerError {
+			requestLog.Info("Handled request")
+			return nil
+		}
+		if err == nil {
+			err = fmt.Errorf("Responded with status %d", status)
+		}
+		requestLog.Error(err)
+		return nil
+	}
+}
+
+// loggerFromLocals returns the logger set by WithRequestLogger, which discards everything if the
+// request is not being logged
+func loggerFromLocals(ctx *fiber.Ctx) *logger.Logger {
+	if log, ok := ctx.Locals(loggerKey).(*logger.Logger); ok {
+		return log
+	}
+	return logger.NewDiscard()
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/expfmt"
+	"github.com/sasalatart/batcoms/pkg/metrics"
+)
+
+// WithMetrics middleware counts every request and observes how long it took, labelled by the route
+// that handled it rather than by its path, so that the number of series stays bounded. Requests
+// that did not match any route are labelled as "unmatched"
+func WithMetrics() func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		start := time.Now()
+		own := ctx.Route()
+		handleError(ctx, ctx.Next())
+
+		// The matched route is left as the current one, unless no route other than the middleware
+		// ones matched
+		route := ctx.Route().Path
+		if ctx.Route() == own {
+			route = "unmatched"
+		}
+		method := ctx.Method()
+		status := strconv.Itoa(ctx.Response().StatusCode())
+		metrics.HTTPRequests.WithLabelValues(method, route, status).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(method, route).Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// ServeMetrics renders the metrics gathered by g in the Prometheus text format
+func ServeMetrics(g prometheus.Gatherer) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		families, err := g.Gather()
+		if err != nil {
+			return err
+		}
+		ctx.Set(fiber.HeaderContentType, string(expfmt.FmtText))
+		encoder := expfmt.NewEncoder(ctx, expfmt.FmtText)
+		for _, f := range families {
+			if err := encoder.Encode(f); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/factions"
 	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/linkeddata"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -26,10 +27,28 @@ func WithPage() func(*fiber.Ctx) error {
 	}
 }
 
-// JSONFrom middleware renders a JSON response from the provided ctx.Locals key
+// JSONFrom middleware renders a JSON response from the provided ctx.Locals key. When present, the
+// fields stored by WithFields restrict each entity rendered to them. When JSON-LD is accepted
+// instead of JSON, entities are described with schema.org and the custom vocabulary of
+// pkg/linkeddata
 func JSONFrom(key string) func(ctx *fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
-		return ctx.JSON(ctx.Locals(key))
+		if acceptedType(ctx, linkeddata.MIMEType) != "" {
+			if err := ctx.JSON(linkedDataFrom(ctx, ctx.Locals(key))); err != nil {
+				return err
+			}
+			ctx.Set(fiber.HeaderContentType, linkeddata.MIMEType)
+			return nil
+		}
+		fields := fieldsFromLocals(ctx)
+		if fields == nil {
+			return ctx.JSON(ctx.Locals(key))
+		}
+		selected, err := selectFields(ctx.Locals(key), fields)
+		if err != nil {
+			return err
+		}
+		return ctx.JSON(selected)
 	}
 }
 
@@ -41,7 +60,7 @@ func WithFaction(r factions.Reader) func(*fiber.Ctx) error {
 		if err != nil {
 			return newErrBadRequest("Invalid FactionID")
 		}
-		faction, err := r.FindOne(factions.FindOneQuery{ID: id})
+		faction, err := r.FindOne(traceContext(ctx), factions.FindOneQuery{ID: id})
 		if err != nil {
 			return handleFindOneError(err, "Faction")
 		}
@@ -53,7 +72,8 @@ func WithFaction(r factions.Reader) func(*fiber.Ctx) error {
 // WithFactions middleware finds factions according to the optional :commanderID URL parameter and
 // the optional "page" query parameter (falling back to 1), and sets them into ctx.Locals under the
 // key "factions". When present, it will also use the "name" and "summary" query parameters to
-// refine this search
+// refine this search. When CSV or NDJSON is accepted instead of JSON, all of the factions found are
+// streamed in that format without going through the next handlers
 func WithFactions(r factions.Reader) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		query := factions.FindManyQuery{
@@ -61,7 +81,13 @@ func WithFactions(r factions.Reader) func(*fiber.Ctx) error {
 			Summary:     ctx.Query("summary"),
 			CommanderID: commanderIDFromLocals(ctx),
 		}
-		factions, pages, err := r.FindMany(query, pageFromLocals(ctx))
+		if exportType := exportTypeFromHeader(ctx); exportType != "" {
+			spanCtx := traceContext(ctx)
+			return streamExport(ctx, exportType, factions.Faction{}, func(write func(interface{}) error) error {
+				return r.Stream(spanCtx, query, func(f factions.Faction) error { return write(f) })
+			})
+		}
+		factions, pages, err := r.FindMany(traceContext(ctx), query, pageFromLocals(ctx))
 		if err != nil {
 			return err
 		}
@@ -79,7 +105,7 @@ func WithCommander(r commanders.Reader) func(*fiber.Ctx) error {
 		if err != nil {
 			return newErrBadRequest("Invalid CommanderID")
 		}
-		commander, err := r.FindOne(commanders.FindOneQuery{ID: id})
+		commander, err := r.FindOne(traceContext(ctx), commanders.FindOneQuery{ID: id})
 		if err != nil {
 			return handleFindOneError(err, "Commander")
 		}
@@ -91,15 +117,28 @@ func WithCommander(r commanders.Reader) func(*fiber.Ctx) error {
 // WithCommanders middleware finds commanders according to the optional :factionID URL parameter and
 // the optional "page" query parameter (falling back to 1), and sets them into ctx.Locals under the
 // key "commanders". When present, it will also use the "name" and "summary" query parameters to
-// refine this search
+// refine this search, and the "includeRelated" one to extend it to the factions related to the
+// given one. When CSV or NDJSON is accepted instead of JSON, all of the commanders found are
+// streamed in that format without going through the next handlers
 func WithCommanders(r commanders.Reader) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
+		includeRelated, err := includeRelatedFromQuery(ctx)
+		if err != nil {
+			return err
+		}
 		query := commanders.FindManyQuery{
-			Name:      ctx.Query("name"),
-			Summary:   ctx.Query("summary"),
-			FactionID: factionIDFromLocals(ctx),
+			Name:           ctx.Query("name"),
+			Summary:        ctx.Query("summary"),
+			FactionID:      factionIDFromLocals(ctx),
+			IncludeRelated: includeRelated,
 		}
-		commanders, pages, err := r.FindMany(query, pageFromLocals(ctx))
+		if exportType := exportTypeFromHeader(ctx); exportType != "" {
+			spanCtx := traceContext(ctx)
+			return streamExport(ctx, exportType, commanders.Commander{}, func(write func(interface{}) error) error {
+				return r.Stream(spanCtx, query, func(c commanders.Commander) error { return write(c) })
+			})
+		}
+		commanders, pages, err := r.FindMany(traceContext(ctx), query, pageFromLocals(ctx))
 		if err != nil {
 			return err
 		}
@@ -112,54 +151,94 @@ func WithCommanders(r commanders.Reader) func(*fiber.Ctx) error {
 // WithBattles middleware finds battles according to the optional :factionID or :commanderID URL
 // parameters and the optional "page" query parameter (falling back to 1), and sets them into
 // ctx.Locals under the key "battles". When present, it will also use the "name", "summary", "place"
-// and "result" query parameters to refine this search
+// and "result" query parameters to refine this search, and the "includeRelated" one to extend it to
+// the factions related to the given one. When CSV or NDJSON is accepted instead of JSON, all of the
+// battles found are streamed in that format without going through the next handlers
 func WithBattles(r battles.Reader) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
+		includeRelated, err := includeRelatedFromQuery(ctx)
+		if err != nil {
+			return err
+		}
 		var fromDate dates.Historic
 		if ctx.Query("fromDate") != "" {
-			date, err := dates.New(ctx.Query("fromDate"))
+			date, err := parseDateQuery(ctx, "fromDate", true)
 			if err != nil {
-				return newErrBadRequest("Invalid fromDate, must be in YYYY-MM-DD format")
+				return err
 			}
-			fromDate = date.ToBeginning()
+			fromDate = date
 		}
 		var toDate dates.Historic
 		if ctx.Query("toDate") != "" {
-			date, err := dates.New(ctx.Query("toDate"))
+			date, err := parseDateQuery(ctx, "toDate", false)
 			if err != nil {
-				return newErrBadRequest("Invalid toDate, must be in YYYY-MM-DD format")
+				return err
 			}
-			toDate = date.ToEnd()
+			toDate = date
 		}
 		query := battles.FindManyQuery{
-			Name:        ctx.Query("name"),
-			Summary:     ctx.Query("summary"),
-			Place:       ctx.Query("place"),
-			Result:      ctx.Query("result"),
-			FromDate:    fromDate,
-			ToDate:      toDate,
-			FactionID:   factionIDFromLocals(ctx),
-			CommanderID: commanderIDFromLocals(ctx),
+			Name:           ctx.Query("name"),
+			Summary:        ctx.Query("summary"),
+			Place:          ctx.Query("place"),
+			Result:         ctx.Query("result"),
+			FromDate:       fromDate,
+			ToDate:         toDate,
+			FactionID:      factionIDFromLocals(ctx),
+			IncludeRelated: includeRelated,
+			CommanderID:    commanderIDFromLocals(ctx),
 		}
-		battles, pages, err := r.FindMany(query, pageFromLocals(ctx))
-		if err != nil {
-			return err
+		return setBattles(ctx, r, query)
+	}
+}
+
+// WithConcurrentBattles middleware finds the other battles whose dates overlap with those of the
+// battle stored in ctx.Locals under the key "battle", according to the optional "page" query
+// parameter (falling back to 1), and sets them into ctx.Locals under the key "battles". When
+// present, it will also use the "withinKm" query parameter to only include those that were fought
+// at most that many kilometres away
+func WithConcurrentBattles(r battles.Reader) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		var withinKm float64
+		if ctx.Query("withinKm") != "" {
+			km, err := strconv.ParseFloat(ctx.Query("withinKm"), 64)
+			if err != nil || km <= 0 {
+				return newErrBadRequest("Invalid withinKm, must be a positive number")
+			}
+			withinKm = km
 		}
-		ctx.Set("x-pages", fmt.Sprint(pages))
-		ctx.Locals("battles", battles)
-		return ctx.Next()
+		query := battles.FindManyQuery{
+			ConcurrentWith: battleIDFromLocals(ctx),
+			WithinKm:       withinKm,
+		}
+		return setBattles(ctx, r, query)
+	}
+}
+
+// WithRelatedBattles middleware finds the other battles that share factions, commanders or the
+// conflict they were part of with the battle stored in ctx.Locals under the key "battle", according
+// to the optional "page" query parameter (falling back to 1), and sets them into ctx.Locals under
+// the key "battles"
+func WithRelatedBattles(r battles.Reader) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		query := battles.FindManyQuery{RelatedTo: battleIDFromLocals(ctx)}
+		return setBattles(ctx, r, query)
 	}
 }
 
 // WithBattle middleware sets the battle corresponding to the :battleID URL parameter into
-// ctx.Locals under the key "battle"
+// ctx.Locals under the key "battle", without loading the relations that the optional "expand" and
+// "fields" query parameters leave out
 func WithBattle(r battles.Reader) func(*fiber.Ctx) error {
 	return func(ctx *fiber.Ctx) error {
 		id, err := uuid.FromString(ctx.Params("battleID"))
 		if err != nil {
 			return newErrBadRequest("Invalid BattleID")
 		}
-		battle, err := r.FindOne(battles.FindOneQuery{ID: id})
+		omit, err := omitFromQuery(ctx)
+		if err != nil {
+			return err
+		}
+		battle, err := r.FindOne(traceContext(ctx), battles.FindOneQuery{ID: id, Omit: omit})
 		if err != nil {
 			return handleFindOneError(err, "Battle")
 		}
@@ -168,6 +247,38 @@ func WithBattle(r battles.Reader) func(*fiber.Ctx) error {
 	}
 }
 
+func setBattles(ctx *fiber.Ctx, r battles.Reader, query battles.FindManyQuery) error {
+	omit, err := omitFromQuery(ctx)
+	if err != nil {
+		return err
+	}
+	query.Omit = omit
+	if exportType := exportTypeFromHeader(ctx); exportType != "" {
+		return streamBattles(ctx, r, query, exportType)
+	}
+	battles, pages, err := r.FindMany(traceContext(ctx), query, pageFromLocals(ctx))
+	if err != nil {
+		return err
+	}
+	ctx.Set("x-pages", fmt.Sprint(pages))
+	ctx.Locals("battles", battles)
+	return ctx.Next()
+}
+
+// includeRelatedFromQuery parses the optional "includeRelated" query parameter, which defaults to
+// false
+func includeRelatedFromQuery(ctx *fiber.Ctx) (bool, error) {
+	value := ctx.Query("includeRelated")
+	if value == "" {
+		return false, nil
+	}
+	includeRelated, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, newErrBadRequest("Invalid includeRelated, must be true or false")
+	}
+	return includeRelated, nil
+}
+
 func pageFromLocals(ctx *fiber.Ctx) int {
 	page := 1
 	if queryPage, hasPage := ctx.Locals("page").(int); hasPage {
@@ -190,6 +301,13 @@ func commanderIDFromLocals(ctx *fiber.Ctx) uuid.UUID {
 	return uuid.Nil
 }
 
+func battleIDFromLocals(ctx *fiber.Ctx) uuid.UUID {
+	if battle, hasBattle := ctx.Locals("battle").(battles.Battle); hasBattle {
+		return battle.ID
+	}
+	return uuid.Nil
+}
+
 func handleFindOneError(err error, resourceName string) error {
 	if err != domain.ErrNotFound {
 		return err
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	"github.com/sasalatart/batcoms/pkg/ratelimit"
+)
+
+// APIKeyHeader is the request header through which clients send their API keys
+const APIKeyHeader = "X-API-Key"
+
+// Headers through which the state of the bucket of each client is sent
+const (
+	RateLimitLimitHeader     = "X-RateLimit-Limit"
+	RateLimitRemainingHeader = "X-RateLimit-Remaining"
+	RateLimitResetHeader     = "X-RateLimit-Reset"
+)
+
+// RateLimits are the limits enforced by WithRateLimit. Anonymous limits the requests of clients
+// without an API key, per IP. PerIP limits all of the requests made from each IP, whether with an
+// API key or not, so that many keys may not be used from a single IP to get around their limits
+type RateLimits struct {
+	Anonymous ratelimit.Limit
+	PerIP     ratelimit.Limit
+}
+
+// WithRateLimit middleware limits how many requests each client may make with token buckets.
+// Clients sending a key under the X-API-Key header are limited by the limits of their key, and all
+// others by the anonymous ones, per IP. Requests from the same IP are also limited together by the
+// per-IP limits, regardless of their keys. Keys are looked up through r and cached for cacheTTL, so
+// revoking one takes up to that long to take effect. Unknown and revoked keys are rejected with a
+// 401 status, and count towards the anonymous limits of their IP so that keys may not be guessed at
+// will. The state of the bucket of the client (or of its IP, when that one is the one exceeded) is
+// sent under the X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (in seconds)
+// headers, and requests over any limit are rejected with a 429 status and a Retry-After header
+func WithRateLimit(r apikeys.Reader, limits RateLimits, cacheTTL time.Duration) func(*fiber.Ctx) error {
+	limiter := ratelimit.New()
+	keys := &keyCache{ttl: cacheTTL, byHash: make(map[string]cachedKey)}
+	return func(ctx *fiber.Ctx) error {
+		now := time.Now()
+		bucket, limit := "anonymous:"+ctx.IP(), limits.Anonymous
+		var keyErr error
+		if plain := ctx.Get(APIKeyHeader); plain != "" {
+			key, err := keys.find(traceContext(ctx), r, apikeys.Hash(plain), now)
+			switch {
+			case err == nil && !key.Revoked():
+				bucket = "key:" + key.ID.String()
+				limit = ratelimit.Limit{PerMinute: key.RequestsPerMinute, Burst: key.Burst}
+			case err == nil || err == domain.ErrNotFound:
+				keyErr = newErrUnauthorized("Invalid API key")
+			default:
+				return err
+			}
+		}
+
+		result := limiter.Take("ip:"+ctx.IP(), limits.PerIP, now)
+		if result.Allowed {
+			result = limiter.Take(bucket, limit, now)
+		}
+		ctx.Set(RateLimitLimitHeader, strconv.Itoa(result.Limit))
+		ctx.Set(RateLimitRemainingHeader, strconv.Itoa(result.Remaining))
+		ctx.Set(RateLimitResetHeader, seconds(result.Reset))
+		if !result.Allowed {
+			ctx.Set(fiber.HeaderRetryAfter, seconds(result.RetryAfter))
+			return newErrTooManyRequests()
+		}
+		if keyErr != nil {
+			return keyErr
+		}
+		return ctx.Next()
+	}
+}
+
+func seconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+type cachedKey struct {
+	key     apikeys.APIKey
+	expires time.Time
+}
+
+// keyCache keeps the API keys that were found by their hash, so that the database is not queried on
+// every request. Keys that were not found are not kept, as there is no bound to how many of them
+// there may be
+type keyCache struct {
+	mu     sync.Mutex
+	ttl    time.Duration
+	byHash map[string]cachedKey
+}
+
+func (c *keyCache) find(ctx context.Context, r apikeys.Reader, hash string, now time.Time) (apikeys.APIKey, error) {
+	c.mu.Lock()
+	cached, ok := c.byHash[hash]
+	c.mu.Unlock()
+	if ok && now.Before(cached.expires) {
+		return cached.key, nil
+	}
+	if r == nil {
+		return apikeys.APIKey{}, domain.ErrNotFound
+	}
+
+	key, err := r.FindOne(ctx, apikeys.FindOneQuery{Hash: hash})
+	if err != nil {
+		return apikeys.APIKey{}, err
+	}
+	c.mu.Lock()
+	c.byHash[hash] = cachedKey{key: key, expires: now.Add(c.ttl)}
+	c.mu.Unlock()
+	return key, nil
+}
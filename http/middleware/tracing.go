@@ -0,0 +1,147 @@
+package middleware
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/pkg/tracing"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceIDHeader is the response header through which the ID of the trace of each request is sent
+const TraceIDHeader = "X-Trace-Id"
+
+const traceContextKey = "traceContext"
+
+// WithTracing middleware starts a span for every request, continuing the trace given through the
+// "traceparent" header when present, and sends its ID under the X-Trace-Id header. The span is
+// named after the route that handled the request, and its context is set into ctx.Locals so that
+// the spans of the next handlers and of the queries they make are nested within it. It must be
+// registered before any other middleware for all of them to be traced
+func WithTracing() func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		carrier := headerCarrier{ctx}
+		parent := otel.GetTextMapPropagator().Extract(context.Background(), carrier)
+		spanCtx, span := tracing.Tracer().Start(parent, ctx.Method(), trace.WithSpanKind(trace.SpanKindServer))
+		defer span.End()
+
+		ctx.Locals(traceContextKey, spanCtx)
+		if sc := span.SpanContext(); sc.HasTraceID() {
+			ctx.Set(TraceIDHeader, sc.TraceID().String())
+		}
+		own := ctx.Route()
+		handleError(ctx, ctx.Next())
+
+		route := ctx.Route().Path
+		if ctx.Route() == own {
+			route = "unmatched"
+		}
+		status := ctx.Response().StatusCode()
+		span.SetName(ctx.Method() + " " + route)
+		span.SetAttributes(
+			semconv.HTTPMethodKey.String(ctx.Method()),
+			semconv.HTTPTargetKey.String(ctx.OriginalURL()),
+			semconv.HTTPRouteKey.String(route),
+			semconv.HTTPStatusCodeKey.Int(status),
+		)
+		if status >= fiber.StatusInternalServerError {
+			span.SetStatus(codes.Error, fiber.ErrInternalServerError.Message)
+		}
+		return nil
+	}
+}
+
+// Traced wraps each one of the given handlers so that it runs within a span of its own, named after
+// the function that built it (e.g. "WithPage" or "JSONFrom"). As handlers call the next ones, each
+// span is the parent of those that follow it in the chain
+func Traced(handlers ...fiber.Handler) []fiber.Handler {
+	traced := make([]fiber.Handler, len(handlers))
+	for i, h := range handlers {
+		traced[i] = withSpan(handlerName(h), h)
+	}
+	return traced
+}
+
+func withSpan(name string, h fiber.Handler) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		parent := traceContext(ctx)
+		spanCtx, span := tracing.Tracer().Start(parent, name)
+		defer span.End()
+
+		ctx.Locals(traceContextKey, spanCtx)
+		err := h(ctx)
+		ctx.Locals(traceContextKey, parent)
+		if err != nil {
+			span.RecordError(err)
+			if code := errorCode(err); code >= fiber.StatusInternalServerError {
+				span.SetStatus(codes.Error, err.Error())
+			}
+		}
+		return err
+	}
+}
+
+// traceContext returns the context of the innermost span started for the request, falling back to
+// an empty context if it is not being traced
+func traceContext(ctx *fiber.Ctx) context.Context {
+	if spanCtx, ok := ctx.Locals(traceContextKey).(context.Context); ok {
+		return spanCtx
+	}
+	return context.Background()
+}
+
+// traceIDFromLocals returns the ID of the trace of the request, or an empty string if it has none
+func traceIDFromLocals(ctx *fiber.Ctx) string {
+	sc := trace.SpanContextFromContext(traceContext(ctx))
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// handlerName turns the name of the closure h, such as
+// "github.com/sasalatart/batcoms/http/middleware.WithPage.func1", into that of the function that
+// returned it, "WithPage"
+func handlerName(h fiber.Handler) string {
+	name := runtime.FuncForPC(reflect.ValueOf(h).Pointer()).Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+	parts := strings.Split(name, ".")
+	if len(parts) < 2 {
+		return name
+	}
+	return parts[1]
+}
+
+func errorCode(err error) int {
+	if e, ok := err.(*fiber.Error); ok {
+		return e.Code
+	}
+	return fiber.StatusInternalServerError
+}
+
+// headerCarrier adapts the headers of a request so that a trace context may be extracted from them
+type headerCarrier struct {
+	ctx *fiber.Ctx
+}
+
+func (c headerCarrier) Get(key string) string {
+	return c.ctx.Get(key)
+}
+
+func (c headerCarrier) Set(key, value string) {
+	c.ctx.Request().Header.Set(key, value)
+}
+
+func (c headerCarrier) Keys() []string {
+	var keys []string
+	c.ctx.Request().Header.VisitAll(func(key, _ []byte) {
+		keys = append(keys, string(key))
+	})
+	return keys
+}
@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
+	uuid "github.com/satori/go.uuid"
+)
+
+type translationsByID map[uuid.UUID]translations.Translation
+
+// WithTranslations middleware negotiates the language in which content is served, from the optional
+// "lang" query parameter or, when absent, the Accept-Language header. The names and summaries of the
+// faction, commander or battle (or slices of them) stored into ctx.Locals under the given key are
+// then replaced by their translations into that language. Those without a translation keep their
+// names and summaries in English, so the Content-Language header is only set to the negotiated
+// language when at least one translation was found, and to English otherwise
+func WithTranslations(r translations.Reader, key string) func(*fiber.Ctx) error {
+	return func(ctx *fiber.Ctx) error {
+		language := translations.DefaultLanguage
+		if ctx.Query("lang") != "" {
+			language = ctx.Query("lang")
+			if !translations.IsSupported(language) {
+				return newErrBadRequest("Invalid lang, must be one of " + strings.Join(translations.Languages, ", "))
+			}
+		} else if accepted := ctx.AcceptsLanguages(translations.Languages...); accepted != "" {
+			language = accepted
+		}
+		ctx.Vary(fiber.HeaderAcceptLanguage)
+		ctx.Set(fiber.HeaderContentLanguage, translations.DefaultLanguage)
+		if language == translations.DefaultLanguage {
+			return ctx.Next()
+		}
+
+		value := ctx.Locals(key)
+		ids := entityIDs(value)
+		if len(ids) == 0 {
+			return ctx.Next()
+		}
+		found, err := r.FindMany(traceContext(ctx), translations.FindManyQuery{Language: language, EntityIDs: ids})
+		if err != nil {
+			return err
+		}
+		byID := make(translationsByID)
+		for _, t := range found {
+			byID[t.EntityID] = t
+		}
+		if len(byID) > 0 {
+			ctx.Set(fiber.HeaderContentLanguage, language)
+			ctx.Locals(key, byID.apply(value))
+		}
+		return ctx.Next()
+	}
+}
+
+// entityIDs returns the IDs of all the factions, commanders and battles that may be translated in
+// the given value, without repetitions
+func entityIDs(value interface{}) []uuid.UUID {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	add := func(id uuid.UUID) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	addBattle := func(b battles.Battle) {
+		add(b.ID)
+		for _, side := range [][]factions.Faction{b.Factions.A, b.Factions.B} {
+			for _, f := range side {
+				add(f.ID)
+			}
+		}
+		for _, side := range [][]commanders.Commander{b.Commanders.A, b.Commanders.B} {
+			for _, c := range side {
+				add(c.ID)
+			}
+		}
+	}
+
+	switch v := value.(type) {
+	case factions.Faction:
+		add(v.ID)
+	case []factions.Faction:
+		for _, f := range v {
+			add(f.ID)
+		}
+	case commanders.Commander:
+		add(v.ID)
+	case []commanders.Commander:
+		for _, c := range v {
+			add(c.ID)
+		}
+	case battles.Battle:
+		addBattle(v)
+	case []battles.Battle:
+		for _, b := range v {
+			addBattle(b)
+		}
+	}
+	return ids
+}
+
+// apply returns a copy of the given value, where the names and summaries of all the factions,
+// commanders and battles with a translation have been replaced
+func (byID translationsByID) apply(value interface{}) interface{} {
+	switch v := value.(type) {
+	case factions.Faction:
+		return byID.faction(v)
+	case []factions.Faction:
+		return byID.factions(v)
+	case commanders.Commander:
+		return byID.commander(v)
+	case []commanders.Commander:
+		return byID.commanders(v)
+	case battles.Battle:
+		return byID.battle(v)
+	case []battles.Battle:
+		res := make([]battles.Battle, 0, len(v))
+		for _, b := range v {
+			res = append(res, byID.battle(b))
+		}
+		return res
+	}
+	return value
+}
+
+func (byID translationsByID) faction(f factions.Faction) factions.Faction {
+	if t, ok := byID[f.ID]; ok {
+		f.Name, f.Summary = t.Name, translatedSummary(t, f.Summary)
+	}
+	return f
+}
+
+func (byID translationsByID) factions(ff []factions.Faction) []factions.Faction {
+	res := make([]factions.Faction, 0, len(ff))
+	for _, f := range ff {
+		res = append(res, byID.faction(f))
+	}
+	return res
+}
+
+func (byID translationsByID) commander(c commanders.Commander) commanders.Commander {
+	if t, ok := byID[c.ID]; ok {
+		c.Name, c.Summary = t.Name, translatedSummary(t, c.Summary)
+	}
+	return c
+}
+
+func (byID translationsByID) commanders(cc []commanders.Commander) []commanders.Commander {
+	res := make([]commanders.Commander, 0, len(cc))
+	for _, c := range cc {
+		res = append(res, byID.commander(c))
+	}
+	return res
+}
+
+func (byID translationsByID) battle(b battles.Battle) battles.Battle {
+	if t, ok := byID[b.ID]; ok {
+		b.Name, b.Summary = t.Name, translatedSummary(t, b.Summary)
+	}
+	b.Factions.A = byID.factions(b.Factions.A)
+	b.Factions.B = byID.factions(b.Factions.B)
+	b.Commanders.A = byID.commanders(b.Commanders.A)
+	b.Commanders.B = byID.commanders(b.Commanders.B)
+	return b
+}
+
+// translatedSummary returns the summary of a translation, or the English one if it has none
+func translatedSummary(t translations.Translation, english string) string {
+	if t.Summary == "" {
+		return english
+	}
+	return t.Summary
+}
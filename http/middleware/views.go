@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sasalatart/batcoms/domain/battles"
+)
+
+// battleView is an alternate JSON representation of battles.Battle, which lets middleware such as
+// WithDateFormat and WithExpand change the shape of some of its fields. Its fields shadow those of
+// the embedded battle with the same JSON names, so they must always be set from it
+type battleView struct {
+	battles.Battle
+	StartDate            interface{}    `json:"startDate"`
+	EndDate              interface{}    `json:"endDate"`
+	Factions             interface{}    `json:"factions,omitempty"`
+	Commanders           interface{}    `json:"commanders,omitempty"`
+	CommandersByFaction  interface{}    `json:"commandersByFaction,omitempty"`
+	CommanderConfidences interface{}    `json:"commanderConfidences,omitempty"`
+	Sides                *battles.Sides `json:"sides,omitempty"`
+}
+
+func newBattleView(b battles.Battle) battleView {
+	v := battleView{
+		Battle:              b,
+		StartDate:           b.StartDate,
+		EndDate:             b.EndDate,
+		Factions:            b.Factions,
+		Commanders:          b.Commanders,
+		CommandersByFaction: b.CommandersByFaction,
+	}
+	if len(b.CommanderConfidences) > 0 {
+		v.CommanderConfidences = b.CommanderConfidences
+	}
+	return v
+}
+
+// mapBattleViews replaces the battle or battles stored into ctx.Locals under the given key by
+// their views, transformed with the given function. Battles that are already views are transformed
+// as they are, so that several middleware may change them
+func mapBattleViews(ctx *fiber.Ctx, key string, transform func(battleView) battleView) {
+	switch value := ctx.Locals(key).(type) {
+	case battles.Battle:
+		ctx.Locals(key, transform(newBattleView(value)))
+	case battleView:
+		ctx.Locals(key, transform(value))
+	case []battles.Battle:
+		views := make([]battleView, 0, len(value))
+		for _, b := range value {
+			views = append(views, transform(newBattleView(b)))
+		}
+		ctx.Locals(key, views)
+	case []battleView:
+		views := make([]battleView, 0, len(value))
+		for _, v := range value {
+			views = append(views, transform(v))
+		}
+		ctx.Locals(key, views)
+	}
+}
@@ -348,7 +348,97 @@ func TestBattlesEndpoints(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("GET /battles/:battleID/concurrent", func(t *testing.T) {
+		t.Parallel()
+
+		route := func(battleID string) string {
+			return URL(fmt.Sprintf("/battles/%s/concurrent", battleID))
+		}
+
+		const expectedPages = 1
+		lodiURL := route(BattleOfLodi(t).ID.String())
+		cases := []battlesEndpointCase{
+			{
+				description:     "With no filters",
+				url:             lodiURL,
+				expectedBattles: []battles.Battle{},
+			},
+			{
+				description:     "With withinKm filter",
+				url:             lodiURL + "?withinKm=500",
+				expectedBattles: []battles.Battle{},
+			},
+			{
+				description:          "With invalid withinKm",
+				url:                  lodiURL + "?withinKm=-1",
+				expectedErrorCode:    http.StatusBadRequest,
+				expectedErrorMessage: "Invalid withinKm, must be a positive number",
+			},
+			{
+				description:          "With valid, non-persisted BattleID",
+				url:                  route(uuid.NewV4().String()),
+				expectedErrorCode:    http.StatusNotFound,
+				expectedErrorMessage: "Battle not found",
+			},
+			{
+				description:          "With invalid BattleID",
+				url:                  route("invalid-id"),
+				expectedErrorCode:    http.StatusBadRequest,
+				expectedErrorMessage: "Invalid BattleID",
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				assertBattlesEndpointCase(t, c, expectedPages)
+			})
+		}
+	})
+
+	t.Run("GET /battles/:battleID/related", func(t *testing.T) {
+		t.Parallel()
+
+		route := func(battleID string) string {
+			return URL(fmt.Sprintf("/battles/%s/related", battleID))
+		}
+
+		const expectedPages = 1
+		cases := []battlesEndpointCase{
+			{
+				description:     "Sharing commanders",
+				url:             route(BattleOfAusterlitz(t).ID.String()),
+				expectedBattles: []battles.Battle{BattleOfLodi(t), BattleOfArcole(t)},
+			},
+			{
+				description:     "Sharing factions and commanders",
+				url:             route(BattleOfLodi(t).ID.String()),
+				expectedBattles: []battles.Battle{BattleOfArcole(t), BattleOfAusterlitz(t)},
+			},
+			{
+				description:     "With no related battles",
+				url:             route(BattleOfMegiddo(t).ID.String()),
+				expectedBattles: []battles.Battle{},
+			},
+			{
+				description:          "With valid, non-persisted BattleID",
+				url:                  route(uuid.NewV4().String()),
+				expectedErrorCode:    http.StatusNotFound,
+				expectedErrorMessage: "Battle not found",
+			},
+			{
+				description:          "With invalid BattleID",
+				url:                  route("invalid-id"),
+				expectedErrorCode:    http.StatusBadRequest,
+				expectedErrorMessage: "Invalid BattleID",
+			},
+		}
+		for _, c := range cases {
+			t.Run(c.description, func(t *testing.T) {
+				assertBattlesEndpointCase(t, c, expectedPages)
+			})
+		}
+	})
 }
 
-const invalidFromDateMessage = "Invalid fromDate, must be in YYYY-MM-DD format"
-const invalidToDateMessage = "Invalid toDate, must be in YYYY-MM-DD format"
+const invalidFromDateMessage = "Invalid fromDate, must be in YYYY-MM-DD, ISO 8601 or EDTF format"
+const invalidToDateMessage = "Invalid toDate, must be in YYYY-MM-DD, ISO 8601 or EDTF format"
@@ -1,36 +1,37 @@
 package integration_test
 
 import (
-	"database/sql"
+	"context"
 	"log"
 	"os"
 	"testing"
 
 	"github.com/sasalatart/batcoms/config"
-	"github.com/sasalatart/batcoms/db/postgresql"
+	"github.com/sasalatart/batcoms/db/backend"
 	"github.com/sasalatart/batcoms/db/seeder"
 	"github.com/sasalatart/batcoms/domain/battles"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/translations"
 	"github.com/sasalatart/batcoms/pkg/io/json"
 	"github.com/sasalatart/batcoms/pkg/logger"
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/require"
-	"gorm.io/gorm"
 )
 
-var db *gorm.DB
-var sqlDB *sql.DB
-var battlesRepo *postgresql.BattlesRepository
-var factionsRepo *postgresql.FactionsRepository
-var commandersRepo *postgresql.CommandersRepository
+var db backend.Backend
+var battlesRepo battles.Repository
+var factionsRepo factions.Repository
+var commandersRepo commanders.Repository
+var translationsRepo translations.Repository
 
 func init() {
 	config.Setup()
-	db, sqlDB = postgresql.Connect(postgresql.DefaultTestConfig())
-	battlesRepo = postgresql.NewBattlesRepository(db)
-	factionsRepo = postgresql.NewFactionsRepository(db)
-	commandersRepo = postgresql.NewCommandersRepository(db)
+	db = backend.Connect(true)
+	battlesRepo = db.Battles
+	factionsRepo = db.Factions
+	commandersRepo = db.Commanders
+	translationsRepo = db.Translations
 }
 
 func TestMain(m *testing.M) {
@@ -40,11 +41,11 @@ func TestMain(m *testing.M) {
 		log.Fatalf("Error importing data: %s\n", err)
 	}
 
-	postgresql.Reset(db)
-	seeder.Seed(importedData, factionsRepo, commandersRepo, battlesRepo, logger.NewDiscard())
+	db.Reset()
+	seeder.Seed(importedData, factionsRepo, commandersRepo, battlesRepo, translationsRepo, logger.NewDiscard())
 
 	code := m.Run()
-	sqlDB.Close()
+	db.Close()
 	os.Exit(code)
 }
 
@@ -159,21 +160,21 @@ func requireNoError(t *testing.T, err error, name string) {
 
 func requireFaction(t *testing.T, factionName string) factions.Faction {
 	t.Helper()
-	faction, err := factionsRepo.FindOne(factions.FindOneQuery{Name: factionName})
+	faction, err := factionsRepo.FindOne(context.Background(), factions.FindOneQuery{Name: factionName})
 	requireNoError(t, err, factionName)
 	return faction
 }
 
 func requireCommander(t *testing.T, commanderName string) commanders.Commander {
 	t.Helper()
-	commander, err := commandersRepo.FindOne(commanders.FindOneQuery{Name: commanderName})
+	commander, err := commandersRepo.FindOne(context.Background(), commanders.FindOneQuery{Name: commanderName})
 	requireNoError(t, err, commanderName)
 	return commander
 }
 
 func requireBattle(t *testing.T, battleName string) battles.Battle {
 	t.Helper()
-	battle, err := battlesRepo.FindOne(battles.FindOneQuery{Name: battleName})
+	battle, err := battlesRepo.FindOne(context.Background(), battles.FindOneQuery{Name: battleName})
 	requireNoError(t, err, battleName)
 	return battle
 }
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/sasalatart/batcoms/domain/apikeys"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// APIKeysRepository mocks repositories used to read and write API keys
+type APIKeysRepository struct {
+	mock.Mock
+}
+
+// FindOne mocks finding one API key via APIKeysRepository
+func (r *APIKeysRepository) FindOne(ctx context.Context, query apikeys.FindOneQuery) (apikeys.APIKey, error) {
+	mockArgs := r.Called(ctx, query)
+	return mockArgs.Get(0).(apikeys.APIKey), mockArgs.Error(1)
+}
+
+// FindMany mocks finding all API keys via APIKeysRepository
+func (r *APIKeysRepository) FindMany(ctx context.Context) ([]apikeys.APIKey, error) {
+	mockArgs := r.Called(ctx)
+	return mockArgs.Get(0).([]apikeys.APIKey), mockArgs.Error(1)
+}
+
+// CreateOne mocks creating one API key via APIKeysRepository
+func (r *APIKeysRepository) CreateOne(data apikeys.CreationInput) (uuid.UUID, error) {
+	mockArgs := r.Called(data)
+	return mockArgs.Get(0).(uuid.UUID), mockArgs.Error(1)
+}
+
+// Revoke mocks revoking one API key via APIKeysRepository
+func (r *APIKeysRepository) Revoke(id uuid.UUID) error {
+	mockArgs := r.Called(id)
+	return mockArgs.Error(0)
+}
+
+// APIKey returns an instance of apikeys.APIKey that may be used for mocking purposes. It is the one
+// stored for the key returned by PlainAPIKey
+func APIKey() apikeys.APIKey {
+	return apikeys.APIKey{
+		ID:                uuid.FromStringOrNil("4d1fe0a4-7c5f-4b1a-9d59-2d9f3c1bbf7e"),
+		Name:              "Mobile client",
+		RequestsPerMinute: 120,
+		Burst:             3,
+		CreatedAt:         time.Date(2020, time.October, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+// PlainAPIKey returns the key handed to the client of the API key returned by APIKey
+func PlainAPIKey() string {
+	return "bck_mobile-client-key"
+}
+
+// APIKeyCreationInput returns an instance of apikeys.CreationInput that may be used for mocking
+// inputs to create API keys
+func APIKeyCreationInput(k apikeys.APIKey) apikeys.CreationInput {
+	return apikeys.CreationInput{
+		Name:              k.Name,
+		Hash:              apikeys.Hash(PlainAPIKey()),
+		RequestsPerMinute: k.RequestsPerMinute,
+		Burst:             k.Burst,
+	}
+}
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"log"
 
 	"github.com/sasalatart/batcoms/domain/battles"
@@ -21,17 +22,34 @@ type BattlesRepository struct {
 }
 
 // FindOne mocks finding one battle via BattlesRepository
-func (r *BattlesRepository) FindOne(query battles.FindOneQuery) (battles.Battle, error) {
-	mockArgs := r.Called(query)
+func (r *BattlesRepository) FindOne(ctx context.Context, query battles.FindOneQuery) (battles.Battle, error) {
+	mockArgs := r.Called(ctx, query)
 	return mockArgs.Get(0).(battles.Battle), mockArgs.Error(1)
 }
 
 // FindMany mocks finding many battles via BattlesRepository
-func (r *BattlesRepository) FindMany(query battles.FindManyQuery, page int) ([]battles.Battle, int, error) {
-	mockArgs := r.Called(query, page)
+func (r *BattlesRepository) FindMany(ctx context.Context, query battles.FindManyQuery, page int) ([]battles.Battle, int, error) {
+	mockArgs := r.Called(ctx, query, page)
 	return mockArgs.Get(0).([]battles.Battle), mockArgs.Int(1), mockArgs.Error(2)
 }
 
+// Stream mocks going through many battles via BattlesRepository, calling each with the mocked ones
+func (r *BattlesRepository) Stream(ctx context.Context, query battles.FindManyQuery, each func(battles.Battle) error) error {
+	mockArgs := r.Called(ctx, query)
+	for _, record := range mockArgs.Get(0).([]battles.Battle) {
+		if err := each(record); err != nil {
+			return err
+		}
+	}
+	return mockArgs.Error(1)
+}
+
+// Any mocks telling whether any battle is stored via BattlesRepository
+func (r *BattlesRepository) Any(ctx context.Context) (bool, error) {
+	mockArgs := r.Called(ctx)
+	return mockArgs.Bool(0), mockArgs.Error(1)
+}
+
 // CreateOne mocks creating one battle via BattlesRepository
 func (r *BattlesRepository) CreateOne(data battles.CreationInput) (uuid.UUID, error) {
 	mockArgs := r.Called(data)
@@ -84,6 +102,14 @@ func Battle() battles.Battle {
 			Faction2().ID: []uuid.UUID{Commander2().ID, Commander3().ID},
 			Faction3().ID: []uuid.UUID{Commander4().ID, Commander5().ID},
 		},
+		CommanderConfidences: map[uuid.UUID]battles.Confidence{
+			Commander().ID:  battles.Confidence(wb.CommanderConfidences[WikiCommander().ID]),
+			Commander2().ID: battles.Confidence(wb.CommanderConfidences[WikiCommander2().ID]),
+			Commander3().ID: battles.Confidence(wb.CommanderConfidences[WikiCommander3().ID]),
+			Commander4().ID: battles.Confidence(wb.CommanderConfidences[WikiCommander4().ID]),
+			Commander5().ID: battles.Confidence(wb.CommanderConfidences[WikiCommander5().ID]),
+		},
+		Wikidata: wb.Wikidata,
 	}
 }
 
@@ -129,5 +155,10 @@ func BattleCreationInput() battles.CreationInput {
 			Faction2().ID: []uuid.UUID{Commander2().ID, Commander3().ID},
 			Faction3().ID: []uuid.UUID{Commander4().ID, Commander5().ID},
 		},
+		CommanderConfidences: b.CommanderConfidences,
+		FlagsByFaction: map[uuid.UUID]string{
+			Faction().ID: WikiBattle().Flags[WikiFaction().ID],
+		},
+		Wikidata: b.Wikidata,
 	}
 }
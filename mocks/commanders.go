@@ -1,8 +1,10 @@
 package mocks
 
 import (
+	"context"
 	"github.com/sasalatart/batcoms/domain/commanders"
 	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/dates"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/mock"
 )
@@ -19,26 +21,52 @@ type CommandersRepository struct {
 }
 
 // FindOne mocks finding one commander via CommandersRepository
-func (r *CommandersRepository) FindOne(query commanders.FindOneQuery) (commanders.Commander, error) {
-	mockArgs := r.Called(query)
+func (r *CommandersRepository) FindOne(ctx context.Context, query commanders.FindOneQuery) (commanders.Commander, error) {
+	mockArgs := r.Called(ctx, query)
 	return mockArgs.Get(0).(commanders.Commander), mockArgs.Error(1)
 }
 
 // FindMany mocks finding many commanders via CommandersRepository
-func (r *CommandersRepository) FindMany(query commanders.FindManyQuery, page int) ([]commanders.Commander, int, error) {
-	mockArgs := r.Called(query, page)
+func (r *CommandersRepository) FindMany(ctx context.Context, query commanders.FindManyQuery, page int) ([]commanders.Commander, int, error) {
+	mockArgs := r.Called(ctx, query, page)
 	return mockArgs.Get(0).([]commanders.Commander), mockArgs.Int(1), mockArgs.Error(2)
 }
 
+// Stream mocks going through many commanders via CommandersRepository, calling each with the mocked
+// ones
+func (r *CommandersRepository) Stream(ctx context.Context, query commanders.FindManyQuery, each func(commanders.Commander) error) error {
+	mockArgs := r.Called(ctx, query)
+	for _, record := range mockArgs.Get(0).([]commanders.Commander) {
+		if err := each(record); err != nil {
+			return err
+		}
+	}
+	return mockArgs.Error(1)
+}
+
 // CreateOne mocks creating one commander via CommandersRepository
 func (r *CommandersRepository) CreateOne(data commanders.CreationInput) (uuid.UUID, error) {
 	mockArgs := r.Called(data)
 	return mockArgs.Get(0).(uuid.UUID), mockArgs.Error(1)
 }
 
+// CreateAlias mocks keeping the aliases of a merged commander via CommandersRepository
+func (r *CommandersRepository) CreateAlias(data commanders.AliasCreationInput) error {
+	mockArgs := r.Called(data)
+	return mockArgs.Error(0)
+}
+
 // Commander returns an instance of commanders.Commander that may be used for mocking purposes
 func Commander() commanders.Commander {
-	return commanderFromScraped(WikiCommander(), commanderUUID)
+	c := commanderFromScraped(WikiCommander(), commanderUUID)
+	c.BirthDate = &dates.Historic{Year: 1769, Month: 8, Day: 15}
+	c.DeathDate = &dates.Historic{Year: 1821, Month: 5, Day: 5}
+	c.Allegiances = []commanders.Allegiance{
+		{Name: "Kingdom of France", From: &dates.Historic{Year: 1785}, To: &dates.Historic{Year: 1792}},
+		{Name: "French First Republic", From: &dates.Historic{Year: 1792}, To: &dates.Historic{Year: 1804}},
+		{Name: "First French Empire", From: &dates.Historic{Year: 1804}, To: &dates.Historic{Year: 1815}},
+	}
+	return c
 }
 
 // Commander2 returns an instance of commanders.Commander that may be used for mocking purposes
@@ -93,19 +121,24 @@ func CommanderCreationInput5() commanders.CreationInput {
 
 func commanderFromScraped(wc wikiactors.Actor, uuid uuid.UUID) commanders.Commander {
 	return commanders.Commander{
-		ID:      uuid,
-		WikiID:  wc.ID,
-		URL:     wc.URL,
-		Name:    wc.Name,
-		Summary: wc.Extract,
+		ID:       uuid,
+		WikiID:   wc.ID,
+		URL:      wc.URL,
+		Name:     wc.Name,
+		Summary:  wc.Extract,
+		Wikidata: wc.Wikidata,
 	}
 }
 
 func createCommanderInputFromCommander(c commanders.Commander) commanders.CreationInput {
 	return commanders.CreationInput{
-		WikiID:  c.WikiID,
-		URL:     c.URL,
-		Name:    c.Name,
-		Summary: c.Summary,
+		WikiID:      c.WikiID,
+		URL:         c.URL,
+		Name:        c.Name,
+		Summary:     c.Summary,
+		BirthDate:   c.BirthDate,
+		DeathDate:   c.DeathDate,
+		Allegiances: c.Allegiances,
+		Wikidata:    c.Wikidata,
 	}
 }
@@ -1,6 +1,7 @@
 package mocks
 
 import (
+	"context"
 	"github.com/sasalatart/batcoms/domain/factions"
 	"github.com/sasalatart/batcoms/domain/wikiactors"
 	uuid "github.com/satori/go.uuid"
@@ -17,23 +18,47 @@ type FactionsRepository struct {
 }
 
 // FindOne mocks finding one faction via FactionsRepository
-func (r *FactionsRepository) FindOne(query factions.FindOneQuery) (factions.Faction, error) {
-	mockArgs := r.Called(query)
+func (r *FactionsRepository) FindOne(ctx context.Context, query factions.FindOneQuery) (factions.Faction, error) {
+	mockArgs := r.Called(ctx, query)
 	return mockArgs.Get(0).(factions.Faction), mockArgs.Error(1)
 }
 
 // FindMany mocks finding many commanders via FactionsRepository
-func (r *FactionsRepository) FindMany(query factions.FindManyQuery, page int) ([]factions.Faction, int, error) {
-	mockArgs := r.Called(query, page)
+func (r *FactionsRepository) FindMany(ctx context.Context, query factions.FindManyQuery, page int) ([]factions.Faction, int, error) {
+	mockArgs := r.Called(ctx, query, page)
 	return mockArgs.Get(0).([]factions.Faction), mockArgs.Int(1), mockArgs.Error(2)
 }
 
+// Stream mocks going through many factions via FactionsRepository, calling each with the mocked
+// ones
+func (r *FactionsRepository) Stream(ctx context.Context, query factions.FindManyQuery, each func(factions.Faction) error) error {
+	mockArgs := r.Called(ctx, query)
+	for _, record := range mockArgs.Get(0).([]factions.Faction) {
+		if err := each(record); err != nil {
+			return err
+		}
+	}
+	return mockArgs.Error(1)
+}
+
 // CreateOne mocks creating one faction via FactionsRepository
 func (r *FactionsRepository) CreateOne(data factions.CreationInput) (uuid.UUID, error) {
 	mockArgs := r.Called(data)
 	return mockArgs.Get(0).(uuid.UUID), mockArgs.Error(1)
 }
 
+// CreateRelationship mocks relating one faction to another via FactionsRepository
+func (r *FactionsRepository) CreateRelationship(data factions.RelationshipCreationInput) error {
+	mockArgs := r.Called(data)
+	return mockArgs.Error(0)
+}
+
+// CreateAlias mocks keeping the aliases of a merged faction via FactionsRepository
+func (r *FactionsRepository) CreateAlias(data factions.AliasCreationInput) error {
+	mockArgs := r.Called(data)
+	return mockArgs.Error(0)
+}
+
 // Faction returns an instance of factions.Faction that may be used for mocking purposes
 func Faction() factions.Faction {
 	return factionFromScraped(WikiFaction(), factionUUID)
@@ -69,19 +94,23 @@ func FactionCreationInput3() factions.CreationInput {
 
 func factionFromScraped(wf wikiactors.Actor, uuid uuid.UUID) factions.Faction {
 	return factions.Faction{
-		ID:      uuid,
-		WikiID:  wf.ID,
-		URL:     wf.URL,
-		Name:    wf.Name,
-		Summary: wf.Extract,
+		ID:       uuid,
+		WikiID:   wf.ID,
+		URL:      wf.URL,
+		Name:     wf.Name,
+		Summary:  wf.Extract,
+		Flag:     wf.Flag,
+		Wikidata: wf.Wikidata,
 	}
 }
 
 func createFactionInputFromFaction(f factions.Faction) factions.CreationInput {
 	return factions.CreationInput{
-		WikiID:  f.WikiID,
-		URL:     f.URL,
-		Name:    f.Name,
-		Summary: f.Summary,
+		WikiID:   f.WikiID,
+		URL:      f.URL,
+		Name:     f.Name,
+		Summary:  f.Summary,
+		Flag:     f.Flag,
+		Wikidata: f.Wikidata,
 	}
 }
@@ -0,0 +1,14 @@
+package mocks
+
+import "github.com/stretchr/testify/mock"
+
+// FlagDownloader mocks downloaders used to store the images of flags locally
+type FlagDownloader struct {
+	mock.Mock
+}
+
+// Download mocks storing the image of a flag via FlagDownloader
+func (d *FlagDownloader) Download(path string) (string, error) {
+	mockArgs := d.Called(path)
+	return mockArgs.String(0), mockArgs.Error(1)
+}
@@ -0,0 +1,60 @@
+package mocks
+
+import (
+	"context"
+	"github.com/sasalatart/batcoms/domain/translations"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/mock"
+)
+
+// TranslationsRepository mocks repositories used to read and write translations
+type TranslationsRepository struct {
+	mock.Mock
+}
+
+// FindMany mocks finding many translations via TranslationsRepository
+func (r *TranslationsRepository) FindMany(ctx context.Context, query translations.FindManyQuery) ([]translations.Translation, error) {
+	mockArgs := r.Called(ctx, query)
+	return mockArgs.Get(0).([]translations.Translation), mockArgs.Error(1)
+}
+
+// CreateOne mocks creating one translation via TranslationsRepository
+func (r *TranslationsRepository) CreateOne(data translations.CreationInput) (uuid.UUID, error) {
+	mockArgs := r.Called(data)
+	return mockArgs.Get(0).(uuid.UUID), mockArgs.Error(1)
+}
+
+// BattleTranslation returns the Spanish translation of the battle returned by Battle, that may be
+// used for mocking purposes
+func BattleTranslation() translations.Translation {
+	l := WikiBattle().Localizations["es"]
+	return translations.Translation{
+		EntityID: Battle().ID,
+		Language: "es",
+		Name:     l.Name,
+		Summary:  l.Extract,
+	}
+}
+
+// CommanderTranslation returns the Spanish translation of the commander returned by Commander,
+// that may be used for mocking purposes
+func CommanderTranslation() translations.Translation {
+	l := WikiCommander().Localizations["es"]
+	return translations.Translation{
+		EntityID: Commander().ID,
+		Language: "es",
+		Name:     l.Name,
+		Summary:  l.Extract,
+	}
+}
+
+// TranslationCreationInput returns an instance of translations.CreationInput that may be used for
+// mocking inputs to create translations
+func TranslationCreationInput(t translations.Translation) translations.CreationInput {
+	return translations.CreationInput{
+		EntityID: t.EntityID,
+		Language: t.Language,
+		Name:     t.Name,
+		Summary:  t.Summary,
+	}
+}
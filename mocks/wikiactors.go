@@ -2,6 +2,8 @@ package mocks
 
 import (
 	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
 )
 
 // WikiFaction returns a faction instance of wikiactors.Actor that may be used for testing purposes
@@ -10,6 +12,7 @@ func WikiFaction() wikiactors.Actor {
 		Kind:        wikiactors.FactionKind,
 		ID:          21418258,
 		URL:         "https://en.wikipedia.org/wiki/French_First_Empire",
+		Flag:        "/thumb/c/c3/Flag_of_France.svg/23px-Flag_of_France.svg.png",
 		Name:        "First French Empire",
 		Description: "Empire of Napoleon I of France between 1804–1815",
 		Extract:     "The First French Empire, officially the French Empire or the Napoleonic Empire, was the empire of Napoleon Bonaparte of France and the dominant power in much of continental Europe at the beginning of the 19th century. Although France had already established an overseas colonial empire beginning in the 17th century, the French state had remained a kingdom under the Bourbons and a republic after the French Revolution. Historians refer to Napoleon's regime as the First Empire to distinguish it from the restorationist Second Empire (1852–1870) ruled by his nephew Napoleon III.",
@@ -49,6 +52,30 @@ func WikiCommander() wikiactors.Actor {
 		Name:        "Napoleon",
 		Description: "19th century French military leader, strategist, and politician",
 		Extract:     "Napoleon Bonaparte, born Napoleone di Buonaparte, was a French statesman and military leader who became famous as an artillery commander during the French Revolution. He led many successful campaigns during the French Revolutionary Wars and was Emperor of the French as Napoleon I from 1804 until 1814 and again briefly in 1815 during the Hundred Days. Napoleon dominated European and global affairs for more than a decade while leading France against a series of coalitions during the Napoleonic Wars. He won many of these wars and a vast majority of his battles, building a large empire that ruled over much of continental Europe before its final collapse in 1815. He is considered one of the greatest commanders in history, and his wars and campaigns are studied at military schools worldwide. Napoleon's political and cultural legacy has made him one of the most celebrated and controversial leaders in human history.",
+		Localizations: map[string]wikiactors.Localization{
+			"es": {
+				Name:        "Napoleón Bonaparte",
+				Description: "Militar y gobernante francés",
+				Extract:     "Napoleón Bonaparte fue un militar y gobernante francés, general republicano durante la Revolución y el Directorio, y artífice del golpe de Estado del 18 de brumario que lo convirtió en primer cónsul de la República.",
+			},
+		},
+		Biography: &wikiactors.Biography{
+			Born: "1769-08-15",
+			Died: "5 May 1821 (aged 51). Longwood, Saint Helena",
+			Allegiances: []wikiactors.Allegiance{
+				{Name: "Kingdom of France", Period: "1785–1792"},
+				{Name: "French First Republic", Period: "1792–1804"},
+				{Name: "First French Empire", Period: "1804–1815"},
+			},
+		},
+		Wikidata: &wikidata.Facts{
+			QID:           "Q517",
+			BirthDate:     &dates.Historic{Year: 1769, Month: 8, Day: 15, Calendar: dates.GregorianCalendar},
+			BirthPlace:    "Ajaccio",
+			DeathDate:     &dates.Historic{Year: 1821, Month: 5, Day: 5, Calendar: dates.GregorianCalendar},
+			DeathPlace:    "Longwood",
+			Nationalities: []string{"France"},
+		},
 	}
 }
 
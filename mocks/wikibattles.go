@@ -4,6 +4,8 @@ import (
 	"github.com/sasalatart/batcoms/domain/locations"
 	"github.com/sasalatart/batcoms/domain/statistics"
 	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
 )
 
 // WikiBattle returns an instance of wikibattles.Battle that may be used for testing purposes
@@ -44,5 +46,28 @@ func WikiBattle() wikibattles.Battle {
 			20611504: {27126603, 251000},
 			266894:   {11551, 14092123},
 		},
+		CommanderConfidences: map[int]wikibattles.Confidence{
+			69880:    wikibattles.SingleFactionConfidence,
+			27126603: wikibattles.FlagConfidence,
+			251000:   wikibattles.FlagConfidence,
+			11551:    wikibattles.FlagConfidence,
+			14092123: wikibattles.FlagConfidence,
+		},
+		Flags: map[int]string{
+			21418258: "/thumb/c/c3/Flag_of_France.svg/23px-Flag_of_France.svg.png",
+		},
+		Localizations: map[string]wikibattles.Localization{
+			"es": {
+				URL:         "https://es.wikipedia.org/wiki/Batalla_de_Austerlitz",
+				Name:        "Batalla de Austerlitz",
+				Description: "Batalla de las guerras napoleónicas",
+				Extract:     "La batalla de Austerlitz, también conocida como la batalla de los Tres Emperadores, fue una de las batallas más importantes de las guerras napoleónicas.",
+			},
+		},
+		Wikidata: &wikidata.Facts{
+			QID:         "Q134114",
+			PointInTime: &dates.Historic{Year: 1805, Month: 12, Day: 2, Calendar: dates.GregorianCalendar},
+			Coordinates: &wikidata.Coordinates{Latitude: 49.128, Longitude: 16.763},
+		},
 	}
 }
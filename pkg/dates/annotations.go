@@ -0,0 +1,136 @@
+package dates
+
+import (
+	"strconv"
+	"strings"
+)
+
+// annotations collects the qualifiers found by the cleanerPipeline while it simplifies the text
+// of a date, so that they can be attached to the Historic values detected afterwards. Years are
+// stored as they were written, which is how they end up in Historic.Year
+type annotations struct {
+	approximate       bool
+	seasons           map[int]string
+	decades           map[int]bool
+	centuries         map[int]bool
+	yearAlternatives  map[int]int
+	monthAlternatives map[int]int
+}
+
+func newAnnotations() *annotations {
+	return &annotations{
+		seasons:           make(map[int]string),
+		decades:           make(map[int]bool),
+		centuries:         make(map[int]bool),
+		yearAlternatives:  make(map[int]int),
+		monthAlternatives: make(map[int]int),
+	}
+}
+
+// seasonMonths maps seasons to the months in which they begin and end. Winter begins in December
+// of the previous year
+var seasonMonths = map[string][2]int{
+	"spring": {3, 5},
+	"summer": {6, 8},
+	"fall":   {9, 11},
+	"autumn": {9, 11},
+	"winter": {12, 2},
+}
+
+func annotateApproximate(_ []string, a *annotations) {
+	a.approximate = true
+}
+
+func annotateSeason(matches []string, a *annotations) {
+	if year, err := strconv.Atoi(matches[2]); err == nil {
+		a.seasons[year] = strings.ToLower(matches[1])
+	}
+}
+
+func annotateDecade(matches []string, a *annotations) {
+	if year, err := strconv.Atoi(matches[1]); err == nil {
+		a.decades[year] = true
+	}
+}
+
+func annotateCentury(matches []string, a *annotations) {
+	if century, err := strconv.Atoi(matches[1]); err == nil {
+		a.centuries[century*100] = true
+	}
+}
+
+func annotateYearAlternatives(matches []string, a *annotations) {
+	first, err1 := strconv.Atoi(matches[1])
+	second, err2 := strconv.Atoi(matches[3])
+	if err1 == nil && err2 == nil {
+		a.yearAlternatives[first] = second
+	}
+}
+
+// annotateMonthAlternatives records the first and last of the listed months, given that those in
+// between them are within the interval they enclose
+func annotateMonthAlternatives(matches []string, a *annotations) {
+	first, err1 := strconv.Atoi(mToi[strings.ToLower(matches[1])])
+	last, err2 := strconv.Atoi(mToi[strings.ToLower(matches[4])])
+	if err1 == nil && err2 == nil {
+		a.monthAlternatives[first] = last
+	}
+}
+
+// apply attaches the collected annotations to a Historic whose IsBCE value has already been decided
+func (a *annotations) apply(h Historic) Historic {
+	h.Approximate = a.approximate
+	plain := Historic{Year: h.Year, Month: h.Month, Day: h.Day, IsBCE: h.IsBCE}
+
+	switch {
+	case h.Month == 0 && a.centuries[h.Year]:
+		first, last := Historic{Year: h.Year - 99, IsBCE: h.IsBCE}, Historic{Year: h.Year, IsBCE: h.IsBCE}
+		h = withUncertainty(h, first, last, CenturyPrecision)
+	case h.Month == 0 && a.decades[h.Year]:
+		first, last := Historic{Year: h.Year, IsBCE: h.IsBCE}, Historic{Year: h.Year + 9, IsBCE: h.IsBCE}
+		h = withUncertainty(h, first, last, DecadePrecision)
+	case h.Month == 0 && a.seasons[h.Year] != "":
+		months := seasonMonths[a.seasons[h.Year]]
+		first := Historic{Year: h.Year, Month: months[0], IsBCE: h.IsBCE}
+		if months[0] > months[1] {
+			first = previousYear(first)
+		}
+		last := Historic{Year: h.Year, Month: months[1], IsBCE: h.IsBCE}
+		h = withUncertainty(h, first, last, SeasonPrecision)
+	case a.yearAlternatives[h.Year] != 0:
+		other := plain
+		other.Year = a.yearAlternatives[h.Year]
+		h = withUncertainty(h, plain, other, ImpliedPrecision)
+	case h.Month != 0 && a.monthAlternatives[h.Month] != 0:
+		other := plain
+		other.Month = a.monthAlternatives[h.Month]
+		h = withUncertainty(h, plain, other, ImpliedPrecision)
+	}
+	return h
+}
+
+// withUncertainty sets the Uncertainty interval of a Historic to the one enclosed by the given
+// dates, moving its Year to the earliest one when the interval covers more than a year
+func withUncertainty(h Historic, a, b Historic, p Precision) Historic {
+	earliest, latest := minMax(a, b)
+	h.Precision = p
+	h.Uncertainty = &Uncertainty{Earliest: earliest, Latest: latest}
+	if p == CenturyPrecision || p == DecadePrecision {
+		h.Year = earliest.Year
+	}
+	return h
+}
+
+// previousYear returns the same date, but one year earlier. There is no year 0, so 1 CE is
+// preceded by 1 BCE
+func previousYear(h Historic) Historic {
+	switch {
+	case h.IsBCE:
+		h.Year++
+	case h.Year == 1:
+		h.IsBCE = true
+	default:
+		h.Year--
+	}
+	return h
+}
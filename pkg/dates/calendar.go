@@ -0,0 +1,175 @@
+package dates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Calendar represents the calendar in which a Historic date was expressed
+type Calendar int
+
+const (
+	// UnspecifiedCalendar means that the source of a Historic did not state its calendar. This is
+	// the zero value of Calendar
+	UnspecifiedCalendar Calendar = iota
+	// GregorianCalendar represents dates expressed in the Gregorian calendar ("New Style")
+	GregorianCalendar
+	// JulianCalendar represents dates expressed in the Julian calendar ("Old Style")
+	JulianCalendar
+)
+
+var calendarNames = map[Calendar]string{
+	UnspecifiedCalendar: "",
+	GregorianCalendar:   "gregorian",
+	JulianCalendar:      "julian",
+}
+
+func (c Calendar) String() string {
+	return calendarNames[c]
+}
+
+// MarshalText implements encoding.TextMarshaler, so that calendars are serialized by their names
+func (c Calendar) MarshalText() ([]byte, error) {
+	name, ok := calendarNames[c]
+	if !ok {
+		return nil, fmt.Errorf("Unknown calendar %d", c)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, and is the counterpart of MarshalText
+func (c *Calendar) UnmarshalText(text []byte) error {
+	for calendar, name := range calendarNames {
+		if name == strings.ToLower(string(text)) {
+			*c = calendar
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown calendar %q", text)
+}
+
+// gregorianReformJDN is the Julian Day Number of 15 October 1582, the first day of the Gregorian
+// calendar. The day before was 4 October 1582 in the Julian calendar
+const gregorianReformJDN = 2299161
+
+// EffectiveCalendar returns the Calendar of the Historic. When none was stated by its source, the
+// usual convention is assumed: dates before 15 October 1582 are Julian, and later ones Gregorian
+func (h Historic) EffectiveCalendar() Calendar {
+	if h.Calendar != UnspecifiedCalendar {
+		return h.Calendar
+	}
+	b := h.ToBeginning()
+	if JulianToJDN(b.astronomicalYear(), b.Month, b.Day) < gregorianReformJDN {
+		return JulianCalendar
+	}
+	return GregorianCalendar
+}
+
+// JDN returns the Julian Day Number of the Historic, taking its EffectiveCalendar into account.
+// Missing months and days are considered to be the first ones, as in ToBeginning
+func (h Historic) JDN() int {
+	b := h.ToBeginning()
+	if h.EffectiveCalendar() == JulianCalendar {
+		return JulianToJDN(b.astronomicalYear(), b.Month, b.Day)
+	}
+	return GregorianToJDN(b.astronomicalYear(), b.Month, b.Day)
+}
+
+// ToGregorian converts the Historic into the proleptic Gregorian calendar. Only dates known down
+// to their day are shifted, because the difference between both calendars is always less than a
+// month, so coarser dates just get their Calendar updated
+func (h Historic) ToGregorian() Historic {
+	return h.convert(GregorianCalendar)
+}
+
+// ToJulian converts the Historic into the proleptic Julian calendar, and is the counterpart of
+// ToGregorian
+func (h Historic) ToJulian() Historic {
+	return h.convert(JulianCalendar)
+}
+
+func (h Historic) convert(c Calendar) Historic {
+	res := h
+	if h.Day != 0 && h.EffectiveCalendar() != c {
+		converted := FromJDN(h.JDN(), c)
+		res.Year, res.Month, res.Day, res.IsBCE = converted.Year, converted.Month, converted.Day, converted.IsBCE
+	}
+	res.Calendar = c
+	if h.Uncertainty != nil {
+		res.Uncertainty = &Uncertainty{
+			Earliest: h.Uncertainty.Earliest.convert(c),
+			Latest:   h.Uncertainty.Latest.convert(c),
+		}
+	}
+	return res
+}
+
+// FromJDN creates a Historic from a Julian Day Number, expressed in the given calendar. An
+// UnspecifiedCalendar is treated as the Gregorian one
+func FromJDN(jdn int, c Calendar) Historic {
+	var year, month, day int
+	if c == JulianCalendar {
+		year, month, day = jdnToDate(0, jdn+32082)
+	} else {
+		c = GregorianCalendar
+		a := jdn + 32044
+		b := floorDiv(4*a+3, 146097)
+		year, month, day = jdnToDate(100*b, a-floorDiv(146097*b, 4))
+	}
+	h := Historic{Year: year, Month: month, Day: day, Calendar: c}
+	if year <= 0 {
+		h.Year = 1 - year
+		h.IsBCE = true
+	}
+	return h
+}
+
+// GregorianToJDN calculates the Julian Day Number of a date in the proleptic Gregorian calendar.
+// The year must use astronomical numbering, where 1 BCE is the year 0 and 2 BCE is the year -1
+func GregorianToJDN(year, month, day int) int {
+	y, m := shiftedYearMonth(year, month)
+	return day + floorDiv(153*m+2, 5) + 365*y + floorDiv(y, 4) - floorDiv(y, 100) + floorDiv(y, 400) - 32045
+}
+
+// JulianToJDN calculates the Julian Day Number of a date in the proleptic Julian calendar. The
+// year must use astronomical numbering, where 1 BCE is the year 0 and 2 BCE is the year -1
+func JulianToJDN(year, month, day int) int {
+	y, m := shiftedYearMonth(year, month)
+	return day + floorDiv(153*m+2, 5) + 365*y + floorDiv(y, 4) - 32083
+}
+
+// shiftedYearMonth moves the beginning of the year to March, so that leap days fall at its end,
+// and moves the epoch to 4801 BCE so that the years of the conversions are positive
+func shiftedYearMonth(year, month int) (int, int) {
+	a := floorDiv(14-month, 12)
+	return year + 4800 - a, month + 12*a - 3
+}
+
+// jdnToDate is the common part of converting Julian Day Numbers into both calendars, where
+// centuries holds the years already accounted for and c the remaining days
+func jdnToDate(centuries, c int) (int, int, int) {
+	d := floorDiv(4*c+3, 1461)
+	e := c - floorDiv(1461*d, 4)
+	m := floorDiv(5*e+2, 153)
+	day := e - floorDiv(153*m+2, 5) + 1
+	month := m + 3 - 12*floorDiv(m, 10)
+	year := centuries + d - 4800 + floorDiv(m, 10)
+	return year, month, day
+}
+
+// astronomicalYear returns the year of the Historic using astronomical numbering, where 1 BCE is
+// the year 0 and 2 BCE is the year -1
+func (h Historic) astronomicalYear() int {
+	if h.IsBCE {
+		return 1 - h.Year
+	}
+	return h.Year
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if (a%b != 0) && ((a < 0) != (b < 0)) {
+		q--
+	}
+	return q
+}
@@ -0,0 +1,211 @@
+package dates_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalendar(t *testing.T) {
+	t.Run("JDN", func(t *testing.T) {
+		cases := []struct {
+			input    dates.Historic
+			expected int
+		}{
+			{
+				input:    dates.Historic{Year: 2000, Month: 1, Day: 1},
+				expected: 2451545,
+			},
+			{
+				input:    dates.Historic{Year: 1582, Month: 10, Day: 15},
+				expected: 2299161,
+			},
+			{
+				input:    dates.Historic{Year: 1582, Month: 10, Day: 4},
+				expected: 2299160,
+			},
+			{
+				input:    dates.Historic{Year: 44, Month: 3, Day: 15, IsBCE: true},
+				expected: 1705426,
+			},
+			{
+				input:    dates.Historic{Year: 4713, IsBCE: true},
+				expected: 0,
+			},
+			{
+				input:    dates.Historic{Year: 1917, Month: 10, Day: 25, Calendar: dates.JulianCalendar},
+				expected: 2421540,
+			},
+			{
+				input:    dates.Historic{Year: 1917, Month: 11, Day: 7, Calendar: dates.GregorianCalendar},
+				expected: 2421540,
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, c.input.JDN(), "JDN of %v", c.input)
+		}
+	})
+
+	t.Run("FromJDN", func(t *testing.T) {
+		cases := []struct {
+			jdn      int
+			calendar dates.Calendar
+			expected dates.Historic
+		}{
+			{
+				jdn:      2299161,
+				calendar: dates.GregorianCalendar,
+				expected: dates.Historic{Year: 1582, Month: 10, Day: 15, Calendar: dates.GregorianCalendar},
+			},
+			{
+				jdn:      2299161,
+				calendar: dates.JulianCalendar,
+				expected: dates.Historic{Year: 1582, Month: 10, Day: 5, Calendar: dates.JulianCalendar},
+			},
+			{
+				jdn:      0,
+				calendar: dates.JulianCalendar,
+				expected: dates.Historic{Year: 4713, Month: 1, Day: 1, IsBCE: true, Calendar: dates.JulianCalendar},
+			},
+			{
+				jdn:      1721423,
+				calendar: dates.UnspecifiedCalendar,
+				expected: dates.Historic{Year: 1, Month: 12, Day: 29, IsBCE: true, Calendar: dates.GregorianCalendar},
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, dates.FromJDN(c.jdn, c.calendar), "Converting JDN %d", c.jdn)
+		}
+	})
+
+	t.Run("EffectiveCalendar", func(t *testing.T) {
+		cases := []struct {
+			input    dates.Historic
+			expected dates.Calendar
+		}{
+			{
+				input:    dates.Historic{Year: 1582, Month: 10, Day: 4},
+				expected: dates.JulianCalendar,
+			},
+			{
+				input:    dates.Historic{Year: 1582, Month: 10, Day: 15},
+				expected: dates.GregorianCalendar,
+			},
+			{
+				input:    dates.Historic{Year: 1582},
+				expected: dates.JulianCalendar,
+			},
+			{
+				input:    dates.Historic{Year: 218, IsBCE: true},
+				expected: dates.JulianCalendar,
+			},
+			{
+				input:    dates.Historic{Year: 1066, Month: 10, Day: 14, Calendar: dates.GregorianCalendar},
+				expected: dates.GregorianCalendar,
+			},
+			{
+				input:    dates.Historic{Year: 1709, Month: 6, Day: 27, Calendar: dates.JulianCalendar},
+				expected: dates.JulianCalendar,
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, c.input.EffectiveCalendar(), "Calendar of %v", c.input)
+		}
+	})
+
+	t.Run("ToGregorian", func(t *testing.T) {
+		cases := []struct {
+			input    dates.Historic
+			expected dates.Historic
+		}{
+			{
+				input:    dates.Historic{Year: 1917, Month: 10, Day: 25, Calendar: dates.JulianCalendar},
+				expected: dates.Historic{Year: 1917, Month: 11, Day: 7, Calendar: dates.GregorianCalendar},
+			},
+			{
+				input:    dates.Historic{Year: 1752, Month: 9, Day: 2, Calendar: dates.JulianCalendar},
+				expected: dates.Historic{Year: 1752, Month: 9, Day: 13, Calendar: dates.GregorianCalendar},
+			},
+			{
+				input:    dates.Historic{Year: 44, Month: 3, Day: 15, IsBCE: true},
+				expected: dates.Historic{Year: 44, Month: 3, Day: 13, IsBCE: true, Calendar: dates.GregorianCalendar},
+			},
+			{
+				input:    dates.Historic{Year: 1805, Month: 12, Day: 2},
+				expected: dates.Historic{Year: 1805, Month: 12, Day: 2, Calendar: dates.GregorianCalendar},
+			},
+			{
+				input:    dates.Historic{Year: 1709, Month: 6, Calendar: dates.JulianCalendar},
+				expected: dates.Historic{Year: 1709, Month: 6, Calendar: dates.GregorianCalendar},
+			},
+			{
+				input: dates.Historic{
+					Year:        1700,
+					Month:       2,
+					Day:         11,
+					Approximate: true,
+					Calendar:    dates.JulianCalendar,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1700, Month: 2, Day: 11, Calendar: dates.JulianCalendar},
+						Latest:   dates.Historic{Year: 1700, Month: 2, Day: 12, Calendar: dates.JulianCalendar},
+					},
+				},
+				expected: dates.Historic{
+					Year:        1700,
+					Month:       2,
+					Day:         21,
+					Approximate: true,
+					Calendar:    dates.GregorianCalendar,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1700, Month: 2, Day: 21, Calendar: dates.GregorianCalendar},
+						Latest:   dates.Historic{Year: 1700, Month: 2, Day: 22, Calendar: dates.GregorianCalendar},
+					},
+				},
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, c.input.ToGregorian(), "Converting %v", c.input)
+		}
+	})
+
+	t.Run("ToJulian", func(t *testing.T) {
+		cases := []struct {
+			input    dates.Historic
+			expected dates.Historic
+		}{
+			{
+				input:    dates.Historic{Year: 1917, Month: 11, Day: 7},
+				expected: dates.Historic{Year: 1917, Month: 10, Day: 25, Calendar: dates.JulianCalendar},
+			},
+			{
+				input:    dates.Historic{Year: 1066, Month: 10, Day: 14, Calendar: dates.GregorianCalendar},
+				expected: dates.Historic{Year: 1066, Month: 10, Day: 8, Calendar: dates.JulianCalendar},
+			},
+			{
+				input:    dates.Historic{Year: 1066, Month: 10, Day: 14},
+				expected: dates.Historic{Year: 1066, Month: 10, Day: 14, Calendar: dates.JulianCalendar},
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, c.input.ToJulian(), "Converting %v", c.input)
+		}
+	})
+
+	t.Run("ToJDNNum", func(t *testing.T) {
+		julian := dates.Historic{Year: 1917, Month: 10, Day: 25, Calendar: dates.JulianCalendar}
+		gregorian := dates.Historic{Year: 1917, Month: 11, Day: 1}
+		assert.Greater(t, julian.ToJDNNum(), gregorian.ToJDNNum(), "Comparing %v (O.S.) with %v", julian, gregorian)
+
+		assert.Equal(t, float64(2421540), dates.Historic{Year: 1917, Month: 11, Day: 7}.ToJDNNum())
+		year, month, day := dates.Historic{Year: 1769}, dates.Historic{Year: 1769, Month: 1}, dates.Historic{Year: 1769, Month: 1, Day: 1}
+		assert.Less(t, year.ToJDNNum(), month.ToJDNNum(), "Comparing %v with %v", year, month)
+		assert.Less(t, month.ToJDNNum(), day.ToJDNNum(), "Comparing %v with %v", month, day)
+	})
+
+	t.Run("NumScale", func(t *testing.T) {
+		h := dates.Historic{Year: 1917, Month: 11, Day: 7}
+		assert.Equal(t, h.ToNum(), dates.ScaleFor(false).Num(h))
+		assert.Equal(t, h.ToJDNNum(), dates.ScaleFor(true).Num(h))
+	})
+}
@@ -9,12 +9,20 @@ var historicStringFormat = regexp.MustCompile(`^\d{1,4}(-\d{1,2}(-\d{1,2})?)?(\s
 
 // Historic represents a specific date from history. It stores the year, month, day and if whether
 // or not the underlying date was BCE. Both month and day are optional, because some dates in
-// history do not have that level of precision
+// history do not have that level of precision.
+//
+// Sources often qualify dates further, so a Historic may also store an explicit Precision (for
+// example, a season or a century), whether it is Approximate ("c. 1200 BC"), the Calendar in which
+// it was expressed, and an Uncertainty interval within which it is known to have happened
 type Historic struct {
-	Year  int  `json:"year"`
-	Month int  `json:"month,omitempty"`
-	Day   int  `json:"day,omitempty"`
-	IsBCE bool `json:"isBCE"`
+	Year        int          `json:"year"`
+	Month       int          `json:"month,omitempty"`
+	Day         int          `json:"day,omitempty"`
+	IsBCE       bool         `json:"isBCE"`
+	Precision   Precision    `json:"precision,omitempty"`
+	Approximate bool         `json:"approximate,omitempty"`
+	Calendar    Calendar     `json:"calendar,omitempty"`
+	Uncertainty *Uncertainty `json:"uncertainty,omitempty"`
 }
 
 func (h Historic) String() string {
@@ -31,6 +39,22 @@ func (h Historic) String() string {
 	return result
 }
 
+// Resolution returns the Precision of the Historic. If none has been set explicitly, then it is
+// derived from the Month and Day values: "1769-08-15" has DayPrecision, "1769-08" has
+// MonthPrecision and "1769" has YearPrecision
+func (h Historic) Resolution() Precision {
+	switch {
+	case h.Precision != ImpliedPrecision:
+		return h.Precision
+	case h.Day != 0:
+		return DayPrecision
+	case h.Month != 0:
+		return MonthPrecision
+	default:
+		return YearPrecision
+	}
+}
+
 // ToNum converts the Historic to a floating-point number, where the magnitude of the result
 // will be directly proportional to how recent the underlying date was. For this purpose, if the
 // Month or Day values of the Historic are zero, they will not be considered in this calculaiton.
@@ -47,6 +71,20 @@ func (h Historic) ToNum() float64 {
 	return float64(h.Year) + decimalPart
 }
 
+// ToJDNNum converts the Historic to a floating-point number based on its Julian Day Number, so that
+// dates expressed in different calendars are placed in their actual order. Missing Month or Day
+// values place the result slightly before the first day they would otherwise refer to
+func (h Historic) ToJDNNum() float64 {
+	num := float64(h.JDN())
+	if h.Day == 0 {
+		num -= 0.25
+	}
+	if h.Month == 0 {
+		num -= 0.25
+	}
+	return num
+}
+
 // ToBeginning fills the missing month and day of a partial Historic by setting them to 1 if
 // they are missing. For example, the underlying date "1769" is converted into "1769-01-01",
 // "1769-08" is converted into "1769-08-01", and "1769-08-15" stays the same
@@ -74,6 +112,12 @@ func (h Historic) ToEnd() Historic {
 	return h
 }
 
+// IsZero returns whether the Historic is the zero value, such as when a date has not been set. No
+// date may be zero, given that there was no year 0
+func (h Historic) IsZero() bool {
+	return h == Historic{}
+}
+
 // IsValid applies the IsValid algorithm to check if the underlying date is valid or not
 func (h Historic) IsValid() bool {
 	return IsValid(h.String())
@@ -86,6 +130,34 @@ func New(s string) (Historic, error) {
 	return extract(s)
 }
 
+// NumScale decides how Historic dates are converted into numbers. Numbers calculated in different
+// scales can not be compared with each other, so all the processes that store or compare them must
+// use the same one
+type NumScale int
+
+const (
+	// YearScale converts dates into numbers with ToNum
+	YearScale NumScale = iota
+	// JDNScale converts dates into numbers with ToJDNNum
+	JDNScale
+)
+
+// ScaleFor returns JDNScale if jdn is true, and YearScale otherwise
+func ScaleFor(jdn bool) NumScale {
+	if jdn {
+		return JDNScale
+	}
+	return YearScale
+}
+
+// Num converts h into a number in this scale
+func (s NumScale) Num(h Historic) float64 {
+	if s == JDNScale {
+		return h.ToJDNNum()
+	}
+	return h.ToNum()
+}
+
 const monthsInYear = 12
 const maxDaysInMonth = 31
 
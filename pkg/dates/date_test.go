@@ -1,10 +1,12 @@
 package dates_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/sasalatart/batcoms/pkg/dates"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDate(t *testing.T) {
@@ -271,4 +273,73 @@ func TestDate(t *testing.T) {
 			assert.Equal(t, c.expected, c.input.String())
 		}
 	})
+
+	t.Run("IsZero", func(t *testing.T) {
+		t.Parallel()
+		assert.True(t, dates.Historic{}.IsZero())
+		assert.False(t, dates.Historic{Year: 1}.IsZero())
+		assert.False(t, dates.Historic{Year: 1, IsBCE: true}.IsZero())
+		assert.NotZero(t, dates.JDNScale.Num(dates.Historic{}), "Should not be told apart by its number")
+	})
+
+	t.Run("Resolution", func(t *testing.T) {
+		cases := []struct {
+			input    dates.Historic
+			expected dates.Precision
+		}{
+			{
+				input:    dates.Historic{Year: 1769, Month: 8, Day: 15},
+				expected: dates.DayPrecision,
+			},
+			{
+				input:    dates.Historic{Year: 1769, Month: 8},
+				expected: dates.MonthPrecision,
+			},
+			{
+				input:    dates.Historic{Year: 1769},
+				expected: dates.YearPrecision,
+			},
+			{
+				input:    dates.Historic{Year: 500, IsBCE: true, Precision: dates.CenturyPrecision},
+				expected: dates.CenturyPrecision,
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, c.input.Resolution(), "Resolution of %v", c.input)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		h := dates.Historic{
+			Year:        551,
+			Precision:   dates.SeasonPrecision,
+			Approximate: true,
+			Calendar:    dates.JulianCalendar,
+			Uncertainty: &dates.Uncertainty{
+				Earliest: dates.Historic{Year: 551, Month: 3},
+				Latest:   dates.Historic{Year: 551, Month: 5},
+			},
+		}
+		marshalled, err := json.Marshal(h)
+		require.NoError(t, err, "Marshalling %v", h)
+		assert.JSONEq(t, `{
+			"year": 551,
+			"isBCE": false,
+			"precision": "season",
+			"approximate": true,
+			"calendar": "julian",
+			"uncertainty": {
+				"earliest": {"year": 551, "month": 3, "isBCE": false},
+				"latest": {"year": 551, "month": 5, "isBCE": false}
+			}
+		}`, string(marshalled))
+
+		var unmarshalled dates.Historic
+		require.NoError(t, json.Unmarshal(marshalled, &unmarshalled), "Unmarshalling %s", marshalled)
+		assert.Equal(t, h, unmarshalled)
+
+		marshalled, err = json.Marshal(dates.Historic{Year: 1769})
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"year": 1769, "isBCE": false}`, string(marshalled))
+	})
 }
@@ -0,0 +1,201 @@
+package dates
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var isoMatcher = regexp.MustCompile(`^([+-])?(\d{4,})(?:-(\d{2})(?:-(\d{2}))?)?$`)
+var edtfMatcher = regexp.MustCompile(`^([+-])?([\dX]{4,})(?:-(\d{2})(?:-(\d{2}))?)?([~?%])?$`)
+
+// edtfSeasons maps the seasons of EDTF (written in place of months, such as "1805-21") to the ones
+// understood by Parse
+var edtfSeasons = map[int]string{
+	21: "spring",
+	22: "summer",
+	23: "autumn",
+	24: "winter",
+}
+
+// ISO returns the date in ISO 8601 extended format, such as "1805-12-02", "1805-12" or "1805".
+// Years use astronomical numbering and at least four digits, so 1 BCE is "0000" and 490 BCE
+// is "-0489"
+func (h Historic) ISO() string {
+	year := h.astronomicalYear()
+	sign := ""
+	if year < 0 {
+		sign = "-"
+		year = -year
+	}
+	res := fmt.Sprintf("%s%04d", sign, year)
+	if h.Month != 0 {
+		res += fmt.Sprintf("-%02d", h.Month)
+		if h.Day != 0 {
+			res += fmt.Sprintf("-%02d", h.Day)
+		}
+	}
+	return res
+}
+
+// ParseISO creates a Historic date from its ISO 8601 representation, as returned by ISO
+func ParseISO(s string) (Historic, error) {
+	matches := isoMatcher.FindStringSubmatch(strings.TrimSpace(s))
+	if matches == nil {
+		return Historic{}, ErrNotDate
+	}
+	return fromISOParts(matches[1], matches[2], matches[3], matches[4])
+}
+
+// EDTF returns the date in the Extended Date/Time Format (level 1) of the Library of Congress.
+// Approximate dates get the "~" qualifier ("1805-12~"), seasons are written in place of months
+// ("0551-21" for the spring of 551), and dates with an Uncertainty interval are written as that
+// interval ("1690/1699" for the 1690s). The Calendar is not part of this format, so it is lost
+func (h Historic) EDTF() string {
+	qualifier := ""
+	if h.Approximate {
+		qualifier = "~"
+	}
+	switch {
+	case h.Precision == SeasonPrecision && edtfSeason(h) != 0:
+		year := Historic{Year: h.Year, IsBCE: h.IsBCE}.ISO()
+		return fmt.Sprintf("%s-%d%s", year, edtfSeason(h), qualifier)
+	case h.Uncertainty != nil:
+		return h.Uncertainty.Earliest.ISO() + qualifier + "/" + h.Uncertainty.Latest.ISO() + qualifier
+	default:
+		return h.ISO() + qualifier
+	}
+}
+
+// ParseEDTF creates a Historic date from its EDTF (level 1) representation. Besides what EDTF
+// returns, it understands unspecified digits in years ("169X"), and treats both the uncertain
+// ("?") and the approximate-and-uncertain ("%") qualifiers as approximations. Plain ISO 8601
+// dates are valid EDTF, so they are also accepted
+func ParseEDTF(s string) (Historic, error) {
+	s = strings.TrimSpace(s)
+	if parts := strings.Split(s, "/"); len(parts) == 2 {
+		first, err := ParseEDTF(parts[0])
+		if err != nil {
+			return Historic{}, err
+		}
+		last, err := ParseEDTF(parts[1])
+		if err != nil {
+			return Historic{}, err
+		}
+		return fromInterval(first, last), nil
+	}
+
+	matches := edtfMatcher.FindStringSubmatch(s)
+	if matches == nil {
+		return Historic{}, ErrNotDate
+	}
+	sign, year, month, day, qualifier := matches[1], matches[2], matches[3], matches[4], matches[5]
+	season, _ := strconv.Atoi(month)
+
+	var res Historic
+	var err error
+	if edtfSeasons[season] != "" && day == "" {
+		res, err = fromISOParts(sign, year, "", "")
+		if err != nil {
+			return Historic{}, err
+		}
+		notes := newAnnotations()
+		notes.seasons[res.Year] = edtfSeasons[season]
+		res = notes.apply(res)
+	} else if strings.Contains(year, "X") {
+		if month != "" {
+			return Historic{}, ErrNotDate
+		}
+		first, err := fromISOParts(sign, strings.ReplaceAll(year, "X", "0"), "", "")
+		if err != nil {
+			return Historic{}, err
+		}
+		last, err := fromISOParts(sign, strings.ReplaceAll(year, "X", "9"), "", "")
+		if err != nil {
+			return Historic{}, err
+		}
+		res = fromInterval(first, last)
+	} else {
+		res, err = fromISOParts(sign, year, month, day)
+		if err != nil {
+			return Historic{}, err
+		}
+	}
+	res.Approximate = res.Approximate || qualifier != ""
+	return res, nil
+}
+
+// ParseFormatted creates a Historic date from any of the formats supported by this package: the
+// one accepted by New ("1769-08-15", "1457-04-16 BC"), ISO 8601 ("-1456-04-16") and EDTF
+// ("1805-12~", "1805/1806"). There is no year 0 in the format accepted by New, so such years are
+// read as astronomical ones, meaning that "0000" is 1 BCE, in the same way that "-0001" is 2 BCE
+func ParseFormatted(s string) (Historic, error) {
+	if h, err := New(s); err == nil && h.Year != 0 {
+		return h, nil
+	}
+	return ParseEDTF(s)
+}
+
+// fromInterval creates a Historic whose Uncertainty is the interval between two dates. Intervals
+// covering exactly a decade or a century of years are given that Precision
+func fromInterval(first, last Historic) Historic {
+	earliest, latest := minMax(first, last)
+	res := Historic{Year: earliest.Year, Month: earliest.Month, Day: earliest.Day, IsBCE: earliest.IsBCE}
+	res.Approximate = first.Approximate || last.Approximate
+	res.Uncertainty = &Uncertainty{
+		Earliest: Historic{Year: earliest.Year, Month: earliest.Month, Day: earliest.Day, IsBCE: earliest.IsBCE},
+		Latest:   Historic{Year: latest.Year, Month: latest.Month, Day: latest.Day, IsBCE: latest.IsBCE},
+	}
+	if earliest.Month != 0 || latest.Month != 0 || earliest.IsBCE != latest.IsBCE {
+		return res
+	}
+	switch {
+	case !earliest.IsBCE && earliest.Year%100 == 1 && latest.Year == earliest.Year+99,
+		earliest.IsBCE && earliest.Year%100 == 0 && latest.Year == earliest.Year-99:
+		res.Precision = CenturyPrecision
+	case !earliest.IsBCE && earliest.Year%10 == 0 && latest.Year == earliest.Year+9,
+		earliest.IsBCE && latest.Year%10 == 0 && earliest.Year == latest.Year+9:
+		res.Precision = DecadePrecision
+	}
+	return res
+}
+
+// edtfSeason returns the EDTF number of the season of a Historic, judging by the months of its
+// Uncertainty interval, or 0 if it does not have one
+func edtfSeason(h Historic) int {
+	if h.Uncertainty == nil {
+		return 0
+	}
+	for number, name := range edtfSeasons {
+		months := seasonMonths[name]
+		if h.Uncertainty.Earliest.Month == months[0] && h.Uncertainty.Latest.Month == months[1] {
+			return number
+		}
+	}
+	return 0
+}
+
+func fromISOParts(sign, year, month, day string) (Historic, error) {
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return Historic{}, ErrNotDate
+	}
+	if sign == "-" {
+		y = -y
+	}
+	res := Historic{Year: y}
+	if y <= 0 {
+		res = Historic{Year: 1 - y, IsBCE: true}
+	}
+	if month != "" {
+		res.Month, _ = strconv.Atoi(month)
+	}
+	if day != "" {
+		res.Day, _ = strconv.Atoi(day)
+	}
+	if !res.IsValid() {
+		return Historic{}, ErrNotDate
+	}
+	return res, nil
+}
@@ -0,0 +1,234 @@
+package dates_test
+
+import (
+	"testing"
+
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEDTF(t *testing.T) {
+	t.Run("ISO", func(t *testing.T) {
+		cases := []struct {
+			input    dates.Historic
+			expected string
+		}{
+			{
+				input:    dates.Historic{Year: 1805, Month: 12, Day: 2},
+				expected: "1805-12-02",
+			},
+			{
+				input:    dates.Historic{Year: 1805, Month: 12},
+				expected: "1805-12",
+			},
+			{
+				input:    dates.Historic{Year: 870},
+				expected: "0870",
+			},
+			{
+				input:    dates.Historic{Year: 1, IsBCE: true},
+				expected: "0000",
+			},
+			{
+				input:    dates.Historic{Year: 490, Month: 9, Day: 12, IsBCE: true},
+				expected: "-0489-09-12",
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, c.input.ISO(), "ISO of %v", c.input)
+
+			parsed, err := dates.ParseISO(c.expected)
+			require.NoError(t, err, "Parsing %q", c.expected)
+			assert.Equal(t, c.input, parsed, "Parsing %q", c.expected)
+		}
+	})
+
+	t.Run("ParseISO with invalid input", func(t *testing.T) {
+		for _, input := range []string{"", "1805-13", "1805-02-30", "805", "1805-12~", "2 December 1805"} {
+			_, err := dates.ParseISO(input)
+			assert.Equal(t, dates.ErrNotDate, err, "Parsing %q", input)
+		}
+	})
+
+	t.Run("EDTF", func(t *testing.T) {
+		cases := []struct {
+			input    dates.Historic
+			expected string
+		}{
+			{
+				input:    dates.Historic{Year: 1805, Month: 12, Day: 2},
+				expected: "1805-12-02",
+			},
+			{
+				input:    dates.Historic{Year: 1805, Month: 12, Approximate: true},
+				expected: "1805-12~",
+			},
+			{
+				input:    dates.Historic{Year: 491, IsBCE: true, Approximate: true},
+				expected: "-0490~",
+			},
+			{
+				input: dates.Historic{
+					Year:      551,
+					Precision: dates.SeasonPrecision,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 551, Month: 3},
+						Latest:   dates.Historic{Year: 551, Month: 5},
+					},
+				},
+				expected: "0551-21",
+			},
+			{
+				input: dates.Historic{
+					Year:      1805,
+					Precision: dates.SeasonPrecision,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1804, Month: 12},
+						Latest:   dates.Historic{Year: 1805, Month: 2},
+					},
+				},
+				expected: "1805-24",
+			},
+			{
+				input: dates.Historic{
+					Year:        1690,
+					Precision:   dates.DecadePrecision,
+					Approximate: true,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1690},
+						Latest:   dates.Historic{Year: 1699},
+					},
+				},
+				expected: "1690~/1699~",
+			},
+			{
+				input: dates.Historic{
+					Year:      500,
+					IsBCE:     true,
+					Precision: dates.CenturyPrecision,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 500, IsBCE: true},
+						Latest:   dates.Historic{Year: 401, IsBCE: true},
+					},
+				},
+				expected: "-0499/-0400",
+			},
+			{
+				input: dates.Historic{
+					Year:  1521,
+					Month: 4,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1521, Month: 4},
+						Latest:   dates.Historic{Year: 1521, Month: 5},
+					},
+				},
+				expected: "1521-04/1521-05",
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.expected, c.input.EDTF(), "EDTF of %v", c.input)
+
+			parsed, err := dates.ParseEDTF(c.expected)
+			require.NoError(t, err, "Parsing %q", c.expected)
+			assert.Equal(t, c.input, parsed, "Parsing %q", c.expected)
+		}
+	})
+
+	t.Run("ParseEDTF", func(t *testing.T) {
+		cases := []struct {
+			input    string
+			expected dates.Historic
+		}{
+			{
+				input:    "-0490?",
+				expected: dates.Historic{Year: 491, IsBCE: true, Approximate: true},
+			},
+			{
+				input:    "1805-12-02%",
+				expected: dates.Historic{Year: 1805, Month: 12, Day: 2, Approximate: true},
+			},
+			{
+				input: "1805/1806",
+				expected: dates.Historic{
+					Year: 1805,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1805},
+						Latest:   dates.Historic{Year: 1806},
+					},
+				},
+			},
+			{
+				input: "169X",
+				expected: dates.Historic{
+					Year:      1690,
+					Precision: dates.DecadePrecision,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 1690},
+						Latest:   dates.Historic{Year: 1699},
+					},
+				},
+			},
+		}
+		for _, c := range cases {
+			parsed, err := dates.ParseEDTF(c.input)
+			require.NoError(t, err, "Parsing %q", c.input)
+			assert.Equal(t, c.expected, parsed, "Parsing %q", c.input)
+		}
+
+		for _, input := range []string{"", "1805-12~~", "1805/1806/1807", "18X5-12", "1805-25", "December 1805"} {
+			_, err := dates.ParseEDTF(input)
+			assert.Equal(t, dates.ErrNotDate, err, "Parsing %q", input)
+		}
+	})
+
+	t.Run("ParseFormatted", func(t *testing.T) {
+		cases := []struct {
+			input    string
+			expected dates.Historic
+		}{
+			{
+				input:    "1457-04-16 BC",
+				expected: dates.Historic{Year: 1457, Month: 4, Day: 16, IsBCE: true},
+			},
+			{
+				input:    "-1456-04-16",
+				expected: dates.Historic{Year: 1457, Month: 4, Day: 16, IsBCE: true},
+			},
+			{
+				input:    "1805-12~",
+				expected: dates.Historic{Year: 1805, Month: 12, Approximate: true},
+			},
+			{
+				input:    "0000",
+				expected: dates.Historic{Year: 1, IsBCE: true},
+			},
+			{
+				input:    "0000-03",
+				expected: dates.Historic{Year: 1, Month: 3, IsBCE: true},
+			},
+			{
+				input:    "-0001",
+				expected: dates.Historic{Year: 2, IsBCE: true},
+			},
+		}
+		for _, c := range cases {
+			parsed, err := dates.ParseFormatted(c.input)
+			require.NoError(t, err, "Parsing %q", c.input)
+			assert.Equal(t, c.expected, parsed, "Parsing %q", c.input)
+		}
+	})
+
+	t.Run("ParseFormatted from ISO", func(t *testing.T) {
+		for _, h := range []dates.Historic{
+			{Year: 1, IsBCE: true},
+			{Year: 1, Month: 8, Day: 19, IsBCE: true},
+			{Year: 490, Month: 9, IsBCE: true},
+			{Year: 1, Month: 1, Day: 1},
+		} {
+			parsed, err := dates.ParseFormatted(h.ISO())
+			require.NoError(t, err, "Parsing %q", h.ISO())
+			assert.Equal(t, h, parsed, "Parsing %q", h.ISO())
+		}
+	})
+}
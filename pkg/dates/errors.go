@@ -4,3 +4,6 @@ import "github.com/sasalatart/batcoms/domain"
 
 // ErrNotDate is used to communicate that a value is not able to be parsed as a date
 const ErrNotDate = domain.Error("Value is not a date")
+
+// ErrUnsupportedLanguage is used to communicate that dates can not be parsed in a language
+const ErrUnsupportedLanguage = domain.Error("Unsupported language")
@@ -0,0 +1,158 @@
+package dates
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// translator is a rule that rewrites part of a date written in some language into the English form
+// understood by Parse. When replace is set, it is used instead of replaceWith
+type translator struct {
+	regex       *regexp.Regexp
+	replaceWith string
+	replace     func(matches []string) string
+}
+
+// ParseIn is like Parse, but for text written in the given language, identified by its ISO 639-1
+// code (for example, "es" for "12 de septiembre de 1683"). English is used when the language is
+// empty
+func ParseIn(language, t string) ([]Historic, error) {
+	if language == "" || language == "en" {
+		return Parse(t)
+	}
+	rules, ok := translators[language]
+	if !ok {
+		return []Historic{}, errors.Wrapf(ErrUnsupportedLanguage, "Parsing dates in %q", language)
+	}
+	for _, r := range rules {
+		if r.replace == nil {
+			t = r.regex.ReplaceAllString(t, r.replaceWith)
+			continue
+		}
+		t = r.regex.ReplaceAllStringFunc(t, func(match string) string {
+			return r.replace(r.regex.FindStringSubmatch(match))
+		})
+	}
+	return Parse(t)
+}
+
+// SupportedLanguages returns the ISO 639-1 codes of the languages understood by ParseIn
+func SupportedLanguages() []string {
+	res := []string{"en"}
+	for language := range translators {
+		res = append(res, language)
+	}
+	sort.Strings(res)
+	return res
+}
+
+var translators = map[string][]translator{
+	"es": append(
+		monthTranslators([]string{
+			"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "sep?tiembre", "octubre", "noviembre", "diciembre",
+		}),
+		translator{regex: regexp.MustCompile(`(?i)siglo\s+([IVXL]+)`), replace: centuryFromRoman},
+		translator{regex: regexp.MustCompile(`(?i)a\.\s?(de\s)?C\.|a\.\s?n\.\s?e\.`), replaceWith: "BC"},
+		translator{regex: regexp.MustCompile(`(?i)d\.\s?(de\s)?C\.|n\.\s?e\.`), replaceWith: "AD"},
+		translator{regex: regexp.MustCompile(`(?i)\b(hacia|alrededor\sde|aprox\.|ca\.)\s*`), replaceWith: "c. "},
+		translator{regex: regexp.MustCompile(`(?i)\b(primavera)\b`), replaceWith: "Spring"},
+		translator{regex: regexp.MustCompile(`(?i)\b(verano)\b`), replaceWith: "Summer"},
+		translator{regex: regexp.MustCompile(`(?i)(otoño)`), replaceWith: "Autumn"},
+		translator{regex: regexp.MustCompile(`(?i)\b(invierno)\b`), replaceWith: "Winter"},
+		translator{regex: regexp.MustCompile(`(?i)\s(al|a)\s`), replaceWith: " – "},
+		translator{regex: regexp.MustCompile(`(?i)(\d)\s(y)\s(\d)`), replaceWith: "$1 – $3"},
+		translator{regex: regexp.MustCompile(`(?i)\s(o)\s`), replaceWith: " or "},
+		translator{regex: regexp.MustCompile(`(?i)\b(del|de)\s`), replaceWith: ""},
+	),
+	"fr": append(
+		monthTranslators([]string{
+			"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre",
+		}),
+		translator{regex: regexp.MustCompile(`(?i)([IVXL]+)e\s+siècle`), replace: centuryFromRoman},
+		translator{regex: regexp.MustCompile(`(?i)(\d)er\b`), replaceWith: "$1"},
+		translator{regex: regexp.MustCompile(`(?i)av\.\s?J\.?-C\.?`), replaceWith: "BC"},
+		translator{regex: regexp.MustCompile(`(?i)(apr\.\s?)?J\.?-C\.?`), replaceWith: "AD"},
+		translator{regex: regexp.MustCompile(`(?i)\b(vers|environ)\s*`), replaceWith: "c. "},
+		translator{regex: regexp.MustCompile(`(?i)\b(printemps)\b`), replaceWith: "Spring"},
+		translator{regex: regexp.MustCompile(`(?i)(été)`), replaceWith: "Summer"},
+		translator{regex: regexp.MustCompile(`(?i)\b(automne)\b`), replaceWith: "Autumn"},
+		translator{regex: regexp.MustCompile(`(?i)\b(hiver)\b`), replaceWith: "Winter"},
+		translator{regex: regexp.MustCompile(`(?i)\s(au)\s`), replaceWith: " – "},
+		translator{regex: regexp.MustCompile(`(?i)(\d)\s(et)\s(\d)`), replaceWith: "$1 – $3"},
+		translator{regex: regexp.MustCompile(`(?i)\s(ou)\s`), replaceWith: " or "},
+		translator{regex: regexp.MustCompile(`(?i)\b(du|le|en)\s`), replaceWith: ""},
+	),
+	"de": append(
+		monthTranslators([]string{
+			"januar", "februar", "märz", "april", "mai", "juni", "juli", "august", "september", "oktober", "november", "dezember",
+		}),
+		translator{regex: regexp.MustCompile(`(?i)(\d{1,2})\.\s*Jahrhundert`), replace: centuryFromNumber},
+		translator{regex: regexp.MustCompile(`\b(\d{1,2})\.\s*`), replaceWith: "$1 "},
+		translator{regex: regexp.MustCompile(`(?i)v\.\s?Chr\.?`), replaceWith: "BC"},
+		translator{regex: regexp.MustCompile(`(?i)n\.\s?Chr\.?`), replaceWith: "AD"},
+		translator{regex: regexp.MustCompile(`(?i)\b(um|etwa|ca\.)\s*`), replaceWith: "c. "},
+		translator{regex: regexp.MustCompile(`(?i)\b(Frühjahr|Frühling)\b`), replaceWith: "Spring"},
+		translator{regex: regexp.MustCompile(`(?i)\b(Sommer)\b`), replaceWith: "Summer"},
+		translator{regex: regexp.MustCompile(`(?i)\b(Herbst)\b`), replaceWith: "Autumn"},
+		translator{regex: regexp.MustCompile(`(?i)\s(bis)\s`), replaceWith: " – "},
+		translator{regex: regexp.MustCompile(`(?i)(\d)\s(und)\s(\d)`), replaceWith: "$1 – $3"},
+		translator{regex: regexp.MustCompile(`(?i)\s(oder)\s`), replaceWith: " or "},
+		translator{regex: regexp.MustCompile(`(?i)\b(vom|am|im)\s`), replaceWith: ""},
+	),
+}
+
+// monthTranslators builds the rules that translate the names of months, which must be given in
+// order, from January to December
+func monthTranslators(names []string) []translator {
+	var res []translator
+	for i, name := range names {
+		res = append(res, translator{
+			regex:       regexp.MustCompile(`(?i)\b` + name + `\b`),
+			replaceWith: strings.Title(months[i]),
+		})
+	}
+	return res
+}
+
+var romanValues = map[rune]int{'I': 1, 'V': 5, 'X': 10, 'L': 50}
+
+// centuryFromRoman translates centuries written with roman numerals, such as "siglo XII", into the
+// English form understood by Parse ("12th century")
+func centuryFromRoman(matches []string) string {
+	numeral := strings.ToUpper(matches[1])
+	var n int
+	for i, r := range numeral {
+		value := romanValues[r]
+		if i+1 < len(numeral) && value < romanValues[rune(numeral[i+1])] {
+			n -= value
+		} else {
+			n += value
+		}
+	}
+	return ordinalCentury(n)
+}
+
+// centuryFromNumber translates centuries written with arabic numerals, such as "12. Jahrhundert",
+// into the English form understood by Parse ("12th century")
+func centuryFromNumber(matches []string) string {
+	n, _ := strconv.Atoi(matches[1])
+	return ordinalCentury(n)
+}
+
+func ordinalCentury(n int) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return strconv.Itoa(n) + suffix + " century"
+}
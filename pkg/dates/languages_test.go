@@ -0,0 +1,114 @@
+package dates_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatesParseIn(t *testing.T) {
+	cases := []struct {
+		language string
+		raw      string
+		expected []dates.Historic
+	}{
+		{
+			"",
+			"2 December 1805",
+			[]dates.Historic{{Year: 1805, Month: 12, Day: 2}},
+		},
+		{
+			"es",
+			"12 de septiembre de 1683",
+			[]dates.Historic{{Year: 1683, Month: 9, Day: 12}},
+		},
+		{
+			"es",
+			"1 al 3 de julio de 1863",
+			[]dates.Historic{
+				{Year: 1863, Month: 7, Day: 1},
+				{Year: 1863, Month: 7, Day: 3},
+			},
+		},
+		{
+			"es",
+			"agosto de 216 a. C.",
+			[]dates.Historic{{Year: 216, Month: 8, IsBCE: true}},
+		},
+		{
+			"es",
+			"hacia 1200 a. C.",
+			[]dates.Historic{{Year: 1200, IsBCE: true, Approximate: true}},
+		},
+		{
+			"es",
+			"siglo XII",
+			[]dates.Historic{{
+				Year:      1101,
+				Precision: dates.CenturyPrecision,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 1101},
+					Latest:   dates.Historic{Year: 1200},
+				},
+			}},
+		},
+		{
+			"fr",
+			"2 décembre 1805",
+			[]dates.Historic{{Year: 1805, Month: 12, Day: 2}},
+		},
+		{
+			"fr",
+			"du 1er au 3 juillet 1863",
+			[]dates.Historic{
+				{Year: 1863, Month: 7, Day: 1},
+				{Year: 1863, Month: 7, Day: 3},
+			},
+		},
+		{
+			"fr",
+			"août 490 av. J.-C.",
+			[]dates.Historic{{Year: 490, Month: 8, IsBCE: true}},
+		},
+		{
+			"de",
+			"2. Dezember 1805",
+			[]dates.Historic{{Year: 1805, Month: 12, Day: 2}},
+		},
+		{
+			"de",
+			"12. März 1881",
+			[]dates.Historic{{Year: 1881, Month: 3, Day: 12}},
+		},
+		{
+			"de",
+			"vom 1. bis 3. Juli 1863",
+			[]dates.Historic{
+				{Year: 1863, Month: 7, Day: 1},
+				{Year: 1863, Month: 7, Day: 3},
+			},
+		},
+		{
+			"de",
+			"um 1200 v. Chr.",
+			[]dates.Historic{{Year: 1200, IsBCE: true, Approximate: true}},
+		},
+	}
+	for _, c := range cases {
+		got, err := dates.ParseIn(c.language, c.raw)
+		require.NoErrorf(t, err, "Parsing dates in %q text %q", c.language, c.raw)
+		assert.Equal(t, c.expected, got, "Error parsing %q date %q", c.language, c.raw)
+	}
+
+	t.Run("UnsupportedLanguage", func(t *testing.T) {
+		_, err := dates.ParseIn("xx", "2 December 1805")
+		assert.Equal(t, dates.ErrUnsupportedLanguage, errors.Cause(err))
+	})
+
+	t.Run("SupportedLanguages", func(t *testing.T) {
+		assert.Equal(t, []string{"de", "en", "es", "fr"}, dates.SupportedLanguages())
+	})
+}
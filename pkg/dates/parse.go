@@ -11,10 +11,21 @@ import (
 // Parse receives text containing a potential date or range of dates, and translates them into a
 // slice of Historic, where the first element is the earliest date detected in the text, and the
 // second element is the latest date detected in the text. If there is just one date detected, then
-// the slice will just have one element
+// the slice will just have one element.
+//
+// Qualifiers found in the text, such as approximations, seasons, decades, centuries, alternatives
+// and calendars, are kept in the Precision, Approximate, Calendar and Uncertainty values of the
+// returned dates
 func Parse(t string) ([]Historic, error) {
 	isBCE := bcMatcher.MatchString(t)
+	calendar := detectCalendar(t)
+	notes := newAnnotations()
 	for _, p := range cleanerPipeline {
+		if p.annotate != nil {
+			for _, matches := range p.regex.FindAllStringSubmatch(t, -1) {
+				p.annotate(matches, notes)
+			}
+		}
 		t = p.regex.ReplaceAllString(t, p.replaceWith)
 	}
 
@@ -29,13 +40,30 @@ func Parse(t string) ([]Historic, error) {
 	min, max := minMax(dates...)
 	min.IsBCE = isBCE
 	max.IsBCE = isBCE
+	res := []Historic{min, max}
 	if min == max {
-		return []Historic{min}, nil
+		res = []Historic{min}
+	} else if isBCE && min.Year != max.Year {
+		res = []Historic{max, min}
 	}
-	if isBCE && min.Year != max.Year {
-		min, max = max, min
+	for i := range res {
+		res[i] = notes.apply(res[i])
+		res[i].Calendar = calendar
+	}
+	return res, nil
+}
+
+// detectCalendar finds the calendar in which the dates of a text were expressed. When both the
+// Gregorian and Julian calendars are mentioned, the cleanerPipeline prefers the Gregorian one
+func detectCalendar(t string) Calendar {
+	switch {
+	case gregorianMatcher.MatchString(t):
+		return GregorianCalendar
+	case julianMatcher.MatchString(t):
+		return JulianCalendar
+	default:
+		return UnspecifiedCalendar
 	}
-	return []Historic{min, max}, nil
 }
 
 // fromPhrase finds historic date pairs (start, finish) given a phrase
@@ -127,6 +155,7 @@ func minMax(dates ...Historic) (Historic, Historic) {
 var cleanerPipeline = []struct {
 	regex       *regexp.Regexp
 	replaceWith string
+	annotate    func(matches []string, a *annotations)
 }{
 	{
 		// Prefer Gregorian Calendar, New System (N.S.) and "probable" over other kinds
@@ -144,17 +173,34 @@ var cleanerPipeline = []struct {
 		regex:       regexp.MustCompile(`\[[^[]*\]`),
 		replaceWith: "",
 	},
+	{
+		// Translate centuries into their last year (CE) or first year (BCE)
+		// Example: 5th century BC -> 500 BC
+		regex:       regexp.MustCompile(`(?i)(\d{1,2})(?:st|nd|rd|th)[\s-]+century`),
+		replaceWith: `${1}00`,
+		annotate:    annotateCentury,
+	},
+	{
+		// Translate decades into their first year
+		// Example: 1690s -> 1690
+		regex:       regexp.MustCompile(`\b(\d{1,3}0)s\b`),
+		replaceWith: `$1`,
+		annotate:    annotateDecade,
+	},
 	{
 		// Decide on numerical uncertainties
 		// Example: 404 or 403 -> 404
 		regex:       regexp.MustCompile(`(\d{1,4})[\s,]*(?:BC)?[\s,]*(/|or)[\s,]*(\d{1,4})[\s,]*(?:BC)?`),
 		replaceWith: `$1`,
+		annotate:    annotateYearAlternatives,
 	},
 	{
-		// Decide on monthly uncertainties
+		// Decide on monthly uncertainties, which may list more than two months
 		// Example: April or May 1521 -> April 1521
-		regex:       withMonths(`(?i)(%s)[\s,]*(/|or)[\s,]*(%s)`),
+		// Example: June, July or August 251 -> June 251
+		regex:       withMonths(`(?i)(%s)((?:[\s,]+(?:%s))*)[\s,]*(/|or)[\s,]*(%s)`),
 		replaceWith: `$1`,
+		annotate:    annotateMonthAlternatives,
 	},
 	{
 		// Remove days of week
@@ -175,10 +221,24 @@ var cleanerPipeline = []struct {
 		// Remove circa variants
 		regex:       regexp.MustCompile(`(?i)[\s,]*(c\.|circ(a)?(\.)?)[\s,]*`),
 		replaceWith: "",
+		annotate:    annotateApproximate,
+	},
+	{
+		// Remove approximation symbols
+		regex:       regexp.MustCompile(`[\s,]*(~|\?)`),
+		replaceWith: "",
+		annotate:    annotateApproximate,
+	},
+	{
+		// Remove seasons
+		// Example: Spring of 551 -> 551
+		regex:       regexp.MustCompile(`(?i)[\s,]*(Spring|Summer|Fall|Autumn|Winter)(?:\sSolstice)?(?:\sof)?[\s,]*(\d{1,4})?`),
+		replaceWith: ` $2`,
+		annotate:    annotateSeason,
 	},
 	{
 		// Remove other kinds of approximations
-		regex:       regexp.MustCompile(`(?i)[\s,]*(~|Between|Spring|Summer|Fall|Autumn|Winter|Early to (Mid|Late)|Mid to Late|Early (days)?|Mid|Late|Solstice|Morning|Noon|Night|(First|Second) half|\?)(\sof)?`),
+		regex:       regexp.MustCompile(`(?i)[\s,]*(Between|Early to (Mid|Late)|Mid to Late|Early (days)?|Mid|Late|Solstice|Morning|Noon|Night|(First|Second) half)(\sof)?`),
 		replaceWith: "",
 	},
 	{
@@ -13,6 +13,8 @@ type formatMatcher struct {
 }
 
 var bcMatcher = regexp.MustCompile(`[\s,]*(B(\.)?C(\.)?E?(\.)?)[\s,]*`)
+var gregorianMatcher = regexp.MustCompile(`(?i)(gregorian|new style|\(N\.?S\.?\))`)
+var julianMatcher = regexp.MustCompile(`(?i)(julian|old style|\(O\.?S\.?\)|\sO\.S\.)`)
 var monthNameMatcher = withMonths(`(?i).*(%s).*`)
 var yearMatcher = regexp.MustCompile(`^(\d{1,4})-?`)
 var monthMatcher = regexp.MustCompile(`^\d{1,4}-(\d{1,2})`)
@@ -97,7 +99,7 @@ func padZeroes(d string) string {
 
 func withMonths(format string) *regexp.Regexp {
 	joined := strings.Join(append(months, shortMonths...), "|")
-	amount := len(regexp.MustCompile(`\(%s\)`).FindAllStringIndex(format, -1))
+	amount := strings.Count(format, "%s")
 
 	var joins []interface{}
 	for i := 0; i < amount; i++ {
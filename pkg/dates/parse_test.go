@@ -15,7 +15,7 @@ func TestDatesParse(t *testing.T) {
 	}{
 		{
 			"~2500 BC",
-			[]dates.Historic{{Year: 2500, IsBCE: true}},
+			[]dates.Historic{{Year: 2500, IsBCE: true, Approximate: true}},
 		},
 		{
 			"211 BC",
@@ -43,39 +43,83 @@ func TestDatesParse(t *testing.T) {
 		},
 		{
 			"404 BC or 403 BC",
-			[]dates.Historic{{Year: 404, IsBCE: true}},
+			[]dates.Historic{{
+				Year:  404,
+				IsBCE: true,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 404, IsBCE: true},
+					Latest:   dates.Historic{Year: 403, IsBCE: true},
+				},
+			}},
 		},
 		{
 			"268 or 269 CE",
-			[]dates.Historic{{Year: 268}},
+			[]dates.Historic{{
+				Year: 268,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 268},
+					Latest:   dates.Historic{Year: 269},
+				},
+			}},
 		},
 		{
 			"Between 1480, or 1483",
-			[]dates.Historic{{Year: 1480}},
+			[]dates.Historic{{
+				Year: 1480,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 1480},
+					Latest:   dates.Historic{Year: 1483},
+				},
+			}},
 		},
 		{
 			"April or May 1521",
-			[]dates.Historic{{Year: 1521, Month: 4}},
+			[]dates.Historic{{
+				Year:  1521,
+				Month: 4,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 1521, Month: 4},
+					Latest:   dates.Historic{Year: 1521, Month: 5},
+				},
+			}},
 		},
 		{
 			"June, July or August 251 CE",
-			[]dates.Historic{{Year: 251, Month: 7}},
+			[]dates.Historic{{
+				Year:  251,
+				Month: 6,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 251, Month: 6},
+					Latest:   dates.Historic{Year: 251, Month: 8},
+				},
+			}},
+		},
+		{
+			"March, April, May or June 1521",
+			[]dates.Historic{{
+				Year:  1521,
+				Month: 3,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 1521, Month: 3},
+					Latest:   dates.Historic{Year: 1521, Month: 6},
+				},
+			}},
 		},
 		{
 			"circa September 9 CE",
-			[]dates.Historic{{Year: 9, Month: 9}},
+			[]dates.Historic{{Year: 9, Month: 9, Approximate: true}},
 		},
 		{
 			"circa. 380 BC",
-			[]dates.Historic{{Year: 380, IsBCE: true}},
+			[]dates.Historic{{Year: 380, IsBCE: true, Approximate: true}},
 		},
 		{
 			"circ. 360 BC",
-			[]dates.Historic{{Year: 360, IsBCE: true}},
+			[]dates.Historic{{Year: 360, IsBCE: true, Approximate: true}},
 		},
 		{
 			"c. 870 AD",
-			[]dates.Historic{{Year: 870}},
+			[]dates.Historic{{Year: 870, Approximate: true}},
 		},
 		{
 			"April 19, 1775",
@@ -103,7 +147,15 @@ func TestDatesParse(t *testing.T) {
 		},
 		{
 			"August/September (Metageitnion), 490 BC",
-			[]dates.Historic{{Year: 490, Month: 8, IsBCE: true}},
+			[]dates.Historic{{
+				Year:  490,
+				Month: 8,
+				IsBCE: true,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 490, Month: 8, IsBCE: true},
+					Latest:   dates.Historic{Year: 490, Month: 9, IsBCE: true},
+				},
+			}},
 		},
 		{
 			"Winter solstice, December 218 BC",
@@ -115,11 +167,11 @@ func TestDatesParse(t *testing.T) {
 		},
 		{
 			"February 11, 1700 (O.S.) February 12, 1700 (Swedish calendar) February 22, 1700 (N.S.)",
-			[]dates.Historic{{Year: 1700, Month: 2, Day: 22}},
+			[]dates.Historic{{Year: 1700, Month: 2, Day: 22, Calendar: dates.GregorianCalendar}},
 		},
 		{
 			"July 8, 1702 (O.S.). July 9, 1702 (Swedish calendar). July 19, 1702 (1702-07-19) (N.S.)",
-			[]dates.Historic{{Year: 1702, Month: 7, Day: 19}},
+			[]dates.Historic{{Year: 1702, Month: 7, Day: 19, Calendar: dates.GregorianCalendar}},
 		},
 		{
 			"1769 – 1821",
@@ -138,7 +190,15 @@ func TestDatesParse(t *testing.T) {
 		{
 			"Spring 218 – 201 BC (17 years)",
 			[]dates.Historic{
-				{Year: 218, IsBCE: true},
+				{
+					Year:      218,
+					IsBCE:     true,
+					Precision: dates.SeasonPrecision,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 218, Month: 3, IsBCE: true},
+						Latest:   dates.Historic{Year: 218, Month: 5, IsBCE: true},
+					},
+				},
 				{Year: 201, IsBCE: true},
 			},
 		},
@@ -146,7 +206,14 @@ func TestDatesParse(t *testing.T) {
 			"550 – spring of 551",
 			[]dates.Historic{
 				{Year: 550},
-				{Year: 551},
+				{
+					Year:      551,
+					Precision: dates.SeasonPrecision,
+					Uncertainty: &dates.Uncertainty{
+						Earliest: dates.Historic{Year: 551, Month: 3},
+						Latest:   dates.Historic{Year: 551, Month: 5},
+					},
+				},
 			},
 		},
 		{
@@ -362,22 +429,22 @@ func TestDatesParse(t *testing.T) {
 		{
 			"18-19 July 1913 (N.S.)(5-6 July in O.S.)",
 			[]dates.Historic{
-				{Year: 1913, Month: 7, Day: 18},
-				{Year: 1913, Month: 7, Day: 19},
+				{Year: 1913, Month: 7, Day: 18, Calendar: dates.GregorianCalendar},
+				{Year: 1913, Month: 7, Day: 19, Calendar: dates.GregorianCalendar},
 			},
 		},
 		{
 			"May 23, 1592 – December 16, 1598 (Gregorian Calendar); April 13, 1592 – November 19, 1598 (Lunar calendar)",
 			[]dates.Historic{
-				{Year: 1592, Month: 5, Day: 23},
-				{Year: 1598, Month: 12, Day: 16},
+				{Year: 1592, Month: 5, Day: 23, Calendar: dates.GregorianCalendar},
+				{Year: 1598, Month: 12, Day: 16, Calendar: dates.GregorianCalendar},
 			},
 		},
 		{
 			"29–30 November 1612 (Julian calendar); 9-10 December 1612 (Gregorian calendar)",
 			[]dates.Historic{
-				{Year: 1612, Month: 12, Day: 9},
-				{Year: 1612, Month: 12, Day: 10},
+				{Year: 1612, Month: 12, Day: 9, Calendar: dates.GregorianCalendar},
+				{Year: 1612, Month: 12, Day: 10, Calendar: dates.GregorianCalendar},
 			},
 		},
 		{
@@ -387,9 +454,75 @@ func TestDatesParse(t *testing.T) {
 		{
 			"September 25 – September 28?, 539 BC",
 			[]dates.Historic{
-				{Year: 539, Month: 9, Day: 25, IsBCE: true},
-				{Year: 539, Month: 9, Day: 28, IsBCE: true},
-			},
+				{Year: 539, Month: 9, Day: 25, IsBCE: true, Approximate: true},
+				{Year: 539, Month: 9, Day: 28, IsBCE: true, Approximate: true},
+			},
+		},
+		{
+			"11 September 1709 (O.S.)",
+			[]dates.Historic{{Year: 1709, Month: 9, Day: 11, Calendar: dates.JulianCalendar}},
+		},
+		{
+			"5 March 1705 (Old Style)",
+			[]dates.Historic{{Year: 1705, Month: 3, Day: 5, Calendar: dates.JulianCalendar}},
+		},
+		{
+			"Winter 1 AD",
+			[]dates.Historic{{
+				Year:      1,
+				Precision: dates.SeasonPrecision,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 1, Month: 12, IsBCE: true},
+					Latest:   dates.Historic{Year: 1, Month: 2},
+				},
+			}},
+		},
+		{
+			"1690s",
+			[]dates.Historic{{
+				Year:      1690,
+				Precision: dates.DecadePrecision,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 1690},
+					Latest:   dates.Historic{Year: 1699},
+				},
+			}},
+		},
+		{
+			"c. 490s BC",
+			[]dates.Historic{{
+				Year:        499,
+				IsBCE:       true,
+				Precision:   dates.DecadePrecision,
+				Approximate: true,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 499, IsBCE: true},
+					Latest:   dates.Historic{Year: 490, IsBCE: true},
+				},
+			}},
+		},
+		{
+			"12th century",
+			[]dates.Historic{{
+				Year:      1101,
+				Precision: dates.CenturyPrecision,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 1101},
+					Latest:   dates.Historic{Year: 1200},
+				},
+			}},
+		},
+		{
+			"Early 5th century BC",
+			[]dates.Historic{{
+				Year:      500,
+				IsBCE:     true,
+				Precision: dates.CenturyPrecision,
+				Uncertainty: &dates.Uncertainty{
+					Earliest: dates.Historic{Year: 500, IsBCE: true},
+					Latest:   dates.Historic{Year: 401, IsBCE: true},
+				},
+			}},
 		},
 	}
 	for _, c := range cases {
@@ -0,0 +1,86 @@
+package dates
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Precision represents how precisely a Historic date is known
+type Precision int
+
+const (
+	// ImpliedPrecision means that the precision of a Historic is implied by which of its Month and
+	// Day values are set. This is the zero value of Precision
+	ImpliedPrecision Precision = iota
+	// DayPrecision represents dates known down to their day
+	DayPrecision
+	// MonthPrecision represents dates known down to their month
+	MonthPrecision
+	// SeasonPrecision represents dates known down to the season (spring, summer, autumn, winter) of
+	// their year
+	SeasonPrecision
+	// YearPrecision represents dates known down to their year
+	YearPrecision
+	// DecadePrecision represents dates known down to their decade
+	DecadePrecision
+	// CenturyPrecision represents dates known down to their century
+	CenturyPrecision
+)
+
+var precisionNames = map[Precision]string{
+	ImpliedPrecision: "",
+	DayPrecision:     "day",
+	MonthPrecision:   "month",
+	SeasonPrecision:  "season",
+	YearPrecision:    "year",
+	DecadePrecision:  "decade",
+	CenturyPrecision: "century",
+}
+
+func (p Precision) String() string {
+	return precisionNames[p]
+}
+
+// MarshalText implements encoding.TextMarshaler, so that precisions are serialized by their names
+func (p Precision) MarshalText() ([]byte, error) {
+	name, ok := precisionNames[p]
+	if !ok {
+		return nil, fmt.Errorf("Unknown precision %d", p)
+	}
+	return []byte(name), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, and is the counterpart of MarshalText
+func (p *Precision) UnmarshalText(text []byte) error {
+	for precision, name := range precisionNames {
+		if name == strings.ToLower(string(text)) {
+			*p = precision
+			return nil
+		}
+	}
+	return fmt.Errorf("Unknown precision %q", text)
+}
+
+// Uncertainty represents the interval of time within which a Historic date is known to have
+// happened, such as the whole century for "5th century BC", or both years for "404 or 403 BC"
+type Uncertainty struct {
+	Earliest Historic `json:"earliest"`
+	Latest   Historic `json:"latest"`
+}
+
+// Earliest returns the earliest date at which the Historic may have happened, which is the
+// beginning of its Uncertainty interval, or the Historic itself if it does not have one
+func (h Historic) Earliest() Historic {
+	if h.Uncertainty != nil {
+		return h.Uncertainty.Earliest
+	}
+	return h
+}
+
+// Latest is the counterpart of Earliest
+func (h Historic) Latest() Historic {
+	if h.Uncertainty != nil {
+		return h.Uncertainty.Latest
+	}
+	return h
+}
@@ -0,0 +1,110 @@
+package flags
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultURL is the URL from which the images of Wikimedia Commons are served. Flags are scraped
+// as paths relative to it
+const DefaultURL = "https://upload.wikimedia.org/wikipedia/commons"
+
+// thumbMatcher matches the paths of thumbnails, capturing the path of their original image. Example:
+// "/thumb/c/c3/Flag_of_France.svg/23px-Flag_of_France.svg.png"
+var thumbMatcher = regexp.MustCompile(`^(.*)/thumb(/.+)/[^/]+$`)
+
+// Original returns the path of the original image of a flag, given the path of any of its
+// thumbnails. Example: "/thumb/c/c3/Flag_of_France.svg/23px-Flag_of_France.svg.png" becomes
+// "/c/c3/Flag_of_France.svg"
+func Original(path string) string {
+	if matches := thumbMatcher.FindStringSubmatch(path); matches != nil {
+		return matches[1] + matches[2]
+	}
+	return path
+}
+
+// URL returns the URL of the original image of a flag. Paths of images that are not part of
+// Wikimedia Commons (such as "//upload.wikimedia.org/wikipedia/en/...") are kept as they are
+func URL(path string) string {
+	original := Original(path)
+	if strings.HasPrefix(original, "//") {
+		return "https:" + original
+	}
+	return DefaultURL + original
+}
+
+// Local returns the location of a flag inside the given directory, and whether it has already been
+// downloaded there. Locations mirror the paths of original images (such as "c/c3/Flag_of_France.svg"),
+// so that different images with the same file name do not overwrite each other. Flags are never
+// stored locally when the directory is empty
+func Local(dir, path string) (string, bool) {
+	if dir == "" || path == "" {
+		return "", false
+	}
+	relative := pathpkg.Clean("/" + strings.TrimLeft(Original(path), "/"))
+	location := filepath.Join(dir, filepath.FromSlash(relative))
+	if _, err := os.Stat(location); err != nil {
+		return location, false
+	}
+	return location, true
+}
+
+// Downloader stores the original images of flags in a directory
+type Downloader struct {
+	baseURL    string
+	dir        string
+	httpClient *http.Client
+}
+
+// NewDownloader creates a new instance of flags.Downloader, that downloads flags from the given URL
+// (usually DefaultURL) into the given directory
+func NewDownloader(baseURL, dir string) *Downloader {
+	return &Downloader{baseURL: baseURL, dir: dir, httpClient: http.DefaultClient}
+}
+
+// Download stores the original image of a flag, unless it was already stored, and returns its
+// location
+func (d *Downloader) Download(path string) (string, error) {
+	location, downloaded := Local(d.dir, path)
+	if location == "" {
+		return "", errors.New("No directory in which to store flags")
+	}
+	if downloaded {
+		return location, nil
+	}
+
+	url := d.baseURL + Original(path)
+	if strings.HasPrefix(Original(path), "//") {
+		url = "https:" + Original(path)
+	}
+	res, err := d.httpClient.Get(url)
+	if err != nil {
+		return "", errors.Wrapf(err, "Downloading flag from %s", url)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Downloading flag from %s: unexpected status %d", url, res.StatusCode)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(location), 0755); err != nil {
+		return "", errors.Wrapf(err, "Creating directory %s", filepath.Dir(location))
+	}
+	out, err := os.Create(location)
+	if err != nil {
+		return "", errors.Wrapf(err, "Creating file for flag from %s", url)
+	}
+	defer out.Close()
+	if _, err := io.Copy(out, res.Body); err != nil {
+		os.Remove(location)
+		return "", errors.Wrapf(err, "Storing flag from %s", url)
+	}
+	return location, nil
+}
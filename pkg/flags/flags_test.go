@@ -0,0 +1,108 @@
+package flags_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sasalatart/batcoms/pkg/flags"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlags(t *testing.T) {
+	t.Run("Original and URL", func(t *testing.T) {
+		cases := []struct {
+			path     string
+			original string
+			url      string
+		}{
+			{
+				path:     "/thumb/c/c3/Flag_of_France.svg/23px-Flag_of_France.svg.png",
+				original: "/c/c3/Flag_of_France.svg",
+				url:      "https://upload.wikimedia.org/wikipedia/commons/c/c3/Flag_of_France.svg",
+			},
+			{
+				path:     "/8/88/Flag_of_Austria.png",
+				original: "/8/88/Flag_of_Austria.png",
+				url:      "https://upload.wikimedia.org/wikipedia/commons/8/88/Flag_of_Austria.png",
+			},
+			{
+				path:     "//upload.wikimedia.org/wikipedia/en/thumb/a/ae/Flag_of_the_United_Kingdom.svg/23px-Flag_of_the_United_Kingdom.svg.png",
+				original: "//upload.wikimedia.org/wikipedia/en/a/ae/Flag_of_the_United_Kingdom.svg",
+				url:      "https://upload.wikimedia.org/wikipedia/en/a/ae/Flag_of_the_United_Kingdom.svg",
+			},
+		}
+		for _, c := range cases {
+			assert.Equal(t, c.original, flags.Original(c.path), "Original of %q", c.path)
+			assert.Equal(t, c.url, flags.URL(c.path), "URL of %q", c.path)
+		}
+	})
+
+	t.Run("Download", func(t *testing.T) {
+		const path = "/thumb/c/c3/Flag_of_France.svg/23px-Flag_of_France.svg.png"
+		image := []byte(`<svg xmlns="http://www.w3.org/2000/svg"/>`)
+		var requested []string
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requested = append(requested, r.URL.Path)
+			w.Write(image)
+		}))
+		defer server.Close()
+
+		_, err := flags.NewDownloader(server.URL, "").Download(path)
+		assert.Error(t, err, "Downloading without a directory")
+
+		tmp, err := ioutil.TempDir("", "flags")
+		require.NoError(t, err, "Creating flags directory")
+		defer os.RemoveAll(tmp)
+		dir := filepath.Join(tmp, "flags")
+
+		_, downloaded := flags.Local(dir, path)
+		assert.False(t, downloaded, "Flag should not be downloaded yet")
+
+		for i := 0; i < 2; i++ {
+			location, err := flags.NewDownloader(server.URL, dir).Download(path)
+			require.NoError(t, err, "Downloading %q", path)
+			assert.Equal(t, filepath.Join(dir, "c", "c3", "Flag_of_France.svg"), location)
+			stored, err := ioutil.ReadFile(location)
+			require.NoError(t, err, "Reading downloaded flag")
+			assert.Equal(t, image, stored)
+		}
+		assert.Equal(t, []string{"/c/c3/Flag_of_France.svg"}, requested, "Flags should be downloaded once")
+
+		_, downloaded = flags.Local(dir, path)
+		assert.True(t, downloaded, "Flag should be downloaded")
+	})
+
+	t.Run("Local", func(t *testing.T) {
+		cases := []struct {
+			path     string
+			location string
+		}{
+			{
+				path:     "/thumb/c/c3/Flag_of_France.svg/23px-Flag_of_France.svg.png",
+				location: filepath.Join("flags", "c", "c3", "Flag_of_France.svg"),
+			},
+			{
+				path:     "//upload.wikimedia.org/wikipedia/en/a/ae/Flag_of_France.svg",
+				location: filepath.Join("flags", "upload.wikimedia.org", "wikipedia", "en", "a", "ae", "Flag_of_France.svg"),
+			},
+			{
+				path:     "/../../etc/passwd",
+				location: filepath.Join("flags", "etc", "passwd"),
+			},
+		}
+		for _, c := range cases {
+			location, downloaded := flags.Local("flags", c.path)
+			assert.Equal(t, c.location, location, "Location of %q", c.path)
+			assert.False(t, downloaded, "%q should not be downloaded", c.path)
+		}
+
+		location, downloaded := flags.Local("", cases[0].path)
+		assert.Empty(t, location, "Location without a directory")
+		assert.False(t, downloaded, "Flags should not be downloaded without a directory")
+	})
+}
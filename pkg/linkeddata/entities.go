@@ -0,0 +1,153 @@
+package linkeddata
+
+import (
+	"strings"
+
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/statistics"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	uuid "github.com/satori/go.uuid"
+)
+
+// Mapper describes factions, commanders and battles as nodes, identified by the IRIs under which the
+// API serves them
+type Mapper struct {
+	baseURL string
+}
+
+// NewMapper returns a pointer to a Mapper whose IRIs start with the given base URL, such as
+// "https://batcoms.example.com"
+func NewMapper(baseURL string) *Mapper {
+	return &Mapper{baseURL: strings.TrimSuffix(baseURL, "/")}
+}
+
+// Faction describes the given faction as a schema.org Organization
+func (m *Mapper) Faction(f factions.Faction) Node {
+	n := m.factionRef(f).Node
+	n.Add("description", f.Summary)
+	n.Add("url", IRI(f.URL))
+	n.Add("owl:sameAs", wikidataIRI(f.Wikidata))
+	return n
+}
+
+// Commander describes the given commander as a schema.org Person. Their allegiances are described
+// by name with the custom vocabulary, given that these need not be factions
+func (m *Mapper) Commander(c commanders.Commander) Node {
+	n := m.commanderRef(c).Node
+	n.Add("description", c.Summary)
+	n.Add("url", IRI(c.URL))
+	n.Add("owl:sameAs", wikidataIRI(c.Wikidata))
+	if c.BirthDate != nil {
+		n.Add("birthDate", Date(*c.BirthDate))
+	}
+	if c.DeathDate != nil {
+		n.Add("deathDate", Date(*c.DeathDate))
+	}
+	for _, a := range c.Allegiances {
+		n.Add("bc:allegiance", a.Name)
+	}
+	return n
+}
+
+// Battle describes the given battle as a schema.org Event. Each of its sides is a bc:Side, with the
+// factions and commanders that fought in it together with its strength and casualties, and its
+// outcome is described with the custom vocabulary too
+func (m *Mapper) Battle(b battles.Battle) Node {
+	id := m.iri("battles", b.ID.String())
+	n := Node{ID: id, Type: "Event"}
+	n.Add("name", b.Name)
+	n.Add("description", b.Summary)
+	n.Add("url", IRI(b.URL))
+	n.Add("owl:sameAs", IRI(b.URL))
+	n.Add("owl:sameAs", wikidataIRI(b.Wikidata))
+	n.Add("startDate", Date(b.StartDate))
+	n.Add("endDate", Date(b.EndDate))
+	if b.Location.Place != "" {
+		place := Node{Type: "Place"}
+		place.Add("name", b.Location.Place)
+		if lat, lon, ok := b.Location.Coordinates(); ok {
+			place.Add("geo", Node{
+				Type: "GeoCoordinates",
+				Properties: []Property{
+					{Term: "latitude", Value: lat},
+					{Term: "longitude", Value: lon},
+				},
+			})
+		}
+		n.Add("location", place)
+	}
+	n.Add("bc:partOf", b.PartOf)
+	n.Add("bc:outcome", b.Result)
+	n.Add("bc:territorialChanges", b.TerritorialChanges)
+	n.Add("bc:totalStrength", b.Strength.AB)
+	n.Add("bc:totalCasualties", b.Casualties.AB)
+
+	sides := b.Sides()
+	sideA := m.side(id+"#sideA", "A", sides.A, b.Strength, b.Casualties)
+	sideB := m.side(id+"#sideB", "B", sides.B, b.Strength, b.Casualties)
+	sideA.Add("bc:opponent", IRI(sideB.ID))
+	sideB.Add("bc:opponent", IRI(sideA.ID))
+	n.Add("bc:side", sideA)
+	n.Add("bc:side", sideB)
+	return n
+}
+
+// side describes one of the sides of a battle, whose factions and commanders are referred to
+func (m *Mapper) side(id, label string, s battles.Side, strength, casualties statistics.SideNumbers) Node {
+	n := Node{ID: id, Type: "bc:Side"}
+	n.Add("bc:label", label)
+	if label == "A" {
+		n.Add("bc:strength", strength.A)
+		n.Add("bc:casualties", casualties.A)
+	} else {
+		n.Add("bc:strength", strength.B)
+		n.Add("bc:casualties", casualties.B)
+	}
+	seen := make(map[uuid.UUID]bool)
+	addCommander := func(c commanders.Commander) {
+		if !seen[c.ID] {
+			seen[c.ID] = true
+			n.Add("bc:commander", m.commanderRef(c))
+		}
+	}
+	for _, f := range s.Factions {
+		n.Add("bc:faction", m.factionRef(f.Faction))
+		for _, c := range f.Commanders {
+			addCommander(c.Commander)
+		}
+	}
+	for _, c := range s.UnassignedCommanders {
+		addCommander(c)
+	}
+	return n
+}
+
+// factionRef refers to the given faction by its IRI, name and Wikipedia URL
+func (m *Mapper) factionRef(f factions.Faction) Reference {
+	n := Node{ID: m.iri("factions", f.ID.String()), Type: "Organization"}
+	n.Add("name", f.Name)
+	n.Add("owl:sameAs", IRI(f.URL))
+	return Reference{n}
+}
+
+// commanderRef refers to the given commander by its IRI, name and Wikipedia URL
+func (m *Mapper) commanderRef(c commanders.Commander) Reference {
+	n := Node{ID: m.iri("commanders", c.ID.String()), Type: "Person"}
+	n.Add("name", c.Name)
+	n.Add("owl:sameAs", IRI(c.URL))
+	return Reference{n}
+}
+
+func (m *Mapper) iri(collection, id string) string {
+	return m.baseURL + "/" + collection + "/" + id
+}
+
+// wikidataIRI returns the IRI of the Wikidata item described by the given facts, if any
+func wikidataIRI(facts *wikidata.Facts) IRI {
+	if facts == nil || facts.QID == "" {
+		return ""
+	}
+	return IRI("http://www.wikidata.org/entity/" + facts.QID)
+}
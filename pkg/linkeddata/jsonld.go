@@ -0,0 +1,67 @@
+package linkeddata
+
+// context is the JSON-LD context of all documents, under which terms of schema.org need no prefix
+var context = map[string]string{
+	"@vocab": SchemaOrg,
+	"bc":     Vocab,
+	"owl":    OWL,
+	"xsd":    XSD,
+}
+
+// Document returns the JSON-LD document that describes the given nodes, ready to be serialized as
+// JSON. A single node is described at the top level of the document, while many of them are
+// described in its "@graph"
+func Document(nodes ...Node) map[string]interface{} {
+	if len(nodes) == 1 {
+		doc := compact(nodes[0])
+		doc["@context"] = context
+		return doc
+	}
+	graph := make([]map[string]interface{}, 0, len(nodes))
+	for _, n := range nodes {
+		graph = append(graph, compact(n))
+	}
+	return map[string]interface{}{
+		"@context": context,
+		"@graph":   graph,
+	}
+}
+
+// compact returns the JSON-LD object of the given node. Terms with many values are rendered as
+// arrays of them
+func compact(n Node) map[string]interface{} {
+	obj := make(map[string]interface{})
+	if n.ID != "" {
+		obj["@id"] = n.ID
+	}
+	if n.Type != "" {
+		obj["@type"] = n.Type
+	}
+	for _, p := range n.Properties {
+		value := compactValue(p.Value)
+		switch existing := obj[p.Term].(type) {
+		case nil:
+			obj[p.Term] = value
+		case []interface{}:
+			obj[p.Term] = append(existing, value)
+		default:
+			obj[p.Term] = []interface{}{existing, value}
+		}
+	}
+	return obj
+}
+
+func compactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case IRI:
+		return map[string]string{"@id": string(v)}
+	case Literal:
+		return map[string]string{"@value": v.Value, "@type": v.Datatype}
+	case Node:
+		return compact(v)
+	case Reference:
+		return compact(v.Node)
+	default:
+		return v
+	}
+}
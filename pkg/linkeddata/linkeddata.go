@@ -0,0 +1,97 @@
+package linkeddata
+
+import (
+	"github.com/sasalatart/batcoms/pkg/dates"
+)
+
+// MIMEType is the media type of JSON-LD documents
+const MIMEType = "application/ld+json"
+
+// Namespaces of the vocabularies used to describe factions, commanders and battles. Terms of
+// schema.org are written without a prefix, while those of the others are prefixed as in Prefixes
+const (
+	SchemaOrg = "https://schema.org/"
+	Vocab     = "https://github.com/sasalatart/batcoms/vocab#"
+	OWL       = "http://www.w3.org/2002/07/owl#"
+	RDF       = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	XSD       = "http://www.w3.org/2001/XMLSchema#"
+)
+
+// Prefixes maps the prefixes of terms to the namespaces they stand for
+var Prefixes = map[string]string{
+	"schema": SchemaOrg,
+	"bc":     Vocab,
+	"owl":    OWL,
+	"rdf":    RDF,
+	"xsd":    XSD,
+}
+
+// Node is a resource described by its properties. Nodes without an ID are blank nodes, which are
+// only described where they appear
+type Node struct {
+	ID         string
+	Type       string
+	Properties []Property
+}
+
+// Property is a value of a node for the given term. Values may be strings, float64s, IRIs, Literals,
+// nested Nodes or References to nodes described elsewhere
+type Property struct {
+	Term  string
+	Value interface{}
+}
+
+// IRI is a value that refers to a resource by its IRI
+type IRI string
+
+// Literal is a value of the given datatype, which is a term such as "xsd:date"
+type Literal struct {
+	Value    string
+	Datatype string
+}
+
+// Reference is a value that refers to a node described elsewhere. JSON-LD documents embed it as it
+// is, while RDF dumps only refer to it by its ID
+type Reference struct {
+	Node
+}
+
+// Add appends a property with the given term and value to the node, unless the value is empty
+func (n *Node) Add(term string, value interface{}) {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return
+		}
+	case IRI:
+		if v == "" {
+			return
+		}
+	case nil:
+		return
+	}
+	n.Properties = append(n.Properties, Property{Term: term, Value: value})
+}
+
+// Date returns the given date as a literal in ISO 8601, whose datatype depends on whether its month
+// and day are known
+func Date(h dates.Historic) Literal {
+	switch {
+	case h.Month == 0:
+		return Literal{Value: h.ISO(), Datatype: "xsd:gYear"}
+	case h.Day == 0:
+		return Literal{Value: h.ISO(), Datatype: "xsd:gYearMonth"}
+	default:
+		return Literal{Value: h.ISO(), Datatype: "xsd:date"}
+	}
+}
+
+// expand returns the full IRI of the given term
+func expand(term string) string {
+	for prefix, namespace := range Prefixes {
+		if len(term) > len(prefix) && term[:len(prefix)+1] == prefix+":" {
+			return namespace + term[len(prefix)+1:]
+		}
+	}
+	return SchemaOrg + term
+}
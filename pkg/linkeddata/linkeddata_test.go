@@ -0,0 +1,222 @@
+package linkeddata_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/battles"
+	"github.com/sasalatart/batcoms/domain/commanders"
+	"github.com/sasalatart/batcoms/domain/factions"
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/domain/statistics"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/linkeddata"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	uuid "github.com/satori/go.uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseURL = "https://batcoms.example.com"
+
+var (
+	factionID   = uuid.FromStringOrNil("6c1d5bc4-5d3b-4a37-8e3a-7a4f1f2b1c01")
+	faction2ID  = uuid.FromStringOrNil("6c1d5bc4-5d3b-4a37-8e3a-7a4f1f2b1c02")
+	commanderID = uuid.FromStringOrNil("6c1d5bc4-5d3b-4a37-8e3a-7a4f1f2b1c03")
+	battleID    = uuid.FromStringOrNil("6c1d5bc4-5d3b-4a37-8e3a-7a4f1f2b1c04")
+)
+
+func faction() factions.Faction {
+	return factions.Faction{
+		ID:       factionID,
+		URL:      "https://en.wikipedia.org/wiki/First_French_Empire",
+		Name:     "First French Empire",
+		Summary:  "The First French Empire was the empire ruled by Napoleon.",
+		Wikidata: &wikidata.Facts{QID: "Q71084"},
+	}
+}
+
+func faction2() factions.Faction {
+	return factions.Faction{
+		ID:   faction2ID,
+		URL:  "https://en.wikipedia.org/wiki/Russian_Empire",
+		Name: "Russian Empire",
+	}
+}
+
+func commander() commanders.Commander {
+	birthDate := dates.Historic{Year: 1769, Month: 8, Day: 15}
+	deathDate := dates.Historic{Year: 1821, Month: 5}
+	return commanders.Commander{
+		ID:          commanderID,
+		URL:         "https://en.wikipedia.org/wiki/Napoleon",
+		Name:        "Napoleon",
+		BirthDate:   &birthDate,
+		DeathDate:   &deathDate,
+		Allegiances: []commanders.Allegiance{{Name: "France"}},
+	}
+}
+
+func battle() battles.Battle {
+	return battles.Battle{
+		ID:        battleID,
+		URL:       "https://en.wikipedia.org/wiki/Battle_of_Austerlitz",
+		Name:      "Battle of Austerlitz",
+		StartDate: dates.Historic{Year: 1805, Month: 12, Day: 2},
+		EndDate:   dates.Historic{Year: 1805, Month: 12, Day: 2},
+		Location: locations.Location{
+			Place:     "Austerlitz, Moravia",
+			Latitude:  "49°8′N",
+			Longitude: "16°46′E",
+		},
+		Result:     "Decisive French victory",
+		Strength:   statistics.SideNumbers{A: "65,000–75,000", B: "84,000–95,000"},
+		Casualties: statistics.SideNumbers{A: "1,305 killed"},
+		Factions: battles.FactionsBySide{
+			A: []factions.Faction{faction()},
+			B: []factions.Faction{faction2()},
+		},
+		Commanders: battles.CommandersBySide{
+			A: []commanders.Commander{commander()},
+		},
+		CommandersByFaction: battles.CommandersByFaction{
+			factionID: []uuid.UUID{commanderID},
+		},
+		Wikidata: &wikidata.Facts{QID: "Q134114"},
+	}
+}
+
+func TestDocument(t *testing.T) {
+	m := linkeddata.NewMapper(baseURL + "/")
+
+	t.Run("Single", func(t *testing.T) {
+		doc, err := json.Marshal(linkeddata.Document(m.Commander(commander())))
+		require.NoError(t, err, "Encoding document")
+		assert.JSONEq(t, `{
+			"@context": {
+				"@vocab": "https://schema.org/",
+				"bc": "https://github.com/sasalatart/batcoms/vocab#",
+				"owl": "http://www.w3.org/2002/07/owl#",
+				"xsd": "http://www.w3.org/2001/XMLSchema#"
+			},
+			"@id": "https://batcoms.example.com/commanders/6c1d5bc4-5d3b-4a37-8e3a-7a4f1f2b1c03",
+			"@type": "Person",
+			"name": "Napoleon",
+			"owl:sameAs": {"@id": "https://en.wikipedia.org/wiki/Napoleon"},
+			"url": {"@id": "https://en.wikipedia.org/wiki/Napoleon"},
+			"birthDate": {"@value": "1769-08-15", "@type": "xsd:date"},
+			"deathDate": {"@value": "1821-05", "@type": "xsd:gYearMonth"},
+			"bc:allegiance": "France"
+		}`, string(doc))
+	})
+
+	t.Run("Graph", func(t *testing.T) {
+		doc := linkeddata.Document(m.Faction(faction()), m.Faction(faction2()))
+		graph, ok := doc["@graph"].([]map[string]interface{})
+		require.True(t, ok, "Document should describe the nodes in its graph")
+		require.Len(t, graph, 2)
+		assert.Equal(t, []interface{}{
+			map[string]string{"@id": faction().URL},
+			map[string]string{"@id": "http://www.wikidata.org/entity/Q71084"},
+		}, graph[0]["owl:sameAs"], "Repeated terms should be rendered as arrays")
+		assert.Equal(t, map[string]string{"@id": faction2().URL}, graph[1]["owl:sameAs"])
+	})
+
+	t.Run("Battle", func(t *testing.T) {
+		doc := linkeddata.Document(m.Battle(battle()))
+		id := baseURL + "/battles/" + battleID.String()
+		assert.Equal(t, id, doc["@id"])
+		assert.Equal(t, "Event", doc["@type"])
+		assert.Equal(t, map[string]string{"@value": "1805-12-02", "@type": "xsd:date"}, doc["startDate"])
+		assert.Equal(t, "Decisive French victory", doc["bc:outcome"])
+
+		location := doc["location"].(map[string]interface{})
+		geo := location["geo"].(map[string]interface{})
+		assert.InDelta(t, 49.1333, geo["latitude"], 0.001)
+		assert.InDelta(t, 16.7667, geo["longitude"], 0.001)
+
+		sides := doc["bc:side"].([]interface{})
+		require.Len(t, sides, 2)
+		sideA := sides[0].(map[string]interface{})
+		assert.Equal(t, id+"#sideA", sideA["@id"])
+		assert.Equal(t, "bc:Side", sideA["@type"])
+		assert.Equal(t, "65,000–75,000", sideA["bc:strength"])
+		assert.Equal(t, map[string]string{"@id": id + "#sideB"}, sideA["bc:opponent"])
+		assert.Equal(t, baseURL+"/factions/"+factionID.String(), sideA["bc:faction"].(map[string]interface{})["@id"])
+		assert.Equal(t, baseURL+"/commanders/"+commanderID.String(), sideA["bc:commander"].(map[string]interface{})["@id"])
+		sideB := sides[1].(map[string]interface{})
+		assert.NotContains(t, sideB, "bc:casualties", "Unknown numbers should be left out")
+		assert.NotContains(t, sideB, "bc:commander")
+	})
+}
+
+func TestWriter(t *testing.T) {
+	m := linkeddata.NewMapper(baseURL)
+
+	t.Run("Turtle", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := linkeddata.NewWriter(&buf, linkeddata.FormatTurtle)
+		require.NoError(t, err)
+		w.Write(m.Faction(faction2()))
+		w.Write(m.Commander(commander()))
+		require.NoError(t, w.Flush())
+		assert.Equal(t, `@prefix bc: <https://github.com/sasalatart/batcoms/vocab#> .
+@prefix owl: <http://www.w3.org/2002/07/owl#> .
+@prefix rdf: <http://www.w3.org/1999/02/22-rdf-syntax-ns#> .
+@prefix schema: <https://schema.org/> .
+@prefix xsd: <http://www.w3.org/2001/XMLSchema#> .
+
+<https://batcoms.example.com/factions/6c1d5bc4-5d3b-4a37-8e3a-7a4f1f2b1c02> a schema:Organization ;
+    schema:name "Russian Empire" ;
+    owl:sameAs <https://en.wikipedia.org/wiki/Russian_Empire> ;
+    schema:url <https://en.wikipedia.org/wiki/Russian_Empire> .
+
+<https://batcoms.example.com/commanders/6c1d5bc4-5d3b-4a37-8e3a-7a4f1f2b1c03> a schema:Person ;
+    schema:name "Napoleon" ;
+    owl:sameAs <https://en.wikipedia.org/wiki/Napoleon> ;
+    schema:url <https://en.wikipedia.org/wiki/Napoleon> ;
+    schema:birthDate "1769-08-15"^^xsd:date ;
+    schema:deathDate "1821-05"^^xsd:gYearMonth ;
+    bc:allegiance "France" .
+
+`, buf.String())
+	})
+
+	t.Run("NTriples", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := linkeddata.NewWriter(&buf, linkeddata.FormatNTriples)
+		require.NoError(t, err)
+		w.Write(m.Battle(battle()))
+		require.NoError(t, w.Flush())
+
+		id := "<" + baseURL + "/battles/" + battleID.String() + ">"
+		dump := buf.String()
+		assert.Contains(t, dump, id+" <http://www.w3.org/1999/02/22-rdf-syntax-ns#type> <https://schema.org/Event> .\n")
+		assert.Contains(t, dump, id+" <http://www.w3.org/2002/07/owl#sameAs> <https://en.wikipedia.org/wiki/Battle_of_Austerlitz> .\n")
+		assert.Contains(t, dump, id+" <http://www.w3.org/2002/07/owl#sameAs> <http://www.wikidata.org/entity/Q134114> .\n")
+		assert.Contains(t, dump, id+` <https://schema.org/startDate> "1805-12-02"^^<http://www.w3.org/2001/XMLSchema#date> .`+"\n")
+		assert.Contains(t, dump, id+" <https://github.com/sasalatart/batcoms/vocab#side> <"+baseURL+"/battles/"+battleID.String()+"#sideA> .\n")
+		assert.Contains(t, dump, `<https://schema.org/latitude> "49.13333333333333"^^<http://www.w3.org/2001/XMLSchema#double> .`+"\n")
+		assert.NotContains(t, dump, "@prefix")
+		assert.NotContains(t, dump, `"First French Empire"`, "Referenced factions should not be described")
+	})
+
+	t.Run("Escaping", func(t *testing.T) {
+		var buf bytes.Buffer
+		w, err := linkeddata.NewWriter(&buf, linkeddata.FormatNTriples)
+		require.NoError(t, err)
+		f := faction2()
+		f.URL = "https://en.wikipedia.org/wiki/Kingdom of <Naples>"
+		f.Name = "The \"Kingdom\"\nof Naples"
+		w.Write(m.Faction(f))
+		require.NoError(t, w.Flush())
+		assert.Contains(t, buf.String(), `<https://en.wikipedia.org/wiki/Kingdom%20of%20%3CNaples%3E>`)
+		assert.Contains(t, buf.String(), `"The \"Kingdom\"\nof Naples"`)
+	})
+
+	t.Run("UnknownFormat", func(t *testing.T) {
+		_, err := linkeddata.NewWriter(&bytes.Buffer{}, "rdfxml")
+		assert.EqualError(t, err, `Unknown RDF format "rdfxml", must be turtle or ntriples`)
+	})
+}
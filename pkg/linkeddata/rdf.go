@@ -0,0 +1,162 @@
+package linkeddata
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Formats in which RDF dumps may be written
+const (
+	FormatTurtle   = "turtle"
+	FormatNTriples = "ntriples"
+)
+
+// Writer writes nodes as RDF, in Turtle or N-Triples. Each node is written as the triples that
+// describe it and the nodes nested in it, except for References, whose IRI is written instead
+type Writer struct {
+	w           *bufio.Writer
+	format      string
+	blankNodes  int
+	wroteHeader bool
+}
+
+// NewWriter returns a pointer to a Writer that writes to w in the given format
+func NewWriter(w io.Writer, format string) (*Writer, error) {
+	if format != FormatTurtle && format != FormatNTriples {
+		return nil, errors.Errorf("Unknown RDF format %q, must be %s or %s", format, FormatTurtle, FormatNTriples)
+	}
+	return &Writer{w: bufio.NewWriter(w), format: format}, nil
+}
+
+// Write writes the triples that describe the given node. Errors writing them are returned by Flush
+func (w *Writer) Write(n Node) {
+	if w.format == FormatTurtle && !w.wroteHeader {
+		w.wroteHeader = true
+		prefixes := make([]string, 0, len(Prefixes))
+		for prefix := range Prefixes {
+			prefixes = append(prefixes, prefix)
+		}
+		sort.Strings(prefixes)
+		for _, prefix := range prefixes {
+			fmt.Fprintf(w.w, "@prefix %s: <%s> .\n", prefix, Prefixes[prefix])
+		}
+		w.w.WriteString("\n")
+	}
+	w.describe(n, w.subject(n))
+}
+
+// Flush writes any buffered data to the underlying io.Writer
+func (w *Writer) Flush() error {
+	return w.w.Flush()
+}
+
+// describe writes the triples of the given node as the given subject, followed by those of the nodes
+// nested in it
+func (w *Writer) describe(n Node, subject string) {
+	type nested struct {
+		node    Node
+		subject string
+	}
+	var pending []nested
+	var objects [][2]string
+	if n.Type != "" {
+		objects = append(objects, [2]string{w.predicate("rdf:type"), w.term(n.Type)})
+	}
+	for _, p := range n.Properties {
+		var object string
+		switch v := p.Value.(type) {
+		case Node:
+			object = w.subject(v)
+			pending = append(pending, nested{v, object})
+		case Reference:
+			object = w.subject(v.Node)
+		default:
+			object = w.object(v)
+		}
+		objects = append(objects, [2]string{w.predicate(p.Term), object})
+	}
+
+	if w.format == FormatNTriples {
+		for _, o := range objects {
+			fmt.Fprintf(w.w, "%s %s %s .\n", subject, o[0], o[1])
+		}
+	} else if len(objects) > 0 {
+		w.w.WriteString(subject)
+		for i, o := range objects {
+			separator := " ;\n   "
+			if i == 0 {
+				separator = ""
+			}
+			fmt.Fprintf(w.w, "%s %s %s", separator, o[0], o[1])
+		}
+		w.w.WriteString(" .\n\n")
+	}
+	for _, p := range pending {
+		w.describe(p.node, p.subject)
+	}
+}
+
+// subject returns how the given node is written as the subject or object of triples, which is a
+// new blank node label for those without an ID
+func (w *Writer) subject(n Node) string {
+	if n.ID != "" {
+		return iriRef(n.ID)
+	}
+	w.blankNodes++
+	return fmt.Sprintf("_:b%d", w.blankNodes)
+}
+
+func (w *Writer) predicate(term string) string {
+	if w.format == FormatTurtle && term == "rdf:type" {
+		return "a"
+	}
+	return w.term(term)
+}
+
+// term returns how the given term is written: prefixed in Turtle, and as a full IRI in N-Triples
+func (w *Writer) term(term string) string {
+	if w.format == FormatNTriples {
+		return iriRef(expand(term))
+	}
+	if strings.Contains(term, ":") {
+		return term
+	}
+	return "schema:" + term
+}
+
+func (w *Writer) object(value interface{}) string {
+	switch v := value.(type) {
+	case IRI:
+		return iriRef(string(v))
+	case Literal:
+		return quote(v.Value) + "^^" + w.term(v.Datatype)
+	case float64:
+		return quote(strconv.FormatFloat(v, 'f', -1, 64)) + "^^" + w.term("xsd:double")
+	default:
+		return quote(fmt.Sprint(v))
+	}
+}
+
+// iriRef returns the given IRI enclosed in angle brackets, with the characters that may not appear
+// in them percent-encoded
+func iriRef(iri string) string {
+	return "<" + iriEscaper.Replace(iri) + ">"
+}
+
+var iriEscaper = strings.NewReplacer(
+	" ", "%20", "<", "%3C", ">", "%3E", `"`, "%22", "{", "%7B", "}", "%7D",
+	"|", "%7C", "^", "%5E", "`", "%60", `\`, "%5C",
+)
+
+// quote returns the given string as a quoted literal, escaped as both Turtle and N-Triples require
+func quote(s string) string {
+	return `"` + literalEscaper.Replace(s) + `"`
+}
+
+var literalEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
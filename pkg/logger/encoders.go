@@ -0,0 +1,132 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Entry is a single log entry, as passed to an Encoder
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  []Field
+}
+
+// Encoder turns entries into lines. The returned bytes must not include the trailing newline
+type Encoder interface {
+	Encode(e Entry) []byte
+}
+
+// EncoderFor returns the Encoder for the given format, which may be "console" or "json"
+func EncoderFor(format string) (Encoder, error) {
+	switch format {
+	case "", "console":
+		return ConsoleEncoder{}, nil
+	case "json":
+		return JSONEncoder{}, nil
+	default:
+		return nil, fmt.Errorf("Unknown log format %q, must be console or json", format)
+	}
+}
+
+// JSONEncoder encodes each entry as a JSON object with its time, level, message and fields, in
+// that order. Fields are encoded with encoding/json, except for errors, which are encoded by their
+// message. When many fields share a key, only the last one is kept
+type JSONEncoder struct{}
+
+// Encode encodes an entry as a JSON object
+func (JSONEncoder) Encode(e Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	writeJSON(&buf, e.Time.UTC().Format(time.RFC3339Nano))
+	buf.WriteString(`,"level":`)
+	writeJSON(&buf, e.Level.String())
+	buf.WriteString(`,"msg":`)
+	writeJSON(&buf, e.Message)
+	for _, f := range uniqueFields(e.Fields) {
+		buf.WriteByte(',')
+		writeJSON(&buf, f.Key)
+		buf.WriteByte(':')
+		writeJSON(&buf, fieldValue(f.Value))
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+func writeJSON(buf *bytes.Buffer, value interface{}) {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		encoded, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(encoded)
+}
+
+// ConsoleEncoder encodes each entry as a human-readable line with its time, level, message and
+// fields, which are written as key=value pairs
+type ConsoleEncoder struct{}
+
+// Encode encodes an entry as a human-readable line
+func (ConsoleEncoder) Encode(e Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(e.Time.Format("2006-01-02T15:04:05.000Z07:00"))
+	buf.WriteByte(' ')
+	buf.WriteString(fmt.Sprintf("%-5s", strings.ToUpper(e.Level.String())))
+	buf.WriteByte(' ')
+	buf.Write(messageEncoder{}.Encode(e))
+	return buf.Bytes()
+}
+
+// messageEncoder encodes each entry as its message followed by its fields, which are written as
+// key=value pairs. Its time and level are left to whoever writes the line
+type messageEncoder struct{}
+
+// Encode encodes the message and fields of an entry
+func (messageEncoder) Encode(e Entry) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(e.Message)
+	for _, f := range uniqueFields(e.Fields) {
+		buf.WriteByte(' ')
+		buf.WriteString(f.Key)
+		buf.WriteByte('=')
+		buf.WriteString(consoleValue(fieldValue(f.Value)))
+	}
+	return buf.Bytes()
+}
+
+func consoleValue(value interface{}) string {
+	text := fmt.Sprint(value)
+	if text == "" || strings.ContainsAny(text, " =\"\t\r\n") {
+		return strconv.Quote(text)
+	}
+	return text
+}
+
+func fieldValue(value interface{}) interface{} {
+	if err, ok := value.(error); ok {
+		return err.Error()
+	}
+	return value
+}
+
+// uniqueFields returns the given fields without those whose keys are repeated later on
+func uniqueFields(fields []Field) []Field {
+	res := make([]Field, 0, len(fields))
+	for i, f := range fields {
+		repeated := false
+		for _, later := range fields[i+1:] {
+			if later.Key == f.Key {
+				repeated = true
+				break
+			}
+		}
+		if !repeated {
+			res = append(res, f)
+		}
+	}
+	return res
+}
@@ -0,0 +1,39 @@
+package logger
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+)
+
+// Field is a key-value pair attached to log entries, so that these may be filtered and aggregated
+// by it, such as "url", "wikiID" or "duration_ms"
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// KV returns a Field with the given key and value
+func KV(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// ErrorKindKey is the key of the field that classifies errors logged with Logger.Error
+const ErrorKindKey = "error_kind"
+
+// errorKind classifies an error by its cause: known domain errors are classified by their message,
+// which does not change, and any other cause by its type. Errors created on the fly with
+// errors.New or fmt.Errorf are not classified, as their types say nothing about them
+func errorKind(err error) string {
+	cause := errors.Cause(err)
+	if de, ok := cause.(domain.Error); ok {
+		return string(de)
+	}
+	switch kind := fmt.Sprintf("%T", cause); kind {
+	case "*errors.fundamental", "*errors.errorString":
+		return ""
+	default:
+		return kind
+	}
+}
@@ -0,0 +1,44 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Level is the severity of a log entry. Loggers discard the entries below their level
+type Level int8
+
+const (
+	// DebugLevel is used for detailed entries that are only useful when diagnosing problems
+	DebugLevel Level = iota
+	// InfoLevel is used for entries that describe the normal operation of a process
+	InfoLevel
+	// WarnLevel is used for entries that describe unexpected situations that could be handled
+	WarnLevel
+	// ErrorLevel is used for entries that describe operations that failed
+	ErrorLevel
+)
+
+var levelNames = map[Level]string{
+	DebugLevel: "debug",
+	InfoLevel:  "info",
+	WarnLevel:  "warn",
+	ErrorLevel: "error",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("level(%d)", l)
+}
+
+// ParseLevel returns the Level with the given name, which may be debug, info, warn or error
+func ParseLevel(name string) (Level, error) {
+	for l, n := range levelNames {
+		if strings.EqualFold(name, n) {
+			return l, nil
+		}
+	}
+	return InfoLevel, fmt.Errorf("Unknown log level %q, must be debug, info, warn or error", name)
+}
@@ -1,9 +1,12 @@
 package logger
 
 import (
-	"fmt"
 	"io"
 	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
 )
 
 // Interface defines behaviour for "info" and "error" logging
@@ -12,28 +15,162 @@ type Interface interface {
 	Error(err error)
 }
 
-type logger struct {
-	infoWriter io.Writer
-	errWriter  io.Writer
+var (
+	defaultLevel           = InfoLevel
+	defaultEncoder Encoder = ConsoleEncoder{}
+)
+
+// Configure sets the level and format (console or json) of the loggers created from then onwards
+// with New, so that every process logs in the way its environment expects
+func Configure(level, format string) error {
+	l, err := ParseLevel(level)
+	if err != nil {
+		return err
+	}
+	e, err := EncoderFor(format)
+	if err != nil {
+		return err
+	}
+	defaultLevel, defaultEncoder = l, e
+	return nil
+}
+
+// Logger writes levelled entries, each one of them in a single line. Debug and info entries are
+// written into one io.Writer, and warn and error entries into another one. Fields attached with
+// With are included in every entry written by the returned child Logger
+type Logger struct {
+	core   *core
+	fields []Field
+}
+
+// core is shared by a Logger and all its children, so that lines written concurrently by any of them
+// are not interleaved
+type core struct {
+	mu      sync.Mutex
+	level   Level
+	encoder Encoder
+	now     func() time.Time
+	write   func(level Level, line []byte)
+}
+
+// New returns a *Logger, which satisfies logger.Interface, by using two different io.Writer values:
+// one for debug and info logs and another one for warn and error logs. Its level and format are
+// those set with Configure
+func New(infoWriter, errWriter io.Writer) *Logger {
+	return NewEncoded(infoWriter, errWriter, defaultEncoder, defaultLevel)
+}
+
+// NewEncoded is like New, but uses the given encoder and level instead of the configured ones
+func NewEncoded(infoWriter, errWriter io.Writer, encoder Encoder, level Level) *Logger {
+	return &Logger{core: &core{
+		level:   level,
+		encoder: encoder,
+		now:     time.Now,
+		write: func(l Level, line []byte) {
+			w := infoWriter
+			if l >= WarnLevel {
+				w = errWriter
+			}
+			w.Write(append(line, '\n'))
+		},
+	}}
+}
+
+// NewDiscard returns a *Logger which discards every commanded write
+func NewDiscard() *Logger {
+	return NewEncoded(ioutil.Discard, ioutil.Discard, defaultEncoder, ErrorLevel+1)
 }
 
-// New returns a logger.Interface instance by using two different io.Writer values: one for info
-// logs and another one for error logs
-func New(infoWriter, errWriter io.Writer) Interface {
-	return logger{infoWriter: infoWriter, errWriter: errWriter}
+// From returns l itself if it already is a *Logger. Otherwise, it returns a *Logger that passes the
+// message and fields of its entries through l, which formats them as it pleases, so that packages
+// may log with fields while still accepting any implementation of logger.Interface
+func From(l Interface) *Logger {
+	if logger, ok := l.(*Logger); ok {
+		return logger
+	}
+	return &Logger{core: &core{
+		level:   defaultLevel,
+		encoder: messageEncoder{},
+		now:     time.Now,
+		write: func(level Level, line []byte) {
+			if level >= ErrorLevel {
+				l.Error(errors.New(string(line)))
+				return
+			}
+			l.Info(string(line))
+		},
+	}}
 }
 
-// NewDiscard returns a logger.Interface compatible logger which discards every commanded write
-func NewDiscard() Interface {
-	return New(ioutil.Discard, ioutil.Discard)
+// With returns a child Logger that includes the given fields in every one of its entries
+func (l *Logger) With(fields ...Field) *Logger {
+	child := &Logger{core: l.core, fields: make([]Field, 0, len(l.fields)+len(fields))}
+	child.fields = append(child.fields, l.fields...)
+	child.fields = append(child.fields, fields...)
+	return child
 }
 
-// Info writes a text message into the service's info io.Writer
-func (s logger) Info(text string) {
-	s.infoWriter.Write([]byte(text))
+// Named returns a child Logger for the given component, such as "seeder" or "http"
+func (l *Logger) Named(component string) *Logger {
+	return l.With(KV("component", component))
 }
 
-// Error writes an error message into the service's error io.Writer
-func (s logger) Error(err error) {
-	s.errWriter.Write([]byte(fmt.Sprintln(err)))
+// Enabled reports whether entries of the given level are written, so that callers may avoid
+// computing expensive fields for entries that would be discarded
+func (l *Logger) Enabled(level Level) bool {
+	return level >= l.core.level
+}
+
+// Debug writes a debug entry with the given message
+func (l *Logger) Debug(text string) {
+	l.log(DebugLevel, text, nil)
+}
+
+// Info writes an info entry with the given message
+func (l *Logger) Info(text string) {
+	l.log(InfoLevel, text, nil)
+}
+
+// Warn writes a warn entry with the given message
+func (l *Logger) Warn(text string) {
+	l.log(WarnLevel, text, nil)
+}
+
+// Error writes an error entry with the message of err. Unless the Logger already has one, an
+// error_kind field classifying the cause of err is also included
+func (l *Logger) Error(err error) {
+	if err == nil {
+		return
+	}
+	var extra []Field
+	if !l.has(ErrorKindKey) {
+		if kind := errorKind(err); kind != "" {
+			extra = append(extra, KV(ErrorKindKey, kind))
+		}
+	}
+	l.log(ErrorLevel, err.Error(), extra)
+}
+
+func (l *Logger) has(key string) bool {
+	for _, f := range l.fields {
+		if f.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Logger) log(level Level, text string, extra []Field) {
+	if !l.Enabled(level) {
+		return
+	}
+	fields := l.fields
+	if len(extra) > 0 {
+		fields = append(append([]Field{}, l.fields...), extra...)
+	}
+	line := l.core.encoder.Encode(Entry{Time: l.core.now(), Level: level, Message: text, Fields: fields})
+
+	l.core.mu.Lock()
+	defer l.core.mu.Unlock()
+	l.core.write(level, line)
 }
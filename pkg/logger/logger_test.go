@@ -0,0 +1,143 @@
+package logger_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	pkgErrors "github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLogger(t *testing.T) {
+	newJSON := func(level logger.Level) (*logger.Logger, *bytes.Buffer, *bytes.Buffer) {
+		infoWriter, errWriter := new(bytes.Buffer), new(bytes.Buffer)
+		return logger.NewEncoded(infoWriter, errWriter, logger.JSONEncoder{}, level), infoWriter, errWriter
+	}
+	decode := func(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+		var res []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			if line == "" {
+				continue
+			}
+			entry := make(map[string]interface{})
+			require.NoError(t, json.Unmarshal([]byte(line), &entry), "Decoding %q", line)
+			res = append(res, entry)
+		}
+		return res
+	}
+
+	t.Run("JSONEncoder", func(t *testing.T) {
+		l, infoWriter, errWriter := newJSON(logger.DebugLevel)
+		l.Named("seeder").With(logger.KV("url", "https://en.wikipedia.org/wiki/Battle_of_Austerlitz"), logger.KV("wikiID", 118372)).Info("Seeding battle")
+		l.Warn("Something odd")
+
+		infos := decode(t, infoWriter)
+		require.Len(t, infos, 1)
+		assert.Equal(t, "info", infos[0]["level"])
+		assert.Equal(t, "Seeding battle", infos[0]["msg"])
+		assert.Equal(t, "seeder", infos[0]["component"])
+		assert.Equal(t, "https://en.wikipedia.org/wiki/Battle_of_Austerlitz", infos[0]["url"])
+		assert.Equal(t, float64(118372), infos[0]["wikiID"])
+		assert.NotEmpty(t, infos[0]["time"])
+		assert.True(t, strings.HasPrefix(infoWriter.String(), `{"time":`), "Time should be the first key")
+
+		errs := decode(t, errWriter)
+		require.Len(t, errs, 1, "Warnings should be written into the error writer")
+		assert.Equal(t, "warn", errs[0]["level"])
+	})
+
+	t.Run("ConsoleEncoder", func(t *testing.T) {
+		infoWriter := new(bytes.Buffer)
+		l := logger.NewEncoded(infoWriter, new(bytes.Buffer), logger.ConsoleEncoder{}, logger.InfoLevel)
+		l.With(logger.KV("name", "Battle of Austerlitz"), logger.KV("count", 3)).Info("Scraped")
+
+		line := infoWriter.String()
+		assert.True(t, strings.HasSuffix(line, ` INFO  Scraped name="Battle of Austerlitz" count=3`+"\n"), "Got %q", line)
+		assert.Equal(t, 1, strings.Count(line, "\n"), "Each entry should be written in a single line")
+	})
+
+	t.Run("Levels", func(t *testing.T) {
+		l, infoWriter, errWriter := newJSON(logger.WarnLevel)
+		l.Debug("debug")
+		l.Info("info")
+		l.Warn("warn")
+		l.Error(errors.New("error"))
+
+		assert.Empty(t, infoWriter.String())
+		var levels []interface{}
+		for _, entry := range decode(t, errWriter) {
+			levels = append(levels, entry["level"])
+		}
+		assert.Equal(t, []interface{}{"warn", "error"}, levels)
+		assert.False(t, l.Enabled(logger.InfoLevel))
+		assert.True(t, l.Enabled(logger.ErrorLevel))
+
+		for name, expected := range map[string]logger.Level{"debug": logger.DebugLevel, "WARN": logger.WarnLevel} {
+			level, err := logger.ParseLevel(name)
+			require.NoError(t, err, "Parsing %q", name)
+			assert.Equal(t, expected, level)
+		}
+		_, err := logger.ParseLevel("verbose")
+		assert.Error(t, err)
+	})
+
+	t.Run("With", func(t *testing.T) {
+		l, infoWriter, _ := newJSON(logger.InfoLevel)
+		parent := l.With(logger.KV("url", "a"))
+		parent.With(logger.KV("url", "b")).Info("child")
+		parent.Info("parent")
+
+		entries := decode(t, infoWriter)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "b", entries[0]["url"], "Later fields should replace those with the same key")
+		assert.Equal(t, "a", entries[1]["url"], "Children should not modify their parents")
+	})
+
+	t.Run("ErrorKind", func(t *testing.T) {
+		l, _, errWriter := newJSON(logger.InfoLevel)
+		l.Error(pkgErrors.Wrap(domain.ErrNotFound, "Finding battle"))
+		l.Error(pkgErrors.Wrap(&json.SyntaxError{}, "Importing data"))
+		l.Error(errors.New("plain"))
+		l.With(logger.KV(logger.ErrorKindKey, "custom")).Error(domain.ErrNotFound)
+
+		entries := decode(t, errWriter)
+		require.Len(t, entries, 4)
+		assert.Equal(t, "Finding battle: "+domain.ErrNotFound.Error(), entries[0]["msg"])
+		assert.Equal(t, domain.ErrNotFound.Error(), entries[0][logger.ErrorKindKey])
+		assert.Equal(t, "*json.SyntaxError", entries[1][logger.ErrorKindKey])
+		assert.NotContains(t, entries[2], logger.ErrorKindKey)
+		assert.Equal(t, "custom", entries[3][logger.ErrorKindKey])
+	})
+
+	t.Run("From", func(t *testing.T) {
+		l, _, _ := newJSON(logger.InfoLevel)
+		assert.Same(t, l, logger.From(l))
+
+		legacy := &legacyLogger{}
+		logger.From(legacy).With(logger.KV("url", "a")).Info("Scraping")
+		logger.From(legacy).Error(errors.New("failed"))
+		logger.From(legacy).With(logger.KV("title", "Battle of Waterloo")).Info("Scraped")
+		assert.Equal(t, []string{"Scraping url=a", `Scraped title="Battle of Waterloo"`}, legacy.infos)
+		require.Len(t, legacy.errs, 1)
+		assert.Equal(t, "failed", legacy.errs[0].Error())
+	})
+}
+
+type legacyLogger struct {
+	infos []string
+	errs  []error
+}
+
+func (l *legacyLogger) Info(text string) {
+	l.infos = append(l.infos, text)
+}
+
+func (l *legacyLogger) Error(err error) {
+	l.errs = append(l.errs, err)
+}
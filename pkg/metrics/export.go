@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+)
+
+// Export exports the metrics of a process that does not serve them, such as the scraper or the
+// seeder, once it finishes. They are written into "batcoms_<job>.prom" inside textfileDir, to be
+// collected by the textfile collector of the node exporter, and pushed to the Pushgateway found at
+// pushURL under the given job. Either of them may be skipped by leaving it empty
+func Export(job, textfileDir, pushURL string) error {
+	if textfileDir != "" {
+		if err := os.MkdirAll(textfileDir, 0755); err != nil {
+			return errors.Wrapf(err, "Creating directory %s", textfileDir)
+		}
+		fileName := filepath.Join(textfileDir, namespace+"_"+job+".prom")
+		if err := prometheus.WriteToTextfile(fileName, Registry); err != nil {
+			return errors.Wrapf(err, "Writing metrics into %s", fileName)
+		}
+	}
+	if pushURL != "" {
+		if err := push.New(pushURL, job).Gatherer(Registry).Push(); err != nil {
+			return errors.Wrapf(err, "Pushing metrics to %s", pushURL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"gorm.io/gorm"
+)
+
+const startKey = "metrics:start"
+
+// InstrumentGORM registers callbacks into db that observe the duration of each one of its queries
+// in DBQueryDuration, labelled with the given backend
+func InstrumentGORM(db *gorm.DB, backend string) error {
+	before := func(db *gorm.DB) {
+		db.InstanceSet(startKey, time.Now())
+	}
+	after := func(operation string) func(*gorm.DB) {
+		return func(db *gorm.DB) {
+			start, ok := db.InstanceGet(startKey)
+			if !ok {
+				return
+			}
+			DBQueryDuration.
+				WithLabelValues(backend, operation, db.Statement.Table).
+				Observe(time.Since(start.(time.Time)).Seconds())
+		}
+	}
+
+	cb := db.Callback()
+	for _, err := range []error{
+		cb.Create().Before("gorm:create").Register("metrics:before_create", before),
+		cb.Create().After("gorm:create").Register("metrics:after_create", after("create")),
+		cb.Query().Before("gorm:query").Register("metrics:before_query", before),
+		cb.Query().After("gorm:query").Register("metrics:after_query", after("query")),
+		cb.Update().Before("gorm:update").Register("metrics:before_update", before),
+		cb.Update().After("gorm:update").Register("metrics:after_update", after("update")),
+		cb.Delete().Before("gorm:delete").Register("metrics:before_delete", before),
+		cb.Delete().After("gorm:delete").Register("metrics:after_delete", after("delete")),
+		cb.Row().Before("gorm:row").Register("metrics:before_row", before),
+		cb.Row().After("gorm:row").Register("metrics:after_row", after("row")),
+		cb.Raw().Before("gorm:raw").Register("metrics:before_raw", before),
+		cb.Raw().After("gorm:raw").Register("metrics:after_raw", after("raw")),
+	} {
+		if err != nil {
+			return errors.Wrap(err, "Registering metrics callbacks")
+		}
+	}
+	return nil
+}
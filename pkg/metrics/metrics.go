@@ -0,0 +1,117 @@
+// Package metrics defines the Prometheus metrics of the API, the scraper and the seeder. All of them
+// are registered into Registry, which is served by the API under /metrics and exported by the
+// scraper and the seeder once they finish
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "batcoms"
+
+// Registry is the registry into which all the metrics of this package are registered, together
+// with those of the Go runtime and the process
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts the requests handled by the API, by method, route and status
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Requests handled by the API, by method, route and status.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes how long the API takes to handle requests, by method and route
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Time taken by the API to handle requests, by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// DBQueryDuration observes how long database queries take, by backend, operation and table
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "db",
+		Name:      "query_duration_seconds",
+		Help:      "Time taken by database queries, by backend, operation and table.",
+		Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"backend", "operation", "table"})
+
+	// ScraperPages counts the Wikipedia pages fetched by the scraper, by kind (list, battle or
+	// biography)
+	ScraperPages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "pages_fetched_total",
+		Help:      "Wikipedia pages fetched by the scraper, by kind.",
+	}, []string{"kind"})
+
+	// ScraperSummaries counts the summaries fetched from Wikipedia's API
+	ScraperSummaries = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "summaries_fetched_total",
+		Help:      "Summaries fetched from Wikipedia's API.",
+	})
+
+	// ScraperBattles counts the battles scraped successfully
+	ScraperBattles = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "battles_scraped_total",
+		Help:      "Battles scraped successfully.",
+	})
+
+	// ScraperFailures counts the battles that could not be scraped, by the reason why they failed
+	ScraperFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scraper",
+		Name:      "failures_total",
+		Help:      "Battles that could not be scraped, by reason.",
+	}, []string{"reason"})
+
+	// SeederSeeded counts the entities stored by the seeder, by entity
+	SeederSeeded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "seeder",
+		Name:      "seeded_total",
+		Help:      "Entities stored by the seeder, by entity.",
+	}, []string{"entity"})
+
+	// SeederFailures counts the entities that the seeder could not store, by entity
+	SeederFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "seeder",
+		Name:      "failures_total",
+		Help:      "Entities that the seeder could not store, by entity.",
+	}, []string{"entity"})
+
+	// SeederDuration is how long the seeder took to store each kind of entity, which together with
+	// SeederSeeded gives its throughput
+	SeederDuration = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: "seeder",
+		Name:      "duration_seconds",
+		Help:      "Time taken by the seeder to store each kind of entity.",
+	}, []string{"entity"})
+)
+
+func init() {
+	Registry.MustRegister(
+		prometheus.NewGoCollector(),
+		prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+		HTTPRequests,
+		HTTPRequestDuration,
+		DBQueryDuration,
+		ScraperPages,
+		ScraperSummaries,
+		ScraperBattles,
+		ScraperFailures,
+		SeederSeeded,
+		SeederFailures,
+		SeederDuration,
+	)
+}
@@ -0,0 +1,91 @@
+package metrics_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sasalatart/batcoms/pkg/metrics"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestExport(t *testing.T) {
+	seeded := metrics.SeederSeeded.WithLabelValues("battles")
+	seeded.Add(3)
+	expected := fmt.Sprintf(`batcoms_seeder_seeded_total{entity="battles"} %v`, testutil.ToFloat64(seeded))
+
+	t.Run("Textfile", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "metrics")
+		require.NoError(t, err, "Creating temp dir")
+		defer os.RemoveAll(dir)
+
+		require.NoError(t, metrics.Export("seeder", dir, ""), "Exporting metrics")
+		contents, err := ioutil.ReadFile(filepath.Join(dir, "batcoms_seeder.prom"))
+		require.NoError(t, err, "Reading textfile")
+		assert.Contains(t, string(contents), expected)
+	})
+
+	t.Run("Pushgateway", func(t *testing.T) {
+		var pushedPath string
+		var pushed []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			pushedPath = r.URL.Path
+			pushed, _ = ioutil.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		require.NoError(t, metrics.Export("seeder", "", server.URL), "Exporting metrics")
+		assert.Equal(t, "/metrics/job/seeder", pushedPath)
+		assert.Contains(t, string(pushed), "batcoms_seeder_seeded_total")
+	})
+}
+
+func TestInstrumentGORM(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Opening stub database")
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err, "Opening gorm database")
+	require.NoError(t, metrics.InstrumentGORM(db, "postgresql"), "Instrumenting gorm database")
+
+	before := factionQueries(t)
+	mock.ExpectQuery(`SELECT \* FROM "factions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	var rows []struct{ ID int }
+	require.NoError(t, db.Table("factions").Find(&rows).Error, "Querying")
+	require.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, before+1, factionQueries(t), "Should observe the duration of the query")
+}
+
+// factionQueries returns how many queries on factions have been observed so far, as metrics are
+// registered globally and thus shared by every test run
+func factionQueries(t *testing.T) uint64 {
+	t.Helper()
+	families, err := metrics.Registry.Gather()
+	require.NoError(t, err, "Gathering metrics")
+	var count uint64
+	for _, f := range families {
+		if f.GetName() != "batcoms_db_query_duration_seconds" {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			labels := make(map[string]string)
+			for _, l := range m.GetLabel() {
+				labels[l.GetName()] = l.GetValue()
+			}
+			if labels["backend"] == "postgresql" && labels["operation"] == "query" && labels["table"] == "factions" {
+				count += m.GetHistogram().GetSampleCount()
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,107 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit sets how many requests may be made: up to Burst of them at once, after which tokens for new
+// ones are refilled at a pace of PerMinute
+type Limit struct {
+	PerMinute int
+	Burst     int
+}
+
+func (l Limit) perSecond() float64 {
+	return float64(l.PerMinute) / 60
+}
+
+// Result describes whether a request was allowed, and the state of its bucket after taking it
+type Result struct {
+	Allowed bool
+	// Limit is the size of the bucket
+	Limit int
+	// Remaining is how many requests may still be made right away
+	Remaining int
+	// Reset is how long it takes for the bucket to be full again
+	Reset time.Duration
+	// RetryAfter is how long it takes for a request to be allowed again, when it was not
+	RetryAfter time.Duration
+}
+
+// sweepEvery is how many requests are taken between each sweep of the buckets that are full, which
+// are the same as those that do not exist
+const sweepEvery = 1024
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	limit  Limit
+}
+
+// Limiter keeps a token bucket for each key, such as an IP or an API key. It is safe for concurrent
+// use
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+}
+
+// New returns a pointer to a ready-to-use ratelimit.Limiter
+func New() *Limiter {
+	return &Limiter{buckets: make(map[string]*bucket)}
+}
+
+// Take takes a token from the bucket of key at the given time, refilling it first according to limit.
+// Buckets start full
+func (l *Limiter) Take(key string, limit Limit, now time.Time) Result {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.takes++
+	if l.takes%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	b.limit = limit
+	b.tokens = refill(b, now)
+	b.last = now
+
+	result := Result{Limit: limit.Burst}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = durationFor(1-b.tokens, limit)
+	}
+	result.Remaining = int(math.Floor(b.tokens))
+	result.Reset = durationFor(float64(limit.Burst)-b.tokens, limit)
+	return result
+}
+
+// sweep forgets the buckets that would be full by now, so that only those of recent clients are kept
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if refill(b, now) >= float64(b.limit.Burst) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func refill(b *bucket, now time.Time) float64 {
+	elapsed := now.Sub(b.last).Seconds()
+	return math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.perSecond())
+}
+
+func durationFor(tokens float64, limit Limit) time.Duration {
+	if tokens <= 0 || limit.PerMinute <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(tokens / limit.perSecond() * float64(time.Second)))
+}
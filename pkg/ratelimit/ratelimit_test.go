@@ -0,0 +1,44 @@
+package ratelimit_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sasalatart/batcoms/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	limit := ratelimit.Limit{PerMinute: 60, Burst: 2}
+	start := time.Date(2020, time.October, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("Burst", func(t *testing.T) {
+		l := ratelimit.New()
+		assert.Equal(t, ratelimit.Result{Allowed: true, Limit: 2, Remaining: 1, Reset: time.Second}, l.Take("a", limit, start))
+		assert.Equal(t, ratelimit.Result{Allowed: true, Limit: 2, Remaining: 0, Reset: 2 * time.Second}, l.Take("a", limit, start))
+		assert.Equal(
+			t,
+			ratelimit.Result{Allowed: false, Limit: 2, Remaining: 0, Reset: 2 * time.Second, RetryAfter: time.Second},
+			l.Take("a", limit, start),
+		)
+		assert.True(t, l.Take("b", limit, start).Allowed, "Should keep a bucket per key")
+	})
+
+	t.Run("Refill", func(t *testing.T) {
+		l := ratelimit.New()
+		l.Take("a", limit, start)
+		l.Take("a", limit, start)
+
+		denied := l.Take("a", limit, start.Add(500*time.Millisecond))
+		assert.False(t, denied.Allowed)
+		assert.Equal(t, 500*time.Millisecond, denied.RetryAfter)
+
+		allowed := l.Take("a", limit, start.Add(time.Second))
+		assert.True(t, allowed.Allowed)
+		assert.Equal(t, 0, allowed.Remaining)
+
+		full := l.Take("a", limit, start.Add(time.Hour))
+		assert.True(t, full.Allowed)
+		assert.Equal(t, 1, full.Remaining, "Should not refill beyond the burst")
+	})
+}
@@ -6,6 +6,9 @@ import (
 	"github.com/pkg/errors"
 	"github.com/sasalatart/batcoms/domain/summaries"
 	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/scraper/biographies"
 	"github.com/sasalatart/batcoms/pkg/scraper/urls"
 	"github.com/sasalatart/batcoms/pkg/strclean"
 
@@ -25,20 +28,23 @@ func (s *Scraper) subscribeActors(ctx *battleCtx) {
 }
 
 func (s *Scraper) subscribeFactions(ctx *battleCtx, fm factionsMapper) {
-	subscribeSetInfoBoxID(ctx, "belligerents", customFactionsID)
+	s.subscribeSetInfoBoxID(ctx, s.edition.Headings.Belligerents, customFactionsID)
 
 	handleFaction := func(id int, flag string, ids *[]int, err error) {
 		if err != nil {
-			s.logger.Error(err)
+			s.logger.With(logger.KV("url", ctx.battle.URL)).Error(err)
 			return
 		}
 		if _, saved := fm[flag]; !saved && flag != "" {
 			fm[flag] = id
 		}
+		if _, saved := ctx.battle.Flags[id]; !saved && flag != "" {
+			ctx.battle.Flags[id] = flag
+		}
 		*ids = append(*ids, id)
 	}
 
-	ctx.collector.OnHTML(infoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
+	ctx.collector.OnHTML(s.edition.InfoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
 		s.actorsSide(ctx, e, wikiactors.FactionKind, sideASelector, func(id int, flag string, err error) {
 			handleFaction(id, flag, &ctx.battle.Factions.A, err)
 		})
@@ -49,11 +55,11 @@ func (s *Scraper) subscribeFactions(ctx *battleCtx, fm factionsMapper) {
 }
 
 func (s *Scraper) subscribeCommanders(ctx *battleCtx, cm commandersMapper) {
-	subscribeSetInfoBoxID(ctx, "Commanders and leaders", customCommandersID)
+	s.subscribeSetInfoBoxID(ctx, s.edition.Headings.Commanders, customCommandersID)
 
 	handleCommander := func(id int, flag string, ids *[]int, err error) {
 		if err != nil {
-			s.logger.Error(err)
+			s.logger.With(logger.KV("url", ctx.battle.URL)).Error(err)
 			return
 		}
 		if flag != "" {
@@ -62,7 +68,7 @@ func (s *Scraper) subscribeCommanders(ctx *battleCtx, cm commandersMapper) {
 		*ids = append(*ids, id)
 	}
 
-	ctx.collector.OnHTML(infoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
+	ctx.collector.OnHTML(s.edition.InfoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
 		s.actorsSide(ctx, e, wikiactors.CommanderKind, sideASelector, func(id int, flag string, err error) {
 			handleCommander(id, flag, &ctx.battle.Commanders.A, err)
 		})
@@ -86,7 +92,7 @@ func (s *Scraper) actorsSide(ctx *battleCtx, e *colly.HTMLElement, kind wikiacto
 
 			pURL := node.Attr("href")
 			if !strings.Contains(pURL, "://") {
-				pURL = "https://en.wikipedia.org" + pURL
+				pURL = s.edition.ArticleURL(pURL)
 			}
 			if urls.ShouldSkip(pURL) {
 				return
@@ -99,7 +105,7 @@ func (s *Scraper) actorsSide(ctx *battleCtx, e *colly.HTMLElement, kind wikiacto
 
 			summary, err := summaries.Fetch(pURL)
 			if err != nil {
-				s.logger.Error(errors.Wrapf(err, "Error fetching summary for %s", pURL))
+				s.logger.With(logger.KV("url", pURL)).Error(errors.Wrap(err, "Error fetching summary"))
 				return
 			}
 
@@ -110,13 +116,18 @@ func (s *Scraper) actorsSide(ctx *battleCtx, e *colly.HTMLElement, kind wikiacto
 
 			flag := flagURL(node)
 			wikiActor := wikiactors.Actor{
-				Kind:        kind,
-				ID:          int(summary.PageID),
-				URL:         pURL,
-				Flag:        flag,
-				Name:        name,
-				Description: summary.Description,
-				Extract:     summary.Extract,
+				Kind:          kind,
+				ID:            int(summary.PageID),
+				URL:           pURL,
+				RedirectURL:   summary.RedirectURL,
+				Flag:          flag,
+				Name:          name,
+				Description:   summary.Description,
+				Extract:       summary.Extract,
+				Localizations: s.actorLocalizations(pURL),
+			}
+			if kind == wikiactors.CommanderKind {
+				wikiActor.Biography = s.biography(pURL)
 			}
 			err = s.wikiActorsRepo.Save(wikiActor)
 			onDone(wikiActor.ID, flag, err)
@@ -131,29 +142,45 @@ func (s *Scraper) subscribeGroupings(ctx *battleCtx, factionsByFlag factionsMapp
 		ctx.battle.Commanders.A = unique(ctx.battle.Commanders.A)
 		ctx.battle.Commanders.B = unique(ctx.battle.Commanders.B)
 
-		for fFlag, fID := range factionsByFlag {
-			for cFlag, cIDs := range commandersByFlag {
-				if fFlag != cFlag {
-					continue
-				}
-
-				ctx.battle.CommandersByFaction[fID] = cIDs
-				break
+		flagsByCommander := make(map[int]string)
+		for flag, cIDs := range commandersByFlag {
+			for _, cID := range cIDs {
+				flagsByCommander[cID] = flag
 			}
 		}
 
-		groupIfOneFaction := func(factions []int, commanders []int) {
-			if len(factions) != 1 || len(commanders) == 0 {
-				return
+		// Commanders are assigned to the faction of their side that shares their flag, falling back
+		// to the only faction of their side, if there is just one
+		groupSide := func(factions []int, commanders []int) {
+			for _, cID := range commanders {
+				if _, assigned := ctx.battle.CommanderConfidences[cID]; assigned {
+					continue
+				}
+				fID, sharesFlag := factionsByFlag[flagsByCommander[cID]]
+				switch {
+				case sharesFlag && contains(factions, fID):
+					ctx.battle.Assign(fID, cID, wikibattles.FlagConfidence)
+				case len(factions) == 1:
+					ctx.battle.Assign(factions[0], cID, wikibattles.SingleFactionConfidence)
+				}
 			}
-
-			ctx.battle.CommandersByFaction[factions[0]] = commanders
 		}
-		groupIfOneFaction(ctx.battle.Factions.A, ctx.battle.Commanders.A)
-		groupIfOneFaction(ctx.battle.Factions.B, ctx.battle.Commanders.B)
+		groupSide(ctx.battle.Factions.A, ctx.battle.Commanders.A)
+		groupSide(ctx.battle.Factions.B, ctx.battle.Commanders.B)
 	})
 }
 
+// biography scrapes the details of the life of a commander from their own article, returning nil
+// if these could not be found
+func (s *Scraper) biography(url string) *wikiactors.Biography {
+	res, err := biographies.Scrape(s.edition, url)
+	if err != nil {
+		s.logger.With(logger.KV("url", url)).Error(errors.Wrap(err, "Error scraping biography"))
+		return nil
+	}
+	return &res
+}
+
 func flagURL(participantNode *colly.HTMLElement) string {
 	prevNode := participantNode.DOM.Prev()
 	if !prevNode.HasClass("flagicon") {
@@ -167,6 +194,15 @@ func flagURL(participantNode *colly.HTMLElement) string {
 	return strings.ReplaceAll(flagSRC, "//upload.wikimedia.org/wikipedia/commons", "")
 }
 
+func contains(ids []int, id int) bool {
+	for _, current := range ids {
+		if current == id {
+			return true
+		}
+	}
+	return false
+}
+
 func unique(ids []int) []int {
 	set := make(map[int]struct{})
 	result := []int{}
@@ -1,30 +1,50 @@
 package battles
 
 import (
-	"fmt"
-
 	"github.com/gocolly/colly"
 	"github.com/pkg/errors"
 	"github.com/sasalatart/batcoms/domain/summaries"
 	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/metrics"
 )
 
-// ScrapeOne scrapes information about the battle found in the URL passed to it
+// ScrapeOne scrapes information about the battle found in the URL passed to it. Battles that could
+// not be scraped are counted by the reason why they failed
 func (s *Scraper) ScrapeOne(url string) (wikibattles.Battle, error) {
-	battle := wikibattles.Battle{URL: url, CommandersByFaction: make(wikibattles.CommandersByFaction)}
+	battle, err := s.scrapeOne(url)
+	if err != nil {
+		metrics.ScraperFailures.WithLabelValues(failureReason(err)).Inc()
+		return battle, err
+	}
+	metrics.ScraperBattles.Inc()
+	return battle, nil
+}
+
+func (s *Scraper) scrapeOne(url string) (wikibattles.Battle, error) {
+	battle := wikibattles.Battle{
+		URL:                  url,
+		CommandersByFaction:  make(wikibattles.CommandersByFaction),
+		CommanderConfidences: make(map[int]wikibattles.Confidence),
+		Flags:                make(map[int]string),
+	}
 	if err := s.assignSummary(&battle); err != nil {
 		return battle, errors.Wrap(err, "Assigning summary")
 	}
 
 	ctx := &battleCtx{&battle, colly.NewCollector(), nil}
 	ctx.collector.OnRequest(func(r *colly.Request) {
-		s.logger.Info(fmt.Sprintf("Scraping %s\n", r.URL))
+		s.logger.With(logger.KV("url", r.URL.String())).Debug("Scraping battle")
+	})
+	ctx.collector.OnResponse(func(*colly.Response) {
+		metrics.ScraperPages.WithLabelValues("battle").Inc()
 	})
 	s.assertHasOneInfoBox(ctx)
 	s.subscribeMeta(ctx)
 	s.subscribeActors(ctx)
 	s.subscribeStrength(ctx)
 	s.subscribeCasualties(ctx)
+	s.subscribeLocalizations(ctx)
 
 	if err := ctx.collector.Visit(url); err != nil {
 		return battle, errors.Wrap(err, "Doing the request to scrape")
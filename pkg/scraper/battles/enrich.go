@@ -0,0 +1,38 @@
+package battles
+
+import (
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+)
+
+// Enrich fetches the Wikidata facts of all the battles, factions and commanders that have been
+// scraped after successive runs of scraper.ScrapeOne, and logs the discrepancies found between
+// them and the scraped dates and coordinates of battles. Those whose facts can not be fetched are
+// logged and left as they were
+func (s *Scraper) Enrich(c *wikidata.Client) {
+	for _, b := range s.wikiBattlesRepo.Data() {
+		facts, err := c.Facts(b.URL)
+		if err != nil {
+			s.logger.With(logger.KV("url", b.URL)).Error(errors.Wrap(err, "Error fetching Wikidata facts"))
+			continue
+		}
+		b.Wikidata = &facts
+		for _, d := range facts.Discrepancies(b.Date, b.Location) {
+			s.logger.With(logger.KV("url", b.URL), logger.KV("discrepancy", d)).Warn("Wikidata discrepancy")
+		}
+	}
+
+	factionsByID, commandersByID := s.wikiActorsRepo.Data()
+	for _, actorsByID := range []map[int]*wikiactors.Actor{factionsByID, commandersByID} {
+		for _, a := range actorsByID {
+			facts, err := c.Facts(a.URL)
+			if err != nil {
+				s.logger.With(logger.KV("url", a.URL)).Error(errors.Wrap(err, "Error fetching Wikidata facts"))
+				continue
+			}
+			a.Wikidata = &facts
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package battles
 
-import "github.com/sasalatart/batcoms/domain"
+import (
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/summaries"
+)
 
 // ErrMoreThanOneInfoBox is used to communicate that more than one info box was found
 const ErrMoreThanOneInfoBox = domain.Error("More than one info box found")
@@ -19,3 +23,23 @@ const ErrNoResult = domain.Error("No results in info box")
 
 // ErrNoPlace is used to communicate that the info box did not specify a place
 const ErrNoPlace = domain.Error("No place in info box")
+
+// failureReasons names the known errors by which battles may fail to be scraped
+var failureReasons = map[error]string{
+	ErrMoreThanOneInfoBox:  "more_than_one_info_box",
+	ErrNoInfoBox:           "no_info_box",
+	ErrNoSummaryExtract:    "no_summary_extract",
+	ErrNoDate:              "no_date",
+	ErrNoResult:            "no_result",
+	ErrNoPlace:             "no_place",
+	summaries.ErrNoSummary: "no_summary",
+	summaries.ErrNotWiki:   "not_wiki",
+}
+
+// failureReason returns the name of the cause of err if it is a known one, or "other" if not
+func failureReason(err error) string {
+	if reason, ok := failureReasons[errors.Cause(err)]; ok {
+		return reason
+	}
+	return "other"
+}
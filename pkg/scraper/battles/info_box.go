@@ -1,15 +1,14 @@
 package battles
 
 import (
-	"strings"
-
 	"github.com/gocolly/colly"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
 )
 
 func (s *Scraper) assertHasOneInfoBox(ctx *battleCtx) {
 	ctx.collector.OnHTML(contentSelector, ctx.abortable(func(e *colly.HTMLElement) {
 		infoBoxAmount := 0
-		e.ForEach(infoBoxSelector, func(infoBoxIndex int, c *colly.HTMLElement) {
+		e.ForEach(s.edition.InfoBoxSelector, func(infoBoxIndex int, c *colly.HTMLElement) {
 			infoBoxAmount++
 			if infoBoxAmount > 1 {
 				ctx.err = ErrMoreThanOneInfoBox
@@ -22,10 +21,10 @@ func (s *Scraper) assertHasOneInfoBox(ctx *battleCtx) {
 	}))
 }
 
-func subscribeSetInfoBoxID(ctx *battleCtx, title string, id string) {
-	ctx.collector.OnHTML(infoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
+func (s *Scraper) subscribeSetInfoBoxID(ctx *battleCtx, titles []string, id string) {
+	ctx.collector.OnHTML(s.edition.InfoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
 		e.ForEachWithBreak("th", func(_ int, c *colly.HTMLElement) bool {
-			if strings.ToLower(c.Text) != strings.ToLower(title) {
+			if !editions.MatchesAny(c.Text, titles) {
 				return true
 			}
 			c.DOM.Parent().Next().SetAttr("id", id)
@@ -0,0 +1,85 @@
+package battles
+
+import (
+	"github.com/gocolly/colly"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/summaries"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/domain/wikibattles"
+	"github.com/sasalatart/batcoms/pkg/logger"
+)
+
+// subscribeLocalizations follows the interlanguage links of a battle towards the languages it
+// should be localized to, and stores the names and summaries found in those editions
+func (s *Scraper) subscribeLocalizations(ctx *battleCtx) {
+	if len(s.localizeTo) == 0 {
+		return
+	}
+
+	ctx.collector.OnHTML(interlanguageLinksSelector, ctx.abortable(func(e *colly.HTMLElement) {
+		language := e.Attr("hreflang")
+		if !s.shouldLocalizeTo(language) {
+			return
+		}
+
+		url := e.Request.AbsoluteURL(e.Attr("href"))
+		summary, err := summaries.Fetch(url)
+		if err != nil {
+			s.logger.With(logger.KV("url", url), logger.KV("language", language)).Error(
+				errors.Wrap(err, "Error fetching localized summary"),
+			)
+			return
+		}
+
+		if ctx.battle.Localizations == nil {
+			ctx.battle.Localizations = make(map[string]wikibattles.Localization)
+		}
+		ctx.battle.Localizations[language] = wikibattles.Localization{
+			URL:         url,
+			Name:        summary.Title,
+			Description: summary.Description,
+			Extract:     summary.Extract,
+		}
+	}))
+}
+
+// actorLocalizations fetches the names and summaries of an actor in the languages it should be
+// localized to. Actors not found in some of these languages are simply not localized to them
+func (s *Scraper) actorLocalizations(url string) map[string]wikiactors.Localization {
+	var res map[string]wikiactors.Localization
+	for _, language := range s.localizeTo {
+		if !s.shouldLocalizeTo(language) {
+			continue
+		}
+		summary, err := summaries.FetchIn(url, language)
+		if err != nil {
+			if errors.Cause(err) != summaries.ErrNoLanguageLink {
+				s.logger.With(logger.KV("url", url), logger.KV("language", language)).Error(
+					errors.Wrap(err, "Error fetching localized summary"),
+				)
+			}
+			continue
+		}
+		if res == nil {
+			res = make(map[string]wikiactors.Localization)
+		}
+		res[language] = wikiactors.Localization{
+			Name:        summary.Title,
+			Description: summary.Description,
+			Extract:     summary.Extract,
+		}
+	}
+	return res
+}
+
+func (s *Scraper) shouldLocalizeTo(language string) bool {
+	if language == s.edition.Language {
+		return false
+	}
+	for _, l := range s.localizeTo {
+		if l == language {
+			return true
+		}
+	}
+	return false
+}
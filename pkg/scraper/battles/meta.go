@@ -5,15 +5,18 @@ import (
 	"strings"
 
 	"github.com/gocolly/colly"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
 	"github.com/sasalatart/batcoms/pkg/strclean"
 )
 
 var coordsSep = regexp.MustCompile(`^(.*?)\d+°.*`)
 
 func (s *Scraper) subscribeMeta(ctx *battleCtx) {
-	ctx.collector.OnHTML(infoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
+	headings := s.edition.Headings
+
+	ctx.collector.OnHTML(s.edition.InfoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
 		e.ForEachWithBreak(partOfSelector, func(_ int, c *colly.HTMLElement) bool {
-			if !strings.Contains(strings.ToLower(c.Text), "part of") {
+			if !containsAny(c.Text, headings.PartOf) {
 				return true
 			}
 			ctx.battle.PartOf = strclean.Apply(c.Text)
@@ -21,40 +24,42 @@ func (s *Scraper) subscribeMeta(ctx *battleCtx) {
 		})
 	}))
 
-	ctx.collector.OnHTML(infoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
-		search := func(childSelector string, toSearch ...string) string {
+	ctx.collector.OnHTML(s.edition.InfoBoxSelector, ctx.abortable(func(e *colly.HTMLElement) {
+		search := func(childSelector string, toSearch []string) string {
 			var res string
 			e.ForEachWithBreak("tr", func(_ int, c *colly.HTMLElement) bool {
-				for _, s := range toSearch {
-					if s == strings.ToLower(c.ChildText("th")) {
-						res = strclean.Apply(c.ChildText(childSelector))
-						return false
-					}
+				if !editions.MatchesAny(c.ChildText("th"), toSearch) {
+					return true
 				}
-				return true
+				res = strclean.Apply(c.ChildText(childSelector))
+				return false
 			})
 			return res
 		}
 
-		ctx.battle.Date = search("td", "date")
+		ctx.battle.Date = search("td", headings.Date)
 		if ctx.battle.Date == "" {
 			ctx.err = ErrNoDate
 			return
 		}
 
-		ctx.battle.Result = search("td", "result", "status")
+		ctx.battle.Result = search("td", headings.Result)
 		if ctx.battle.Result == "" {
 			ctx.err = ErrNoResult
 			return
 		}
 
-		ctx.battle.Location.Place = strings.Trim(coordsSep.ReplaceAllString(search(".location", "location"), "$1"), " ")
+		place := search(".location", headings.Location)
+		if place == "" {
+			place = search("td", headings.Location)
+		}
+		ctx.battle.Location.Place = strings.Trim(coordsSep.ReplaceAllString(place, "$1"), " ")
 		if ctx.battle.Location.Place == "" {
 			ctx.err = ErrNoPlace
 			return
 		}
 
-		ctx.battle.TerritorialChanges = search("td", "territorialchanges")
+		ctx.battle.TerritorialChanges = search("td", headings.TerritorialChanges)
 	}))
 
 	ctx.collector.OnHTML(coordinatesSelector, ctx.abortable(func(e *colly.HTMLElement) {
@@ -62,3 +67,14 @@ func (s *Scraper) subscribeMeta(ctx *battleCtx) {
 		ctx.battle.Location.Longitude = e.ChildText(".longitude")
 	}))
 }
+
+// containsAny returns true if the text contains any of the given (lowercase) substrings
+func containsAny(text string, substrings []string) bool {
+	text = strings.ToLower(text)
+	for _, s := range substrings {
+		if strings.Contains(text, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -8,10 +8,9 @@ import (
 	"github.com/sasalatart/batcoms/pkg/strclean"
 )
 
-func subscribeNumbersFor(ctx *battleCtx, title string, sideNumbers *statistics.SideNumbers) {
+func (s *Scraper) subscribeNumbersFor(ctx *battleCtx, titles []string, customID string, sideNumbers *statistics.SideNumbers) {
 	twoSides := false
-	customID := customID(title)
-	subscribeSetInfoBoxID(ctx, title, customID)
+	s.subscribeSetInfoBoxID(ctx, titles, customID)
 
 	ctx.collector.OnHTML(sideNumbersSelector(sideASelector, customID), ctx.abortable(func(e *colly.HTMLElement) {
 		sideNumbers.A = strclean.Apply(e.Text)
@@ -38,9 +37,9 @@ func subscribeNumbersFor(ctx *battleCtx, title string, sideNumbers *statistics.S
 }
 
 func (s *Scraper) subscribeStrength(ctx *battleCtx) {
-	subscribeNumbersFor(ctx, "Strength", &ctx.battle.Strength)
+	s.subscribeNumbersFor(ctx, s.edition.Headings.Strength, customStrengthID, &ctx.battle.Strength)
 }
 
 func (s *Scraper) subscribeCasualties(ctx *battleCtx) {
-	subscribeNumbersFor(ctx, "Casualties and losses", &ctx.battle.Casualties)
+	s.subscribeNumbersFor(ctx, s.edition.Headings.Casualties, customCasualtiesID, &ctx.battle.Casualties)
 }
@@ -1,10 +1,13 @@
 package battles
 
 import (
+	"time"
+
 	"github.com/sasalatart/batcoms/db/memory"
 	"github.com/sasalatart/batcoms/domain/wikiactors"
 	"github.com/sasalatart/batcoms/domain/wikibattles"
 	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
 )
 
 // Scraper is the struct encapsulating all the necessary behaviour to scrape battles, one by one, as
@@ -12,24 +15,39 @@ import (
 type Scraper struct {
 	wikiActorsRepo  *memory.WikiActorsRepo
 	wikiBattlesRepo *memory.WikiBattlesRepo
-	logger          logger.Interface
+	logger          *logger.Logger
+	edition         editions.Edition
+	localizeTo      []string
 }
 
 // ExportedData is the struct used to retrieve all factions, commanders and battles that have been
 // scraped after successive runs of scraper.ScrapeOne. All of these have been normalized by their
-// Wikipedia IDs
+// Wikipedia IDs, which are only unique within the Language edition they were scraped from. The
+// format of the data is versioned by SchemaVersion, and ScrapedAt is when it was built
 type ExportedData struct {
+	SchemaVersion  int
+	ScrapedAt      time.Time
+	Language       string
 	FactionsByID   map[int]*wikiactors.Actor
 	CommandersByID map[int]*wikiactors.Actor
 	BattlesByID    map[int]*wikibattles.Battle
 }
 
-// NewScraper creates a new instance of battles.Scraper
+// NewScraper creates a new instance of battles.Scraper for the English edition of Wikipedia
 func NewScraper(l logger.Interface) Scraper {
+	return NewScraperFor(editions.English, l)
+}
+
+// NewScraperFor creates a new instance of battles.Scraper for the given language edition of
+// Wikipedia. The names and summaries of each battle in the localizeTo languages are also scraped,
+// by following its interlanguage links
+func NewScraperFor(edition editions.Edition, l logger.Interface, localizeTo ...string) Scraper {
 	return Scraper{
 		wikiActorsRepo:  memory.NewWikiActorsRepo(),
 		wikiBattlesRepo: memory.NewWikiBattlesRepo(),
-		logger:          l,
+		logger:          logger.From(l).Named("battles"),
+		edition:         edition,
+		localizeTo:      localizeTo,
 	}
 }
 
@@ -38,6 +56,9 @@ func NewScraper(l logger.Interface) Scraper {
 func (s *Scraper) Data() ExportedData {
 	factionsByID, commandersByID := s.wikiActorsRepo.Data()
 	return ExportedData{
+		SchemaVersion:  wikibattles.SchemaVersion,
+		ScrapedAt:      time.Now().UTC(),
+		Language:       s.edition.Language,
 		FactionsByID:   factionsByID,
 		CommandersByID: commandersByID,
 		BattlesByID:    s.wikiBattlesRepo.Data(),
@@ -2,15 +2,12 @@ package battles
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/sasalatart/batcoms/domain/wikiactors"
 )
 
 const contentSelector = "#content"
 
-const infoBoxSelector = ".infobox.vevent > tbody"
-
 const partOfSelector = "tr:nth-child(2) > td"
 const coordinatesSelector = "#coordinates"
 const placeSelector = ".location " + coordinatesSelector
@@ -21,10 +18,10 @@ const sideABSelector = "tr>td[colspan='2']"
 
 const customFactionsID = "batcoms-factions"
 const customCommandersID = "batcoms-commanders"
+const customStrengthID = "batcoms-strength"
+const customCasualtiesID = "batcoms-casualties"
 
-func customID(text string) string {
-	return "batcoms-" + strings.ReplaceAll(strings.ToLower(text), " ", "")
-}
+const interlanguageLinksSelector = "a.interlanguage-link-target[hreflang]"
 
 func sideNumbersSelector(side, customID string) string {
 	if side == sideABSelector {
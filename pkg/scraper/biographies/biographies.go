@@ -0,0 +1,110 @@
+package biographies
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gocolly/colly"
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/metrics"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
+	"github.com/sasalatart/batcoms/pkg/strclean"
+)
+
+// ErrNoInfoBox is used to communicate that the article of a commander has no infobox
+const ErrNoInfoBox = domain.Error("No biography infobox found")
+
+// isoDateSelector matches the machine-readable dates that some editions hide inside the rows of
+// births and deaths, such as "1769-08-15"
+const isoDateSelector = ".bday, .dday, .deathdate"
+
+const hiddenSelector = "[style*='display:none'], [style*='display: none'], sup.reference"
+
+var periodMatcher = regexp.MustCompile(`^(.*?)\s*\(([^()]*\d[^()]*)\)\.?$`)
+
+// Scrape scrapes the infobox of the Wikipedia article of a commander, found in the given edition,
+// and retrieves the details of their life. Dates are returned as written, except for those that
+// have a machine-readable version, which is preferred
+func Scrape(edition editions.Edition, url string) (wikiactors.Biography, error) {
+	var res wikiactors.Biography
+	found := false
+	headings := edition.BiographyHeadings
+
+	c := colly.NewCollector()
+	c.OnHTML(edition.BiographyInfoBoxSelector, func(e *colly.HTMLElement) {
+		if found {
+			return
+		}
+		found = true
+		e.ForEach("tr", func(_ int, row *colly.HTMLElement) {
+			heading := row.ChildText("th")
+			cell := row.DOM.Find("td").First()
+			switch {
+			case editions.MatchesAny(heading, headings.Born):
+				res.Born = lifeDate(cell)
+			case editions.MatchesAny(heading, headings.Died):
+				res.Died = lifeDate(cell)
+			case editions.MatchesAny(heading, headings.Allegiance):
+				res.Allegiances = allegiances(cell)
+			}
+		})
+	})
+
+	c.OnResponse(func(*colly.Response) {
+		metrics.ScraperPages.WithLabelValues("biography").Inc()
+	})
+
+	if err := c.Visit(url); err != nil {
+		return wikiactors.Biography{}, errors.Wrapf(err, "Scraping biography in %s", url)
+	}
+	if !found {
+		return wikiactors.Biography{}, ErrNoInfoBox
+	}
+	return res, nil
+}
+
+// lifeDate returns the date of birth or death written in a cell, followed by the place, if any
+func lifeDate(cell *goquery.Selection) string {
+	if iso := strings.TrimSpace(cell.Find(isoDateSelector).First().Text()); iso != "" {
+		return strings.Trim(iso, "()")
+	}
+	return strclean.Apply(cellText(cell))
+}
+
+// allegiances returns the states or factions listed in a cell, which may be written as a list or
+// separated by line breaks, together with the periods written between parentheses after them
+func allegiances(cell *goquery.Selection) []wikiactors.Allegiance {
+	var lines []string
+	if items := cell.Find("li"); items.Length() > 0 {
+		items.Each(func(_ int, item *goquery.Selection) {
+			lines = append(lines, cellText(item))
+		})
+	} else {
+		lines = strings.Split(cellText(cell), "\n")
+	}
+
+	res := []wikiactors.Allegiance{}
+	for _, line := range lines {
+		line = strclean.Apply(line)
+		if line == "" {
+			continue
+		}
+		allegiance := wikiactors.Allegiance{Name: line}
+		if matches := periodMatcher.FindStringSubmatch(line); matches != nil {
+			allegiance = wikiactors.Allegiance{Name: matches[1], Period: strings.TrimSpace(matches[2])}
+		}
+		res = append(res, allegiance)
+	}
+	return res
+}
+
+// cellText returns the visible text of a node of an infobox, with its line breaks preserved
+func cellText(node *goquery.Selection) string {
+	clone := node.Clone()
+	clone.Find(hiddenSelector).Remove()
+	clone.Find("br").ReplaceWithHtml("\n")
+	return clone.Text()
+}
@@ -0,0 +1,57 @@
+package biographies_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sasalatart/batcoms/domain/wikiactors"
+	"github.com/sasalatart/batcoms/pkg/scraper/biographies"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScrape(t *testing.T) {
+	server := httptest.NewServer(http.StripPrefix("/wiki/", http.FileServer(http.Dir("testdata"))))
+	defer server.Close()
+
+	cases := []struct {
+		edition  editions.Edition
+		path     string
+		expected wikiactors.Biography
+	}{
+		{
+			edition: editions.English,
+			path:    "/wiki/Napoleon.html",
+			expected: wikiactors.Biography{
+				Born: "1769-08-15",
+				Died: "5 May 1821 (aged 51). Longwood, Saint Helena",
+				Allegiances: []wikiactors.Allegiance{
+					{Name: "Kingdom of France", Period: "1785–1792"},
+					{Name: "French First Republic", Period: "1792–1804"},
+					{Name: "First French Empire", Period: "1804–1815"},
+				},
+			},
+		},
+		{
+			edition: editions.Spanish,
+			path:    "/wiki/Gustavo_Adolfo_Rey_de_Suecia.html",
+			expected: wikiactors.Biography{
+				Born:        "9 de diciembre de 1594. Estocolmo (Suecia)",
+				Died:        "6 de noviembre de 1632. Lützen (Sacro Imperio Romano Germánico)",
+				Allegiances: []wikiactors.Allegiance{{Name: "Imperio sueco"}},
+			},
+		},
+	}
+	for _, c := range cases {
+		got, err := biographies.Scrape(c.edition, server.URL+c.path)
+		require.NoError(t, err, "Scraping %s", c.path)
+		assert.Equal(t, c.expected, got, "Scraping %s", c.path)
+	}
+
+	t.Run("WithoutInfoBox", func(t *testing.T) {
+		_, err := biographies.Scrape(editions.English, server.URL+"/wiki/Ajaccio.html")
+		assert.Equal(t, biographies.ErrNoInfoBox, err)
+	})
+}
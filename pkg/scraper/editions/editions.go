@@ -0,0 +1,205 @@
+package editions
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain"
+)
+
+// Edition describes a language edition of Wikipedia, with everything the scrapers need to know in
+// order to find battles in it and read their infoboxes, together with those of commanders
+type Edition struct {
+	Language                 string
+	BaseURL                  string
+	BattlesLists             []string
+	InfoBoxSelector          string
+	Headings                 Headings
+	BiographyInfoBoxSelector string
+	BiographyHeadings        BiographyHeadings
+}
+
+// Headings contains the (lowercase) texts used by an Edition to title each section of the infobox
+// of a battle. Some sections have more than one accepted title, and sections without titles are
+// not scraped
+type Headings struct {
+	PartOf             []string
+	Belligerents       []string
+	Commanders         []string
+	Strength           []string
+	Casualties         []string
+	Date               []string
+	Result             []string
+	Location           []string
+	TerritorialChanges []string
+}
+
+// BiographyHeadings contains the (lowercase) texts used by an Edition to title each row of the
+// infobox of a commander
+type BiographyHeadings struct {
+	Born       []string
+	Died       []string
+	Allegiance []string
+}
+
+// ErrUnknownEdition is used to communicate that there is no Edition for a language
+const ErrUnknownEdition = domain.Error("Unknown Wikipedia edition")
+
+// English is the English edition of Wikipedia (en.wikipedia.org)
+var English = Edition{
+	Language: "en",
+	BaseURL:  "https://en.wikipedia.org",
+	BattlesLists: []string{
+		"/Battles_of_the_Seven_Years%27_War",
+		"/List_of_American_Civil_War_battles",
+		"/List_of_American_Revolutionary_War_battles",
+		"/List_of_battles_(alphabetical)",
+		"/List_of_battles_(geographic)",
+		"/List_of_battles_301-1300",
+		"/List_of_battles_1301-1600",
+		"/List_of_battles_1601-1800",
+		"/List_of_battles_1801-1900",
+		"/List_of_battles_1901-2000",
+		"/List_of_battles_before_301",
+		"/List_of_battles_since_2001",
+		"/List_of_Hundred_Years%27_War_battles",
+		"/List_of_military_engagements_of_World_War_I",
+		"/List_of_military_engagements_of_World_War_II",
+		"/List_of_Napoleonic_battles",
+	},
+	InfoBoxSelector: ".infobox.vevent > tbody",
+	Headings: Headings{
+		PartOf:             []string{"part of"},
+		Belligerents:       []string{"belligerents"},
+		Commanders:         []string{"commanders and leaders"},
+		Strength:           []string{"strength"},
+		Casualties:         []string{"casualties and losses"},
+		Date:               []string{"date"},
+		Result:             []string{"result", "status"},
+		Location:           []string{"location"},
+		TerritorialChanges: []string{"territorialchanges"},
+	},
+	BiographyInfoBoxSelector: ".infobox.vcard > tbody",
+	BiographyHeadings: BiographyHeadings{
+		Born:       []string{"born"},
+		Died:       []string{"died"},
+		Allegiance: []string{"allegiance", "allegiances"},
+	},
+}
+
+// Spanish is the Spanish edition of Wikipedia (es.wikipedia.org)
+var Spanish = Edition{
+	Language: "es",
+	BaseURL:  "https://es.wikipedia.org",
+	BattlesLists: []string{
+		"/Anexo:Batallas",
+	},
+	InfoBoxSelector: ".infobox > tbody",
+	Headings: Headings{
+		PartOf:             []string{"parte de"},
+		Belligerents:       []string{"beligerantes"},
+		Commanders:         []string{"comandantes", "líderes políticos", "comandantes y líderes"},
+		Strength:           []string{"fuerzas en combate", "unidades en combate"},
+		Casualties:         []string{"bajas", "bajas y pérdidas"},
+		Date:               []string{"fecha"},
+		Result:             []string{"resultado"},
+		Location:           []string{"lugar"},
+		TerritorialChanges: []string{"cambios territoriales"},
+	},
+	BiographyInfoBoxSelector: ".infobox > tbody",
+	BiographyHeadings: BiographyHeadings{
+		Born:       []string{"nacimiento"},
+		Died:       []string{"fallecimiento"},
+		Allegiance: []string{"lealtad"},
+	},
+}
+
+// French is the French edition of Wikipedia (fr.wikipedia.org)
+var French = Edition{
+	Language: "fr",
+	BaseURL:  "https://fr.wikipedia.org",
+	BattlesLists: []string{
+		"/Liste_de_batailles",
+	},
+	InfoBoxSelector: ".infobox_v2 > tbody",
+	Headings: Headings{
+		Belligerents:       []string{"belligérants"},
+		Commanders:         []string{"commandants"},
+		Strength:           []string{"forces en présence"},
+		Casualties:         []string{"pertes"},
+		Date:               []string{"date"},
+		Result:             []string{"issue"},
+		Location:           []string{"lieu"},
+		TerritorialChanges: []string{"changements territoriaux"},
+	},
+	BiographyInfoBoxSelector: ".infobox_v3 .data-table > tbody",
+	BiographyHeadings: BiographyHeadings{
+		Born:       []string{"naissance"},
+		Died:       []string{"décès"},
+		Allegiance: []string{"allégeance"},
+	},
+}
+
+// German is the German edition of Wikipedia (de.wikipedia.org)
+var German = Edition{
+	Language: "de",
+	BaseURL:  "https://de.wikipedia.org",
+	BattlesLists: []string{
+		"/Liste_von_Schlachten",
+	},
+	InfoBoxSelector: ".infobox > tbody",
+	Headings: Headings{
+		PartOf:       []string{"teil von"},
+		Belligerents: []string{"konfliktparteien"},
+		Commanders:   []string{"befehlshaber"},
+		Strength:     []string{"truppenstärke"},
+		Casualties:   []string{"verluste"},
+		Date:         []string{"datum"},
+		Result:       []string{"ausgang"},
+		Location:     []string{"ort"},
+	},
+	BiographyInfoBoxSelector: ".infobox > tbody",
+	BiographyHeadings: BiographyHeadings{
+		Born:       []string{"geboren"},
+		Died:       []string{"gestorben"},
+		Allegiance: []string{"loyalität"},
+	},
+}
+
+var byLanguage = map[string]Edition{
+	English.Language: English,
+	Spanish.Language: Spanish,
+	French.Language:  French,
+	German.Language:  German,
+}
+
+// ByLanguage returns the Edition of Wikipedia written in the given language, identified by its
+// ISO 639-1 code. English is used when the language is empty
+func ByLanguage(language string) (Edition, error) {
+	if language == "" {
+		return English, nil
+	}
+	e, ok := byLanguage[language]
+	if !ok {
+		return Edition{}, errors.Wrapf(ErrUnknownEdition, "Looking for %q", language)
+	}
+	return e, nil
+}
+
+// ArticleURL returns the full URL of an article of the Edition, given its path (such as
+// "/wiki/Battle_of_Austerlitz")
+func (e Edition) ArticleURL(path string) string {
+	return e.BaseURL + path
+}
+
+// MatchesAny returns true if the text equals any of the given headings, regardless of casing and
+// surrounding spaces
+func MatchesAny(text string, headings []string) bool {
+	text = strings.TrimSpace(text)
+	for _, heading := range headings {
+		if strings.EqualFold(text, heading) {
+			return true
+		}
+	}
+	return false
+}
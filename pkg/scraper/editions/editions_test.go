@@ -0,0 +1,39 @@
+package editions_test
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestByLanguage(t *testing.T) {
+	cases := []struct {
+		language string
+		expected editions.Edition
+	}{
+		{"", editions.English},
+		{"en", editions.English},
+		{"es", editions.Spanish},
+		{"fr", editions.French},
+		{"de", editions.German},
+	}
+	for _, c := range cases {
+		got, err := editions.ByLanguage(c.language)
+		require.NoErrorf(t, err, "Looking for the %q edition", c.language)
+		assert.Equal(t, c.expected.BaseURL, got.BaseURL, "Looking for the %q edition", c.language)
+	}
+
+	_, err := editions.ByLanguage("xx")
+	assert.Equal(t, editions.ErrUnknownEdition, errors.Cause(err))
+}
+
+func TestMatchesAny(t *testing.T) {
+	headings := []string{"born", "Died"}
+	assert.True(t, editions.MatchesAny(" Born\n", headings))
+	assert.True(t, editions.MatchesAny("died", headings))
+	assert.False(t, editions.MatchesAny("Allegiance", headings))
+	assert.False(t, editions.MatchesAny("Born in", headings))
+}
@@ -1,11 +1,13 @@
 package list
 
 import (
-	"fmt"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sasalatart/batcoms/domain/wikibattles"
 	"github.com/sasalatart/batcoms/pkg/logger"
+	"github.com/sasalatart/batcoms/pkg/metrics"
+	"github.com/sasalatart/batcoms/pkg/scraper/editions"
 	"github.com/sasalatart/batcoms/pkg/scraper/names"
 	"github.com/sasalatart/batcoms/pkg/scraper/urls"
 	"github.com/sasalatart/batcoms/pkg/strclean"
@@ -18,19 +20,30 @@ type hrefsCache map[string]struct{}
 // Scrape scrapes and retrieves the full list of Wikipedia's battles when grouped by different
 // criteria, in the form of wikibattles.BattleItem
 func Scrape(l logger.Interface) []wikibattles.BattleItem {
+	return ScrapeEdition(editions.English, l)
+}
+
+// ScrapeEdition is like Scrape, but for the lists of battles of the given language edition of
+// Wikipedia
+func ScrapeEdition(edition editions.Edition, l logger.Interface) []wikibattles.BattleItem {
+	log := logger.From(l).Named("list")
 	hrefs := make(hrefsCache)
 	var items []wikibattles.BattleItem
-	for _, urlPart := range battlesLists {
-		listURL := "https://en.wikipedia.org/wiki" + urlPart
-		if err := do(listURL, &items, hrefs, l); err != nil {
-			l.Error(errors.Wrapf(err, "Error scraping list in %s\n", listURL))
+	start := time.Now()
+	for _, urlPart := range edition.BattlesLists {
+		listURL := edition.ArticleURL("/wiki" + urlPart)
+		if err := do(edition, listURL, &items, hrefs, log); err != nil {
+			log.With(logger.KV("url", listURL)).Error(errors.Wrap(err, "Error scraping list"))
 		}
 	}
-	l.Info(fmt.Sprintf("There are %d items that can be scraped\n", len(items)))
+	log.With(
+		logger.KV("count", len(items)),
+		logger.KV("duration_ms", time.Since(start).Milliseconds()),
+	).Info("Finished scraping battle lists")
 	return items
 }
 
-func do(url string, battlesItems *[]wikibattles.BattleItem, hrefs hrefsCache, l logger.Interface) error {
+func do(edition editions.Edition, url string, battlesItems *[]wikibattles.BattleItem, hrefs hrefsCache, l *logger.Logger) error {
 	c := colly.NewCollector()
 
 	c.OnHTML(listItemsSelector, func(e *colly.HTMLElement) {
@@ -39,40 +52,25 @@ func do(url string, battlesItems *[]wikibattles.BattleItem, hrefs hrefsCache, l
 			return
 		}
 		name := strclean.Apply(e.Text)
-		if !names.IsBattle(name) {
+		if !names.IsBattleIn(edition.Language, name) {
 			return
 		}
 		hrefs[href] = struct{}{}
 		*battlesItems = append(*battlesItems, wikibattles.BattleItem{
-			URL:  "https://en.wikipedia.org" + href,
+			URL:  edition.ArticleURL(href),
 			Name: name,
 		})
 	})
 
 	c.OnRequest(func(r *colly.Request) {
-		l.Info(fmt.Sprintf("Scraping %s\n", r.URL))
+		l.With(logger.KV("url", r.URL.String())).Info("Scraping list")
+	})
+
+	c.OnResponse(func(*colly.Response) {
+		metrics.ScraperPages.WithLabelValues("list").Inc()
 	})
 
 	return c.Visit(url)
 }
 
 const listItemsSelector = "#content a[href]"
-
-var battlesLists = []string{
-	"/Battles_of_the_Seven_Years%27_War",
-	"/List_of_American_Civil_War_battles",
-	"/List_of_American_Revolutionary_War_battles",
-	"/List_of_battles_(alphabetical)",
-	"/List_of_battles_(geographic)",
-	"/List_of_battles_301-1300",
-	"/List_of_battles_1301-1600",
-	"/List_of_battles_1601-1800",
-	"/List_of_battles_1801-1900",
-	"/List_of_battles_1901-2000",
-	"/List_of_battles_before_301",
-	"/List_of_battles_since_2001",
-	"/List_of_Hundred_Years%27_War_battles",
-	"/List_of_military_engagements_of_World_War_I",
-	"/List_of_military_engagements_of_World_War_II",
-	"/List_of_Napoleonic_battles",
-}
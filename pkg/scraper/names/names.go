@@ -26,7 +26,25 @@ var keywords = strings.Join([]string{
 
 var matcher = regexp.MustCompile(fmt.Sprintf(`(?i)(%s)s?`, keywords))
 
+// localizedMatchers contain the keywords that usually appear in the names of battles in languages
+// other than English
+var localizedMatchers = map[string]*regexp.Regexp{
+	"es": regexp.MustCompile(`(?i)(asalto|batalla|bloqueo|bombardeo|campaña|captura|combate|conquista|emboscada|escaramuza|expedición|incursión|invasión|levantamiento|masacre|ofensiva|operación|rebelión|revuelta|saqueo|sitio|toma)`),
+	"fr": regexp.MustCompile(`(?i)(assaut|bataille|blocus|bombardement|campagne|capture|combat|conquête|embuscade|escarmouche|expédition|insurrection|invasion|massacre|offensive|opération|prise|raid|rébellion|révolte|sac|siège|soulèvement)`),
+	"de": regexp.MustCompile(`(?i)(angriff|aufstand|belagerung|blockade|bombardierung|eroberung|expedition|feldzug|gefecht|hinterhalt|invasion|landung|massaker|offensive|operation|plünderung|schlacht|seeschlacht|überfall)`),
+}
+
 // IsBattle returns true if the given name probably corresponds to a battle, and false if not
 func IsBattle(name string) bool {
 	return matcher.MatchString(name)
 }
+
+// IsBattleIn is like IsBattle, but for names written in the given language, identified by its
+// ISO 639-1 code. English keywords are used for languages without keywords of their own
+func IsBattleIn(language, name string) bool {
+	localized, ok := localizedMatchers[language]
+	if !ok {
+		return IsBattle(name)
+	}
+	return localized.MatchString(name)
+}
@@ -76,3 +76,30 @@ func TestNames(t *testing.T) {
 		assert.Equal(t, c.expected, got, "Expected names.IsBattle(%q) to be %t", c.name, c.expected)
 	}
 }
+
+func TestNamesIn(t *testing.T) {
+	cases := []struct {
+		language string
+		name     string
+		expected bool
+	}{
+		{"es", "Batalla de Austerlitz", true},
+		{"es", "Sitio de Viena (1683)", true},
+		{"es", "Toma de Granada", true},
+		{"es", "Napoleón Bonaparte", false},
+		{"fr", "Bataille d'Austerlitz", true},
+		{"fr", "Siège d'Alésia", true},
+		{"fr", "Prise de la Bastille", true},
+		{"fr", "Empire français", false},
+		{"de", "Schlacht bei Austerlitz", true},
+		{"de", "Zweite Wiener Türkenbelagerung", true},
+		{"de", "Gefecht bei Lexington", true},
+		{"de", "Erwin Rommel", false},
+		{"", "Battle of Austerlitz", true},
+		{"xx", "Napoleon", false},
+	}
+	for _, c := range cases {
+		got := names.IsBattleIn(c.language, c.name)
+		assert.Equal(t, c.expected, got, "Expected names.IsBattleIn(%q, %q) to be %t", c.language, c.name, c.expected)
+	}
+}
@@ -19,6 +19,9 @@ var notSpecificMatcher = regexp.MustCompile(`(?i)` + strings.Join([]string{
 // confused to be a battle or participant
 var falsePositivesMatcher = regexp.MustCompile(fmt.Sprintf(`(?i)/wiki/(%s)`, strings.Join([]string{
 	`(category|file|help|portal|talk|wikipedia):`,
+	`(anexo|archivo|ayuda|categoría|categor%c3%ada|discusión|discusi%c3%b3n|portal):`,
+	`(aide|catégorie|cat%c3%a9gorie|discussion|fichier|portail|wikipédia|wikip%c3%a9dia):`,
+	`(datei|diskussion|hilfe|kategorie):`,
 	`(flag|in_absentia|killed_in_action|pow|prisoner_of_war|surrender_\(military\)|wia|wounded_in_action)$`,
 	`[\w-,]*(advisor|chief_of|division|force|marines|participants|politics|rangers|regiment)[\w-,]*`,
 	`(army|auxiliaries|caliphate|cia|commandery|conscription|crusades|empire|islam|islamism|jewish|jews|muslim_conquests|offensive_jihad|roman_emperor|sicherheitsdienst)$`,
@@ -58,6 +58,14 @@ func TestURLs(t *testing.T) {
 			{"/wiki/Talk:Battle_of_Vyazma", true},
 			{"/wiki/Battle_of_Vyazma", false},
 			{"/wiki/Category:Battles_of_World_War_I", true},
+			{"https://es.wikipedia.org/wiki/Categor%C3%ADa:Batallas_de_Espa%C3%B1a", true},
+			{"https://es.wikipedia.org/wiki/Anexo:Batallas", true},
+			{"https://es.wikipedia.org/wiki/Batalla_de_Bail%C3%A9n", false},
+			{"https://fr.wikipedia.org/wiki/Cat%C3%A9gorie:Bataille_de_1805", true},
+			{"https://fr.wikipedia.org/wiki/Portail:Histoire_militaire", true},
+			{"https://fr.wikipedia.org/wiki/Bataille_d%27Austerlitz", false},
+			{"https://de.wikipedia.org/wiki/Datei:Flag_of_France.svg", true},
+			{"https://de.wikipedia.org/wiki/Schlacht_bei_Austerlitz", false},
 			{"/wiki/File:Territorial_Organization_of_the_Aztec_Empire_1519.png", true},
 			{"/wiki/Wikipedia:Citation_needed", true},
 			{"/wiki/Killed_in_action", true},
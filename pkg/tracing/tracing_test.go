@@ -0,0 +1,70 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sasalatart/batcoms/pkg/tracing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestSetup(t *testing.T) {
+	t.Run("None", func(t *testing.T) {
+		shutdown, err := tracing.Setup(tracing.ExporterNone, "", "batcoms")
+		require.NoError(t, err, "Setting up tracing")
+		assert.NoError(t, shutdown(context.Background()))
+	})
+
+	t.Run("Unknown", func(t *testing.T) {
+		_, err := tracing.Setup("jaeger", "", "batcoms")
+		assert.EqualError(t, err, `Unknown tracing exporter "jaeger"`)
+	})
+}
+
+func TestInstrumentGORM(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err, "Opening stub database")
+	defer sqlDB.Close()
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{})
+	require.NoError(t, err, "Opening gorm database")
+	require.NoError(t, tracing.InstrumentGORM(db, "postgresql"), "Instrumenting gorm database")
+
+	ctx, parent := tracing.Tracer().Start(context.Background(), "request")
+	mock.ExpectQuery(`SELECT \* FROM "factions"`).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectQuery(`SELECT \* FROM "battles"`).WillReturnError(errors.New("connection reset"))
+	var rows []struct{ ID int }
+	require.NoError(t, db.WithContext(ctx).Table("factions").Find(&rows).Error, "Querying factions")
+	require.Error(t, db.WithContext(ctx).Table("battles").Find(&rows).Error, "Querying battles")
+	parent.End()
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 3)
+	for i, table := range []string{"factions", "battles"} {
+		span := spans[i]
+		assert.Equal(t, "gorm.query", span.Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), span.Parent().SpanID(), "Should be a child of the request")
+		attributes := make(map[attribute.Key]string)
+		for _, kv := range span.Attributes() {
+			attributes[kv.Key] = kv.Value.Emit()
+		}
+		assert.Equal(t, "postgresql", attributes["db.system"])
+		assert.Equal(t, table, attributes["db.sql.table"])
+		assert.Equal(t, `SELECT * FROM "`+table+`"`, attributes["db.statement"])
+	}
+	assert.Equal(t, codes.Unset, spans[0].Status().Code)
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
@@ -0,0 +1,95 @@
+package wikidata
+
+import (
+	"encoding/json"
+
+	"github.com/sasalatart/batcoms/pkg/dates"
+)
+
+// best returns the claims that should be trusted among the given ones: the preferred ones if there
+// are any, or else all of those that have not been deprecated
+func best(claims []claim) []claim {
+	var preferred, normal []claim
+	for _, c := range claims {
+		switch c.Rank {
+		case "preferred":
+			preferred = append(preferred, c)
+		case "deprecated":
+		default:
+			normal = append(normal, c)
+		}
+	}
+	if len(preferred) > 0 {
+		return preferred
+	}
+	return normal
+}
+
+func timeClaim(claims []claim) *dates.Historic {
+	for _, c := range best(claims) {
+		value := timeValue{}
+		if c.MainSnak.DataValue.Type != "time" || json.Unmarshal(c.MainSnak.DataValue.Value, &value) != nil {
+			continue
+		}
+		if h, err := value.historic(); err == nil {
+			return &h
+		}
+	}
+	return nil
+}
+
+func coordinatesClaim(claims []claim) *Coordinates {
+	for _, c := range best(claims) {
+		value := Coordinates{}
+		if c.MainSnak.DataValue.Type != "globecoordinate" || json.Unmarshal(c.MainSnak.DataValue.Value, &value) != nil {
+			continue
+		}
+		return &value
+	}
+	return nil
+}
+
+// references collects the IDs of the Wikidata items referenced by claims, so that all of their
+// labels may be fetched at once
+type references struct {
+	ids  []string
+	seen map[string]bool
+}
+
+func newReferences() *references {
+	return &references{seen: make(map[string]bool)}
+}
+
+// add collects the IDs of the items referenced by at most limit of the given claims (or all of them
+// if limit is negative), and returns them
+func (r *references) add(claims []claim, limit int) []string {
+	var res []string
+	for _, c := range best(claims) {
+		if limit >= 0 && len(res) == limit {
+			break
+		}
+		value := struct {
+			ID string `json:"id"`
+		}{}
+		if c.MainSnak.DataValue.Type != "wikibase-entityid" || json.Unmarshal(c.MainSnak.DataValue.Value, &value) != nil || value.ID == "" {
+			continue
+		}
+		res = append(res, value.ID)
+		if !r.seen[value.ID] {
+			r.seen[value.ID] = true
+			r.ids = append(r.ids, value.ID)
+		}
+	}
+	return res
+}
+
+// labelsOf returns the labels of the items with the given IDs, skipping those without one
+func labelsOf(ids []string, labels map[string]string) []string {
+	var res []string
+	for _, id := range ids {
+		if l, ok := labels[id]; ok {
+			res = append(res, l)
+		}
+	}
+	return res
+}
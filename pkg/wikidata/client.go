@@ -0,0 +1,147 @@
+package wikidata
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/summaries"
+)
+
+// DefaultURL is the URL of the API of Wikidata
+const DefaultURL = "https://www.wikidata.org/w/api.php"
+
+// Client queries the API of Wikidata for the Facts of Wikipedia pages
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewClient creates a new instance of wikidata.Client, that queries the API found in the given URL
+// (usually DefaultURL)
+func NewClient(baseURL string) *Client {
+	return &Client{baseURL: baseURL, httpClient: http.DefaultClient}
+}
+
+// entitiesResponse is the subset of the response of the wbgetentities action that is used by the
+// Client
+type entitiesResponse struct {
+	Entities map[string]struct {
+		ID      string             `json:"id"`
+		Missing *string            `json:"missing"`
+		Labels  map[string]label   `json:"labels"`
+		Claims  map[string][]claim `json:"claims"`
+	} `json:"entities"`
+}
+
+type label struct {
+	Value string `json:"value"`
+}
+
+type claim struct {
+	MainSnak struct {
+		DataValue struct {
+			Type  string          `json:"type"`
+			Value json.RawMessage `json:"value"`
+		} `json:"datavalue"`
+	} `json:"mainsnak"`
+	Rank string `json:"rank"`
+}
+
+// Facts resolves the Wikipedia page found in the given URL to its Wikidata item, and returns the
+// Facts known about it. The names of places and countries are given in the language of the page
+func (c *Client) Facts(pageURL string) (Facts, error) {
+	site, title, language, err := siteAndTitle(pageURL)
+	if err != nil {
+		return Facts{}, err
+	}
+
+	res, err := c.getEntities(url.Values{"sites": {site}, "titles": {title}, "props": {"claims"}})
+	if err != nil {
+		return Facts{}, errors.Wrapf(err, "Getting Wikidata item of %s", pageURL)
+	}
+	for _, entity := range res.Entities {
+		if entity.Missing != nil || entity.ID == "" {
+			return Facts{}, ErrNotFound
+		}
+		facts := Facts{QID: entity.ID}
+		refs := newReferences()
+		facts.BirthDate = timeClaim(entity.Claims[propBirthDate])
+		facts.DeathDate = timeClaim(entity.Claims[propDeathDate])
+		facts.Inception = timeClaim(entity.Claims[propInception])
+		facts.Dissolution = timeClaim(entity.Claims[propDissolution])
+		facts.PointInTime = timeClaim(entity.Claims[propPointInTime])
+		facts.StartTime = timeClaim(entity.Claims[propStartTime])
+		facts.EndTime = timeClaim(entity.Claims[propEndTime])
+		facts.Coordinates = coordinatesClaim(entity.Claims[propCoordinates])
+		birthPlace := refs.add(entity.Claims[propBirthPlace], 1)
+		deathPlace := refs.add(entity.Claims[propDeathPlace], 1)
+		nationalities := refs.add(entity.Claims[propCitizenship], -1)
+
+		labels, err := c.labels(refs.ids, language)
+		if err != nil {
+			return Facts{}, errors.Wrapf(err, "Getting labels for Wikidata item %s", entity.ID)
+		}
+		facts.BirthPlace = strings.Join(labelsOf(birthPlace, labels), "")
+		facts.DeathPlace = strings.Join(labelsOf(deathPlace, labels), "")
+		facts.Nationalities = labelsOf(nationalities, labels)
+		return facts, nil
+	}
+	return Facts{}, ErrNotFound
+}
+
+// labels returns the labels of the Wikidata items with the given IDs in a language, indexed by ID
+func (c *Client) labels(ids []string, language string) (map[string]string, error) {
+	labels := make(map[string]string)
+	if len(ids) == 0 {
+		return labels, nil
+	}
+	res, err := c.getEntities(url.Values{
+		"ids":       {strings.Join(ids, "|")},
+		"props":     {"labels"},
+		"languages": {language},
+	})
+	if err != nil {
+		return labels, err
+	}
+	for id, entity := range res.Entities {
+		if l, ok := entity.Labels[language]; ok {
+			labels[id] = l.Value
+		}
+	}
+	return labels, nil
+}
+
+func (c *Client) getEntities(params url.Values) (entitiesResponse, error) {
+	params.Set("action", "wbgetentities")
+	params.Set("format", "json")
+	reqURL := c.baseURL + "?" + params.Encode()
+	res := entitiesResponse{}
+
+	resp, err := c.httpClient.Get(reqURL)
+	if err != nil {
+		return res, errors.Wrapf(err, "GET %s failed", reqURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return res, errors.Errorf("GET %s responded with status %d", reqURL, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return res, errors.Wrapf(err, "Unmarshalling response from %s", reqURL)
+	}
+	return res, nil
+}
+
+// siteAndTitle returns the Wikidata site ID (such as "enwiki"), the title and the language of the
+// Wikipedia page found in the given URL
+func siteAndTitle(pageURL string) (string, string, string, error) {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || !strings.HasSuffix(parsed.Host, ".wikipedia.org") || !strings.HasPrefix(parsed.Path, "/wiki/") {
+		return "", "", "", summaries.ErrNotWiki
+	}
+	language := strings.TrimSuffix(parsed.Host, ".wikipedia.org")
+	title := strings.ReplaceAll(strings.TrimPrefix(parsed.Path, "/wiki/"), "_", " ")
+	return language + "wiki", title, language, nil
+}
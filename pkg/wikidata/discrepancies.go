@@ -0,0 +1,45 @@
+package wikidata
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/pkg/dates"
+)
+
+// maxCoordinatesDelta is the maximum difference, in decimal degrees, between scraped coordinates
+// and those of Wikidata before they are reported as a discrepancy
+const maxCoordinatesDelta = 0.5
+
+// Discrepancies cross-checks the text of the date and the location scraped for a battle against
+// its Facts, and describes those that do not match. Only years are compared, since both sources
+// often differ in how precise they are
+func (f Facts) Discrepancies(date string, location locations.Location) []string {
+	var res []string
+
+	if parsed, err := dates.Parse(date); err == nil && len(parsed) > 0 {
+		start, end := parsed[0], parsed[len(parsed)-1]
+		check := func(name string, scraped dates.Historic, found *dates.Historic) {
+			if found != nil && (found.Year != scraped.Year || found.IsBCE != scraped.IsBCE) {
+				res = append(res, fmt.Sprintf("%s is %s, but %s was scraped", name, found, scraped))
+			}
+		}
+		check("point in time", start, f.PointInTime)
+		check("start time", start, f.StartTime)
+		check("end time", end, f.EndTime)
+	}
+
+	if lat, lon, ok := location.Coordinates(); ok && f.Coordinates != nil {
+		if math.Abs(lat-f.Coordinates.Latitude) > maxCoordinatesDelta || math.Abs(lon-f.Coordinates.Longitude) > maxCoordinatesDelta {
+			res = append(res, fmt.Sprintf(
+				"coordinates are %.4f, %.4f, but %.4f, %.4f were scraped",
+				f.Coordinates.Latitude,
+				f.Coordinates.Longitude,
+				lat,
+				lon,
+			))
+		}
+	}
+	return res
+}
@@ -0,0 +1,110 @@
+package wikidata
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/sasalatart/batcoms/domain"
+	"github.com/sasalatart/batcoms/pkg/dates"
+)
+
+// Facts are the structured properties of a Wikidata item that are relevant to battles, commanders
+// and factions. Only those found in Wikidata are set
+type Facts struct {
+	QID           string          `json:"qid"`
+	BirthDate     *dates.Historic `json:"birthDate,omitempty"`
+	BirthPlace    string          `json:"birthPlace,omitempty"`
+	DeathDate     *dates.Historic `json:"deathDate,omitempty"`
+	DeathPlace    string          `json:"deathPlace,omitempty"`
+	Nationalities []string        `json:"nationalities,omitempty"`
+	Inception     *dates.Historic `json:"inception,omitempty"`
+	Dissolution   *dates.Historic `json:"dissolution,omitempty"`
+	PointInTime   *dates.Historic `json:"pointInTime,omitempty"`
+	StartTime     *dates.Historic `json:"startTime,omitempty"`
+	EndTime       *dates.Historic `json:"endTime,omitempty"`
+	Coordinates   *Coordinates    `json:"coordinates,omitempty"`
+}
+
+// Coordinates are a point on Earth, in signed decimal degrees
+type Coordinates struct {
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// ErrNotFound is used to communicate that a Wikipedia page has no Wikidata item
+const ErrNotFound = domain.Error("Wikidata item not found")
+
+// Properties of Wikidata items, as documented in https://www.wikidata.org/wiki/Wikidata:Database_reports/List_of_properties
+const (
+	propBirthDate   = "P569"
+	propBirthPlace  = "P19"
+	propDeathDate   = "P570"
+	propDeathPlace  = "P20"
+	propCitizenship = "P27"
+	propInception   = "P571"
+	propDissolution = "P576"
+	propPointInTime = "P585"
+	propStartTime   = "P580"
+	propEndTime     = "P582"
+	propCoordinates = "P625"
+)
+
+// Precisions of Wikidata time values
+const (
+	precisionDecade = 8
+	precisionYear   = 9
+	precisionMonth  = 10
+	precisionDay    = 11
+)
+
+var calendarModels = map[string]dates.Calendar{
+	"http://www.wikidata.org/entity/Q1985727": dates.GregorianCalendar,
+	"http://www.wikidata.org/entity/Q1985786": dates.JulianCalendar,
+}
+
+var timeMatcher = regexp.MustCompile(`^([+-])(\d+)-(\d{2})-(\d{2})T`)
+
+// timeValue is the value of a Wikidata claim about a point in time. Years before the common era are
+// negative, without a year 0
+type timeValue struct {
+	Time          string `json:"time"`
+	Precision     int    `json:"precision"`
+	CalendarModel string `json:"calendarmodel"`
+}
+
+// historic translates a timeValue into a Historic date, keeping only the parts of the date allowed
+// by its precision. Values less precise than a decade are kept as years, but marked as approximate
+func (t timeValue) historic() (dates.Historic, error) {
+	matches := timeMatcher.FindStringSubmatch(t.Time)
+	if matches == nil {
+		return dates.Historic{}, dates.ErrNotDate
+	}
+	year, _ := strconv.Atoi(matches[2])
+	month, _ := strconv.Atoi(matches[3])
+	day, _ := strconv.Atoi(matches[4])
+	if year == 0 {
+		return dates.Historic{}, dates.ErrNotDate
+	}
+
+	h := dates.Historic{Year: year, IsBCE: matches[1] == "-"}
+	switch {
+	case t.Precision >= precisionDay:
+		h.Month, h.Day = month, day
+	case t.Precision == precisionMonth:
+		h.Month = month
+	case t.Precision == precisionDecade && !h.IsBCE:
+		decade, err := dates.ParseEDTF(fmt.Sprintf("%03dX", year/10))
+		if err != nil {
+			return dates.Historic{}, err
+		}
+		h = decade
+	case t.Precision < precisionDecade:
+		h.Approximate = true
+	}
+	if !h.IsValid() {
+		return dates.Historic{}, dates.ErrNotDate
+	}
+	h.Calendar = calendarModels[t.CalendarModel]
+	return h, nil
+}
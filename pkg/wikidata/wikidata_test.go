@@ -0,0 +1,117 @@
+package wikidata_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/sasalatart/batcoms/domain/locations"
+	"github.com/sasalatart/batcoms/domain/summaries"
+	"github.com/sasalatart/batcoms/pkg/dates"
+	"github.com/sasalatart/batcoms/pkg/wikidata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixturesServer is a stand-in for the API of Wikidata, that serves the files in testdata
+func fixturesServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		assert.Equal(t, "wbgetentities", query.Get("action"), "Requesting %s", r.URL)
+		fileName := "missing.json"
+		switch {
+		case query.Get("ids") != "":
+			assert.Equal(t, "en", query.Get("languages"), "Requesting %s", r.URL)
+			fileName = "labels.json"
+		case query.Get("sites") == "enwiki":
+			title := strings.ReplaceAll(query.Get("titles"), " ", "_") + ".json"
+			if _, err := os.Stat(filepath.Join("testdata", title)); err == nil {
+				fileName = title
+			}
+		}
+		http.ServeFile(w, r, filepath.Join("testdata", fileName))
+	}))
+}
+
+func TestFacts(t *testing.T) {
+	server := fixturesServer(t)
+	defer server.Close()
+	client := wikidata.NewClient(server.URL)
+
+	cases := []struct {
+		url      string
+		expected wikidata.Facts
+	}{
+		{
+			url: "https://en.wikipedia.org/wiki/Napoleon",
+			expected: wikidata.Facts{
+				QID:           "Q517",
+				BirthDate:     &dates.Historic{Year: 1769, Month: 8, Day: 15, Calendar: dates.GregorianCalendar},
+				BirthPlace:    "Ajaccio",
+				DeathDate:     &dates.Historic{Year: 1821, Month: 5, Day: 5, Calendar: dates.GregorianCalendar},
+				DeathPlace:    "Longwood House",
+				Nationalities: []string{"France"},
+			},
+		},
+		{
+			url: "https://en.wikipedia.org/wiki/First_French_Empire",
+			expected: wikidata.Facts{
+				QID:         "Q71084",
+				Inception:   &dates.Historic{Year: 1804, Calendar: dates.GregorianCalendar},
+				Dissolution: &dates.Historic{Year: 1814, Month: 4, Day: 6, Calendar: dates.GregorianCalendar},
+			},
+		},
+		{
+			url: "https://en.wikipedia.org/wiki/Battle_of_Austerlitz",
+			expected: wikidata.Facts{
+				QID:         "Q134114",
+				PointInTime: &dates.Historic{Year: 1805, Month: 12, Day: 2, Calendar: dates.GregorianCalendar},
+				Coordinates: &wikidata.Coordinates{Latitude: 49.128, Longitude: 16.762},
+			},
+		},
+		{
+			url: "https://en.wikipedia.org/wiki/Battle_of_Megiddo_(15th_century_BC)",
+			expected: wikidata.Facts{
+				QID:         "Q652328",
+				PointInTime: &dates.Historic{Year: 1457, IsBCE: true, Calendar: dates.JulianCalendar},
+				Coordinates: &wikidata.Coordinates{Latitude: 29.5, Longitude: 31.2},
+			},
+		},
+	}
+	for _, c := range cases {
+		got, err := client.Facts(c.url)
+		require.NoErrorf(t, err, "Fetching facts for %s", c.url)
+		assert.Equal(t, c.expected, got, "Fetching facts for %s", c.url)
+	}
+
+	t.Run("MissingItem", func(t *testing.T) {
+		_, err := client.Facts("https://en.wikipedia.org/wiki/Nothing_here")
+		assert.Equal(t, wikidata.ErrNotFound, errors.Cause(err))
+	})
+
+	t.Run("NonWikiURL", func(t *testing.T) {
+		_, err := client.Facts("https://www.example.org/wiki/Napoleon")
+		assert.Equal(t, summaries.ErrNotWiki, errors.Cause(err))
+	})
+}
+
+func TestDiscrepancies(t *testing.T) {
+	facts := wikidata.Facts{
+		PointInTime: &dates.Historic{Year: 1457, IsBCE: true},
+		Coordinates: &wikidata.Coordinates{Latitude: 29.5, Longitude: 31.2},
+	}
+
+	matching := locations.Location{Latitude: "29°30′N", Longitude: "31°12′E"}
+	assert.Empty(t, facts.Discrepancies("April 16, 1457 BC", matching), "Comparing with matching data")
+
+	distant := locations.Location{Latitude: "32°35′N", Longitude: "35°11′E"}
+	assert.Equal(t, []string{
+		"point in time is 1457 BC, but 1458 BC was scraped",
+		"coordinates are 29.5000, 31.2000, but 32.5833, 35.1833 were scraped",
+	}, facts.Discrepancies("1458 BC", distant), "Comparing with mismatching data")
+}